@@ -0,0 +1,135 @@
+// Package conversion serves the CustomResourceDefinition conversion webhook that converts
+// AgenticSessions between the v1alpha1 storage version and v1.
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	vteamv1 "ambient-code-api/vteam/v1"
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The ConversionReview wire types of apiextensions.k8s.io/v1, declared here so the module does not
+// depend on k8s.io/apiextensions-apiserver.
+
+// ConversionReview describes a conversion request/response
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *ConversionRequest  `json:"request,omitempty"`
+	Response        *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest describes the conversion request parameters
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse describes a conversion response
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// NewHandler returns the http.Handler for the conversion webhook endpoint
+func NewHandler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 10<<20))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	var review ConversionReview
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, "invalid ConversionReview", http.StatusBadRequest)
+		return
+	}
+
+	resp := &ConversionResponse{UID: review.Request.UID}
+	converted, err := Convert(review.Request.Objects, review.Request.DesiredAPIVersion)
+	if err != nil {
+		log.Printf("conversion: request %s to %s failed: %v", review.Request.UID, review.Request.DesiredAPIVersion, err)
+		resp.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+	} else {
+		resp.ConvertedObjects = converted
+		resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	}
+
+	out := ConversionReview{TypeMeta: review.TypeMeta, Response: resp}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		log.Printf("conversion: failed to write response: %v", err)
+	}
+}
+
+// Convert converts AgenticSession objects to desiredAPIVersion. Objects already in that version are
+// returned unchanged.
+func Convert(objects []runtime.RawExtension, desiredAPIVersion string) ([]runtime.RawExtension, error) {
+	converted := make([]runtime.RawExtension, 0, len(objects))
+	for i, obj := range objects {
+		raw, err := convertObject(obj.Raw, desiredAPIVersion)
+		if err != nil {
+			return nil, fmt.Errorf("object %d: %w", i, err)
+		}
+		converted = append(converted, runtime.RawExtension{Raw: raw})
+	}
+	return converted, nil
+}
+
+func convertObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	var meta metav1.TypeMeta
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, fmt.Errorf("decode type metadata: %w", err)
+	}
+	if meta.Kind != "AgenticSession" {
+		return nil, fmt.Errorf("unsupported kind %q", meta.Kind)
+	}
+	if meta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	// Convert through the storage version
+	var stored *vteamv1alpha1.AgenticSession
+	switch meta.APIVersion {
+	case vteamv1alpha1.SchemeGroupVersion.String():
+		stored = &vteamv1alpha1.AgenticSession{}
+		if err := json.Unmarshal(raw, stored); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", meta.APIVersion, err)
+		}
+	case vteamv1.SchemeGroupVersion.String():
+		in := &vteamv1.AgenticSession{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", meta.APIVersion, err)
+		}
+		stored = vteamv1alpha1.ConvertFromV1(in)
+	default:
+		return nil, fmt.Errorf("unsupported apiVersion %q", meta.APIVersion)
+	}
+
+	switch desiredAPIVersion {
+	case vteamv1alpha1.SchemeGroupVersion.String():
+		stored.APIVersion = desiredAPIVersion
+		return json.Marshal(stored)
+	case vteamv1.SchemeGroupVersion.String():
+		return json.Marshal(stored.ConvertToV1())
+	default:
+		return nil, fmt.Errorf("unsupported desired apiVersion %q", desiredAPIVersion)
+	}
+}
@@ -0,0 +1,117 @@
+package conversion
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	vteamv1 "ambient-code-api/vteam/v1"
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// storedSession is a v1alpha1 AgenticSession as stored, with every spec and status field set
+const storedSession = `{
+  "apiVersion": "vteam.ambient-code/v1alpha1",
+  "kind": "AgenticSession",
+  "metadata": {"name": "session-1", "namespace": "team-a", "labels": {"ambient-code.io/github-trigger": "true"}},
+  "spec": {
+    "prompt": "fix the build",
+    "displayName": "Fix the build",
+    "project": "team-a",
+    "interactive": true,
+    "llmSettings": {"model": "sonnet", "temperature": 0.7, "maxTokens": 4000},
+    "timeout": 300,
+    "autoPushOnComplete": true,
+    "repos": [
+      {"input": {"url": "https://github.com/acme/app", "branch": "main"}, "output": {"url": "https://github.com/acme/app", "branch": "ambient/fix"}},
+      {"input": {"url": "https://github.com/acme/lib"}}
+    ],
+    "mainRepoIndex": 1,
+    "agentPersonas": ["architect"],
+    "environmentVariables": {"LOG_LEVEL": "debug"},
+    "userContext": {"userId": "alice", "displayName": "Alice", "email": "alice@example.com", "groups": ["devs"]},
+    "botAccount": {"name": "ci-bot"},
+    "resourceOverrides": {"cpu": "2", "memory": "4Gi", "storageClass": "fast", "priorityClass": "high"},
+    "secrets": {"keys": ["ANTHROPIC_API_KEY"], "bundles": ["github"]}
+  },
+  "status": {
+    "phase": "Completed",
+    "message": "done",
+    "startTime": "2025-03-01T10:00:00Z",
+    "completionTime": "2025-03-01T10:30:00Z",
+    "jobName": "session-1-job",
+    "stateDir": "/workspace/session-1",
+    "grantedSecretKeys": ["ANTHROPIC_API_KEY", "GITHUB_TOKEN"],
+    "egressPolicy": {
+      "mode": "Restricted",
+      "presets": ["github"],
+      "allowedHosts": ["github.com"],
+      "allowedCIDRs": ["140.82.112.0/20"],
+      "proxy": true,
+      "networkPolicy": "ambient-egress-session-1",
+      "warnings": ["addresses resolved at Job creation"]
+    },
+    "subtype": "success",
+    "is_error": true,
+    "num_turns": 12,
+    "session_id": "sdk-session",
+    "total_cost_usd": 0.42,
+    "usage": {"input_tokens": 100, "output_tokens": 50},
+    "result": "Fixed the build",
+    "has_workspace_changes": true,
+    "repos": [{"name": "app", "status": "pushed", "last_updated": "2025-03-01T10:30:00Z", "total_added": 10, "total_removed": 2}]
+  }
+}`
+
+func convertOne(t *testing.T, raw []byte, desiredAPIVersion string) []byte {
+	t.Helper()
+	out, err := Convert([]runtime.RawExtension{{Raw: raw}}, desiredAPIVersion)
+	if err != nil {
+		t.Fatalf("Convert to %s: %v", desiredAPIVersion, err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("Convert to %s returned %d objects, want 1", desiredAPIVersion, len(out))
+	}
+	return out[0].Raw
+}
+
+func decodeJSON(t *testing.T, raw []byte) map[string]interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		t.Fatalf("decode %s: %v", raw, err)
+	}
+	return m
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	v1Raw := convertOne(t, []byte(storedSession), vteamv1.SchemeGroupVersion.String())
+
+	var v1Session vteamv1.AgenticSession
+	if err := json.Unmarshal(v1Raw, &v1Session); err != nil {
+		t.Fatalf("decode v1 object: %v", err)
+	}
+	st := v1Session.Status
+	if v1Session.APIVersion != vteamv1.SchemeGroupVersion.String() {
+		t.Errorf("apiVersion = %q, want %q", v1Session.APIVersion, vteamv1.SchemeGroupVersion.String())
+	}
+	if !st.IsError || st.NumTurns != 12 || st.SessionID != "sdk-session" || st.TotalCostUSD == nil || *st.TotalCostUSD != 0.42 || !st.HasWorkspaceChanges {
+		t.Errorf("v1 result fields not converted: %+v", st)
+	}
+	if len(st.Repos) != 1 || st.Repos[0].LastUpdated == nil || st.Repos[0].TotalAdded != 10 || st.Repos[0].TotalRemoved != 2 {
+		t.Errorf("v1 status.repos not converted: %+v", st.Repos)
+	}
+	if st.EgressPolicy == nil || st.EgressPolicy.NetworkPolicy != "ambient-egress-session-1" {
+		t.Errorf("v1 status.egressPolicy not converted: %+v", st.EgressPolicy)
+	}
+	if st.Usage == nil || !reflect.DeepEqual(decodeJSON(t, st.Usage.Raw), map[string]interface{}{"input_tokens": 100.0, "output_tokens": 50.0}) {
+		t.Errorf("v1 status.usage not preserved: %+v", st.Usage)
+	}
+
+	back := convertOne(t, v1Raw, vteamv1alpha1.SchemeGroupVersion.String())
+	if got, want := decodeJSON(t, back), decodeJSON(t, []byte(storedSession)); !reflect.DeepEqual(got, want) {
+		t.Errorf("v1alpha1 -> v1 -> v1alpha1 changed the object:\n got %s\nwant %s", back, storedSession)
+	}
+}
@@ -0,0 +1,28 @@
+#!/bin/bash
+
+set -euo pipefail
+
+# Regenerates deepcopy functions and the typed clientset for the vteam.ambient-code API.
+# Requires k8s.io/code-generator at the version matching k8s.io/client-go in go.mod.
+
+API_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+MODULE="ambient-code-api"
+CODEGEN_VERSION="${CODEGEN_VERSION:-$(cd "${API_DIR}" && go list -m -f '{{.Version}}' k8s.io/client-go)}"
+CODEGEN_PKG="${CODEGEN_PKG:-$(go env GOMODCACHE)/k8s.io/code-generator@${CODEGEN_VERSION}}"
+
+if [[ ! -d "${CODEGEN_PKG}" ]]; then
+  (cd "${API_DIR}" && go mod download "k8s.io/code-generator@${CODEGEN_VERSION}")
+fi
+
+source "${CODEGEN_PKG}/kube_codegen.sh"
+
+kube::codegen::gen_helpers \
+  --boilerplate /dev/null \
+  "${API_DIR}/vteam"
+
+kube::codegen::gen_client \
+  --output-dir "${API_DIR}" \
+  --output-pkg "${MODULE}" \
+  --boilerplate /dev/null \
+  --one-input-api vteam \
+  "${API_DIR}"
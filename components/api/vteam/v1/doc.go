@@ -0,0 +1,6 @@
+// Package v1 contains the v1 API of the vteam.ambient-code group. AgenticSession is served in
+// v1 and converted to and from the v1alpha1 storage version by the conversion webhook.
+//
+// +k8s:deepcopy-gen=package
+// +groupName=vteam.ambient-code
+package v1
@@ -0,0 +1,188 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AgenticSession is one run of the Claude Code runner in a project namespace
+type AgenticSession struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   AgenticSessionSpec   `json:"spec"`
+	Status AgenticSessionStatus `json:"status,omitempty"`
+}
+
+// AgenticSessionSpec is the desired state of an AgenticSession
+type AgenticSessionSpec struct {
+	// Prompt is the initial prompt for the session
+	Prompt string `json:"prompt"`
+	// DisplayName is a descriptive name generated from the prompt
+	DisplayName string `json:"displayName,omitempty"`
+	// Project is the namespace the session runs in
+	Project string `json:"project,omitempty"`
+	// Interactive runs the session in chat mode using inbox/outbox files
+	Interactive bool `json:"interactive,omitempty"`
+	// LLMSettings configures the model
+	LLMSettings LLMSettings `json:"llmSettings"`
+	// Timeout in seconds
+	Timeout int64 `json:"timeout"`
+	// AutoPushOnComplete commits and pushes changes after the runner finishes
+	AutoPushOnComplete bool `json:"autoPushOnComplete,omitempty"`
+
+	// Repos are the repositories cloned into the workspace
+	Repos []RepoMapping `json:"repos,omitempty"`
+	// MainRepoIndex is the index in Repos used as the runner's working directory
+	MainRepoIndex *int32 `json:"mainRepoIndex,omitempty"`
+
+	// AgentPersonas are agents from the linked RFE workflow's .claude/agents activated for the session
+	AgentPersonas []string `json:"agentPersonas,omitempty"`
+	// EnvironmentVariables are passed to the runner
+	EnvironmentVariables map[string]string `json:"environmentVariables,omitempty"`
+	// UserContext is the authenticated caller captured at creation time
+	UserContext *UserContext `json:"userContext,omitempty"`
+	// BotAccount names the bot account the session acts as
+	BotAccount *BotAccountRef `json:"botAccount,omitempty"`
+	// ResourceOverrides adjusts the runner's resources and scheduling
+	ResourceOverrides *ResourceOverrides `json:"resourceOverrides,omitempty"`
+	// Secrets selects the runner secret keys the session may see; nil injects the whole secret
+	Secrets *SessionSecrets `json:"secrets,omitempty"`
+}
+
+// MainRepo returns the repo at MainRepoIndex (the first repo by default), or nil without repos
+func (s *AgenticSessionSpec) MainRepo() *RepoMapping {
+	if len(s.Repos) == 0 {
+		return nil
+	}
+	i := 0
+	if s.MainRepoIndex != nil && int(*s.MainRepoIndex) >= 0 && int(*s.MainRepoIndex) < len(s.Repos) {
+		i = int(*s.MainRepoIndex)
+	}
+	return &s.Repos[i]
+}
+
+// LLMSettings configures the model used by the runner
+type LLMSettings struct {
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"`
+	MaxTokens   int64   `json:"maxTokens"`
+}
+
+// RepoMapping is a repository to clone and, optionally, where to push changes
+type RepoMapping struct {
+	Input  GitRepoRef  `json:"input"`
+	Output *GitRepoRef `json:"output,omitempty"`
+}
+
+// GitRepoRef is a Git repository URL and branch
+type GitRepoRef struct {
+	URL    string  `json:"url"`
+	Branch *string `json:"branch,omitempty"`
+}
+
+// UserContext identifies the user who created a session
+type UserContext struct {
+	UserID      string   `json:"userId"`
+	DisplayName string   `json:"displayName"`
+	Email       string   `json:"email,omitempty"`
+	Groups      []string `json:"groups"`
+}
+
+// BotAccountRef references a bot account by name
+type BotAccountRef struct {
+	Name string `json:"name"`
+}
+
+// ResourceOverrides adjusts the runner pod
+type ResourceOverrides struct {
+	CPU           string `json:"cpu,omitempty"`
+	Memory        string `json:"memory,omitempty"`
+	StorageClass  string `json:"storageClass,omitempty"`
+	PriorityClass string `json:"priorityClass,omitempty"`
+}
+
+// SessionSecrets names runner secret keys directly or through ProjectSettings secret bundles
+type SessionSecrets struct {
+	Keys    []string `json:"keys,omitempty"`
+	Bundles []string `json:"bundles,omitempty"`
+}
+
+// AgenticSessionPhase is the lifecycle phase of a session
+type AgenticSessionPhase string
+
+const (
+	AgenticSessionPhasePending   AgenticSessionPhase = "Pending"
+	AgenticSessionPhaseCreating  AgenticSessionPhase = "Creating"
+	AgenticSessionPhaseRunning   AgenticSessionPhase = "Running"
+	AgenticSessionPhaseCompleted AgenticSessionPhase = "Completed"
+	AgenticSessionPhaseFailed    AgenticSessionPhase = "Failed"
+	AgenticSessionPhaseStopped   AgenticSessionPhase = "Stopped"
+	AgenticSessionPhaseError     AgenticSessionPhase = "Error"
+)
+
+// AgenticSessionStatus is the observed state of an AgenticSession
+type AgenticSessionStatus struct {
+	Phase          AgenticSessionPhase `json:"phase,omitempty"`
+	Message        string              `json:"message,omitempty"`
+	StartTime      *metav1.Time        `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time        `json:"completionTime,omitempty"`
+	JobName        string              `json:"jobName,omitempty"`
+	StateDir       string              `json:"stateDir,omitempty"`
+	// GrantedSecretKeys are the runner secret keys projected into the Job
+	GrantedSecretKeys []string `json:"grantedSecretKeys,omitempty"`
+	// EgressPolicy is the network egress policy applied to the Job
+	EgressPolicy *EgressPolicyStatus `json:"egressPolicy,omitempty"`
+
+	// Result summary from the runner
+	Subtype      string                `json:"subtype,omitempty"`
+	IsError      bool                  `json:"isError,omitempty"`
+	NumTurns     int64                 `json:"numTurns,omitempty"`
+	SessionID    string                `json:"sessionId,omitempty"`
+	TotalCostUSD *float64              `json:"totalCostUSD,omitempty"`
+	Usage        *runtime.RawExtension `json:"usage,omitempty"`
+	Result       *string               `json:"result,omitempty"`
+
+	HasWorkspaceChanges bool         `json:"hasWorkspaceChanges,omitempty"`
+	Repos               []RepoStatus `json:"repos,omitempty"`
+}
+
+// EgressPolicyStatus records the effective egress policy of a session's Job
+type EgressPolicyStatus struct {
+	// Mode is Unrestricted or Restricted
+	Mode string `json:"mode"`
+	// Presets are the named host groups included in the allow-list
+	Presets []string `json:"presets,omitempty"`
+	// AllowedHosts are the hostnames allowed, presets expanded
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+	// AllowedCIDRs are the address ranges allowed, including resolved hostnames
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+	// Proxy reports whether the hostname-filtering egress proxy sidecar runs in the Job
+	Proxy bool `json:"proxy,omitempty"`
+	// NetworkPolicy names the NetworkPolicy created for the Job
+	NetworkPolicy string `json:"networkPolicy,omitempty"`
+	// Warnings explain where enforcement is weaker than the declared policy
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// RepoStatus tracks what happened to one repository's changes
+type RepoStatus struct {
+	Name         string       `json:"name,omitempty"`
+	Status       string       `json:"status,omitempty"`
+	LastUpdated  *metav1.Time `json:"lastUpdated,omitempty"`
+	TotalAdded   int64        `json:"totalAdded,omitempty"`
+	TotalRemoved int64        `json:"totalRemoved,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// AgenticSessionList is a list of AgenticSessions
+type AgenticSessionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []AgenticSession `json:"items"`
+}
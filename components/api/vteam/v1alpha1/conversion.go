@@ -0,0 +1,192 @@
+package v1alpha1
+
+import (
+	v1 "ambient-code-api/vteam/v1"
+)
+
+// The spec is identical in both versions. The status differs only in the names of the runner's
+// result fields, which v1 spells in camelCase.
+
+// ConvertToV1 converts a stored v1alpha1 AgenticSession into its v1 form
+func (in *AgenticSession) ConvertToV1() *v1.AgenticSession {
+	out := &v1.AgenticSession{}
+	out.TypeMeta = in.TypeMeta
+	out.APIVersion = v1.SchemeGroupVersion.String()
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	convertSpecToV1(&in.Spec, &out.Spec)
+	convertStatusToV1(&in.Status, &out.Status)
+	return out
+}
+
+// ConvertFromV1 converts a v1 AgenticSession into the v1alpha1 storage form
+func ConvertFromV1(in *v1.AgenticSession) *AgenticSession {
+	out := &AgenticSession{}
+	out.TypeMeta = in.TypeMeta
+	out.APIVersion = SchemeGroupVersion.String()
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	convertSpecFromV1(&in.Spec, &out.Spec)
+	convertStatusFromV1(&in.Status, &out.Status)
+	return out
+}
+
+func convertSpecToV1(in *AgenticSessionSpec, out *v1.AgenticSessionSpec) {
+	in = in.DeepCopy()
+	out.Prompt = in.Prompt
+	out.DisplayName = in.DisplayName
+	out.Project = in.Project
+	out.Interactive = in.Interactive
+	out.LLMSettings = v1.LLMSettings{
+		Model:       in.LLMSettings.Model,
+		Temperature: in.LLMSettings.Temperature,
+		MaxTokens:   in.LLMSettings.MaxTokens,
+	}
+	out.Timeout = in.Timeout
+	out.AutoPushOnComplete = in.AutoPushOnComplete
+	out.Repos = nil
+	for _, r := range in.Repos {
+		m := v1.RepoMapping{Input: v1.GitRepoRef{URL: r.Input.URL, Branch: r.Input.Branch}}
+		if r.Output != nil {
+			m.Output = &v1.GitRepoRef{URL: r.Output.URL, Branch: r.Output.Branch}
+		}
+		out.Repos = append(out.Repos, m)
+	}
+	out.MainRepoIndex = in.MainRepoIndex
+	out.AgentPersonas = in.AgentPersonas
+	out.EnvironmentVariables = in.EnvironmentVariables
+	if in.UserContext != nil {
+		out.UserContext = &v1.UserContext{
+			UserID:      in.UserContext.UserID,
+			DisplayName: in.UserContext.DisplayName,
+			Email:       in.UserContext.Email,
+			Groups:      in.UserContext.Groups,
+		}
+	}
+	if in.BotAccount != nil {
+		out.BotAccount = &v1.BotAccountRef{Name: in.BotAccount.Name}
+	}
+	if in.ResourceOverrides != nil {
+		out.ResourceOverrides = &v1.ResourceOverrides{
+			CPU:           in.ResourceOverrides.CPU,
+			Memory:        in.ResourceOverrides.Memory,
+			StorageClass:  in.ResourceOverrides.StorageClass,
+			PriorityClass: in.ResourceOverrides.PriorityClass,
+		}
+	}
+	if in.Secrets != nil {
+		out.Secrets = &v1.SessionSecrets{Keys: in.Secrets.Keys, Bundles: in.Secrets.Bundles}
+	}
+}
+
+func convertSpecFromV1(in *v1.AgenticSessionSpec, out *AgenticSessionSpec) {
+	in = in.DeepCopy()
+	out.Prompt = in.Prompt
+	out.DisplayName = in.DisplayName
+	out.Project = in.Project
+	out.Interactive = in.Interactive
+	out.LLMSettings = LLMSettings{
+		Model:       in.LLMSettings.Model,
+		Temperature: in.LLMSettings.Temperature,
+		MaxTokens:   in.LLMSettings.MaxTokens,
+	}
+	out.Timeout = in.Timeout
+	out.AutoPushOnComplete = in.AutoPushOnComplete
+	out.Repos = nil
+	for _, r := range in.Repos {
+		m := RepoMapping{Input: GitRepoRef{URL: r.Input.URL, Branch: r.Input.Branch}}
+		if r.Output != nil {
+			m.Output = &GitRepoRef{URL: r.Output.URL, Branch: r.Output.Branch}
+		}
+		out.Repos = append(out.Repos, m)
+	}
+	out.MainRepoIndex = in.MainRepoIndex
+	out.AgentPersonas = in.AgentPersonas
+	out.EnvironmentVariables = in.EnvironmentVariables
+	if in.UserContext != nil {
+		out.UserContext = &UserContext{
+			UserID:      in.UserContext.UserID,
+			DisplayName: in.UserContext.DisplayName,
+			Email:       in.UserContext.Email,
+			Groups:      in.UserContext.Groups,
+		}
+	}
+	if in.BotAccount != nil {
+		out.BotAccount = &BotAccountRef{Name: in.BotAccount.Name}
+	}
+	if in.ResourceOverrides != nil {
+		out.ResourceOverrides = &ResourceOverrides{
+			CPU:           in.ResourceOverrides.CPU,
+			Memory:        in.ResourceOverrides.Memory,
+			StorageClass:  in.ResourceOverrides.StorageClass,
+			PriorityClass: in.ResourceOverrides.PriorityClass,
+		}
+	}
+	if in.Secrets != nil {
+		out.Secrets = &SessionSecrets{Keys: in.Secrets.Keys, Bundles: in.Secrets.Bundles}
+	}
+}
+
+func convertStatusToV1(in *AgenticSessionStatus, out *v1.AgenticSessionStatus) {
+	in = in.DeepCopy()
+	out.Phase = v1.AgenticSessionPhase(in.Phase)
+	out.Message = in.Message
+	out.StartTime = in.StartTime
+	out.CompletionTime = in.CompletionTime
+	out.JobName = in.JobName
+	out.StateDir = in.StateDir
+	out.GrantedSecretKeys = in.GrantedSecretKeys
+	if in.EgressPolicy != nil {
+		p := v1.EgressPolicyStatus(*in.EgressPolicy)
+		out.EgressPolicy = &p
+	}
+	out.Subtype = in.Subtype
+	out.IsError = in.IsError
+	out.NumTurns = in.NumTurns
+	out.SessionID = in.SessionID
+	out.TotalCostUSD = in.TotalCostUSD
+	out.Usage = in.Usage
+	out.Result = in.Result
+	out.HasWorkspaceChanges = in.HasWorkspaceChanges
+	out.Repos = nil
+	for _, r := range in.Repos {
+		out.Repos = append(out.Repos, v1.RepoStatus{
+			Name:         r.Name,
+			Status:       r.Status,
+			LastUpdated:  r.LastUpdated,
+			TotalAdded:   r.TotalAdded,
+			TotalRemoved: r.TotalRemoved,
+		})
+	}
+}
+
+func convertStatusFromV1(in *v1.AgenticSessionStatus, out *AgenticSessionStatus) {
+	in = in.DeepCopy()
+	out.Phase = AgenticSessionPhase(in.Phase)
+	out.Message = in.Message
+	out.StartTime = in.StartTime
+	out.CompletionTime = in.CompletionTime
+	out.JobName = in.JobName
+	out.StateDir = in.StateDir
+	out.GrantedSecretKeys = in.GrantedSecretKeys
+	if in.EgressPolicy != nil {
+		p := EgressPolicyStatus(*in.EgressPolicy)
+		out.EgressPolicy = &p
+	}
+	out.Subtype = in.Subtype
+	out.IsError = in.IsError
+	out.NumTurns = in.NumTurns
+	out.SessionID = in.SessionID
+	out.TotalCostUSD = in.TotalCostUSD
+	out.Usage = in.Usage
+	out.Result = in.Result
+	out.HasWorkspaceChanges = in.HasWorkspaceChanges
+	out.Repos = nil
+	for _, r := range in.Repos {
+		out.Repos = append(out.Repos, RepoStatus{
+			Name:         r.Name,
+			Status:       r.Status,
+			LastUpdated:  r.LastUpdated,
+			TotalAdded:   r.TotalAdded,
+			TotalRemoved: r.TotalRemoved,
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// populatedSession sets every spec and status field, so a field missed by the conversion
+// functions shows up as a round-trip difference
+func populatedSession() *AgenticSession {
+	str := func(s string) *string { return &s }
+	idx := int32(1)
+	cost := 0.42
+	started := metav1.NewTime(time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC))
+	finished := metav1.NewTime(time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC))
+	return &AgenticSession{
+		TypeMeta: metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "AgenticSession"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "session-1",
+			Namespace:   "team-a",
+			Labels:      map[string]string{"ambient-code.io/github-trigger": "true"},
+			Annotations: map[string]string{"ambient-code.io/runner-token-secret": "ambient-runner-token-session-1"},
+		},
+		Spec: AgenticSessionSpec{
+			Prompt:             "fix the build",
+			DisplayName:        "Fix the build",
+			Project:            "team-a",
+			Interactive:        true,
+			LLMSettings:        LLMSettings{Model: "sonnet", Temperature: 0.7, MaxTokens: 4000},
+			Timeout:            300,
+			AutoPushOnComplete: true,
+			Repos: []RepoMapping{
+				{Input: GitRepoRef{URL: "https://github.com/acme/app", Branch: str("main")}, Output: &GitRepoRef{URL: "https://github.com/acme/app", Branch: str("ambient/fix")}},
+				{Input: GitRepoRef{URL: "https://github.com/acme/lib"}},
+			},
+			MainRepoIndex:        &idx,
+			AgentPersonas:        []string{"architect"},
+			EnvironmentVariables: map[string]string{"LOG_LEVEL": "debug"},
+			UserContext:          &UserContext{UserID: "alice", DisplayName: "Alice", Email: "alice@example.com", Groups: []string{"devs"}},
+			BotAccount:           &BotAccountRef{Name: "ci-bot"},
+			ResourceOverrides:    &ResourceOverrides{CPU: "2", Memory: "4Gi", StorageClass: "fast", PriorityClass: "high"},
+			Secrets:              &SessionSecrets{Keys: []string{"ANTHROPIC_API_KEY"}, Bundles: []string{"github"}},
+		},
+		Status: AgenticSessionStatus{
+			Phase:             AgenticSessionPhaseCompleted,
+			Message:           "done",
+			StartTime:         &started,
+			CompletionTime:    &finished,
+			JobName:           "session-1-job",
+			StateDir:          "/workspace/session-1",
+			GrantedSecretKeys: []string{"ANTHROPIC_API_KEY", "GITHUB_TOKEN"},
+			EgressPolicy: &EgressPolicyStatus{
+				Mode:          "Restricted",
+				Presets:       []string{"github"},
+				AllowedHosts:  []string{"github.com"},
+				AllowedCIDRs:  []string{"140.82.112.0/20"},
+				Proxy:         true,
+				NetworkPolicy: "ambient-egress-session-1",
+				Warnings:      []string{"addresses resolved at Job creation"},
+			},
+			Subtype:             "success",
+			IsError:             true,
+			NumTurns:            12,
+			SessionID:           "sdk-session",
+			TotalCostUSD:        &cost,
+			Usage:               &runtime.RawExtension{Raw: []byte(`{"input_tokens":100,"output_tokens":50}`)},
+			Result:              str("Fixed the build"),
+			HasWorkspaceChanges: true,
+			Repos: []RepoStatus{{
+				Name:         "app",
+				Status:       "pushed",
+				LastUpdated:  &finished,
+				TotalAdded:   10,
+				TotalRemoved: 2,
+			}},
+		},
+	}
+}
+
+// assertPopulated fails for zero-valued struct fields, so new fields must be added to
+// populatedSession (and to the conversion functions) before the round trip passes
+func assertPopulated(t *testing.T, path string, v reflect.Value) {
+	t.Helper()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			t.Errorf("%s is not set in the test object", path)
+			return
+		}
+		assertPopulated(t, path, v.Elem())
+	case reflect.Struct:
+		// Times and raw extensions are leaves; checking their internals says nothing useful
+		if v.Type() == reflect.TypeOf(metav1.Time{}) || v.Type() == reflect.TypeOf(runtime.RawExtension{}) {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			assertPopulated(t, path+"."+v.Type().Field(i).Name, v.Field(i))
+		}
+	default:
+		if v.IsZero() {
+			t.Errorf("%s is not set in the test object", path)
+		}
+	}
+}
+
+func TestConversionRoundTrip(t *testing.T) {
+	in := populatedSession()
+	assertPopulated(t, "Spec", reflect.ValueOf(in.Spec))
+	assertPopulated(t, "Status", reflect.ValueOf(in.Status))
+
+	out := ConvertFromV1(in.DeepCopy().ConvertToV1())
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("v1alpha1 -> v1 -> v1alpha1 changed the object:\n got %+v\nwant %+v", out, in)
+	}
+}
@@ -0,0 +1,6 @@
+// Package v1alpha1 contains the v1alpha1 API of the vteam.ambient-code group, the storage
+// version of AgenticSession, RFEWorkflow and ProjectSettings.
+//
+// +k8s:deepcopy-gen=package
+// +groupName=vteam.ambient-code
+package v1alpha1
@@ -0,0 +1,244 @@
+// Package admission implements the validating and defaulting admission webhooks for the
+// AgenticSession, RFEWorkflow and ProjectSettings custom resources, so objects created with
+// kubectl get the same checks as those created through the backend API.
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"ambient-code-api/conversion"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authnv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxReviewBytes bounds AdmissionReview bodies; the API server caps objects well below this
+const maxReviewBytes = 3 << 20
+
+// Request is what validators and defaulters see of an AdmissionRequest
+type Request struct {
+	Operation admissionv1.Operation
+	Namespace string
+	Name      string
+	Object    map[string]interface{}
+	OldObject map[string]interface{} // UPDATE only
+	User      authnv1.UserInfo
+}
+
+// resourceHooks are the admission rules for one resource
+type resourceHooks struct {
+	// validate returns every problem with the object; empty means valid
+	validate func(req *Request) []string
+	// setDefaults fills unset fields of req.Object in place
+	setDefaults func(req *Request)
+}
+
+// hooks is keyed by the resource plural served under vteam.ambient-code
+var hooks = map[string]resourceHooks{
+	"agenticsessions": {validate: validateAgenticSession, setDefaults: defaultAgenticSession},
+	"rfeworkflows":    {validate: validateRFEWorkflow, setDefaults: defaultRFEWorkflow},
+	"projectsettings": {validate: validateProjectSettings, setDefaults: defaultProjectSettings},
+}
+
+// NewHandler returns the webhook HTTP handler: /validate and /mutate take AdmissionReviews,
+// /convert takes the AgenticSession CRD's ConversionReviews, /healthz reports liveness
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", serveReview(reviewValidate))
+	mux.HandleFunc("/mutate", serveReview(reviewMutate))
+	mux.Handle("/convert", conversion.NewHandler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	})
+	return mux
+}
+
+func serveReview(review func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxReviewBytes))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		var ar admissionv1.AdmissionReview
+		if err := json.Unmarshal(body, &ar); err != nil || ar.Request == nil {
+			http.Error(w, "invalid AdmissionReview", http.StatusBadRequest)
+			return
+		}
+
+		resp := review(ar.Request)
+		resp.UID = ar.Request.UID
+		out := admissionv1.AdmissionReview{TypeMeta: ar.TypeMeta, Response: resp}
+		if out.APIVersion == "" {
+			out.APIVersion = "admission.k8s.io/v1"
+			out.Kind = "AdmissionReview"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(out); err != nil {
+			log.Printf("admission: failed to write response: %v", err)
+		}
+	}
+}
+
+// decodeRequest unmarshals the objects of an AdmissionRequest
+func decodeRequest(ar *admissionv1.AdmissionRequest) (*Request, error) {
+	req := &Request{Operation: ar.Operation, Namespace: ar.Namespace, Name: ar.Name, User: ar.UserInfo}
+	if len(ar.Object.Raw) > 0 {
+		if err := json.Unmarshal(ar.Object.Raw, &req.Object); err != nil {
+			return nil, fmt.Errorf("decode object: %w", err)
+		}
+	}
+	if len(ar.OldObject.Raw) > 0 {
+		if err := json.Unmarshal(ar.OldObject.Raw, &req.OldObject); err != nil {
+			return nil, fmt.Errorf("decode old object: %w", err)
+		}
+	}
+	if req.Name == "" {
+		req.Name = metadataString(req.Object, "name")
+	}
+	return req, nil
+}
+
+// reviewValidate denies objects with problems. On UPDATE only problems the old object didn't
+// already have count, so objects stored before the webhook existed can still be updated.
+func reviewValidate(ar *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	h, ok := hooks[ar.Resource.Resource]
+	if !ok || h.validate == nil || ar.SubResource != "" {
+		return allowed()
+	}
+	req, err := decodeRequest(ar)
+	if err != nil {
+		return denied(http.StatusBadRequest, err.Error())
+	}
+	if req.Object == nil || metadataString(req.Object, "deletionTimestamp") != "" {
+		return allowed()
+	}
+
+	problems := h.validate(req)
+	if ar.Operation == admissionv1.Update && req.OldObject != nil && len(problems) > 0 {
+		existing := map[string]bool{}
+		for _, p := range h.validate(&Request{Operation: ar.Operation, Namespace: req.Namespace, Name: req.Name, Object: req.OldObject}) {
+			existing[p] = true
+		}
+		kept := problems[:0]
+		for _, p := range problems {
+			if !existing[p] {
+				kept = append(kept, p)
+			}
+		}
+		problems = kept
+	}
+	if len(problems) > 0 {
+		log.Printf("admission: denied %s %s %s/%s: %s", ar.Operation, ar.Kind.Kind, req.Namespace, req.Name, strings.Join(problems, "; "))
+		return denied(http.StatusUnprocessableEntity, fmt.Sprintf("%s %q is invalid: %s", ar.Kind.Kind, req.Name, strings.Join(problems, "; ")))
+	}
+	return allowed()
+}
+
+// reviewMutate applies defaults, returning a JSONPatch that replaces spec when anything changed
+func reviewMutate(ar *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	h, ok := hooks[ar.Resource.Resource]
+	if !ok || h.setDefaults == nil || ar.SubResource != "" {
+		return allowed()
+	}
+	req, err := decodeRequest(ar)
+	if err != nil {
+		return denied(http.StatusBadRequest, err.Error())
+	}
+	if req.Object == nil || metadataString(req.Object, "deletionTimestamp") != "" {
+		return allowed()
+	}
+
+	before, _ := json.Marshal(req.Object["spec"])
+	h.setDefaults(req)
+	after, _ := json.Marshal(req.Object["spec"])
+	if string(before) == string(after) {
+		return allowed()
+	}
+
+	op := "replace"
+	if string(before) == "null" {
+		op = "add"
+	}
+	patch, err := json.Marshal([]map[string]interface{}{{"op": op, "path": "/spec", "value": req.Object["spec"]}})
+	if err != nil {
+		return denied(http.StatusInternalServerError, fmt.Sprintf("encode patch: %v", err))
+	}
+	pt := admissionv1.PatchTypeJSONPatch
+	resp := allowed()
+	resp.Patch = patch
+	resp.PatchType = &pt
+	return resp
+}
+
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func denied(code int32, message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result:  &metav1.Status{Status: metav1.StatusFailure, Code: code, Message: message},
+	}
+}
+
+// spec returns obj.spec, creating it when missing
+func spec(obj map[string]interface{}) map[string]interface{} {
+	s, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		s = map[string]interface{}{}
+		obj["spec"] = s
+	}
+	return s
+}
+
+func metadataString(obj map[string]interface{}, key string) string {
+	meta, _ := obj["metadata"].(map[string]interface{})
+	v, _ := meta[key].(string)
+	return v
+}
+
+// number reads a JSON number, reporting whether the field was set
+func number(m map[string]interface{}, key string) (float64, bool) {
+	v, ok := m[key].(float64)
+	return v, ok
+}
+
+func str(m map[string]interface{}, key string) string {
+	v, _ := m[key].(string)
+	return strings.TrimSpace(v)
+}
+
+func objects(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{})
+	out := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		m, _ := item.(map[string]interface{})
+		if m == nil {
+			m = map[string]interface{}{}
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func stringItems(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, _ := item.(string)
+		out = append(out, s)
+	}
+	return out
+}
@@ -0,0 +1,118 @@
+package admission
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"ambient-code-backend/git"
+
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// validateAgenticSession mirrors the checks CreateSession applies to requests, including the
+// project's session policy
+func validateAgenticSession(req *Request) []string {
+	var problems []string
+	s := spec(req.Object)
+
+	if str(s, "prompt") == "" {
+		problems = append(problems, "spec.prompt must not be empty")
+	}
+	if project := str(s, "project"); project != "" && req.Namespace != "" && project != req.Namespace {
+		problems = append(problems, fmt.Sprintf("spec.project %q must match the namespace %q", project, req.Namespace))
+	}
+	if v, ok := number(s, "timeout"); ok && v <= 0 {
+		problems = append(problems, "spec.timeout must be a positive number of seconds")
+	}
+	if llm, ok := s["llmSettings"].(map[string]interface{}); ok {
+		if v, ok := number(llm, "maxTokens"); ok && v <= 0 {
+			problems = append(problems, "spec.llmSettings.maxTokens must be positive")
+		}
+		if v, ok := number(llm, "temperature"); ok && (v < 0 || v > 2) {
+			problems = append(problems, "spec.llmSettings.temperature must be between 0 and 2")
+		}
+	}
+
+	repos := objects(s["repos"])
+	inputURLs := make([]string, 0, len(repos))
+	for i, r := range repos {
+		in, _ := r["input"].(map[string]interface{})
+		inURL := str(in, "url")
+		if err := validateRepoURL(inURL); err != nil {
+			problems = append(problems, fmt.Sprintf("spec.repos[%d].input.url: %v", i, err))
+		}
+		inputURLs = append(inputURLs, inURL)
+
+		out, ok := r["output"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if outURL := str(out, "url"); outURL != "" {
+			if err := validateRepoURL(outURL); err != nil {
+				problems = append(problems, fmt.Sprintf("spec.repos[%d].output.url: %v", i, err))
+			}
+		}
+		if branch := str(out, "branch"); branch != "" && git.IsProtectedBranch(branch) {
+			problems = append(problems, fmt.Sprintf("spec.repos[%d].output.branch: %v", i, git.ValidateBranchName(branch)))
+		}
+	}
+	if err := git.ValidateUniqueRepoURLs(inputURLs); err != nil {
+		problems = append(problems, "spec.repos: "+err.Error())
+	}
+
+	if v, ok := number(s, "mainRepoIndex"); ok {
+		switch {
+		case v != float64(int(v)):
+			problems = append(problems, "spec.mainRepoIndex must be an integer")
+		case len(repos) == 0 && v != 0:
+			problems = append(problems, "spec.mainRepoIndex must be 0 when spec.repos is empty")
+		case len(repos) > 0 && (v < 0 || int(v) >= len(repos)):
+			problems = append(problems, fmt.Sprintf("spec.mainRepoIndex %d is out of range for %d repos", int(v), len(repos)))
+		}
+	}
+	return append(problems, validateSessionPolicy(req, s)...)
+}
+
+// defaultAgenticSession fills spec.project and gives output mappings without a branch the
+// runner's sessions/<name> branch explicitly
+func defaultAgenticSession(req *Request) {
+	s := spec(req.Object)
+	if str(s, "project") == "" && req.Namespace != "" {
+		s["project"] = req.Namespace
+	}
+	if req.Operation != admissionv1.Create || req.Name == "" {
+		return
+	}
+	for _, r := range objects(s["repos"]) {
+		out, ok := r["output"].(map[string]interface{})
+		if ok && str(out, "url") != "" && str(out, "branch") == "" {
+			out["branch"] = "sessions/" + req.Name
+		}
+	}
+}
+
+// validateRepoURL accepts http(s), ssh and scp-style (git@host:owner/repo) Git URLs
+func validateRepoURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if strings.ContainsAny(raw, " \t\n") {
+		return fmt.Errorf("%q must not contain whitespace", raw)
+	}
+	if at := strings.Index(raw, "@"); at > 0 && !strings.Contains(raw, "://") {
+		if strings.Contains(raw[at:], ":") {
+			return nil
+		}
+		return fmt.Errorf("%q is not a valid Git URL", raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%q is not a valid Git URL", raw)
+	}
+	switch u.Scheme {
+	case "https", "http", "ssh", "git":
+		return nil
+	}
+	return fmt.Errorf("%q uses unsupported scheme %q", raw, u.Scheme)
+}
@@ -0,0 +1,52 @@
+package admission
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// K8sClient runs SubjectAccessReviews for checks that depend on who is making the change.
+// It is set by main in ADMISSION_WEBHOOK_MODE; when nil those changes are denied.
+var K8sClient kubernetes.Interface
+
+// accessReviewTimeout keeps SubjectAccessReviews inside the webhook's own timeout
+const accessReviewTimeout = 3 * time.Second
+
+// isProjectAdmin reports whether user may update the namespace's ProjectSettings, which only
+// project admins (and the backend service account) can do
+func isProjectAdmin(user authnv1.UserInfo, namespace string) (bool, error) {
+	if K8sClient == nil {
+		return false, fmt.Errorf("webhook has no Kubernetes client")
+	}
+	extra := map[string]authv1.ExtraValue{}
+	for k, v := range user.Extra {
+		extra[k] = authv1.ExtraValue(v)
+	}
+	sar := &authv1.SubjectAccessReview{
+		Spec: authv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Group:     "vteam.ambient-code",
+				Resource:  "projectsettings",
+				Verb:      "update",
+				Namespace: namespace,
+			},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), accessReviewTimeout)
+	defer cancel()
+	res, err := K8sClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, sar, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("access review: %w", err)
+	}
+	return res.Status.Allowed, nil
+}
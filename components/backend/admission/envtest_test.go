@@ -0,0 +1,432 @@
+package admission
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// The suite runs the webhook against a real API server started by envtest, with the CRDs from
+// manifests/crds and the webhook configurations from manifests/admission-webhook.yaml. It needs
+// the envtest binaries: KUBEBUILDER_ASSETS=$(setup-envtest use -p path) go test ./admission
+
+const testNamespace = "admission-test"
+
+// policyNamespace has a ProjectSettings session policy; testNamespace has none until
+// TestAdmissionAppliesDefaults creates one
+const policyNamespace = "admission-policy-test"
+
+var (
+	testConfig  *rest.Config
+	testDynamic dynamic.Interface
+
+	sessionsGVR = schema.GroupVersionResource{Group: "vteam.ambient-code", Version: "v1alpha1", Resource: "agenticsessions"}
+	rfeGVR      = schema.GroupVersionResource{Group: "vteam.ambient-code", Version: "v1alpha1", Resource: "rfeworkflows"}
+	settingsGVR = schema.GroupVersionResource{Group: "vteam.ambient-code", Version: "v1alpha1", Resource: "projectsettings"}
+)
+
+func TestMain(m *testing.M) {
+	// Without envtest binaries the tests skip themselves in requireEnv
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		os.Exit(m.Run())
+	}
+	os.Exit(runSuite(m))
+}
+
+func runSuite(m *testing.M) int {
+	manifests := filepath.Join("..", "..", "manifests")
+	env := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join(manifests, "crds")},
+		ErrorIfCRDPathMissing: true,
+		WebhookInstallOptions: envtest.WebhookInstallOptions{
+			Paths: []string{filepath.Join(manifests, "admission-webhook.yaml")},
+		},
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Printf("start envtest: %v\n", err)
+		return 1
+	}
+	defer func() {
+		if err := env.Stop(); err != nil {
+			fmt.Printf("stop envtest: %v\n", err)
+		}
+	}()
+
+	// envtest points the webhook configurations at a local address with its own serving certificate
+	opts := env.WebhookInstallOptions
+	cert, err := tls.LoadX509KeyPair(filepath.Join(opts.LocalServingCertDir, "tls.crt"), filepath.Join(opts.LocalServingCertDir, "tls.key"))
+	if err != nil {
+		fmt.Printf("load serving certificate: %v\n", err)
+		return 1
+	}
+	ln, err := tls.Listen("tcp", net.JoinHostPort(opts.LocalServingHost, fmt.Sprintf("%d", opts.LocalServingPort)), &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		fmt.Printf("listen: %v\n", err)
+		return 1
+	}
+	srv := &http.Server{Handler: NewHandler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("webhook server: %v\n", err)
+		}
+	}()
+	defer srv.Close()
+
+	testConfig = cfg
+	if K8sClient, err = kubernetes.NewForConfig(cfg); err != nil {
+		fmt.Printf("create client: %v\n", err)
+		return 1
+	}
+	if testDynamic, err = dynamic.NewForConfig(cfg); err != nil {
+		fmt.Printf("create dynamic client: %v\n", err)
+		return 1
+	}
+	DynamicClient = testDynamic
+	for _, name := range []string{testNamespace, policyNamespace} {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if _, err := K8sClient.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{}); err != nil {
+			fmt.Printf("create namespace: %v\n", err)
+			return 1
+		}
+	}
+	return m.Run()
+}
+
+func requireEnv(t *testing.T) {
+	t.Helper()
+	if testDynamic == nil {
+		t.Skip("KUBEBUILDER_ASSETS is not set; skipping the admission envtest suite")
+	}
+}
+
+func object(kind, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "vteam.ambient-code/v1alpha1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": testNamespace},
+		"spec":       spec,
+	}}
+}
+
+func repo(url string) map[string]interface{} {
+	return map[string]interface{}{"input": map[string]interface{}{"url": url}}
+}
+
+func TestAdmissionRejectsInvalidObjects(t *testing.T) {
+	requireEnv(t)
+	tests := []struct {
+		name    string
+		gvr     schema.GroupVersionResource
+		obj     *unstructured.Unstructured
+		wantErr string
+	}{
+		{
+			name: "mainRepoIndex out of range",
+			gvr:  sessionsGVR,
+			obj: object("AgenticSession", "bad-main-repo", map[string]interface{}{
+				"prompt":        "fix the build",
+				"repos":         []interface{}{repo("https://github.com/acme/app")},
+				"mainRepoIndex": int64(3),
+			}),
+			wantErr: "spec.mainRepoIndex 3 is out of range for 1 repos",
+		},
+		{
+			name: "duplicate repository URLs",
+			gvr:  sessionsGVR,
+			obj: object("AgenticSession", "duplicate-repos", map[string]interface{}{
+				"prompt": "fix the build",
+				"repos": []interface{}{
+					repo("https://github.com/acme/app"),
+					repo("https://github.com/acme/app.git"),
+				},
+			}),
+			wantErr: "spec.repos:",
+		},
+		{
+			name: "protected output branch",
+			gvr:  sessionsGVR,
+			obj: object("AgenticSession", "protected-branch", map[string]interface{}{
+				"prompt": "fix the build",
+				"repos": []interface{}{map[string]interface{}{
+					"input":  map[string]interface{}{"url": "https://github.com/acme/app"},
+					"output": map[string]interface{}{"url": "https://github.com/acme/app", "branch": "main"},
+				}},
+			}),
+			wantErr: "spec.repos[0].output.branch",
+		},
+		{
+			name: "RFE workflow with duplicate repositories",
+			gvr:  rfeGVR,
+			obj: object("RFEWorkflow", "duplicate-rfe-repos", map[string]interface{}{
+				"title":           "Session export",
+				"description":     "Export transcripts",
+				"umbrellaRepo":    map[string]interface{}{"url": "https://github.com/acme/specs"},
+				"supportingRepos": []interface{}{map[string]interface{}{"url": "https://github.com/acme/specs"}},
+			}),
+			wantErr: "RFEWorkflow \"duplicate-rfe-repos\" is invalid",
+		},
+		{
+			name: "RFE workflow with duplicate gates",
+			gvr:  rfeGVR,
+			obj: object("RFEWorkflow", "duplicate-gates", map[string]interface{}{
+				"title":        "Session export",
+				"description":  "Export transcripts",
+				"umbrellaRepo": map[string]interface{}{"url": "https://github.com/acme/specs"},
+				"gates": []interface{}{
+					map[string]interface{}{"phase": "plan"},
+					map[string]interface{}{"phase": "plan"},
+				},
+			}),
+			wantErr: "duplicate gate for phase \"plan\"",
+		},
+		{
+			name: "ProjectSettings with a wildcard egress host",
+			gvr:  settingsGVR,
+			obj: object("ProjectSettings", "projectsettings", map[string]interface{}{
+				"groupAccess":  []interface{}{},
+				"egressPolicy": map[string]interface{}{"mode": "Restricted", "allowedHosts": []interface{}{"*.example.com"}},
+			}),
+			wantErr: "wildcard \"*.example.com\" is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := testDynamic.Resource(tt.gvr).Namespace(testNamespace).Create(context.Background(), tt.obj, metav1.CreateOptions{})
+			if err == nil {
+				t.Fatalf("create succeeded; want it rejected with %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("create failed with %q; want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdmissionAppliesDefaults(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	session, err := testDynamic.Resource(sessionsGVR).Namespace(testNamespace).Create(ctx, object("AgenticSession", "defaults", map[string]interface{}{
+		"prompt": "fix the build",
+		"repos": []interface{}{map[string]interface{}{
+			"input":  map[string]interface{}{"url": "https://github.com/acme/app"},
+			"output": map[string]interface{}{"url": "https://github.com/acme/fork"},
+		}},
+	}), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if got, _, _ := unstructured.NestedString(session.Object, "spec", "project"); got != testNamespace {
+		t.Errorf("spec.project = %q; want %q", got, testNamespace)
+	}
+	repos, _, _ := unstructured.NestedSlice(session.Object, "spec", "repos")
+	if len(repos) != 1 {
+		t.Fatalf("spec.repos = %v; want one repo", repos)
+	}
+	if got, _, _ := unstructured.NestedString(repos[0].(map[string]interface{}), "output", "branch"); got != "sessions/defaults" {
+		t.Errorf("spec.repos[0].output.branch = %q; want %q", got, "sessions/defaults")
+	}
+
+	rfe, err := testDynamic.Resource(rfeGVR).Namespace(testNamespace).Create(ctx, object("RFEWorkflow", "defaults", map[string]interface{}{
+		"title":        "Export Session Transcripts as Markdown",
+		"description":  "Export transcripts",
+		"umbrellaRepo": map[string]interface{}{"url": "https://github.com/acme/specs"},
+	}), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create RFE workflow: %v", err)
+	}
+	if got, _, _ := unstructured.NestedString(rfe.Object, "spec", "branchName"); got != "ambient-export-session-transcripts" {
+		t.Errorf("spec.branchName = %q; want %q", got, "ambient-export-session-transcripts")
+	}
+
+	// groupAccess is required by the CRD schema, so this only gets past validation once defaulted
+	ps := object("ProjectSettings", "projectsettings", map[string]interface{}{})
+	created, err := testDynamic.Resource(settingsGVR).Namespace(testNamespace).Create(ctx, ps, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create ProjectSettings: %v", err)
+	}
+	if got, found, _ := unstructured.NestedSlice(created.Object, "spec", "groupAccess"); !found || len(got) != 0 {
+		t.Errorf("spec.groupAccess = %v (set: %v); want an empty list", got, found)
+	}
+}
+
+func TestAdmissionLimitsGateChangesToAdmins(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	// An editor may update RFE workflows but not ProjectSettings, like ambient-project-edit
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "rfe-editor", Namespace: testNamespace},
+		Rules: []rbacv1.PolicyRule{{
+			APIGroups: []string{"vteam.ambient-code"},
+			Resources: []string{"rfeworkflows"},
+			Verbs:     []string{"get", "update"},
+		}},
+	}
+	if _, err := K8sClient.RbacV1().Roles(testNamespace).Create(ctx, role, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create role: %v", err)
+	}
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "rfe-editor", Namespace: testNamespace},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: "rfe-editor"},
+		Subjects:   []rbacv1.Subject{{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: "editor"}},
+	}
+	if _, err := K8sClient.RbacV1().RoleBindings(testNamespace).Create(ctx, binding, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create role binding: %v", err)
+	}
+	editorCfg := rest.CopyConfig(testConfig)
+	editorCfg.Impersonate = rest.ImpersonationConfig{UserName: "editor"}
+	editor, err := dynamic.NewForConfig(editorCfg)
+	if err != nil {
+		t.Fatalf("create editor client: %v", err)
+	}
+
+	rfes := testDynamic.Resource(rfeGVR).Namespace(testNamespace)
+	if _, err := rfes.Create(ctx, object("RFEWorkflow", "gated", map[string]interface{}{
+		"title":        "Session export",
+		"description":  "Export transcripts",
+		"umbrellaRepo": map[string]interface{}{"url": "https://github.com/acme/specs"},
+		"gates":        []interface{}{map[string]interface{}{"phase": "plan", "requiredGroups": []interface{}{"architects"}}},
+	}), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create RFE workflow: %v", err)
+	}
+
+	// Editors can change other fields but not drop the gate
+	wf, err := editor.Resource(rfeGVR).Namespace(testNamespace).Get(ctx, "gated", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get as editor: %v", err)
+	}
+	_ = unstructured.SetNestedField(wf.Object, "Export transcripts as Markdown", "spec", "description")
+	if wf, err = editor.Resource(rfeGVR).Namespace(testNamespace).Update(ctx, wf, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("editor update without gate changes: %v", err)
+	}
+	unstructured.RemoveNestedField(wf.Object, "spec", "gates")
+	_, err = editor.Resource(rfeGVR).Namespace(testNamespace).Update(ctx, wf, metav1.UpdateOptions{})
+	if err == nil || !strings.Contains(err.Error(), "spec.gates can only be changed by project admins") {
+		t.Fatalf("editor removing gates: got %v; want it rejected", err)
+	}
+
+	// Admins can
+	wf, err = rfes.Get(ctx, "gated", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get as admin: %v", err)
+	}
+	unstructured.RemoveNestedField(wf.Object, "spec", "gates")
+	if _, err := rfes.Update(ctx, wf, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("admin removing gates: %v", err)
+	}
+}
+
+func TestAdmissionEnforcesSessionPolicy(t *testing.T) {
+	requireEnv(t)
+	ctx := context.Background()
+
+	ps := object("ProjectSettings", "projectsettings", map[string]interface{}{
+		"groupAccess": []interface{}{},
+		"sessionPolicy": map[string]interface{}{
+			"allowedModels":              []interface{}{"sonnet"},
+			"maxTimeout":                 int64(600),
+			"allowedRepositories":        []interface{}{"github.com/acme/*"},
+			"outputBranchPrefixes":       []interface{}{"ambient/"},
+			"bannedEnvironmentVariables": []interface{}{"AWS_*"},
+		},
+	})
+	ps.SetNamespace(policyNamespace)
+	if _, err := testDynamic.Resource(settingsGVR).Namespace(policyNamespace).Create(ctx, ps, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("create ProjectSettings: %v", err)
+	}
+	session := func(name string, mutate func(spec map[string]interface{})) *unstructured.Unstructured {
+		spec := map[string]interface{}{
+			"prompt":      "fix the build",
+			"timeout":     int64(300),
+			"llmSettings": map[string]interface{}{"model": "sonnet"},
+			"repos": []interface{}{map[string]interface{}{
+				"input":  map[string]interface{}{"url": "https://github.com/acme/app"},
+				"output": map[string]interface{}{"url": "https://github.com/acme/app", "branch": "ambient/fix"},
+			}},
+		}
+		if mutate != nil {
+			mutate(spec)
+		}
+		obj := object("AgenticSession", name, spec)
+		obj.SetNamespace(policyNamespace)
+		return obj
+	}
+
+	sessions := testDynamic.Resource(sessionsGVR).Namespace(policyNamespace)
+	tests := []struct {
+		name    string
+		mutate  func(spec map[string]interface{})
+		wantErr string
+	}{
+		{
+			name:    "model",
+			mutate:  func(s map[string]interface{}) { s["llmSettings"] = map[string]interface{}{"model": "opus"} },
+			wantErr: "spec.llmSettings.model \"opus\" is not allowed",
+		},
+		{
+			name:    "timeout",
+			mutate:  func(s map[string]interface{}) { s["timeout"] = int64(3600) },
+			wantErr: "spec.timeout 3600s exceeds the project maximum of 600s",
+		},
+		{
+			name:    "repository",
+			mutate:  func(s map[string]interface{}) { s["repos"] = []interface{}{repo("https://github.com/other/app")} },
+			wantErr: "repository https://github.com/other/app is not allowed",
+		},
+		{
+			name: "branch",
+			mutate: func(s map[string]interface{}) {
+				s["repos"] = []interface{}{map[string]interface{}{
+					"input":  map[string]interface{}{"url": "https://github.com/acme/app"},
+					"output": map[string]interface{}{"url": "https://github.com/acme/app", "branch": "feature/x"},
+				}}
+			},
+			wantErr: "must start with one of ambient/",
+		},
+		{
+			name: "env",
+			mutate: func(s map[string]interface{}) {
+				s["environmentVariables"] = map[string]interface{}{"AWS_SECRET_ACCESS_KEY": "x"}
+			},
+			wantErr: "spec.environmentVariables not allowed in this project: AWS_SECRET_ACCESS_KEY",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sessions.Create(ctx, session("policy-"+tt.name, tt.mutate), metav1.CreateOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("create: got %v; want it rejected with %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// A compliant session is admitted, and editing it out of policy is rejected
+	created, err := sessions.Create(ctx, session("policy-ok", nil), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("create compliant session: %v", err)
+	}
+	_ = unstructured.SetNestedField(created.Object, int64(3600), "spec", "timeout")
+	if _, err := sessions.Update(ctx, created, metav1.UpdateOptions{}); err == nil || !strings.Contains(err.Error(), "exceeds the project maximum") {
+		t.Fatalf("update: got %v; want it rejected", err)
+	}
+}
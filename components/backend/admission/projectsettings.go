@@ -0,0 +1,142 @@
+package admission
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// validateProjectSettings checks access entries, the runner secret name, references, bundles and
+// the egress policy, and that the session defaults satisfy the project's own session policy
+func validateProjectSettings(req *Request) []string {
+	var problems []string
+	s := spec(req.Object)
+
+	if name := str(s, "runnerSecretsName"); name != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			problems = append(problems, "spec.runnerSecretsName: "+msg)
+		}
+	}
+
+	envNames := map[string]bool{}
+	for i, ref := range objects(s["runnerSecretRefs"]) {
+		envName, refPath := str(ref, "envName"), str(ref, "path")
+		if envNames[envName] {
+			problems = append(problems, fmt.Sprintf("spec.runnerSecretRefs[%d]: %s is already referenced", i, envName))
+		}
+		envNames[envName] = true
+		for _, segment := range strings.Split(refPath, "/") {
+			if segment == "." || segment == ".." {
+				problems = append(problems, fmt.Sprintf("spec.runnerSecretRefs[%d].path: must stay within the project scope", i))
+				break
+			}
+		}
+	}
+
+	bundles := map[string]bool{}
+	for i, b := range objects(s["secretBundles"]) {
+		name := str(b, "name")
+		if bundles[name] {
+			problems = append(problems, fmt.Sprintf("spec.secretBundles[%d]: bundle %s is already defined", i, name))
+		}
+		bundles[name] = true
+	}
+
+	// A NetworkPolicy needs addresses, and the proxy sidecar shares the runner's network, so a
+	// wildcard could only be allowed by opening ports 80 and 443 to every address
+	egress, _ := s["egressPolicy"].(map[string]interface{})
+	for i, cidr := range stringItems(egress["allowedCIDRs"]) {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			problems = append(problems, fmt.Sprintf("spec.egressPolicy.allowedCIDRs[%d]: invalid CIDR %q", i, cidr))
+		}
+	}
+	for i, host := range stringItems(egress["allowedHosts"]) {
+		if strings.HasPrefix(host, "*.") {
+			problems = append(problems, fmt.Sprintf("spec.egressPolicy.allowedHosts[%d]: wildcard %q is not supported; list each hostname", i, host))
+		}
+	}
+
+	// The same subject and role declared twice maps to one RoleBinding; flag the copy
+	seen := map[string]bool{}
+	checkSubject := func(field string, i int, subject, role string) {
+		if subject == "" {
+			problems = append(problems, fmt.Sprintf("spec.%s[%d]: subject name must not be empty", field, i))
+			return
+		}
+		key := field + "/" + subject + "/" + strings.ToLower(role)
+		if seen[key] {
+			problems = append(problems, fmt.Sprintf("spec.%s[%d]: %s is already granted %s", field, i, subject, role))
+		}
+		seen[key] = true
+	}
+	for i, e := range objects(s["groupAccess"]) {
+		checkSubject("groupAccess", i, str(e, "groupName"), str(e, "role"))
+	}
+	for i, e := range objects(s["userAccess"]) {
+		checkSubject("userAccess", i, str(e, "userName"), str(e, "role"))
+	}
+	for i, e := range objects(s["serviceAccountAccess"]) {
+		name, ns := str(e, "name"), str(e, "namespace")
+		for _, msg := range validation.IsDNS1123Subdomain(name) {
+			problems = append(problems, fmt.Sprintf("spec.serviceAccountAccess[%d].name: %s", i, msg))
+		}
+		if ns != "" {
+			for _, msg := range validation.IsDNS1123Label(ns) {
+				problems = append(problems, fmt.Sprintf("spec.serviceAccountAccess[%d].namespace: %s", i, msg))
+			}
+		} else {
+			ns = req.Namespace
+		}
+		checkSubject("serviceAccountAccess", i, ns+"/"+name, str(e, "role"))
+	}
+
+	policy, _ := s["sessionPolicy"].(map[string]interface{})
+	for _, field := range []string{"allowedRepositories", "bannedEnvironmentVariables"} {
+		for i, pattern := range stringItems(policy[field]) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("spec.sessionPolicy.%s[%d]: invalid pattern %q", field, i, pattern))
+			}
+		}
+	}
+
+	defaults, _ := s["sessionDefaults"].(map[string]interface{})
+	llm, _ := defaults["llmSettings"].(map[string]interface{})
+	if model := str(llm, "model"); model != "" {
+		if allowedModels := stringItems(policy["allowedModels"]); len(allowedModels) > 0 && !contains(allowedModels, model) {
+			problems = append(problems, fmt.Sprintf("spec.sessionDefaults.llmSettings.model %q is not in spec.sessionPolicy.allowedModels", model))
+		}
+	}
+	selection, _ := defaults["secrets"].(map[string]interface{})
+	for i, b := range stringItems(selection["bundles"]) {
+		if !bundles[b] {
+			problems = append(problems, fmt.Sprintf("spec.sessionDefaults.secrets.bundles[%d]: bundle %q is not defined in spec.secretBundles", i, b))
+		}
+	}
+	if timeout, ok := number(defaults, "timeout"); ok {
+		if maxTimeout, ok := number(policy, "maxTimeout"); ok && timeout > maxTimeout {
+			problems = append(problems, fmt.Sprintf("spec.sessionDefaults.timeout %d exceeds spec.sessionPolicy.maxTimeout %d", int(timeout), int(maxTimeout)))
+		}
+	}
+	return problems
+}
+
+// defaultProjectSettings sets the required spec.groupAccess so settings that only grant users
+// or ServiceAccounts can be applied as-is
+func defaultProjectSettings(req *Request) {
+	s := spec(req.Object)
+	if _, ok := s["groupAccess"]; !ok {
+		s["groupAccess"] = []interface{}{}
+	}
+}
+
+func contains(items []string, v string) bool {
+	for _, item := range items {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"ambient-code-backend/git"
+
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+// validateRFEWorkflow mirrors the checks CreateProjectRFEWorkflow applies to requests
+func validateRFEWorkflow(req *Request) []string {
+	var problems []string
+	s := spec(req.Object)
+
+	if str(s, "title") == "" {
+		problems = append(problems, "spec.title must not be empty")
+	}
+	if err := git.ValidateBranchName(str(s, "branchName")); err != nil {
+		problems = append(problems, "spec.branchName: "+err.Error())
+	}
+
+	umbrella, _ := s["umbrellaRepo"].(map[string]interface{})
+	urls := []string{str(umbrella, "url")}
+	if err := validateRepoURL(urls[0]); err != nil {
+		problems = append(problems, "spec.umbrellaRepo.url: "+err.Error())
+	}
+	for i, r := range objects(s["supportingRepos"]) {
+		u := str(r, "url")
+		if err := validateRepoURL(u); err != nil {
+			problems = append(problems, fmt.Sprintf("spec.supportingRepos[%d].url: %v", i, err))
+		}
+		urls = append(urls, u)
+	}
+	if err := git.ValidateUniqueRepoURLs(urls); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	seen := map[string]bool{}
+	for i, g := range objects(s["gates"]) {
+		phase := str(g, "phase")
+		if phase != "" && seen[phase] {
+			problems = append(problems, fmt.Sprintf("spec.gates[%d]: duplicate gate for phase %q", i, phase))
+		}
+		seen[phase] = true
+		for _, grp := range stringItems(g["requiredGroups"]) {
+			if strings.TrimSpace(grp) == "" {
+				problems = append(problems, fmt.Sprintf("spec.gates[%d]: empty reviewer group", i))
+				break
+			}
+		}
+	}
+
+	// Gates decide who must approve each phase, so editors must not be able to drop or
+	// rewrite them; only project admins may change them once the workflow exists
+	if req.Operation == admissionv1.Update && req.OldObject != nil && gatesChanged(spec(req.OldObject), s) {
+		admin, err := isProjectAdmin(req.User, req.Namespace)
+		if err != nil {
+			log.Printf("admission: gate change check for %s in %s failed: %v", req.User.Username, req.Namespace, err)
+		}
+		if !admin {
+			problems = append(problems, "spec.gates can only be changed by project admins")
+		}
+	}
+	return problems
+}
+
+// gatesChanged compares spec.gates of two specs; unset and empty are the same
+func gatesChanged(old, cur map[string]interface{}) bool {
+	if len(objects(old["gates"])) == 0 && len(objects(cur["gates"])) == 0 {
+		return false
+	}
+	a, _ := json.Marshal(old["gates"])
+	b, _ := json.Marshal(cur["gates"])
+	return string(a) != string(b)
+}
+
+var branchWordSplit = regexp.MustCompile(`[^a-z0-9]+`)
+
+// defaultRFEWorkflow derives spec.branchName from the title the way the UI does
+// (ambient-<first-three-words>) when it's unset
+func defaultRFEWorkflow(req *Request) {
+	s := spec(req.Object)
+	if str(s, "branchName") != "" {
+		return
+	}
+	var words []string
+	for _, w := range branchWordSplit.Split(strings.ToLower(str(s, "title")), -1) {
+		if w != "" {
+			words = append(words, w)
+		}
+		if len(words) == 3 {
+			break
+		}
+	}
+	if len(words) > 0 {
+		s["branchName"] = "ambient-" + strings.Join(words, "-")
+	}
+}
@@ -0,0 +1,154 @@
+package admission
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"ambient-code-backend/git"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// DynamicClient reads the namespace's ProjectSettings so sessions created or edited with kubectl
+// meet the same spec.sessionPolicy guardrails the backend enforces. It is set by main in
+// ADMISSION_WEBHOOK_MODE; when nil AgenticSessions are denied.
+var DynamicClient dynamic.Interface
+
+var projectSettingsGVR = schema.GroupVersionResource{Group: "vteam.ambient-code", Version: "v1alpha1", Resource: "projectsettings"}
+
+// backendSessionEnv are set on sessions by the backend itself and are not subject to
+// spec.sessionPolicy.bannedEnvironmentVariables, as in the backend's own check
+var backendSessionEnv = []string{"AGENT_PERSONAS", "PARENT_SESSION_ID"}
+
+// loadProjectSettingsSpec returns the namespace's ProjectSettings spec, or nil when there is none
+func loadProjectSettingsSpec(namespace string) (map[string]interface{}, error) {
+	if DynamicClient == nil {
+		return nil, fmt.Errorf("webhook has no Kubernetes client")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), accessReviewTimeout)
+	defer cancel()
+	obj, err := DynamicClient.Resource(projectSettingsGVR).Namespace(namespace).Get(ctx, "projectsettings", metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read ProjectSettings: %w", err)
+	}
+	s, _ := obj.Object["spec"].(map[string]interface{})
+	return s, nil
+}
+
+// validateSessionPolicy mirrors the backend's session policy check: allowed models, the maximum
+// timeout, secret bundles, banned environment variables, allowed repositories and output branch
+// prefixes from the namespace's ProjectSettings
+func validateSessionPolicy(req *Request, s map[string]interface{}) []string {
+	settings, err := loadProjectSettingsSpec(req.Namespace)
+	if err != nil {
+		return []string{fmt.Sprintf("the project's session policy could not be checked: %v", err)}
+	}
+	if settings == nil {
+		return nil
+	}
+	policy, _ := settings["sessionPolicy"].(map[string]interface{})
+	defaults, _ := settings["sessionDefaults"].(map[string]interface{})
+	var problems []string
+
+	llm, _ := s["llmSettings"].(map[string]interface{})
+	if model := str(llm, "model"); model != "" {
+		if allowed := stringItems(policy["allowedModels"]); len(allowed) > 0 && !contains(allowed, model) {
+			problems = append(problems, fmt.Sprintf("spec.llmSettings.model %q is not allowed in this project (allowed: %s)", model, strings.Join(allowed, ", ")))
+		}
+	}
+	if timeout, ok := number(s, "timeout"); ok {
+		// Objects read through the API decode integers as int64, unlike the review's JSON
+		if maxTimeout, ok, _ := unstructured.NestedNumberAsFloat64(policy, "maxTimeout"); ok && maxTimeout > 0 && timeout > maxTimeout {
+			problems = append(problems, fmt.Sprintf("spec.timeout %ds exceeds the project maximum of %ds", int(timeout), int(maxTimeout)))
+		}
+	}
+
+	bundles := map[string]bool{}
+	for _, b := range objects(settings["secretBundles"]) {
+		bundles[str(b, "name")] = true
+	}
+	selection, _ := s["secrets"].(map[string]interface{})
+	for i, b := range stringItems(selection["bundles"]) {
+		if b = strings.TrimSpace(b); b != "" && !bundles[b] {
+			problems = append(problems, fmt.Sprintf("spec.secrets.bundles[%d]: bundle %q is not defined in this project", i, b))
+		}
+	}
+
+	// Project default variables are the admin's own choice and always allowed
+	defaultEnv, _ := defaults["environmentVariables"].(map[string]interface{})
+	env, _ := s["environmentVariables"].(map[string]interface{})
+	var banned []string
+	for k := range env {
+		if _, ok := defaultEnv[k]; ok || contains(backendSessionEnv, k) {
+			continue
+		}
+		for _, pattern := range stringItems(policy["bannedEnvironmentVariables"]) {
+			if ok, _ := path.Match(pattern, k); ok || pattern == k {
+				banned = append(banned, k)
+				break
+			}
+		}
+	}
+	if len(banned) > 0 {
+		sort.Strings(banned)
+		problems = append(problems, "spec.environmentVariables not allowed in this project: "+strings.Join(banned, ", "))
+	}
+
+	allowedRepos := stringItems(policy["allowedRepositories"])
+	prefixes := stringItems(policy["outputBranchPrefixes"])
+	for i, r := range objects(s["repos"]) {
+		in, _ := r["input"].(map[string]interface{})
+		out, _ := r["output"].(map[string]interface{})
+		for _, field := range []string{"input", "output"} {
+			ref := in
+			if field == "output" {
+				ref = out
+			}
+			if u := str(ref, "url"); u != "" && !repoAllowed(allowedRepos, u) {
+				problems = append(problems, fmt.Sprintf("spec.repos[%d].%s.url: repository %s is not allowed in this project (allowed: %s)", i, field, u, strings.Join(allowedRepos, ", ")))
+			}
+		}
+		if str(out, "url") == "" || len(prefixes) == 0 {
+			continue
+		}
+		// Output mappings without a branch push to sessions/<name>, as defaulted on create
+		branch := str(out, "branch")
+		if branch == "" {
+			branch = "sessions/"
+		}
+		ok := false
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(branch, prefix) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			problems = append(problems, fmt.Sprintf("spec.repos[%d].output.branch %q must start with one of %s", i, str(out, "branch"), strings.Join(prefixes, ", ")))
+		}
+	}
+	return problems
+}
+
+func repoAllowed(patterns []string, u string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	repo := git.RepoPath(u)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(git.RepoPath(pattern), repo); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+// Package audit records mutating API actions per project and delivers them to pluggable sinks.
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Actor types
+const (
+	ActorUser      = "user"
+	ActorAccessKey = "access-key"
+	// ActorServiceAccount is any other ServiceAccount token (runners, automation)
+	ActorServiceAccount = "service-account"
+	ActorAnonymous      = "anonymous"
+)
+
+// Outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	// OutcomeDenied is an unauthenticated or forbidden attempt
+	OutcomeDenied = "denied"
+)
+
+// Actor is who performed an action
+type Actor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// Target is the resource an action applied to
+type Target struct {
+	Kind string `json:"kind"`
+	Name string `json:"name,omitempty"`
+}
+
+// Event is one audited action
+type Event struct {
+	ID        string            `json:"id"`
+	Time      time.Time         `json:"time"`
+	Project   string            `json:"project,omitempty"`
+	RequestID string            `json:"requestId,omitempty"`
+	Actor     Actor             `json:"actor"`
+	Action    string            `json:"action"`
+	Target    Target            `json:"target"`
+	Method    string            `json:"method,omitempty"`
+	Path      string            `json:"path,omitempty"`
+	Status    int               `json:"status,omitempty"`
+	Outcome   string            `json:"outcome"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// Sink receives audit events
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, e Event) error
+}
+
+// Reader is implemented by sinks that can be queried
+type Reader interface {
+	Query(ctx context.Context, f Filter) ([]Event, error)
+}
+
+// Filter selects events in Query. Empty fields match everything.
+type Filter struct {
+	Project    string
+	Actor      string // matches actor id or name
+	Action     string // exact action or a prefix ending at a dot ("permission" matches "permission.create")
+	TargetKind string
+	Target     string
+	Outcome    string
+	RequestID  string
+	Since      time.Time
+	Until      time.Time
+	Limit      int // newest events are kept; <= 0 uses DefaultQueryLimit
+}
+
+// Query limits
+const (
+	DefaultQueryLimit = 100
+	MaxQueryLimit     = 1000
+)
+
+// ErrNotQueryable is returned by Query when no configured sink supports reading
+var ErrNotQueryable = errors.New("no queryable audit sink configured")
+
+// Matches reports whether e satisfies the filter
+func (f Filter) Matches(e Event) bool {
+	if f.Project != "" && e.Project != f.Project {
+		return false
+	}
+	if f.Actor != "" && e.Actor.ID != f.Actor && e.Actor.Name != f.Actor {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action && !strings.HasPrefix(e.Action, f.Action+".") {
+		return false
+	}
+	if f.TargetKind != "" && e.Target.Kind != f.TargetKind {
+		return false
+	}
+	if f.Target != "" && e.Target.Name != f.Target {
+		return false
+	}
+	if f.Outcome != "" && e.Outcome != f.Outcome {
+		return false
+	}
+	if f.RequestID != "" && e.RequestID != f.RequestID {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+func (f Filter) limit() int {
+	if f.Limit <= 0 {
+		return DefaultQueryLimit
+	}
+	if f.Limit > MaxQueryLimit {
+		return MaxQueryLimit
+	}
+	return f.Limit
+}
+
+// OutcomeForStatus maps an HTTP response status to an outcome
+func OutcomeForStatus(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return OutcomeDenied
+	case status >= 400:
+		return OutcomeFailure
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// NewID returns a random identifier for events and requests
+func NewID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000")
+	}
+	return hex.EncodeToString(b)
+}
+
+// Logger fans events out to its sinks and answers queries from the first queryable one
+type Logger struct {
+	sinks  []Sink
+	reader Reader
+	queue  chan Event
+}
+
+// recordQueueSize bounds events waiting to be written to the sinks
+const recordQueueSize = 1024
+
+// sinkWriteTimeout bounds a single sink write; it is independent of the request that produced the event
+const sinkWriteTimeout = 15 * time.Second
+
+// New creates a Logger writing to sinks from a background goroutine
+func New(sinks ...Sink) *Logger {
+	l := &Logger{sinks: sinks, queue: make(chan Event, recordQueueSize)}
+	for _, s := range sinks {
+		if r, ok := s.(Reader); ok {
+			l.reader = r
+			break
+		}
+	}
+	go l.run()
+	return l
+}
+
+// Record fills in the event id and time and queues it for the sinks. It never blocks the request
+// it describes: sinks write with their own context after the request has finished, failures are
+// logged, and events are dropped (and logged) when the queue is full.
+func (l *Logger) Record(_ context.Context, e Event) {
+	if l == nil {
+		return
+	}
+	if e.ID == "" {
+		e.ID = NewID()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	select {
+	case l.queue <- e:
+	default:
+		log.Printf("audit: queue full, dropped %s %s (request %s)", e.Action, e.ID, e.RequestID)
+	}
+}
+
+func (l *Logger) run() {
+	for e := range l.queue {
+		for _, s := range l.sinks {
+			ctx, cancel := context.WithTimeout(context.Background(), sinkWriteTimeout)
+			if err := s.Write(ctx, e); err != nil {
+				log.Printf("audit: sink %s failed for %s %s (request %s): %v", s.Name(), e.Action, e.ID, e.RequestID, err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Query returns matching events, newest first
+func (l *Logger) Query(ctx context.Context, f Filter) ([]Event, error) {
+	if l == nil || l.reader == nil {
+		return nil, ErrNotQueryable
+	}
+	return l.reader.Query(ctx, f)
+}
+
+// Sinks returns the names of the configured sinks
+func (l *Logger) Sinks() []string {
+	if l == nil {
+		return nil
+	}
+	names := make([]string, 0, len(l.sinks))
+	for _, s := range l.sinks {
+		names = append(names, s.Name())
+	}
+	return names
+}
@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// asyncQueueSize bounds events waiting for a slow remote sink
+const asyncQueueSize = 1024
+
+// asyncSink delivers to a remote sink from its own goroutine so a slow API server or webhook
+// doesn't hold up the Logger's other sinks. Events are dropped (and logged) when the queue is full.
+type asyncSink struct {
+	sink  Sink
+	queue chan Event
+}
+
+// Async wraps sink with a bounded background queue
+func Async(sink Sink) Sink {
+	a := &asyncSink{sink: sink, queue: make(chan Event, asyncQueueSize)}
+	go a.run()
+	return a
+}
+
+func (a *asyncSink) Name() string { return a.sink.Name() }
+
+func (a *asyncSink) Write(_ context.Context, e Event) error {
+	select {
+	case a.queue <- e:
+		return nil
+	default:
+		return fmt.Errorf("queue full, dropped event")
+	}
+}
+
+func (a *asyncSink) run() {
+	for e := range a.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), sinkWriteTimeout)
+		if err := a.sink.Write(ctx, e); err != nil {
+			log.Printf("audit: sink %s failed for %s %s (request %s): %v", a.sink.Name(), e.Action, e.ID, e.RequestID, err)
+		}
+		cancel()
+	}
+}
+
+// NewFromEnv builds a Logger from the environment:
+//
+//	AUDIT_SINKS          comma-separated: file, events, webhook (default "file"; "none" disables)
+//	AUDIT_DIR            directory of the file sink (default <stateBaseDir>/audit)
+//	AUDIT_MAX_FILE_BYTES size at which a project's log rotates (default 50MiB)
+//	AUDIT_WEBHOOK_URL    receiver for the webhook sink
+//	AUDIT_WEBHOOK_SECRET optional HMAC-SHA256 signing secret for the webhook sink
+func NewFromEnv(client kubernetes.Interface, namespace, stateBaseDir string) (*Logger, error) {
+	raw := strings.TrimSpace(os.Getenv("AUDIT_SINKS"))
+	if raw == "" {
+		raw = "file"
+	}
+	var sinks []Sink
+	for _, name := range strings.Split(raw, ",") {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "", "none":
+		case "file":
+			dir := strings.TrimSpace(os.Getenv("AUDIT_DIR"))
+			if dir == "" {
+				dir = filepath.Join(stateBaseDir, "audit")
+			}
+			var maxBytes int64
+			if v := strings.TrimSpace(os.Getenv("AUDIT_MAX_FILE_BYTES")); v != "" {
+				n, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid AUDIT_MAX_FILE_BYTES %q: %w", v, err)
+				}
+				maxBytes = n
+			}
+			fs, err := NewFileSink(dir, maxBytes)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, fs)
+		case "events":
+			if client == nil {
+				return nil, fmt.Errorf("events audit sink requires a Kubernetes client")
+			}
+			sinks = append(sinks, Async(NewEventSink(client, namespace)))
+		case "webhook":
+			url := strings.TrimSpace(os.Getenv("AUDIT_WEBHOOK_URL"))
+			if url == "" {
+				return nil, fmt.Errorf("webhook audit sink requires AUDIT_WEBHOOK_URL")
+			}
+			sinks = append(sinks, Async(NewWebhookSink(url, os.Getenv("AUDIT_WEBHOOK_SECRET"))))
+		default:
+			return nil, fmt.Errorf("unknown audit sink %q (expected file, events or webhook)", name)
+		}
+	}
+	return New(sinks...), nil
+}
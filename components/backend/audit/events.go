@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EventSink emits each audit event as a Kubernetes Event on the project's Namespace, so
+// `kubectl get events` in the project shows who did what. Events without a project go to
+// fallbackNamespace (the backend's own).
+type EventSink struct {
+	client            kubernetes.Interface
+	fallbackNamespace string
+}
+
+// NewEventSink creates an EventSink using the backend service account client
+func NewEventSink(client kubernetes.Interface, fallbackNamespace string) *EventSink {
+	return &EventSink{client: client, fallbackNamespace: fallbackNamespace}
+}
+
+func (s *EventSink) Name() string { return "events" }
+
+func (s *EventSink) Write(ctx context.Context, e Event) error {
+	ns := e.Project
+	if ns == "" {
+		ns = s.fallbackNamespace
+	}
+	if ns == "" {
+		return nil
+	}
+	eventType := corev1.EventTypeNormal
+	if e.Outcome != OutcomeSuccess {
+		eventType = corev1.EventTypeWarning
+	}
+	target := e.Target.Kind
+	if e.Target.Name != "" {
+		target += "/" + e.Target.Name
+	}
+	actor := e.Actor.Name
+	if actor == "" {
+		actor = e.Actor.ID
+	}
+	ev := &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName: "audit-",
+			Namespace:    ns,
+			Labels:       map[string]string{"app": "ambient-audit"},
+			Annotations: map[string]string{
+				"ambient-code.io/audit-id":   e.ID,
+				"ambient-code.io/request-id": e.RequestID,
+				"ambient-code.io/actor":      e.Actor.ID,
+			},
+		},
+		InvolvedObject: corev1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: ns},
+		Reason:         eventReason(e.Action),
+		Message:        fmt.Sprintf("%s %s %s %s: %s (HTTP %d, request %s)", e.Actor.Type, actor, e.Action, target, e.Outcome, e.Status, e.RequestID),
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: "ambient-backend-audit"},
+		FirstTimestamp: v1.NewTime(e.Time),
+		LastTimestamp:  v1.NewTime(e.Time),
+		Count:          1,
+	}
+	if _, err := s.client.CoreV1().Events(ns).Create(ctx, ev, v1.CreateOptions{}); err != nil {
+		return fmt.Errorf("create event in %s: %w", ns, err)
+	}
+	return nil
+}
+
+// eventReason turns "permission.create" into "AuditPermissionCreate"
+func eventReason(action string) string {
+	var b strings.Builder
+	b.WriteString("Audit")
+	for _, part := range strings.FieldsFunc(action, func(r rune) bool { return r == '.' || r == '-' || r == '_' || r == ' ' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// clusterLogName holds events without a project (project creation). The leading underscore
+// keeps it from colliding with a namespace name.
+const clusterLogName = "_cluster"
+
+// DefaultMaxFileBytes is the size at which a project's log is rotated
+const DefaultMaxFileBytes = 50 << 20
+
+var projectNameRe = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// FileSink appends events as JSON lines to <dir>/<project>.jsonl. When a log exceeds maxBytes
+// it is renamed to <project>.jsonl.1 (replacing the previous generation) and a new one started.
+type FileSink struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// NewFileSink creates dir if needed
+func NewFileSink(dir string, maxBytes int64) (*FileSink, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxFileBytes
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create audit dir: %w", err)
+	}
+	return &FileSink{dir: dir, maxBytes: maxBytes}, nil
+}
+
+func (s *FileSink) Name() string { return "file" }
+
+func (s *FileSink) path(project string) (string, error) {
+	if project == "" {
+		return filepath.Join(s.dir, clusterLogName+".jsonl"), nil
+	}
+	if !projectNameRe.MatchString(project) {
+		return "", fmt.Errorf("invalid project name %q", project)
+	}
+	return filepath.Join(s.dir, project+".jsonl"), nil
+}
+
+func (s *FileSink) Write(_ context.Context, e Event) error {
+	p, err := s.path(e.Project)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fi, err := os.Stat(p); err == nil && fi.Size()+int64(len(line)) > s.maxBytes {
+		if err := os.Rename(p, p+".1"); err != nil {
+			return fmt.Errorf("rotate %s: %w", p, err)
+		}
+	}
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", p, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("write %s: %w", p, err)
+	}
+	return nil
+}
+
+// Query scans the project's rotated and current logs, keeping the newest matches
+func (s *FileSink) Query(_ context.Context, f Filter) ([]Event, error) {
+	p, err := s.path(f.Project)
+	if err != nil {
+		return nil, err
+	}
+	limit := f.limit()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []Event
+	for _, name := range []string{p + ".1", p} {
+		if err := scanEvents(name, func(e Event) {
+			if !f.Matches(e) {
+				return
+			}
+			matched = append(matched, e)
+			if len(matched) > 2*limit {
+				matched = append(matched[:0], matched[len(matched)-limit:]...)
+			}
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if len(matched) > limit {
+		matched = matched[len(matched)-limit:]
+	}
+	// newest first
+	for i, j := 0, len(matched)-1; i < j; i, j = i+1, j-1 {
+		matched[i], matched[j] = matched[j], matched[i]
+	}
+	return matched, nil
+}
+
+func scanEvents(name string, fn func(Event)) error {
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open %s: %w", name, err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		var e Event
+		// Skip lines torn by a crash mid-write rather than failing the whole query
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			continue
+		}
+		fn(e)
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+	return nil
+}
@@ -28,12 +28,26 @@ func RFEWorkflowToCRObject(workflow *types.RFEWorkflow) map[string]interface{} {
 		"branchName":    workflow.BranchName,
 		"workspacePath": workflow.WorkspacePath,
 	}
-	if len(workflow.JiraLinks) > 0 {
-		links := make([]map[string]interface{}, 0, len(workflow.JiraLinks))
-		for _, l := range workflow.JiraLinks {
-			links = append(links, map[string]interface{}{"path": l.Path, "jiraKey": l.JiraKey})
+	if len(workflow.TrackerLinks) > 0 {
+		links := make([]map[string]interface{}, 0, len(workflow.TrackerLinks))
+		for _, l := range workflow.TrackerLinks {
+			m := map[string]interface{}{"path": l.Path, "tracker": l.Tracker, "key": l.Key}
+			if l.URL != "" {
+				m["url"] = l.URL
+			}
+			if len(l.Tasks) > 0 {
+				tasks := make(map[string]interface{}, len(l.Tasks))
+				for id, key := range l.Tasks {
+					tasks[id] = key
+				}
+				m["tasks"] = tasks
+			}
+			links = append(links, m)
 		}
-		spec["jiraLinks"] = links
+		spec["trackerLinks"] = links
+	}
+	if len(workflow.Gates) > 0 {
+		spec["gates"] = gatesToCR(workflow.Gates)
 	}
 	if workflow.ParentOutcome != nil && *workflow.ParentOutcome != "" {
 		spec["parentOutcome"] = *workflow.ParentOutcome
@@ -76,6 +90,26 @@ func RFEWorkflowToCRObject(workflow *types.RFEWorkflow) map[string]interface{} {
 	}
 }
 
+// gatesToCR converts phase gates to their spec.gates representation
+func gatesToCR(gates []types.PhaseGate) []interface{} {
+	out := make([]interface{}, 0, len(gates))
+	for _, g := range gates {
+		m := map[string]interface{}{"phase": g.Phase}
+		if len(g.RequiredGroups) > 0 {
+			groups := make([]interface{}, 0, len(g.RequiredGroups))
+			for _, grp := range g.RequiredGroups {
+				groups = append(groups, grp)
+			}
+			m["requiredGroups"] = groups
+		}
+		if g.MinApprovals > 0 {
+			m["minApprovals"] = int64(g.MinApprovals)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
 // UpsertProjectRFEWorkflowCR creates or updates an RFEWorkflow custom resource
 func UpsertProjectRFEWorkflowCR(dyn dynamic.Interface, workflow *types.RFEWorkflow) error {
 	if workflow.Project == "" {
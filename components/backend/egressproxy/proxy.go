@@ -0,0 +1,183 @@
+// Package egressproxy is the forward proxy run as a sidecar of session Jobs in projects that
+// restrict egress. HTTPS (CONNECT) tunnels and plain HTTP requests are only forwarded to hosts
+// on the project's allow-list, so hostnames are checked even where the NetworkPolicy can only
+// allow their addresses.
+package egressproxy
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Allowlist decides which destination hosts the proxy forwards to
+type Allowlist struct {
+	hosts map[string]bool
+	cidrs []*net.IPNet
+}
+
+// ParseAllowlist builds an Allowlist from comma-separated exact hostnames and CIDRs
+func ParseAllowlist(hosts, cidrs string) (*Allowlist, error) {
+	a := &Allowlist{hosts: map[string]bool{}}
+	for _, h := range strings.Split(hosts, ",") {
+		h = strings.ToLower(strings.TrimSpace(h))
+		switch {
+		case h == "":
+		case strings.HasPrefix(h, "*."):
+			return nil, fmt.Errorf("wildcard host %q is not supported", h)
+		default:
+			a.hosts[h] = true
+		}
+	}
+	for _, c := range strings.Split(cidrs, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", c, err)
+		}
+		a.cidrs = append(a.cidrs, n)
+	}
+	return a, nil
+}
+
+// Allows reports whether host (without port) may be reached
+func (a *Allowlist) Allows(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if a.hosts[host] {
+		return true
+	}
+	if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+		for _, n := range a.cidrs {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NewHandler returns the proxy handler enforcing allow
+func NewHandler(allow *Allowlist) http.Handler {
+	return &proxy{
+		allow: allow,
+		transport: &http.Transport{
+			// Never chain to another proxy from the environment
+			Proxy:                 nil,
+			DialContext:           (&net.Dialer{Timeout: 30 * time.Second}).DialContext,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 5 * time.Minute,
+		},
+	}
+}
+
+type proxy struct {
+	allow     *Allowlist
+	transport *http.Transport
+}
+
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "egress proxy only accepts proxy requests", http.StatusBadRequest)
+		return
+	}
+	if !p.allow.Allows(r.URL.Hostname()) {
+		log.Printf("egressproxy: denied %s %s", r.Method, r.URL.Host)
+		http.Error(w, fmt.Sprintf("egress to %s is not allowed by the project's egress policy", r.URL.Hostname()), http.StatusForbidden)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	out.Header.Del("Proxy-Connection")
+	out.Header.Del("Proxy-Authorization")
+	resp, err := p.transport.RoundTrip(out)
+	if err != nil {
+		log.Printf("egressproxy: %s %s: %v", r.Method, r.URL.Host, err)
+		http.Error(w, "upstream request failed", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel serves CONNECT by splicing the client connection to the allowed destination
+func (p *proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		http.Error(w, "CONNECT requires host:port", http.StatusBadRequest)
+		return
+	}
+	if !p.allow.Allows(host) {
+		log.Printf("egressproxy: denied CONNECT %s", r.Host)
+		http.Error(w, fmt.Sprintf("egress to %s is not allowed by the project's egress policy", host), http.StatusForbidden)
+		return
+	}
+	upstream, err := net.DialTimeout("tcp", r.Host, 30*time.Second)
+	if err != nil {
+		log.Printf("egressproxy: CONNECT %s: %v", r.Host, err)
+		http.Error(w, "upstream connection failed", http.StatusBadGateway)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buf, err := hj.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		// Bytes the client sent after the CONNECT request are already buffered
+		if n := buf.Reader.Buffered(); n > 0 {
+			b, _ := buf.Reader.Peek(n)
+			_, _ = upstream.Write(b)
+		}
+		_, _ = io.Copy(upstream, client)
+		closeWrite(upstream)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(client, upstream)
+		closeWrite(client)
+	}()
+	wg.Wait()
+	client.Close()
+	upstream.Close()
+}
+
+func closeWrite(c net.Conn) {
+	if tc, ok := c.(*net.TCPConn); ok {
+		_ = tc.CloseWrite()
+	}
+}
@@ -1,13 +1,11 @@
 package git
 
 import (
-	"archive/zip"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"io/fs"
 	"log"
 	"net/http"
 	"net/url"
@@ -26,9 +24,10 @@ import (
 
 // Package-level dependencies (set from main package)
 var (
-	GetProjectSettingsResource func() schema.GroupVersionResource
-	GetGitHubInstallation      func(context.Context, string) (interface{}, error)
-	GitHubTokenManager         interface{} // *GitHubTokenManager from main package
+	GetProjectSettingsResource   func() schema.GroupVersionResource
+	GetGitHubInstallation        func(context.Context, string) (interface{}, error)
+	GetProjectGitHubInstallation func(context.Context, string) (interface{}, error)
+	GitHubTokenManager           interface{} // *GitHubTokenManager from main package
 )
 
 // ProjectSettings represents the project configuration
@@ -75,35 +74,55 @@ func getProjectSettings(ctx context.Context, dynClient dynamic.Interface, projec
 	return settings, nil
 }
 
-// GetGitHubToken tries to get a GitHub token from GitHub App first, then falls back to project runner secret
+// GetGitHubToken tries the user's GitHub App installation, then the project's installation,
+// and finally falls back to the project runner secret
 func GetGitHubToken(ctx context.Context, k8sClient *kubernetes.Clientset, dynClient dynamic.Interface, project, userID string) (string, error) {
-	// Try GitHub App first if available
+	return GetScopedGitHubToken(ctx, k8sClient, dynClient, project, userID, nil, nil)
+}
+
+// GetScopedGitHubToken is GetGitHubToken with GitHub App tokens restricted to the given
+// owner/name repositories and permissions. The runner secret GIT_TOKEN fallback cannot be
+// scoped, so a scoped request fails instead of falling back when an installation exists but
+// could not mint the token.
+func GetScopedGitHubToken(ctx context.Context, k8sClient *kubernetes.Clientset, dynClient dynamic.Interface, project, userID string, repositories []string, permissions map[string]string) (string, error) {
+	scoped := len(repositories) > 0 || len(permissions) > 0
+	var mintErr error
+
+	// Try the user's GitHub App installation first
 	if GetGitHubInstallation != nil && GitHubTokenManager != nil {
 		installation, err := GetGitHubInstallation(ctx, userID)
 		if err == nil && installation != nil {
-			// Use reflection-like approach to call MintInstallationTokenForHost
-			// This requires the caller to set up the proper interface/struct
-			type githubInstallation interface {
-				GetInstallationID() int64
-				GetHost() string
-			}
-			type tokenManager interface {
-				MintInstallationTokenForHost(context.Context, int64, string) (string, time.Time, error)
+			token, err := mintInstallationToken(ctx, installation, repositories, permissions)
+			if err == nil {
+				log.Printf("Using GitHub App token for user %s", userID)
+				return token, nil
 			}
+			log.Printf("Failed to mint GitHub App token for user %s: %v", userID, err)
+			mintErr = err
+		}
+	}
 
-			if inst, ok := installation.(githubInstallation); ok {
-				if mgr, ok := GitHubTokenManager.(tokenManager); ok {
-					token, _, err := mgr.MintInstallationTokenForHost(ctx, inst.GetInstallationID(), inst.GetHost())
-					if err == nil && token != "" {
-						log.Printf("Using GitHub App token for user %s", userID)
-						return token, nil
-					}
-					log.Printf("Failed to mint GitHub App token for user %s: %v", userID, err)
-				}
+	// Project-level installation (usable by bot accounts and webhook-triggered sessions)
+	if GetProjectGitHubInstallation != nil && GitHubTokenManager != nil && project != "" {
+		installation, err := GetProjectGitHubInstallation(ctx, project)
+		if err == nil && installation != nil {
+			token, err := mintInstallationToken(ctx, installation, repositories, permissions)
+			if err == nil {
+				log.Printf("Using project GitHub App token for %s (user %s)", project, userID)
+				return token, nil
 			}
+			log.Printf("Failed to mint project GitHub App token for %s: %v", project, err)
+			mintErr = err
 		}
 	}
 
+	if scoped {
+		if mintErr != nil {
+			return "", fmt.Errorf("failed to mint a repository-scoped GitHub token: %w", mintErr)
+		}
+		log.Printf("No GitHub App installation for %s/%s; runner secret GIT_TOKEN is not repository-scoped", project, userID)
+	}
+
 	// Fall back to project runner secret GIT_TOKEN
 	if k8sClient == nil {
 		log.Printf("Cannot read runner secret: k8s client is nil")
@@ -148,6 +167,36 @@ func GetGitHubToken(ctx context.Context, k8sClient *kubernetes.Clientset, dynCli
 	return string(token), nil
 }
 
+// mintInstallationToken mints a token for an installation returned by the injected lookups
+func mintInstallationToken(ctx context.Context, installation interface{}, repositories []string, permissions map[string]string) (string, error) {
+	// Use reflection-like approach to call MintScopedInstallationToken
+	// This requires the caller to set up the proper interface/struct
+	type githubInstallation interface {
+		GetInstallationID() int64
+		GetHost() string
+	}
+	type tokenManager interface {
+		MintScopedInstallationToken(context.Context, int64, string, []string, map[string]string) (string, time.Time, error)
+	}
+
+	inst, ok := installation.(githubInstallation)
+	if !ok {
+		return "", fmt.Errorf("unsupported installation type %T", installation)
+	}
+	mgr, ok := GitHubTokenManager.(tokenManager)
+	if !ok {
+		return "", fmt.Errorf("GitHub App token manager not configured")
+	}
+	token, _, err := mgr.MintScopedInstallationToken(ctx, inst.GetInstallationID(), inst.GetHost(), repositories, permissions)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf("empty installation token")
+	}
+	return token, nil
+}
+
 // getSecretKeys returns a list of keys from a secret's Data map for debugging
 func getSecretKeys(data map[string][]byte) []string {
 	keys := make([]string, 0, len(data))
@@ -250,6 +299,52 @@ func ValidateBranchName(branchName string) error {
 	return nil
 }
 
+// NormalizeRepoURL normalizes a repository URL for comparison
+func NormalizeRepoURL(repoURL string) string {
+	normalized := strings.ToLower(strings.TrimSpace(repoURL))
+	// Remove .git suffix
+	normalized = strings.TrimSuffix(normalized, ".git")
+	// Remove trailing slash
+	normalized = strings.TrimSuffix(normalized, "/")
+	return normalized
+}
+
+// RepoPath reduces https://, ssh and scp-style Git URLs to host/owner/repo, the form repository
+// allow-lists in ProjectSettings spec.sessionPolicy are written in
+func RepoPath(u string) string {
+	s := strings.TrimSpace(u)
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+		if at := strings.Index(s, "@"); at >= 0 && at < strings.Index(s+"/", "/") {
+			s = s[at+1:]
+		}
+	} else if at := strings.Index(s, "@"); at >= 0 && strings.Contains(s[at:], ":") {
+		// git@host:owner/repo
+		s = strings.Replace(s[at+1:], ":", "/", 1)
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	if slash := strings.Index(s, "/"); slash >= 0 {
+		return strings.ToLower(s[:slash]) + s[slash:]
+	}
+	return strings.ToLower(s)
+}
+
+// ValidateUniqueRepoURLs checks that no repository URL appears twice; empty URLs are ignored
+func ValidateUniqueRepoURLs(urls []string) error {
+	seen := make(map[string]bool)
+	for _, u := range urls {
+		if strings.TrimSpace(u) == "" {
+			continue
+		}
+		normalized := NormalizeRepoURL(u)
+		if seen[normalized] {
+			return fmt.Errorf("duplicate repository URL detected: %s", u)
+		}
+		seen[normalized] = true
+	}
+	return nil
+}
+
 // checkGitHubPathExists checks if a path exists in a GitHub repo
 func checkGitHubPathExists(ctx context.Context, owner, repo, branch, path, token string) (bool, error) {
 	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s?ref=%s",
@@ -294,8 +389,9 @@ type Workflow interface {
 
 // PerformRepoSeeding performs the actual seeding operations
 // wf parameter should implement the Workflow interface
+// src selects the spec-kit release and agent source; both are served from the seed cache or mirror when available
 // Returns: branchExisted (bool), error
-func PerformRepoSeeding(ctx context.Context, wf Workflow, branchName, githubToken, agentURL, agentBranch, agentPath, specKitRepo, specKitVersion, specKitTemplate string) (bool, error) {
+func PerformRepoSeeding(ctx context.Context, wf Workflow, branchName, githubToken string, src SeedSource) (bool, error) {
 	umbrellaRepo := wf.GetUmbrellaRepo()
 	if umbrellaRepo == nil {
 		return false, fmt.Errorf("workflow has no spec repo")
@@ -328,12 +424,6 @@ func PerformRepoSeeding(ctx context.Context, wf Workflow, branchName, githubToke
 	}
 	defer os.RemoveAll(umbrellaDir)
 
-	agentSrcDir, err := os.MkdirTemp("", "agents-*")
-	if err != nil {
-		return false, fmt.Errorf("failed to create temp dir for agent source: %w", err)
-	}
-	defer os.RemoveAll(agentSrcDir)
-
 	// Clone umbrella repo with authentication
 	log.Printf("Cloning umbrella repo: %s", umbrellaRepo.GetURL())
 	authenticatedURL, err := InjectGitHubToken(umbrellaRepo.GetURL(), githubToken)
@@ -415,130 +505,22 @@ func PerformRepoSeeding(ctx context.Context, wf Workflow, branchName, githubToke
 		}
 	}
 
-	// Download and extract spec-kit template
-	log.Printf("Downloading spec-kit from repo: %s, version: %s", specKitRepo, specKitVersion)
-
-	// Support both releases (vX.X.X) and branch archives (main, branch-name)
-	var specKitURL string
-	if strings.HasPrefix(specKitVersion, "v") {
-		// It's a tagged release - use releases API
-		specKitURL = fmt.Sprintf("https://github.com/%s/releases/download/%s/%s-%s.zip",
-			specKitRepo, specKitVersion, specKitTemplate, specKitVersion)
-		log.Printf("Downloading spec-kit release: %s", specKitURL)
-	} else {
-		// It's a branch name - use archive API
-		specKitURL = fmt.Sprintf("https://github.com/%s/archive/refs/heads/%s.zip",
-			specKitRepo, specKitVersion)
-		log.Printf("Downloading spec-kit branch archive: %s", specKitURL)
-	}
-
-	resp, err := http.Get(specKitURL)
+	// Download spec-kit and add its files where the branch doesn't already have them
+	specKitFiles, _, err := fetchSpecKitFiles(ctx, src)
 	if err != nil {
-		return false, fmt.Errorf("failed to download spec-kit: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("spec-kit download failed with status: %s", resp.Status)
-	}
-
-	zipData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return false, fmt.Errorf("failed to read spec-kit zip: %w", err)
-	}
-
-	zr, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
-	if err != nil {
-		return false, fmt.Errorf("failed to open spec-kit zip: %w", err)
+		return false, err
 	}
-
-	// Extract spec-kit files
 	specKitFilesAdded := 0
-	for _, f := range zr.File {
-		if f.FileInfo().IsDir() {
-			continue
-		}
-
-		rel := strings.TrimPrefix(f.Name, "./")
-		rel = strings.ReplaceAll(rel, "\\", "/")
-
-		// Strip archive prefix from branch downloads (e.g., "spec-kit-rh-vteam-flexible-branches/")
-		// Branch archives have format: "repo-branch-name/file", releases have just "file"
-		if strings.Contains(rel, "/") && !strings.HasPrefix(specKitVersion, "v") {
-			parts := strings.SplitN(rel, "/", 2)
-			if len(parts) == 2 {
-				rel = parts[1] // Take everything after first "/"
-			}
-		}
-
-		// Only extract files needed for umbrella repos (matching official spec-kit release template):
-		// - templates/commands/ → .claude/commands/
-		// - scripts/bash/ → .specify/scripts/bash/
-		// - templates/*.md → .specify/templates/
-		// - memory/ → .specify/memory/
-		// Skip everything else (docs/, media/, root files, .github/, scripts/powershell/, etc.)
-
-		var targetRel string
-		if strings.HasPrefix(rel, "templates/commands/") {
-			// Map templates/commands/*.md to .claude/commands/speckit.*.md
-			cmdFile := strings.TrimPrefix(rel, "templates/commands/")
-			if !strings.HasPrefix(cmdFile, "speckit.") {
-				cmdFile = "speckit." + cmdFile
-			}
-			targetRel = ".claude/commands/" + cmdFile
-		} else if strings.HasPrefix(rel, "scripts/bash/") {
-			// Map scripts/bash/ to .specify/scripts/bash/
-			targetRel = strings.Replace(rel, "scripts/bash/", ".specify/scripts/bash/", 1)
-		} else if strings.HasPrefix(rel, "templates/") && strings.HasSuffix(rel, ".md") {
-			// Map templates/*.md to .specify/templates/
-			targetRel = strings.Replace(rel, "templates/", ".specify/templates/", 1)
-		} else if strings.HasPrefix(rel, "memory/") {
-			// Map memory/ to .specify/memory/
-			targetRel = ".specify/" + rel
-		} else {
-			// Skip all other files (docs/, media/, root files, .github/, scripts/powershell/, etc.)
-			continue
-		}
-
-		// Security: prevent path traversal
-		for strings.Contains(targetRel, "../") {
-			targetRel = strings.ReplaceAll(targetRel, "../", "")
-		}
-
-		targetPath := filepath.Join(umbrellaDir, targetRel)
-
+	for rel, f := range specKitFiles {
+		targetPath := filepath.Join(umbrellaDir, rel)
 		if _, err := os.Stat(targetPath); err == nil {
 			continue
 		}
-
 		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 			log.Printf("Failed to create dir for %s: %v", rel, err)
 			continue
 		}
-
-		rc, err := f.Open()
-		if err != nil {
-			log.Printf("Failed to open zip entry %s: %v", f.Name, err)
-			continue
-		}
-		content, err := io.ReadAll(rc)
-		rc.Close()
-		if err != nil {
-			log.Printf("Failed to read zip entry %s: %v", f.Name, err)
-			continue
-		}
-
-		// Preserve executable permissions for scripts
-		fileMode := fs.FileMode(0644)
-		if strings.HasPrefix(targetRel, ".specify/scripts/") {
-			// Scripts need to be executable
-			fileMode = 0755
-		} else if f.Mode().Perm()&0111 != 0 {
-			// Preserve executable bit from zip if it was set
-			fileMode = 0755
-		}
-
-		if err := os.WriteFile(targetPath, content, fileMode); err != nil {
+		if err := os.WriteFile(targetPath, f.Content, f.Mode); err != nil {
 			log.Printf("Failed to write %s: %v", targetPath, err)
 			continue
 		}
@@ -546,52 +528,22 @@ func PerformRepoSeeding(ctx context.Context, wf Workflow, branchName, githubToke
 	}
 	log.Printf("Extracted %d spec-kit files", specKitFilesAdded)
 
-	// Clone agent source repo
-	log.Printf("Cloning agent source: %s", agentURL)
-	agentArgs := []string{"clone", "--depth", "1"}
-	if agentBranch != "" {
-		agentArgs = append(agentArgs, "--branch", agentBranch)
-	}
-	agentArgs = append(agentArgs, agentURL, agentSrcDir)
-
-	cmd = exec.CommandContext(ctx, "git", agentArgs...)
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return false, fmt.Errorf("failed to clone agent source: %w (output: %s)", err, string(out))
-	}
-
 	// Copy agent markdown files to .claude/agents/
-	agentSourcePath := filepath.Join(agentSrcDir, agentPath)
-	claudeDir := filepath.Join(umbrellaDir, ".claude")
-	claudeAgentsDir := filepath.Join(claudeDir, "agents")
-	if err := os.MkdirAll(claudeAgentsDir, 0755); err != nil {
+	agentFiles, _, err := fetchAgentFiles(ctx, src)
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Join(umbrellaDir, ".claude", "agents"), 0755); err != nil {
 		return false, fmt.Errorf("failed to create .claude/agents directory: %w", err)
 	}
-
 	agentsCopied := 0
-	err = filepath.WalkDir(agentSourcePath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil || d.IsDir() {
-			return nil
-		}
-		if !strings.HasSuffix(strings.ToLower(d.Name()), ".md") {
-			return nil
-		}
-
-		content, err := os.ReadFile(path)
-		if err != nil {
-			log.Printf("Failed to read agent file %s: %v", path, err)
-			return nil
-		}
-
-		targetPath := filepath.Join(claudeAgentsDir, d.Name())
-		if err := os.WriteFile(targetPath, content, 0644); err != nil {
+	for rel, f := range agentFiles {
+		targetPath := filepath.Join(umbrellaDir, rel)
+		if err := os.WriteFile(targetPath, f.Content, f.Mode); err != nil {
 			log.Printf("Failed to write agent file %s: %v", targetPath, err)
-			return nil
+			continue
 		}
 		agentsCopied++
-		return nil
-	})
-	if err != nil {
-		return false, fmt.Errorf("failed to copy agents: %w", err)
 	}
 	log.Printf("Copied %d agent files", agentsCopied)
 
@@ -693,16 +645,31 @@ func DeriveRepoFolderFromURL(u string) string {
 	return strings.TrimSpace(last)
 }
 
-// PushRepo performs git add/commit/push operations on a repository directory
-func PushRepo(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch, githubToken string) (string, error) {
+// PushRepo performs git add/commit/push operations on a repository directory.
+// Before pushing, the target branch is fetched and integrated using the given
+// sync strategy; a *ConflictError is returned when that cannot be done cleanly.
+// Commits are authored, trailered and optionally signed according to identity.
+func PushRepo(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch, strategy, githubToken string, identity CommitIdentity) (string, error) {
 	if fi, err := os.Stat(repoDir); err != nil || !fi.IsDir() {
 		return "", fmt.Errorf("repo directory not found: %s", repoDir)
 	}
 
+	strategy, err := NormalizeSyncStrategy(strategy)
+	if err != nil {
+		return "", err
+	}
+	if identity.RequireSigned && strings.TrimSpace(identity.SigningKey) == "" {
+		return "", fmt.Errorf("signed commits are required but no signing key is configured")
+	}
+
+	var runEnv []string
 	run := func(args ...string) (string, string, error) {
 		start := time.Now()
 		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
 		cmd.Dir = repoDir
+		if len(runEnv) > 0 {
+			cmd.Env = append(os.Environ(), runEnv...)
+		}
 		var stdout, stderr bytes.Buffer
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
@@ -717,55 +684,24 @@ func PushRepo(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch
 		return "", nil
 	}
 
-	// Configure git user identity from GitHub API
-	gitUserName := ""
-	gitUserEmail := ""
+	// Configure git user identity according to the project's commit identity policy
+	gitUserName, gitUserEmail := resolveCommitAuthor(ctx, identity, githubToken)
+	run("git", "config", "user.name", gitUserName)
+	run("git", "config", "user.email", gitUserEmail)
+	log.Printf("gitPushRepo: configured git identity mode=%q name=%q email=%q", identity.Mode, gitUserName, gitUserEmail)
 
-	if githubToken != "" {
-		req, _ := http.NewRequest("GET", "https://api.github.com/user", nil)
-		req.Header.Set("Authorization", "token "+githubToken)
-		req.Header.Set("Accept", "application/vnd.github+json")
-		resp, err := http.DefaultClient.Do(req)
-		if err == nil {
-			defer resp.Body.Close()
-			switch resp.StatusCode {
-			case 200:
-				var ghUser struct {
-					Login string `json:"login"`
-					Name  string `json:"name"`
-					Email string `json:"email"`
-				}
-				if json.Unmarshal([]byte(fmt.Sprintf("%v", resp.Body)), &ghUser) == nil {
-					if gitUserName == "" && ghUser.Name != "" {
-						gitUserName = ghUser.Name
-					} else if gitUserName == "" && ghUser.Login != "" {
-						gitUserName = ghUser.Login
-					}
-					if gitUserEmail == "" && ghUser.Email != "" {
-						gitUserEmail = ghUser.Email
-					}
-					log.Printf("gitPushRepo: fetched GitHub user name=%q email=%q", gitUserName, gitUserEmail)
-				}
-			case 403:
-				log.Printf("gitPushRepo: GitHub API /user returned 403 (token lacks 'read:user' scope, using fallback identity)")
-			default:
-				log.Printf("gitPushRepo: GitHub API /user returned status %d", resp.StatusCode)
-			}
-		} else {
-			log.Printf("gitPushRepo: failed to fetch GitHub user: %v", err)
+	// Signing configuration applies to the commit below and to commits rewritten by the upstream sync
+	var signArgs []string
+	if strings.TrimSpace(identity.SigningKey) != "" {
+		args, env, cleanup, err := prepareCommitSigning(ctx, identity)
+		if err != nil {
+			return "", fmt.Errorf("failed to configure commit signing: %w", err)
 		}
+		defer cleanup()
+		signArgs = args
+		runEnv = env
 	}
 
-	if gitUserName == "" {
-		gitUserName = "Ambient Code Bot"
-	}
-	if gitUserEmail == "" {
-		gitUserEmail = "bot@ambient-code.local"
-	}
-	run("git", "config", "user.name", gitUserName)
-	run("git", "config", "user.email", gitUserEmail)
-	log.Printf("gitPushRepo: configured git identity name=%q email=%q", gitUserName, gitUserEmail)
-
 	// Stage and commit
 	log.Printf("gitPushRepo: staging changes ...")
 	_, _, _ = run("git", "add", "-A")
@@ -774,10 +710,15 @@ func PushRepo(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch
 	if strings.TrimSpace(cm) == "" {
 		cm = "Update from Ambient session"
 	}
+	cm = appendCoAuthorTrailers(cm, identity.CoAuthors, gitUserEmail)
 
-	log.Printf("gitPushRepo: committing changes ...")
-	commitOut, commitErr, commitErrCode := run("git", "commit", "-m", cm)
+	log.Printf("gitPushRepo: committing changes (signed=%t) ...", len(signArgs) > 0)
+	commitArgs := append(append([]string{"git"}, signArgs...), "commit", "-m", cm)
+	commitOut, commitErr, commitErrCode := run(commitArgs...)
 	if commitErrCode != nil {
+		if len(signArgs) > 0 {
+			return "", fmt.Errorf("signed commit failed: %s", strings.TrimSpace(commitErr))
+		}
 		log.Printf("gitPushRepo: commit failed (continuing): err=%v stderr=%q stdout=%q", commitErrCode, commitErr, commitOut)
 	}
 
@@ -797,14 +738,30 @@ func PushRepo(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch
 		ref = "HEAD:" + branch
 	}
 
-	// Push with token authentication
-	var pushArgs []string
+	// Token authentication is shared by the pre-push fetch and the push itself
+	gitArgs := []string{"git"}
 	if githubToken != "" {
 		cfg := fmt.Sprintf("url.https://x-access-token:%s@github.com/.insteadOf=https://github.com/", githubToken)
-		pushArgs = []string{"git", "-c", cfg, "push", "-u", outputRepoURL, ref}
+		gitArgs = append(gitArgs, "-c", cfg)
+	}
+
+	// Integrate upstream changes on the target branch before pushing
+	if strategy != SyncStrategyNone && branch != "auto" {
+		conflict, err := syncWithUpstream(run, gitArgs, signArgs, strategy, outputRepoURL, branch)
+		if err != nil {
+			return "", err
+		}
+		if conflict != nil {
+			log.Printf("gitPushRepo: %s onto %s/%s hit conflicts in %d file(s)", strategy, outputRepoURL, branch, len(conflict.Files))
+			return "", &ConflictError{Info: conflict}
+		}
+	}
+
+	// Push with token authentication
+	pushArgs := append(append([]string{}, gitArgs...), "push", "-u", outputRepoURL, ref)
+	if githubToken != "" {
 		log.Printf("gitPushRepo: running git push with token auth to %s %s", outputRepoURL, ref)
 	} else {
-		pushArgs = []string{"git", "push", "-u", outputRepoURL, ref}
 		log.Printf("gitPushRepo: running git push %s %s in %s", outputRepoURL, ref, repoDir)
 	}
 
@@ -829,6 +786,395 @@ func PushRepo(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch
 	return out, nil
 }
 
+// Commit identity modes configured per project in ProjectSettings spec.commitIdentity
+const (
+	IdentityModeUser   = "user"
+	IdentityModeBot    = "bot"
+	IdentityModeCustom = "custom"
+)
+
+// Identity used when no better author can be determined
+const (
+	DefaultBotName  = "Ambient Code Bot"
+	DefaultBotEmail = "bot@ambient-code.local"
+)
+
+// CommitIdentity controls authorship, trailers and signing of commits created by PushRepo
+type CommitIdentity struct {
+	Mode          string   // user (default), bot or custom
+	Name          string   // author name for custom/bot mode, fallback for user mode
+	Email         string   // author email for custom/bot mode, fallback for user mode
+	CoAuthors     []string // "Name <email>" entries added as Co-authored-by trailers
+	SigningKey    string   // SSH or ASCII-armored OpenPGP private key; empty disables signing
+	SigningFormat string   // "ssh" or "openpgp"; detected from the key when empty
+	RequireSigned bool     // refuse to push when no signing key is available
+}
+
+// resolveCommitAuthor picks the commit author name and email for the identity policy
+func resolveCommitAuthor(ctx context.Context, identity CommitIdentity, githubToken string) (string, string) {
+	name, email := "", ""
+	switch identity.Mode {
+	case IdentityModeCustom, IdentityModeBot:
+		name, email = strings.TrimSpace(identity.Name), strings.TrimSpace(identity.Email)
+	default:
+		if githubToken != "" {
+			name, email = fetchGitHubUserIdentity(ctx, githubToken)
+		}
+		if name == "" {
+			name = strings.TrimSpace(identity.Name)
+		}
+		if email == "" {
+			email = strings.TrimSpace(identity.Email)
+		}
+	}
+	if name == "" {
+		name = DefaultBotName
+	}
+	if email == "" {
+		email = DefaultBotEmail
+	}
+	return name, email
+}
+
+// fetchGitHubUserIdentity returns the name and email of the user owning a GitHub token.
+// Installation tokens cannot read /user, in which case empty strings are returned.
+func fetchGitHubUserIdentity(ctx context.Context, githubToken string) (string, string) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
+	if err != nil {
+		return "", ""
+	}
+	req.Header.Set("Authorization", "token "+githubToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Printf("gitPushRepo: failed to fetch GitHub user: %v", err)
+		return "", ""
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden:
+		log.Printf("gitPushRepo: GitHub API /user returned 403 (token cannot read user profile, using fallback identity)")
+		return "", ""
+	default:
+		log.Printf("gitPushRepo: GitHub API /user returned status %d", resp.StatusCode)
+		return "", ""
+	}
+
+	var ghUser struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&ghUser); err != nil {
+		log.Printf("gitPushRepo: failed to decode GitHub user: %v", err)
+		return "", ""
+	}
+
+	name := ghUser.Name
+	if name == "" {
+		name = ghUser.Login
+	}
+	email := ghUser.Email
+	if email == "" && ghUser.Login != "" && ghUser.ID != 0 {
+		// Private email: use the noreply address so commits still link to the account
+		email = fmt.Sprintf("%d+%s@users.noreply.github.com", ghUser.ID, ghUser.Login)
+	}
+	log.Printf("gitPushRepo: fetched GitHub user name=%q email=%q", name, email)
+	return name, email
+}
+
+// appendCoAuthorTrailers adds Co-authored-by trailers for co-authors other than the commit author
+func appendCoAuthorTrailers(message string, coAuthors []string, authorEmail string) string {
+	var trailers []string
+	for _, ca := range coAuthors {
+		ca = strings.TrimSpace(ca)
+		if ca == "" || !strings.Contains(ca, "<") {
+			continue
+		}
+		if authorEmail != "" && strings.Contains(strings.ToLower(ca), "<"+strings.ToLower(authorEmail)+">") {
+			continue
+		}
+		trailer := "Co-authored-by: " + ca
+		if strings.Contains(message, trailer) {
+			continue
+		}
+		trailers = append(trailers, trailer)
+	}
+	if len(trailers) == 0 {
+		return message
+	}
+	return strings.TrimRight(message, "\n") + "\n\n" + strings.Join(trailers, "\n")
+}
+
+// DetectSigningFormat infers the git signing format ("ssh" or "openpgp") from a private key
+func DetectSigningFormat(key string) string {
+	if strings.Contains(key, "BEGIN PGP PRIVATE KEY BLOCK") {
+		return "openpgp"
+	}
+	return "ssh"
+}
+
+// prepareCommitSigning writes the signing key to a private temporary location and returns
+// git -c arguments and environment enabling signed commits, plus a cleanup function.
+func prepareCommitSigning(ctx context.Context, identity CommitIdentity) ([]string, []string, func(), error) {
+	format := strings.ToLower(strings.TrimSpace(identity.SigningFormat))
+	if format == "" || format == "gpg" {
+		format = DetectSigningFormat(identity.SigningKey)
+	}
+	if format != "ssh" && format != "openpgp" {
+		return nil, nil, func() {}, fmt.Errorf("unsupported signing format %q", identity.SigningFormat)
+	}
+
+	dir, err := os.MkdirTemp("", "ambient-signing-")
+	if err != nil {
+		return nil, nil, func() {}, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	key := strings.TrimSpace(identity.SigningKey) + "\n"
+	keyPath := filepath.Join(dir, "signing-key")
+	if err := os.WriteFile(keyPath, []byte(key), 0o600); err != nil {
+		cleanup()
+		return nil, nil, func() {}, err
+	}
+
+	if format == "ssh" {
+		args := []string{"-c", "gpg.format=ssh", "-c", "user.signingkey=" + keyPath, "-c", "commit.gpgsign=true"}
+		return args, nil, cleanup, nil
+	}
+
+	// OpenPGP: import into an isolated keyring; passphrase-protected keys are not supported
+	env := []string{"GNUPGHOME=" + dir}
+	gpg := func(args ...string) (string, error) {
+		cmd := exec.CommandContext(ctx, "gpg", append([]string{"--batch", "--no-tty"}, args...)...)
+		cmd.Env = append(os.Environ(), env...)
+		var out bytes.Buffer
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+		err := cmd.Run()
+		return out.String(), err
+	}
+	if out, err := gpg("--import", keyPath); err != nil {
+		cleanup()
+		return nil, nil, func() {}, fmt.Errorf("gpg import failed: %s", strings.TrimSpace(out))
+	}
+	listing, err := gpg("--with-colons", "--list-secret-keys")
+	if err != nil {
+		cleanup()
+		return nil, nil, func() {}, fmt.Errorf("gpg list failed: %s", strings.TrimSpace(listing))
+	}
+	fingerprint := ""
+	for _, ln := range strings.Split(listing, "\n") {
+		fields := strings.Split(ln, ":")
+		if len(fields) > 9 && fields[0] == "fpr" {
+			fingerprint = fields[9]
+			break
+		}
+	}
+	if fingerprint == "" {
+		cleanup()
+		return nil, nil, func() {}, fmt.Errorf("no secret key found in signing key material")
+	}
+
+	gpgCleanup := func() {
+		kill := exec.Command("gpgconf", "--kill", "gpg-agent")
+		kill.Env = append(os.Environ(), env...)
+		_ = kill.Run()
+		cleanup()
+	}
+	args := []string{"-c", "gpg.format=openpgp", "-c", "user.signingkey=" + fingerprint, "-c", "commit.gpgsign=true"}
+	return args, env, gpgCleanup, nil
+}
+
+// Pre-push sync strategies used to integrate the upstream output branch
+const (
+	SyncStrategyRebase = "rebase"
+	SyncStrategyMerge  = "merge"
+	SyncStrategyNone   = "none"
+)
+
+// Limits that keep conflict reports small enough to return through the API
+const (
+	maxConflictFiles     = 50
+	maxConflictHunks     = 20
+	maxConflictHunkBytes = 4000
+)
+
+// ConflictHunk is a single region delimited by git conflict markers
+type ConflictHunk struct {
+	StartLine int    `json:"startLine"`
+	OursRef   string `json:"oursRef,omitempty"`
+	Ours      string `json:"ours"`
+	TheirsRef string `json:"theirsRef,omitempty"`
+	Theirs    string `json:"theirs"`
+}
+
+// ConflictFile lists the conflicted regions found in a single file
+type ConflictFile struct {
+	Path  string         `json:"path"`
+	Hunks []ConflictHunk `json:"hunks,omitempty"`
+}
+
+// ConflictInfo describes an upstream integration that could not be completed cleanly
+type ConflictInfo struct {
+	Strategy    string         `json:"strategy"`
+	RepoURL     string         `json:"repoUrl"`
+	Branch      string         `json:"branch"`
+	UpstreamSHA string         `json:"upstreamSha"`
+	LocalSHA    string         `json:"localSha"`
+	Files       []ConflictFile `json:"files"`
+}
+
+// ConflictError is returned by PushRepo when the upstream branch conflicts with local commits
+type ConflictError struct {
+	Info *ConflictInfo
+}
+
+func (e *ConflictError) Error() string {
+	if e.Info == nil {
+		return "upstream conflict"
+	}
+	return fmt.Sprintf("%s onto %s failed with conflicts in %d file(s)", e.Info.Strategy, e.Info.Branch, len(e.Info.Files))
+}
+
+// NormalizeSyncStrategy validates a sync strategy name, defaulting to rebase when empty
+func NormalizeSyncStrategy(strategy string) (string, error) {
+	s := strings.ToLower(strings.TrimSpace(strategy))
+	switch s {
+	case "":
+		return SyncStrategyRebase, nil
+	case SyncStrategyRebase, SyncStrategyMerge, SyncStrategyNone:
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid conflict strategy %q (must be rebase, merge or none)", strategy)
+	}
+}
+
+// syncWithUpstream fetches the target branch and rebases or merges local commits onto it.
+// signArgs are applied so rewritten or merge commits stay signed.
+// It returns conflict details (with the operation aborted) when the integration is not clean.
+func syncWithUpstream(run func(args ...string) (string, string, error), gitArgs, signArgs []string, strategy, repoURL, branch string) (*ConflictInfo, error) {
+	git := func(args ...string) (string, string, error) {
+		return run(append(append([]string{}, gitArgs...), args...)...)
+	}
+
+	log.Printf("gitPushRepo: fetching %s %s before push ...", repoURL, branch)
+	if _, errOut, err := git("fetch", repoURL, branch); err != nil {
+		if strings.Contains(errOut, "couldn't find remote ref") {
+			// Branch does not exist upstream yet; nothing to integrate
+			return nil, nil
+		}
+		// Leave authentication/network errors for the push to report
+		log.Printf("gitPushRepo: pre-push fetch failed (continuing): %v", err)
+		return nil, nil
+	}
+
+	upstream, _, _ := run("git", "rev-parse", "FETCH_HEAD")
+	local, _, _ := run("git", "rev-parse", "HEAD")
+	upstream, local = strings.TrimSpace(upstream), strings.TrimSpace(local)
+	if _, _, err := run("git", "merge-base", "--is-ancestor", "FETCH_HEAD", "HEAD"); err == nil {
+		log.Printf("gitPushRepo: local branch already contains upstream %s", upstream)
+		return nil, nil
+	}
+
+	var opErr error
+	var errOut string
+	signed := append([]string{"git"}, signArgs...)
+	if strategy == SyncStrategyMerge {
+		log.Printf("gitPushRepo: merging upstream %s ...", upstream)
+		_, errOut, opErr = run(append(signed, "merge", "--no-edit", "FETCH_HEAD")...)
+	} else {
+		log.Printf("gitPushRepo: rebasing onto upstream %s ...", upstream)
+		_, errOut, opErr = run(append(signed, "rebase", "FETCH_HEAD")...)
+	}
+	if opErr == nil {
+		return nil, nil
+	}
+
+	info := &ConflictInfo{
+		Strategy:    strategy,
+		RepoURL:     repoURL,
+		Branch:      branch,
+		UpstreamSHA: upstream,
+		LocalSHA:    local,
+	}
+	if out, _, err := run("git", "diff", "--name-only", "--diff-filter=U"); err == nil {
+		for _, p := range strings.Split(strings.TrimSpace(out), "\n") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			if len(info.Files) >= maxConflictFiles {
+				break
+			}
+			info.Files = append(info.Files, ConflictFile{Path: p, Hunks: readConflictHunks(run, p)})
+		}
+	}
+
+	// Always restore the pre-sync state so the workspace stays usable
+	_, _, _ = run("git", strategy, "--abort")
+
+	if len(info.Files) == 0 {
+		return nil, fmt.Errorf("%s onto upstream %s failed: %s", strategy, branch, strings.TrimSpace(errOut))
+	}
+	return info, nil
+}
+
+// readConflictHunks extracts the regions between conflict markers from a worktree file
+func readConflictHunks(run func(args ...string) (string, string, error), path string) []ConflictHunk {
+	root, _, err := run("git", "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(strings.TrimSpace(root), path))
+	if err != nil {
+		return nil
+	}
+
+	clip := func(s string) string {
+		if len(s) > maxConflictHunkBytes {
+			return s[:maxConflictHunkBytes] + "..."
+		}
+		return s
+	}
+
+	var hunks []ConflictHunk
+	var cur *ConflictHunk
+	var ours, theirs strings.Builder
+	section := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<"):
+			cur = &ConflictHunk{StartLine: i + 1, OursRef: strings.TrimSpace(strings.TrimPrefix(line, "<<<<<<<"))}
+			ours.Reset()
+			theirs.Reset()
+			section = "ours"
+		case cur != nil && strings.HasPrefix(line, "|||||||"):
+			section = "base"
+		case cur != nil && strings.HasPrefix(line, "======="):
+			section = "theirs"
+		case cur != nil && strings.HasPrefix(line, ">>>>>>>"):
+			cur.TheirsRef = strings.TrimSpace(strings.TrimPrefix(line, ">>>>>>>"))
+			cur.Ours = clip(ours.String())
+			cur.Theirs = clip(theirs.String())
+			hunks = append(hunks, *cur)
+			cur = nil
+			section = ""
+			if len(hunks) >= maxConflictHunks {
+				return hunks
+			}
+		case section == "ours":
+			ours.WriteString(line + "\n")
+		case section == "theirs":
+			theirs.WriteString(line + "\n")
+		}
+	}
+	return hunks
+}
+
 // AbandonRepo discards all uncommitted changes in a repository directory
 func AbandonRepo(ctx context.Context, repoDir string) error {
 	if fi, err := os.Stat(repoDir); err != nil || !fi.IsDir() {
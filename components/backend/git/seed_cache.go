@@ -0,0 +1,414 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Seed sources are cached content-addressed: objects/sha256/<digest> holds the spec-kit archive
+// or agent bundle, and refs/<key hash> maps a source (repo + ref/version) to its digest. Objects
+// are verified against their digest on every read and against pinned checksums when configured.
+//
+// Environment:
+//   - SEED_CACHE_DIR: cache root (default $STATE_BASE_DIR/seed-cache)
+//   - SEED_CACHE_TTL: how long refs of branches stay fresh (default 1h); tags and commit SHAs never expire
+//   - SEED_MIRROR_DIR: local mirror consulted before the network, laid out as
+//     spec-kit/<owner>/<repo>/<archive name> (the release asset or <branch>.zip) and
+//     agents/<host>/<owner>/<repo>/<branch>/ (a checkout of the agent source)
+//   - SEED_AIRGAPPED: "true" never touches the network; sources must be mirrored or cached
+//   - SEED_CHECKSUMS_FILE: pinned checksums, one "<sha256>  <source key>" per line
+const defaultSeedCacheTTL = time.Hour
+
+var (
+	reCommitSHA   = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	reReleaseTag  = regexp.MustCompile(`^v\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?$`)
+	reSHA256Hex   = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	errNotInCache = errors.New("not cached")
+)
+
+// seedCacheDir returns the cache root
+func seedCacheDir() string {
+	if dir := strings.TrimSpace(os.Getenv("SEED_CACHE_DIR")); dir != "" {
+		return dir
+	}
+	base := strings.TrimSpace(os.Getenv("STATE_BASE_DIR"))
+	if base == "" {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "seed-cache")
+}
+
+func seedAirgapped() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("SEED_AIRGAPPED")), "true")
+}
+
+func seedCacheTTL() time.Duration {
+	if v := strings.TrimSpace(os.Getenv("SEED_CACHE_TTL")); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		log.Printf("seedCacheTTL: invalid SEED_CACHE_TTL %q, using %s", v, defaultSeedCacheTTL)
+	}
+	return defaultSeedCacheTTL
+}
+
+// immutableSeedRef reports refs whose content never changes (release tags and commit SHAs)
+func immutableSeedRef(ref string) bool {
+	return reCommitSHA.MatchString(ref) || reReleaseTag.MatchString(ref)
+}
+
+// specKitSourceKey identifies a spec-kit archive; it is the archive's download URL
+func specKitSourceKey(src SeedSource) string {
+	return specKitURL(src.SpecKitRepo, src.SpecKitVersion, src.SpecKitTemplate)
+}
+
+// agentSourceKey identifies an agent source: "<repo url>#<branch>:<path>"
+func agentSourceKey(src SeedSource) string {
+	return fmt.Sprintf("%s#%s:%s", src.AgentSourceURL, src.AgentSourceBranch, src.AgentSourcePath)
+}
+
+// pinnedSeedChecksum returns the expected SHA-256 of a source: the explicit pin when set,
+// otherwise the entry for key in SEED_CHECKSUMS_FILE
+func pinnedSeedChecksum(explicit, key string) (string, error) {
+	if explicit = strings.ToLower(strings.TrimSpace(explicit)); explicit != "" {
+		if !reSHA256Hex.MatchString(explicit) {
+			return "", fmt.Errorf("invalid pinned sha256 %q for %s", explicit, key)
+		}
+		return explicit, nil
+	}
+	file := strings.TrimSpace(os.Getenv("SEED_CHECKSUMS_FILE"))
+	if file == "" {
+		return "", nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read seed checksums: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == key {
+			sum := strings.ToLower(fields[0])
+			if !reSHA256Hex.MatchString(sum) {
+				return "", fmt.Errorf("invalid sha256 for %s in %s", key, file)
+			}
+			return sum, nil
+		}
+	}
+	return "", sc.Err()
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func seedObjectPath(digest string) string {
+	return filepath.Join(seedCacheDir(), "objects", "sha256", digest[:2], digest)
+}
+
+func seedRefPath(key string) string {
+	return filepath.Join(seedCacheDir(), "refs", sha256Hex([]byte(key)))
+}
+
+// readSeedObject reads a cached object and verifies it still hashes to digest
+func readSeedObject(digest string) ([]byte, error) {
+	if !reSHA256Hex.MatchString(digest) {
+		return nil, errNotInCache
+	}
+	data, err := os.ReadFile(seedObjectPath(digest))
+	if err != nil {
+		return nil, errNotInCache
+	}
+	if got := sha256Hex(data); got != digest {
+		log.Printf("readSeedObject: cached object %s is corrupt (sha256 %s), discarding", digest, got)
+		_ = os.Remove(seedObjectPath(digest))
+		return nil, errNotInCache
+	}
+	return data, nil
+}
+
+// writeFileAtomic writes data via a temp file and rename so readers never see partial files
+func writeFileAtomic(p string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// storeSeedObject caches data and points key at it; failures only cost a re-download
+func storeSeedObject(key string, data []byte) string {
+	digest := sha256Hex(data)
+	if err := writeFileAtomic(seedObjectPath(digest), data); err != nil {
+		log.Printf("storeSeedObject: failed to cache %s: %v", key, err)
+		return digest
+	}
+	if err := writeFileAtomic(seedRefPath(key), []byte(digest+"\n"+key+"\n")); err != nil {
+		log.Printf("storeSeedObject: failed to record ref for %s: %v", key, err)
+	}
+	return digest
+}
+
+// lookupSeedRef returns the cached object for key. Refs of mutable refs (branches) expire after
+// SEED_CACHE_TTL unless allowStale is set.
+func lookupSeedRef(key string, immutable, allowStale bool) ([]byte, string, error) {
+	p := seedRefPath(key)
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, "", errNotInCache
+	}
+	if !immutable && !allowStale && time.Since(info.ModTime()) > seedCacheTTL() {
+		return nil, "", errNotInCache
+	}
+	raw, err := os.ReadFile(p)
+	if err != nil {
+		return nil, "", errNotInCache
+	}
+	digest := strings.TrimSpace(strings.SplitN(string(raw), "\n", 2)[0])
+	data, err := readSeedObject(digest)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, digest, nil
+}
+
+// resolveSeedObject returns a source's content from, in order: a fresh cache entry, the mirror,
+// the network (unless air-gapped), or a stale cache entry. The result is verified against the
+// pinned checksum when one is configured, and fetched content is only cached once it passes.
+func resolveSeedObject(ctx context.Context, key, ref, pinned string, fromMirror func() ([]byte, error), fromNetwork func(context.Context) ([]byte, error)) ([]byte, string, error) {
+	immutable := immutableSeedRef(ref)
+	verify := func(data []byte, digest, origin string) ([]byte, string, error) {
+		if pinned != "" && digest != pinned {
+			return nil, "", fmt.Errorf("checksum mismatch for %s from %s: got sha256 %s, pinned %s", key, origin, digest, pinned)
+		}
+		return data, digest, nil
+	}
+	verifyAndStore := func(data []byte, origin string) ([]byte, string, error) {
+		if _, _, err := verify(data, sha256Hex(data), origin); err != nil {
+			return nil, "", err
+		}
+		return data, storeSeedObject(key, data), nil
+	}
+
+	// A pinned digest identifies the object directly
+	if pinned != "" {
+		if data, err := readSeedObject(pinned); err == nil {
+			return data, pinned, nil
+		}
+	}
+	if data, digest, err := lookupSeedRef(key, immutable, false); err == nil && (pinned == "" || digest == pinned) {
+		return data, digest, nil
+	}
+
+	var errs []string
+	if fromMirror != nil && strings.TrimSpace(os.Getenv("SEED_MIRROR_DIR")) != "" {
+		data, err := fromMirror()
+		if err == nil {
+			log.Printf("resolveSeedObject: using mirror for %s", key)
+			return verifyAndStore(data, "mirror")
+		}
+		errs = append(errs, "mirror: "+err.Error())
+	}
+	if !seedAirgapped() {
+		data, err := fromNetwork(ctx)
+		if err == nil {
+			return verifyAndStore(data, "network")
+		}
+		errs = append(errs, err.Error())
+	} else {
+		errs = append(errs, "network access disabled (SEED_AIRGAPPED)")
+	}
+	// Last resort: an expired cache entry beats failing the seed
+	if data, digest, err := lookupSeedRef(key, immutable, true); err == nil {
+		log.Printf("resolveSeedObject: using stale cache for %s", key)
+		return verify(data, digest, "cache")
+	}
+	return nil, "", fmt.Errorf("failed to obtain %s: %s", key, strings.Join(errs, "; "))
+}
+
+// fetchSpecKitArchive returns the spec-kit archive for src and its SHA-256
+func fetchSpecKitArchive(ctx context.Context, src SeedSource) ([]byte, string, error) {
+	key := specKitSourceKey(src)
+	pinned, err := pinnedSeedChecksum(src.SpecKitSHA256, key)
+	if err != nil {
+		return nil, "", err
+	}
+	fromMirror := func() ([]byte, error) {
+		u, err := url.Parse(key)
+		if err != nil {
+			return nil, err
+		}
+		p := filepath.Join(os.Getenv("SEED_MIRROR_DIR"), "spec-kit", filepath.FromSlash(src.SpecKitRepo), path.Base(u.Path))
+		return os.ReadFile(p)
+	}
+	fromNetwork := func(ctx context.Context) ([]byte, error) {
+		log.Printf("Downloading spec-kit: %s", key)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, key, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create spec-kit request: %w", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download spec-kit: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("spec-kit download failed with status: %s", resp.Status)
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spec-kit zip: %w", err)
+		}
+		return data, nil
+	}
+	return resolveSeedObject(ctx, key, src.SpecKitVersion, pinned, fromMirror, fromNetwork)
+}
+
+// fetchAgentBundle returns the agent files of src as a bundle (see encodeAgentBundle) and its SHA-256
+func fetchAgentBundle(ctx context.Context, src SeedSource) ([]byte, string, error) {
+	key := agentSourceKey(src)
+	pinned, err := pinnedSeedChecksum(src.AgentSourceSHA256, key)
+	if err != nil {
+		return nil, "", err
+	}
+	fromMirror := func() ([]byte, error) {
+		u, err := url.Parse(strings.TrimSuffix(src.AgentSourceURL, ".git"))
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("cannot mirror agent source %q", src.AgentSourceURL)
+		}
+		dir := filepath.Join(os.Getenv("SEED_MIRROR_DIR"), "agents", u.Host, filepath.FromSlash(strings.Trim(u.Path, "/")), src.AgentSourceBranch)
+		if _, err := os.Stat(dir); err != nil {
+			return nil, err
+		}
+		return readAgentDir(filepath.Join(dir, src.AgentSourcePath))
+	}
+	fromNetwork := func(ctx context.Context) ([]byte, error) {
+		agentSrcDir, err := os.MkdirTemp("", "agents-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temp dir for agent source: %w", err)
+		}
+		defer os.RemoveAll(agentSrcDir)
+
+		log.Printf("Cloning agent source: %s", src.AgentSourceURL)
+		args := []string{"clone", "--depth", "1"}
+		if src.AgentSourceBranch != "" {
+			args = append(args, "--branch", src.AgentSourceBranch)
+		}
+		args = append(args, src.AgentSourceURL, agentSrcDir)
+		if out, err := exec.CommandContext(ctx, "git", args...).CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("failed to clone agent source: %w (output: %s)", err, string(out))
+		}
+		return readAgentDir(filepath.Join(agentSrcDir, src.AgentSourcePath))
+	}
+	return resolveSeedObject(ctx, key, src.AgentSourceBranch, pinned, fromMirror, fromNetwork)
+}
+
+// readAgentDir bundles the markdown agents under dir, keyed by file name as they are seeded
+func readAgentDir(dir string) ([]byte, error) {
+	files := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if !strings.HasSuffix(strings.ToLower(d.Name()), ".md") {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			log.Printf("Failed to read agent file %s: %v", p, err)
+			return nil
+		}
+		files[d.Name()] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy agents: %w", err)
+	}
+	return encodeAgentBundle(files), nil
+}
+
+// encodeAgentBundle serializes agent files deterministically (sorted by name, each as
+// name length, name, content length, content) so identical sources hash identically
+func encodeAgentBundle(files map[string][]byte) []byte {
+	names := make([]string, 0, len(files))
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for _, n := range names {
+		_ = binary.Write(&buf, binary.BigEndian, uint32(len(n)))
+		buf.WriteString(n)
+		_ = binary.Write(&buf, binary.BigEndian, uint64(len(files[n])))
+		buf.Write(files[n])
+	}
+	return buf.Bytes()
+}
+
+// decodeAgentBundle is the inverse of encodeAgentBundle
+func decodeAgentBundle(data []byte) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	r := bytes.NewReader(data)
+	for r.Len() > 0 {
+		var nameLen uint32
+		if err := binary.Read(r, binary.BigEndian, &nameLen); err != nil {
+			return nil, fmt.Errorf("corrupt agent bundle: %w", err)
+		}
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, fmt.Errorf("corrupt agent bundle: %w", err)
+		}
+		var size uint64
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, fmt.Errorf("corrupt agent bundle: %w", err)
+		}
+		if size > uint64(r.Len()) {
+			return nil, fmt.Errorf("corrupt agent bundle: truncated %s", name)
+		}
+		content := make([]byte, size)
+		if _, err := io.ReadFull(r, content); err != nil {
+			return nil, fmt.Errorf("corrupt agent bundle: %w", err)
+		}
+		files[string(name)] = content
+	}
+	return files, nil
+}
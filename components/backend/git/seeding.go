@@ -0,0 +1,599 @@
+package git
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SeedSource identifies the spec-kit release and agent source a repo is seeded from
+type SeedSource struct {
+	SpecKitRepo       string `json:"specKitRepo"`
+	SpecKitVersion    string `json:"specKitVersion"`
+	SpecKitTemplate   string `json:"specKitTemplate"`
+	AgentSourceURL    string `json:"agentSourceUrl"`
+	AgentSourceBranch string `json:"agentSourceBranch"`
+	AgentSourcePath   string `json:"agentSourcePath"`
+	// Optional SHA-256 pins of the spec-kit archive and agent bundle; fetched content must match.
+	// Results report the checksums of the content that was used.
+	SpecKitSHA256     string `json:"specKitSha256,omitempty"`
+	AgentSourceSHA256 string `json:"agentSourceSha256,omitempty"`
+}
+
+// Seed file origins
+const (
+	SeedOriginSpecKit = "spec-kit"
+	SeedOriginAgents  = "agents"
+)
+
+// Seed file actions. Seeding only adds missing spec-kit files and refreshes agents; drift and
+// upgrades compare every managed file.
+const (
+	SeedActionCreate    = "create"    // missing on the branch
+	SeedActionUpdate    = "update"    // differs from the source
+	SeedActionUnchanged = "unchanged" // matches the source
+	SeedActionKeep      = "keep"      // differs, but the branch copy is kept
+	SeedActionExtra     = "extra"     // under .claude/ or .specify/ but not in the source
+)
+
+// SeedFileChange is one managed file in a seeding plan, drift report or upgrade
+type SeedFileChange struct {
+	Path   string `json:"path"`
+	Origin string `json:"origin,omitempty"`
+	Action string `json:"action"`
+}
+
+// SeedPlan is the dry-run result of seeding a workflow
+type SeedPlan struct {
+	Branch       string           `json:"branch"`
+	BaseBranch   string           `json:"baseBranch"`
+	BranchExists bool             `json:"branchExists"`
+	Source       SeedSource       `json:"source"`
+	Files        []SeedFileChange `json:"files"`
+	Counts       map[string]int   `json:"counts"`
+	// SupportingRepos lists supporting repos and whether the feature branch would be created
+	SupportingRepos []SeedRepoBranch `json:"supportingRepos,omitempty"`
+}
+
+// SeedRepoBranch reports the feature branch state of a supporting repo
+type SeedRepoBranch struct {
+	URL          string `json:"url"`
+	BranchExists bool   `json:"branchExists"`
+	Error        string `json:"error,omitempty"`
+}
+
+// SeedDrift compares a branch's .claude/ and .specify/ files with a seed source
+type SeedDrift struct {
+	Branch string           `json:"branch"`
+	Source SeedSource       `json:"source"`
+	InSync bool             `json:"inSync"`
+	Files  []SeedFileChange `json:"files"`
+	Counts map[string]int   `json:"counts"`
+}
+
+// SeedUpgrade is the result of applying a seed source to an already seeded branch
+type SeedUpgrade struct {
+	Mode              string           `json:"mode"` // pr | commit
+	Branch            string           `json:"branch"`
+	UpgradeBranch     string           `json:"upgradeBranch,omitempty"`
+	Commit            string           `json:"commit,omitempty"`
+	PullRequestURL    string           `json:"pullRequestUrl,omitempty"`
+	PullRequestNumber int              `json:"pullRequestNumber,omitempty"`
+	Files             []SeedFileChange `json:"files"`
+	Source            SeedSource       `json:"source"`
+}
+
+// Seed upgrade modes
+const (
+	SeedUpgradePR     = "pr"
+	SeedUpgradeCommit = "commit"
+)
+
+// seedFile is a file the seed source places in the repo
+type seedFile struct {
+	Content []byte
+	Mode    fs.FileMode
+	Origin  string
+}
+
+// managedSeedPrefixes are the directories seeding owns
+var managedSeedPrefixes = []string{".claude/", ".specify/"}
+
+// isProjectOwnedSeedPath reports files that seed templates only provide a starting point for
+// (e.g. the constitution); upgrades never overwrite them
+func isProjectOwnedSeedPath(path string) bool {
+	return strings.HasPrefix(path, ".specify/memory/")
+}
+
+// specKitTargetPath maps a spec-kit archive entry to its path in the repo, matching the official
+// release template layout; ok is false for entries umbrella repos don't need
+func specKitTargetPath(name, specKitVersion string) (string, bool) {
+	rel := strings.TrimPrefix(name, "./")
+	rel = strings.ReplaceAll(rel, "\\", "/")
+
+	// Strip archive prefix from branch downloads (e.g., "spec-kit-rh-vteam-flexible-branches/")
+	// Branch archives have format: "repo-branch-name/file", releases have just "file"
+	if strings.Contains(rel, "/") && !strings.HasPrefix(specKitVersion, "v") {
+		parts := strings.SplitN(rel, "/", 2)
+		if len(parts) == 2 {
+			rel = parts[1]
+		}
+	}
+
+	// - templates/commands/ → .claude/commands/
+	// - scripts/bash/ → .specify/scripts/bash/
+	// - templates/*.md → .specify/templates/
+	// - memory/ → .specify/memory/
+	// Skip everything else (docs/, media/, root files, .github/, scripts/powershell/, etc.)
+	var targetRel string
+	switch {
+	case strings.HasPrefix(rel, "templates/commands/"):
+		cmdFile := strings.TrimPrefix(rel, "templates/commands/")
+		if !strings.HasPrefix(cmdFile, "speckit.") {
+			cmdFile = "speckit." + cmdFile
+		}
+		targetRel = ".claude/commands/" + cmdFile
+	case strings.HasPrefix(rel, "scripts/bash/"):
+		targetRel = strings.Replace(rel, "scripts/bash/", ".specify/scripts/bash/", 1)
+	case strings.HasPrefix(rel, "templates/") && strings.HasSuffix(rel, ".md"):
+		targetRel = strings.Replace(rel, "templates/", ".specify/templates/", 1)
+	case strings.HasPrefix(rel, "memory/"):
+		targetRel = ".specify/" + rel
+	default:
+		return "", false
+	}
+
+	// Security: prevent path traversal
+	for strings.Contains(targetRel, "../") {
+		targetRel = strings.ReplaceAll(targetRel, "../", "")
+	}
+	return targetRel, true
+}
+
+// specKitURL returns the download URL of a spec-kit release (vX.Y.Z) or branch archive
+func specKitURL(repo, version, template string) string {
+	if strings.HasPrefix(version, "v") {
+		return fmt.Sprintf("https://github.com/%s/releases/download/%s/%s-%s.zip", repo, version, template, version)
+	}
+	return fmt.Sprintf("https://github.com/%s/archive/refs/heads/%s.zip", repo, version)
+}
+
+// fetchSpecKitFiles returns the files spec-kit places in an umbrella repo and the SHA-256 of the
+// archive they came from (see fetchSpecKitArchive for caching and verification)
+func fetchSpecKitFiles(ctx context.Context, src SeedSource) (map[string]seedFile, string, error) {
+	zipData, digest, err := fetchSpecKitArchive(ctx, src)
+	if err != nil {
+		return nil, "", err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open spec-kit zip: %w", err)
+	}
+
+	files := map[string]seedFile{}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		targetRel, ok := specKitTargetPath(f.Name, src.SpecKitVersion)
+		if !ok {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			log.Printf("Failed to open zip entry %s: %v", f.Name, err)
+			continue
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			log.Printf("Failed to read zip entry %s: %v", f.Name, err)
+			continue
+		}
+		// Scripts need to be executable; otherwise preserve the executable bit from the zip
+		mode := fs.FileMode(0644)
+		if strings.HasPrefix(targetRel, ".specify/scripts/") || f.Mode().Perm()&0111 != 0 {
+			mode = 0755
+		}
+		files[targetRel] = seedFile{Content: content, Mode: mode, Origin: SeedOriginSpecKit}
+	}
+	return files, digest, nil
+}
+
+// fetchAgentFiles returns the agent source's markdown agents as .claude/agents/ files and the
+// SHA-256 of their bundle (see fetchAgentBundle)
+func fetchAgentFiles(ctx context.Context, src SeedSource) (map[string]seedFile, string, error) {
+	bundle, digest, err := fetchAgentBundle(ctx, src)
+	if err != nil {
+		return nil, "", err
+	}
+	agents, err := decodeAgentBundle(bundle)
+	if err != nil {
+		return nil, "", err
+	}
+	files := map[string]seedFile{}
+	for name, content := range agents {
+		files[".claude/agents/"+name] = seedFile{Content: content, Mode: 0644, Origin: SeedOriginAgents}
+	}
+	return files, digest, nil
+}
+
+// fetchSeedFiles returns every file the seed source places in the repo, and src with the
+// checksums of the content actually used
+func fetchSeedFiles(ctx context.Context, src SeedSource) (map[string]seedFile, SeedSource, error) {
+	files, specKitDigest, err := fetchSpecKitFiles(ctx, src)
+	if err != nil {
+		return nil, src, err
+	}
+	agents, agentDigest, err := fetchAgentFiles(ctx, src)
+	if err != nil {
+		return nil, src, err
+	}
+	for p, f := range agents {
+		files[p] = f
+	}
+	src.SpecKitSHA256 = specKitDigest
+	src.AgentSourceSHA256 = agentDigest
+	return files, src, nil
+}
+
+// gitBlobSHA returns the git object ID of content, as listed by the trees API
+func gitBlobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fetchGitTree returns blob SHAs by path for ref; exists is false when ref doesn't exist
+func fetchGitTree(ctx context.Context, owner, repo, ref, token string) (map[string]string, bool, error) {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees/%s?recursive=1", owner, repo, ref)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusConflict {
+		// 409 is returned for empty repositories
+		return map[string]string{}, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, false, fmt.Errorf("GitHub API error: %s (body: %s)", resp.Status, string(body))
+	}
+
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		return nil, false, fmt.Errorf("failed to parse git tree: %w", err)
+	}
+	if tree.Truncated {
+		log.Printf("fetchGitTree: tree of %s/%s@%s truncated; comparison may be incomplete", owner, repo, ref)
+	}
+	blobs := make(map[string]string, len(tree.Tree))
+	for _, e := range tree.Tree {
+		if e.Type == "blob" {
+			blobs[e.Path] = e.SHA
+		}
+	}
+	return blobs, true, nil
+}
+
+// compareSeedFiles classifies each desired file against the branch tree. For initial seeding
+// (seeding=true) existing spec-kit files are kept, mirroring PerformRepoSeeding; otherwise
+// differing files are updates unless project-owned. Managed files that aren't in the source are
+// reported as extra when includeExtra is set.
+func compareSeedFiles(desired map[string]seedFile, tree map[string]string, seeding, includeExtra bool) ([]SeedFileChange, map[string]int) {
+	changes := make([]SeedFileChange, 0, len(desired))
+	for path, f := range desired {
+		action := SeedActionCreate
+		if sha, ok := tree[path]; ok {
+			switch {
+			case sha == gitBlobSHA(f.Content):
+				action = SeedActionUnchanged
+			case seeding && f.Origin == SeedOriginSpecKit, isProjectOwnedSeedPath(path):
+				action = SeedActionKeep
+			default:
+				action = SeedActionUpdate
+			}
+		}
+		changes = append(changes, SeedFileChange{Path: path, Origin: f.Origin, Action: action})
+	}
+	if includeExtra {
+		for path := range tree {
+			if _, ok := desired[path]; ok {
+				continue
+			}
+			for _, prefix := range managedSeedPrefixes {
+				if strings.HasPrefix(path, prefix) {
+					changes = append(changes, SeedFileChange{Path: path, Action: SeedActionExtra})
+					break
+				}
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	counts := map[string]int{}
+	for _, ch := range changes {
+		counts[ch.Action]++
+	}
+	return changes, counts
+}
+
+// PlanRepoSeeding returns what PerformRepoSeeding would change without cloning or pushing:
+// the files it would add or refresh on the feature branch (or on the base branch it would be
+// created from) and the supporting repos that would get the feature branch
+func PlanRepoSeeding(ctx context.Context, wf Workflow, branchName, githubToken string, src SeedSource) (*SeedPlan, error) {
+	umbrellaRepo := wf.GetUmbrellaRepo()
+	if umbrellaRepo == nil {
+		return nil, fmt.Errorf("workflow has no spec repo")
+	}
+	if branchName == "" {
+		return nil, fmt.Errorf("branchName is required")
+	}
+	owner, repo, err := ParseGitHubURL(umbrellaRepo.GetURL())
+	if err != nil {
+		return nil, err
+	}
+	baseBranch := "main"
+	if branch := umbrellaRepo.GetBranch(); branch != nil && strings.TrimSpace(*branch) != "" {
+		baseBranch = strings.TrimSpace(*branch)
+	}
+
+	tree, branchExists, err := fetchGitTree(ctx, owner, repo, branchName, githubToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read branch %s: %w", branchName, err)
+	}
+	if !branchExists {
+		var baseExists bool
+		tree, baseExists, err = fetchGitTree(ctx, owner, repo, baseBranch, githubToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read base branch %s: %w", baseBranch, err)
+		}
+		if !baseExists {
+			return nil, fmt.Errorf("base branch '%s' does not exist in repository. Please ensure the base branch exists before seeding", baseBranch)
+		}
+	}
+
+	desired, src, err := fetchSeedFiles(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	files, counts := compareSeedFiles(desired, tree, true, false)
+	plan := &SeedPlan{
+		Branch:       branchName,
+		BaseBranch:   baseBranch,
+		BranchExists: branchExists,
+		Source:       src,
+		Files:        files,
+		Counts:       counts,
+	}
+	for _, r := range wf.GetSupportingRepos() {
+		exists, err := CheckBranchExists(ctx, r.GetURL(), branchName, githubToken)
+		rb := SeedRepoBranch{URL: r.GetURL(), BranchExists: exists}
+		if err != nil {
+			rb.Error = err.Error()
+		}
+		plan.SupportingRepos = append(plan.SupportingRepos, rb)
+	}
+	return plan, nil
+}
+
+// DetectSeedDrift compares the branch's .claude/ and .specify/ files with what src would seed
+func DetectSeedDrift(ctx context.Context, repoURL, branch, githubToken string, src SeedSource) (*SeedDrift, error) {
+	owner, repo, err := ParseGitHubURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	tree, exists, err := fetchGitTree(ctx, owner, repo, branch, githubToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read branch %s: %w", branch, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("branch %s does not exist; seed the workflow first", branch)
+	}
+	desired, src, err := fetchSeedFiles(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	files, counts := compareSeedFiles(desired, tree, false, true)
+	return &SeedDrift{
+		Branch: branch,
+		Source: src,
+		InSync: counts[SeedActionCreate] == 0 && counts[SeedActionUpdate] == 0,
+		Files:  files,
+		Counts: counts,
+	}, nil
+}
+
+// UpgradeRepoSeeding applies src's missing and updated files to an already seeded branch as
+// one commit. In pr mode the commit goes to a new branch with a pull request into branch so the
+// update can be reviewed; in commit mode it is pushed onto branch directly. Extra and
+// project-owned files are never touched.
+func UpgradeRepoSeeding(ctx context.Context, repoURL, branch, githubToken string, src SeedSource, mode string) (*SeedUpgrade, error) {
+	if mode == "" {
+		mode = SeedUpgradePR
+	}
+	if mode != SeedUpgradePR && mode != SeedUpgradeCommit {
+		return nil, fmt.Errorf("invalid upgrade mode %q (expected %s or %s)", mode, SeedUpgradePR, SeedUpgradeCommit)
+	}
+	owner, repo, err := ParseGitHubURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	tree, exists, err := fetchGitTree(ctx, owner, repo, branch, githubToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read branch %s: %w", branch, err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("branch %s does not exist; seed the workflow first", branch)
+	}
+	desired, src, err := fetchSeedFiles(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	all, _ := compareSeedFiles(desired, tree, false, false)
+	result := &SeedUpgrade{Mode: mode, Branch: branch, Files: []SeedFileChange{}, Source: src}
+	for _, ch := range all {
+		if ch.Action == SeedActionCreate || ch.Action == SeedActionUpdate {
+			result.Files = append(result.Files, ch)
+		}
+	}
+	if len(result.Files) == 0 {
+		return result, nil
+	}
+
+	if err := validatePushAccess(ctx, repoURL, githubToken); err != nil {
+		return nil, fmt.Errorf("spec repo access validation failed: %w", err)
+	}
+	repoDir, err := os.MkdirTemp("", "seed-upgrade-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(repoDir)
+	authenticatedURL, err := InjectGitHubToken(repoURL, githubToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare spec repo URL: %w", err)
+	}
+	run := func(args ...string) (string, error) {
+		out, err := exec.CommandContext(ctx, "git", append([]string{"-C", repoDir}, args...)...).CombinedOutput()
+		if err != nil {
+			return string(out), fmt.Errorf("git %s failed: %w (output: %s)", args[0], err, string(out))
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	if out, err := exec.CommandContext(ctx, "git", "clone", "--depth", "1", "--branch", branch, authenticatedURL, repoDir).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to clone branch '%s': %w (output: %s)", branch, err, string(out))
+	}
+	if _, err := run("config", "user.email", "vteam-bot@ambient-code.io"); err != nil {
+		log.Printf("Warning: failed to set git user.email: %v", err)
+	}
+	if _, err := run("config", "user.name", "vTeam Bot"); err != nil {
+		log.Printf("Warning: failed to set git user.name: %v", err)
+	}
+
+	pushBranch := branch
+	if mode == SeedUpgradePR {
+		pushBranch = fmt.Sprintf("%s-seed-upgrade-%d", branch, time.Now().Unix())
+		if _, err := run("checkout", "-b", pushBranch); err != nil {
+			return nil, err
+		}
+		result.UpgradeBranch = pushBranch
+	}
+
+	for _, ch := range result.Files {
+		f := desired[ch.Path]
+		target := filepath.Join(repoDir, ch.Path)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create dir for %s: %w", ch.Path, err)
+		}
+		if err := os.WriteFile(target, f.Content, f.Mode); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", ch.Path, err)
+		}
+	}
+	if _, err := run("add", "-A", ".claude", ".specify"); err != nil {
+		return nil, err
+	}
+	title := seedUpgradeTitle(src)
+	if _, err := run("commit", "-m", title, "-m", seedUpgradeBody(src, result.Files)); err != nil {
+		return nil, err
+	}
+	if result.Commit, err = run("rev-parse", "HEAD"); err != nil {
+		return nil, err
+	}
+	if _, err := run("push", "origin", "HEAD:refs/heads/"+pushBranch); err != nil {
+		return nil, err
+	}
+	log.Printf("UpgradeRepoSeeding: pushed %d seed updates to %s/%s@%s", len(result.Files), owner, repo, pushBranch)
+
+	if mode == SeedUpgradePR {
+		number, htmlURL, err := createPullRequest(ctx, owner, repo, pushBranch, branch, title, seedUpgradeBody(src, result.Files), githubToken)
+		if err != nil {
+			return result, fmt.Errorf("pushed %s but failed to open pull request: %w", pushBranch, err)
+		}
+		result.PullRequestNumber = number
+		result.PullRequestURL = htmlURL
+	}
+	return result, nil
+}
+
+func seedUpgradeTitle(src SeedSource) string {
+	return fmt.Sprintf("chore: update spec-kit to %s and agents from %s", src.SpecKitVersion, src.AgentSourceBranch)
+}
+
+func seedUpgradeBody(src SeedSource, files []SeedFileChange) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Spec-kit: %s@%s (%s)\n", src.SpecKitRepo, src.SpecKitVersion, src.SpecKitTemplate)
+	fmt.Fprintf(&sb, "Agents: %s@%s/%s\n\n", src.AgentSourceURL, src.AgentSourceBranch, src.AgentSourcePath)
+	for _, f := range files {
+		fmt.Fprintf(&sb, "- %s %s\n", f.Action, f.Path)
+	}
+	return sb.String()
+}
+
+// createPullRequest opens a pull request from head into base
+func createPullRequest(ctx context.Context, owner, repo, head, base, title, body, token string) (int, string, error) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"title": title,
+		"head":  head,
+		"base":  base,
+		"body":  body,
+	})
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls", owner, repo)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		b, _ := io.ReadAll(resp.Body)
+		return 0, "", fmt.Errorf("GitHub API error: %s (body: %s)", resp.Status, string(b))
+	}
+	var pr struct {
+		Number  int    `json:"number"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return 0, "", fmt.Errorf("failed to parse pull request: %w", err)
+	}
+	return pr.Number, pr.HTMLURL, nil
+}
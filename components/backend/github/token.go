@@ -10,8 +10,10 @@ import (
 	"encoding/pem"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -24,7 +26,8 @@ type TokenManager struct {
 	AppID      string
 	PrivateKey *rsa.PrivateKey
 	cacheMu    *sync.Mutex
-	cache      map[int64]cachedInstallationToken
+	cache      map[string]cachedInstallationToken
+	accounts   map[string]string // host|installation id -> account login
 }
 
 type cachedInstallationToken struct {
@@ -63,7 +66,8 @@ func NewTokenManager() (*TokenManager, error) {
 		AppID:      appID,
 		PrivateKey: privateKey,
 		cacheMu:    &sync.Mutex{},
-		cache:      map[int64]cachedInstallationToken{},
+		cache:      map[string]cachedInstallationToken{},
+		accounts:   map[string]string{},
 	}, nil
 }
 
@@ -114,12 +118,35 @@ func (m *TokenManager) MintInstallationToken(ctx context.Context, installationID
 
 // MintInstallationTokenForHost mints an installation token against the specified GitHub API host
 func (m *TokenManager) MintInstallationTokenForHost(ctx context.Context, installationID int64, host string) (string, time.Time, error) {
+	return m.MintScopedInstallationToken(ctx, installationID, host, nil, nil)
+}
+
+// MintScopedInstallationToken mints an installation token restricted to the given owner/name
+// repositories and permissions (GitHub's `repositories` / `permissions` request fields).
+// Repositories of other owners than the installation's account are dropped, since GitHub only
+// scopes tokens to the account's own repositories; when none remain minting fails rather than
+// returning a token for every repository. Empty repositories/permissions inherit the
+// installation's full access.
+func (m *TokenManager) MintScopedInstallationToken(ctx context.Context, installationID int64, host string, repositories []string, permissions map[string]string) (string, time.Time, error) {
 	if m == nil {
 		return "", time.Time{}, fmt.Errorf("GitHub App not configured")
 	}
+	var repos []string
+	if len(repositories) > 0 {
+		account, err := m.installationAccount(ctx, installationID, host)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		repos, err = normalizeRepositoryNames(repositories, account)
+		if err != nil {
+			return "", time.Time{}, err
+		}
+	}
+	key := tokenCacheKey(installationID, host, repos, permissions)
+
 	// Serve from cache if still valid (>3 minutes left)
 	m.cacheMu.Lock()
-	if entry, ok := m.cache[installationID]; ok {
+	if entry, ok := m.cache[key]; ok {
 		if time.Until(entry.expiresAt) > 3*time.Minute {
 			token := entry.token
 			exp := entry.expiresAt
@@ -134,10 +161,21 @@ func (m *TokenManager) MintInstallationTokenForHost(ctx context.Context, install
 		return "", time.Time{}, fmt.Errorf("failed to generate JWT: %w", err)
 	}
 
+	payload := map[string]interface{}{}
+	if len(repos) > 0 {
+		payload["repositories"] = repos
+	}
+	if len(permissions) > 0 {
+		payload["permissions"] = permissions
+	}
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode token request: %w", err)
+	}
+
 	apiBase := APIBaseURL(host)
 	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", apiBase, installationID)
-	reqBody := bytes.NewBuffer([]byte("{}"))
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -164,11 +202,107 @@ func (m *TokenManager) MintInstallationTokenForHost(ctx context.Context, install
 		return "", time.Time{}, fmt.Errorf("failed to parse token response: %w", err)
 	}
 	m.cacheMu.Lock()
-	m.cache[installationID] = cachedInstallationToken{token: parsed.Token, expiresAt: parsed.ExpiresAt}
+	m.cache[key] = cachedInstallationToken{token: parsed.Token, expiresAt: parsed.ExpiresAt}
 	m.cacheMu.Unlock()
 	return parsed.Token, parsed.ExpiresAt, nil
 }
 
+// normalizeRepositoryNames reduces owner/name entries to the sorted, unique names of the
+// repositories owned by account, the installation's user or organization
+func normalizeRepositoryNames(repositories []string, account string) ([]string, error) {
+	seen := map[string]bool{}
+	out := []string{}
+	var skipped []string
+	for _, r := range repositories {
+		r = strings.TrimSuffix(strings.TrimSpace(r), ".git")
+		owner, name, ok := strings.Cut(r, "/")
+		if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid repository %q: expected owner/name", r)
+		}
+		if !strings.EqualFold(owner, account) {
+			skipped = append(skipped, r)
+			continue
+		}
+		if seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		out = append(out, name)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("none of the repositories %s belong to installation account %s", strings.Join(skipped, ", "), account)
+	}
+	if len(skipped) > 0 {
+		log.Printf("GitHub token for %s excludes repositories of other owners: %s", account, strings.Join(skipped, ", "))
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// installationAccount returns the login of the user or organization an installation belongs to
+func (m *TokenManager) installationAccount(ctx context.Context, installationID int64, host string) (string, error) {
+	key := fmt.Sprintf("%s|%d", host, installationID)
+	m.cacheMu.Lock()
+	account, ok := m.accounts[key]
+	m.cacheMu.Unlock()
+	if ok {
+		return account, nil
+	}
+
+	jwtToken, err := m.GenerateJWT()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate JWT: %w", err)
+	}
+	url := fmt.Sprintf("%s/app/installations/%d", APIBaseURL(host), installationID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+jwtToken)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("User-Agent", "vTeam-Backend")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitHub installation lookup failed: %s", string(body))
+	}
+	var parsed struct {
+		Account struct {
+			Login string `json:"login"`
+		} `json:"account"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("failed to parse installation response: %w", err)
+	}
+	if parsed.Account.Login == "" {
+		return "", fmt.Errorf("installation %d has no account", installationID)
+	}
+	m.cacheMu.Lock()
+	m.accounts[key] = parsed.Account.Login
+	m.cacheMu.Unlock()
+	return parsed.Account.Login, nil
+}
+
+// tokenCacheKey identifies a token by installation, host and requested scope
+func tokenCacheKey(installationID int64, host string, repositories []string, permissions map[string]string) string {
+	if host == "" {
+		host = "github.com"
+	}
+	perms := make([]string, 0, len(permissions))
+	for k, v := range permissions {
+		perms = append(perms, k+"="+v)
+	}
+	sort.Strings(perms)
+	return fmt.Sprintf("%s|%d|%s|%s", host, installationID, strings.ToLower(strings.Join(repositories, ",")), strings.Join(perms, ","))
+}
+
 // ValidateInstallationAccess checks if the installation has access to a repository
 func (m *TokenManager) ValidateInstallationAccess(ctx context.Context, installationID int64, repo string) error {
 	if m == nil {
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Access key lifetimes. Tokens are minted with TokenRequest, which rejects expirations under
+// 10 minutes; the API server may also cap the expiry (--service-account-max-token-expiration),
+// so expiresAt always records the expiry the server actually granted.
+const (
+	minAccessKeyTTL     = 10 * time.Minute
+	defaultAccessKeyTTL = 90 * 24 * time.Hour
+	maxAccessKeyTTL     = 365 * 24 * time.Hour
+)
+
+// Annotations on access key ServiceAccounts
+const (
+	annKeyExpiresAt   = "ambient-code.io/expires-at"
+	annKeyTTL         = "ambient-code.io/ttl"
+	annKeyTokenSecret = "ambient-code.io/token-secret"
+	annKeyRotatedAt   = "ambient-code.io/rotated-at"
+	annKeyScopes      = "ambient-code.io/scopes"
+)
+
+var (
+	vteamAPIGroup = []string{"vteam.ambient-code"}
+	readVerbs     = []string{"get", "list", "watch"}
+	writeVerbs    = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+)
+
+// accessKeyScopes maps each scope to the rules of the Role generated for scoped keys
+var accessKeyScopes = map[string][]rbacv1.PolicyRule{
+	"sessions:read": {
+		{APIGroups: vteamAPIGroup, Resources: []string{"agenticsessions", "agenticsessions/status"}, Verbs: readVerbs},
+	},
+	"sessions:write": {
+		{APIGroups: vteamAPIGroup, Resources: []string{"agenticsessions"}, Verbs: writeVerbs},
+		{APIGroups: vteamAPIGroup, Resources: []string{"agenticsessions/status"}, Verbs: []string{"get", "update", "patch"}},
+	},
+	"rfe:read": {
+		{APIGroups: vteamAPIGroup, Resources: []string{"rfeworkflows", "rfeworkflows/status"}, Verbs: readVerbs},
+	},
+	"rfe:write": {
+		{APIGroups: vteamAPIGroup, Resources: []string{"rfeworkflows"}, Verbs: writeVerbs},
+		{APIGroups: vteamAPIGroup, Resources: []string{"rfeworkflows/status"}, Verbs: []string{"get", "update", "patch"}},
+	},
+	"secrets:read": {
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}},
+	},
+	"secrets:write": {
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list", "create", "update", "patch", "delete"}},
+	},
+}
+
+// projectAccessRule lets every scoped key pass the project access check (list agenticsessions)
+var projectAccessRule = rbacv1.PolicyRule{APIGroups: vteamAPIGroup, Resources: []string{"agenticsessions"}, Verbs: []string{"get", "list"}}
+
+// normalizeAccessKeyScopes validates and de-duplicates scopes, returning them sorted
+func normalizeAccessKeyScopes(scopes []string) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range scopes {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		if _, ok := accessKeyScopes[s]; !ok {
+			return nil, fmt.Errorf("unknown scope %q (expected one of: %s)", s, strings.Join(accessKeyScopeNames(), ", "))
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func accessKeyScopeNames() []string {
+	names := make([]string, 0, len(accessKeyScopes))
+	for s := range accessKeyScopes {
+		names = append(names, s)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// accessKeyScopeRules returns the Role rules granting scopes
+func accessKeyScopeRules(scopes []string) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{projectAccessRule}
+	for _, s := range scopes {
+		rules = append(rules, accessKeyScopes[s]...)
+	}
+	return rules
+}
+
+// missingAccessKeyPermissions asks the API server, for every verb and resource in rules, whether
+// the caller holds it in the project, returning those it does not as verb group/resource
+func missingAccessKeyPermissions(ctx context.Context, k8s kubernetes.Interface, project string, rules []rbacv1.PolicyRule) ([]string, error) {
+	var missing []string
+	seen := map[string]bool{}
+	for _, rule := range rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				for _, verb := range rule.Verbs {
+					name := verb + " " + resource
+					if group != "" {
+						name = verb + " " + group + "/" + resource
+					}
+					if seen[name] {
+						continue
+					}
+					seen[name] = true
+					res, sub, _ := strings.Cut(resource, "/")
+					ssar := &authv1.SelfSubjectAccessReview{
+						Spec: authv1.SelfSubjectAccessReviewSpec{
+							ResourceAttributes: &authv1.ResourceAttributes{
+								Group:       group,
+								Resource:    res,
+								Subresource: sub,
+								Verb:        verb,
+								Namespace:   project,
+							},
+						},
+					}
+					review, err := k8s.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, v1.CreateOptions{})
+					if err != nil {
+						return nil, err
+					}
+					if !review.Status.Allowed {
+						missing = append(missing, name)
+					}
+				}
+			}
+		}
+	}
+	return missing, nil
+}
+
+// parseAccessKeyTTL parses a requested key lifetime (Go duration such as "720h"); empty uses
+// ACCESS_KEY_DEFAULT_TTL or 90 days
+func parseAccessKeyTTL(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		raw = strings.TrimSpace(os.Getenv("ACCESS_KEY_DEFAULT_TTL"))
+	}
+	if raw == "" {
+		return defaultAccessKeyTTL, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ttl %q: %w", raw, err)
+	}
+	if ttl < minAccessKeyTTL || ttl > maxAccessKeyTTL {
+		return 0, fmt.Errorf("ttl must be between %s and %s", minAccessKeyTTL, maxAccessKeyTTL)
+	}
+	return ttl, nil
+}
+
+// saOwnerReference makes objects belonging to an access key go away with its ServiceAccount
+func saOwnerReference(sa *corev1.ServiceAccount) v1.OwnerReference {
+	return v1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ServiceAccount",
+		Name:       sa.Name,
+		UID:        sa.UID,
+	}
+}
+
+// issueAccessKeyToken mints a token for the key's ServiceAccount bound to a new Secret. Deleting
+// that Secret revokes the token, which is how rotation invalidates the previous one.
+func issueAccessKeyToken(ctx context.Context, k8s kubernetes.Interface, sa *corev1.ServiceAccount, ttl time.Duration) (string, *corev1.Secret, time.Time, error) {
+	secret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			GenerateName:    sa.Name + "-token-",
+			Namespace:       sa.Namespace,
+			Labels:          map[string]string{"app": "ambient-access-key"},
+			Annotations:     map[string]string{"ambient-code.io/sa-name": sa.Name},
+			OwnerReferences: []v1.OwnerReference{saOwnerReference(sa)},
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	created, err := k8s.CoreV1().Secrets(sa.Namespace).Create(ctx, secret, v1.CreateOptions{})
+	if err != nil {
+		return "", nil, time.Time{}, fmt.Errorf("create token binding secret: %w", err)
+	}
+
+	seconds := int64(ttl / time.Second)
+	tr := &authnv1.TokenRequest{Spec: authnv1.TokenRequestSpec{
+		ExpirationSeconds: &seconds,
+		BoundObjectRef: &authnv1.BoundObjectReference{
+			Kind:       "Secret",
+			APIVersion: "v1",
+			Name:       created.Name,
+			UID:        created.UID,
+		},
+	}}
+	tok, err := k8s.CoreV1().ServiceAccounts(sa.Namespace).CreateToken(ctx, sa.Name, tr, v1.CreateOptions{})
+	if err != nil {
+		_ = k8s.CoreV1().Secrets(sa.Namespace).Delete(ctx, created.Name, v1.DeleteOptions{})
+		return "", nil, time.Time{}, fmt.Errorf("mint token: %w", err)
+	}
+	return tok.Status.Token, created, tok.Status.ExpirationTimestamp.Time.UTC(), nil
+}
+
+// revokeAccessKeyToken deletes one of the key's token binding Secrets, invalidating the token
+// bound to it. Editors may create Secrets but not delete them, so the Secret is deleted with the
+// backend SA once the caller is confirmed to manage the key; it must be a binding Secret of this
+// key, so an edited token-secret annotation can't name any other Secret.
+func revokeAccessKeyToken(ctx context.Context, reqK8s kubernetes.Interface, sa *corev1.ServiceAccount, secretName string) error {
+	ssar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Resource:  "serviceaccounts",
+				Verb:      "update",
+				Namespace: sa.Namespace,
+				Name:      sa.Name,
+			},
+		},
+	}
+	review, err := reqK8s.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, ssar, v1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("access review: %w", err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("not allowed to manage access key %s", sa.Name)
+	}
+	if K8sClient == nil {
+		return fmt.Errorf("backend client not initialized")
+	}
+
+	secret, err := K8sClient.CoreV1().Secrets(sa.Namespace).Get(ctx, secretName, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("get token secret: %w", err)
+	}
+	owned := false
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == sa.UID {
+			owned = true
+		}
+	}
+	if !owned || secret.Labels["app"] != "ambient-access-key" || secret.Annotations["ambient-code.io/sa-name"] != sa.Name {
+		return fmt.Errorf("secret %s is not a token secret of access key %s", secretName, sa.Name)
+	}
+	uid := secret.UID
+	err = K8sClient.CoreV1().Secrets(sa.Namespace).Delete(ctx, secretName, v1.DeleteOptions{Preconditions: &v1.Preconditions{UID: &uid}})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("delete token secret: %w", err)
+	}
+	return nil
+}
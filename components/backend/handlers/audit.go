@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"ambient-code-backend/audit"
+
+	"github.com/gin-gonic/gin"
+	authv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AuditLogger receives audit events; injected from main package. Nil disables auditing.
+var AuditLogger *audit.Logger
+
+const auditContextKey = "auditInfo"
+
+// auditInfo is what a handler declares about its action via auditAction
+type auditInfo struct {
+	action  string
+	kind    string
+	name    string
+	project string
+	details map[string]string
+}
+
+// auditSkipRoutes are mutating routes driven by machines at high volume or authenticated
+// outside of Kubernetes RBAC; they keep their own logging
+var auditSkipRoutes = map[string]bool{
+	"/api/webhooks/github": true,
+	"/api/webhooks/jira":   true,
+	"/api/projects/:projectName/agentic-sessions/:sessionName/status": true,
+	"/api/projects/:projectName/sessions/:sessionId/messages":         true,
+}
+
+// auditKinds maps the first route segment to the target kind recorded for derived actions
+var auditKinds = map[string]string{
+	"projects":         "project",
+	"agentic-sessions": "session",
+	"sessions":         "session",
+	"rfe-workflows":    "rfe-workflow",
+	"permissions":      "permission",
+	"keys":             "access-key",
+	"users":            "user",
+}
+
+// auditAction names the action a handler performs and the target it applies to. AuditMiddleware
+// records it with the request's outcome once the handler returns; without it, mutating requests
+// are recorded under an action derived from the route.
+func auditAction(c *gin.Context, action, targetKind, targetName string, details map[string]string) {
+	info := &auditInfo{action: action, kind: targetKind, name: targetName, details: details}
+	if prev, ok := c.Get(auditContextKey); ok {
+		if p, ok := prev.(*auditInfo); ok {
+			info.project = p.project
+			for k, v := range p.details {
+				if info.details == nil {
+					info.details = map[string]string{}
+				}
+				if _, set := info.details[k]; !set {
+					info.details[k] = v
+				}
+			}
+		}
+	}
+	c.Set(auditContextKey, info)
+}
+
+// auditDetail adds a detail to the request's audit event, e.g. once a handler has resolved it
+func auditDetail(c *gin.Context, key, value string) {
+	info := currentAuditInfo(c)
+	if info.details == nil {
+		info.details = map[string]string{}
+	}
+	info.details[key] = value
+}
+
+// auditProject sets the project of an event for routes without :projectName (project creation)
+func auditProject(c *gin.Context, project string) {
+	currentAuditInfo(c).project = project
+}
+
+func currentAuditInfo(c *gin.Context) *auditInfo {
+	if v, ok := c.Get(auditContextKey); ok {
+		if info, ok := v.(*auditInfo); ok {
+			return info
+		}
+	}
+	info := &auditInfo{}
+	c.Set(auditContextKey, info)
+	return info
+}
+
+// AuditMiddleware records every mutating API request, and any request a handler annotated with
+// auditAction, once the handler has written its response. It runs before project validation so
+// rejected attempts are recorded as denied.
+func AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if AuditLogger == nil {
+			return
+		}
+		route := c.FullPath()
+		if route == "" || auditSkipRoutes[route] {
+			return
+		}
+		info := &auditInfo{}
+		if v, ok := c.Get(auditContextKey); ok {
+			if i, ok := v.(*auditInfo); ok {
+				info = i
+			}
+		}
+		if info.action == "" && !isMutatingMethod(c.Request.Method) {
+			return
+		}
+
+		action, kind, name := deriveAuditAction(c, route)
+		if info.action != "" {
+			action, kind, name = info.action, info.kind, info.name
+		}
+		project := c.Param("projectName")
+		if project == "" {
+			project = info.project
+		}
+		// Requests rejected before the project was validated may name any namespace; only keep
+		// them for real projects so callers can't create audit logs at will
+		if project != "" && c.GetString("project") == "" && !auditNamespaceExists(c, project) {
+			if c.Param("projectName") != "" {
+				return
+			}
+			// e.g. a failed project creation: keep it in the cluster-wide log
+			project = ""
+		}
+		status := c.Writer.Status()
+		AuditLogger.Record(c.Request.Context(), audit.Event{
+			Project:   project,
+			RequestID: c.GetString("requestID"),
+			Actor:     auditActor(c),
+			Action:    action,
+			Target:    audit.Target{Kind: kind, Name: name},
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Status:    status,
+			Outcome:   audit.OutcomeForStatus(status),
+			Details:   info.details,
+		})
+	}
+}
+
+func auditNamespaceExists(c *gin.Context, name string) bool {
+	if K8sClientMw == nil {
+		return false
+	}
+	_, err := K8sClientMw.CoreV1().Namespaces().Get(c.Request.Context(), name, v1.GetOptions{})
+	return err == nil
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// deriveAuditAction names an action from its route, e.g.
+// POST /api/projects/:projectName/agentic-sessions/:sessionName/start -> session.start on session <name>
+// DELETE /api/projects/:projectName/keys/:keyId -> access-key.delete on access-key <keyId>
+func deriveAuditAction(c *gin.Context, route string) (action, kind, name string) {
+	segs := strings.Split(strings.Trim(strings.TrimPrefix(route, "/api"), "/"), "/")
+	if len(segs) > 2 && segs[0] == "projects" && segs[1] == ":projectName" {
+		segs = segs[2:]
+	}
+	var statics []string
+	allStatic := true
+	for _, s := range segs {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			allStatic = false
+			if name == "" && len(statics) > 0 {
+				name = strings.TrimPrefix(c.Param(s[1:]), "/")
+			}
+			continue
+		}
+		statics = append(statics, s)
+	}
+	if len(statics) == 0 {
+		return strings.ToLower(c.Request.Method), "", name
+	}
+	kind = statics[0]
+	if k, ok := auditKinds[kind]; ok {
+		kind = k
+	}
+	parts := append([]string{kind}, statics[1:]...)
+	switch c.Request.Method {
+	case http.MethodPost:
+		if allStatic {
+			parts = append(parts, "create")
+		}
+	case http.MethodPut:
+		parts = append(parts, "update")
+	case http.MethodPatch:
+		parts = append(parts, "patch")
+	case http.MethodDelete:
+		parts = append(parts, "delete")
+	}
+	return strings.Join(parts, "."), kind, name
+}
+
+// auditActor identifies the caller: the ServiceAccount behind a bearer token (access keys are
+// ServiceAccounts named ambient-key-*) or the user forwarded by the OAuth proxy
+func auditActor(c *gin.Context) audit.Actor {
+	if ns, sa, ok := ExtractServiceAccountFromAuth(c); ok {
+		actor := audit.Actor{Type: audit.ActorServiceAccount, ID: "system:serviceaccount:" + ns + ":" + sa, Name: sa}
+		if strings.HasPrefix(sa, "ambient-key-") {
+			actor.Type = audit.ActorAccessKey
+		}
+		return actor
+	}
+	if uid := c.GetString("userID"); uid != "" {
+		return audit.Actor{Type: audit.ActorUser, ID: uid, Name: c.GetString("userName")}
+	}
+	return audit.Actor{Type: audit.ActorAnonymous, ID: "anonymous"}
+}
+
+// GetProjectAuditLog handles GET /api/projects/:projectName/audit
+// Filters: actor, action, targetKind, target, outcome, requestId, since/until (RFC3339 or a
+// duration back from now such as 24h) and limit (default 100, max 1000). Newest first.
+func GetProjectAuditLog(c *gin.Context) {
+	projectName := c.Param("projectName")
+	reqK8s, _ := GetK8sClientsForRequest(c)
+
+	// The audit log reveals who changed permissions and secrets; require the access of a
+	// project editor or admin (reading RoleBindings)
+	ssar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Group:     "rbac.authorization.k8s.io",
+				Resource:  "rolebindings",
+				Verb:      "list",
+				Namespace: projectName,
+			},
+		},
+	}
+	res, err := reqK8s.AuthorizationV1().SelfSubjectAccessReviews().Create(c.Request.Context(), ssar, v1.CreateOptions{})
+	if err != nil {
+		log.Printf("GetProjectAuditLog: SSAR failed for %s: %v", projectName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+		return
+	}
+	if !res.Status.Allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only project editors and admins can read the audit log"})
+		return
+	}
+
+	f := audit.Filter{
+		Project:    projectName,
+		Actor:      strings.TrimSpace(c.Query("actor")),
+		Action:     strings.TrimSpace(c.Query("action")),
+		TargetKind: strings.TrimSpace(c.Query("targetKind")),
+		Target:     strings.TrimSpace(c.Query("target")),
+		Outcome:    strings.TrimSpace(c.Query("outcome")),
+		RequestID:  strings.TrimSpace(c.Query("requestId")),
+	}
+	if f.Outcome != "" && f.Outcome != audit.OutcomeSuccess && f.Outcome != audit.OutcomeFailure && f.Outcome != audit.OutcomeDenied {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "outcome must be one of: success, failure, denied"})
+		return
+	}
+	for param, dst := range map[string]*time.Time{"since": &f.Since, "until": &f.Until} {
+		raw := strings.TrimSpace(c.Query(param))
+		if raw == "" {
+			continue
+		}
+		t, err := parseAuditTime(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be an RFC3339 time or a duration such as 24h"})
+			return
+		}
+		*dst = t
+	}
+	if raw := strings.TrimSpace(c.Query("limit")); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		f.Limit = n
+	}
+
+	events, err := AuditLogger.Query(c.Request.Context(), f)
+	if errors.Is(err, audit.ErrNotQueryable) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Audit log is not queryable; enable the file sink (AUDIT_SINKS=file)"})
+		return
+	}
+	if err != nil {
+		log.Printf("GetProjectAuditLog: query failed for %s: %v", projectName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read audit log"})
+		return
+	}
+	if events == nil {
+		events = []audit.Event{}
+	}
+	c.JSON(http.StatusOK, gin.H{"items": events})
+}
+
+// parseAuditTime accepts an RFC3339 timestamp or a duration relative to now
+func parseAuditTime(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(-d), nil
+}
+
+// sortedKeys returns the keys of m in order, for recording which entries a request changed
+func sortedKeys(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/base64"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -19,10 +20,19 @@ import (
 // Set by main during initialization
 var StateBaseDir string
 
+// RunnerSecretsDir is where the operator mounts the project's git signing key and format in the
+// content container
+// Set by main during initialization
+var RunnerSecretsDir string
+
+// GitSigningConfigured reports that the project has a signing key, so pushes must be signed
+// Set by main during initialization
+var GitSigningConfigured bool
+
 // Git operation functions - set by main package during initialization
 // These are set to the actual implementations from git package
 var (
-	GitPushRepo    func(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch, githubToken string) (string, error)
+	GitPushRepo    func(ctx context.Context, repoDir, commitMessage, outputRepoURL, branch, strategy, githubToken string, identity git.CommitIdentity) (string, error)
 	GitAbandonRepo func(ctx context.Context, repoDir string) error
 	GitDiffRepo    func(ctx context.Context, repoDir string) (*git.DiffSummary, error)
 )
@@ -30,13 +40,20 @@ var (
 // ContentGitPush handles POST /content/github/push in CONTENT_SERVICE_MODE
 func ContentGitPush(c *gin.Context) {
 	var body struct {
-		RepoPath      string `json:"repoPath"`
-		CommitMessage string `json:"commitMessage"`
-		OutputRepoURL string `json:"outputRepoUrl"`
-		Branch        string `json:"branch"`
+		RepoPath         string `json:"repoPath"`
+		CommitMessage    string `json:"commitMessage"`
+		OutputRepoURL    string `json:"outputRepoUrl"`
+		Branch           string `json:"branch"`
+		ConflictStrategy string `json:"conflictStrategy"`
+		// Commit identity resolved by the backend from ProjectSettings and the session's UserContext
+		IdentityMode         string   `json:"identityMode"`
+		AuthorName           string   `json:"authorName"`
+		AuthorEmail          string   `json:"authorEmail"`
+		CoAuthors            []string `json:"coAuthors"`
+		RequireSignedCommits bool     `json:"requireSignedCommits"`
 	}
 	_ = c.BindJSON(&body)
-	log.Printf("contentGitPush: request received repoPath=%q outputRepoUrl=%q branch=%q commitLen=%d", body.RepoPath, body.OutputRepoURL, body.Branch, len(strings.TrimSpace(body.CommitMessage)))
+	log.Printf("contentGitPush: request received repoPath=%q outputRepoUrl=%q branch=%q strategy=%q commitLen=%d", body.RepoPath, body.OutputRepoURL, body.Branch, body.ConflictStrategy, len(strings.TrimSpace(body.CommitMessage)))
 
 	// Require explicit output repo URL and branch from caller
 	if strings.TrimSpace(body.OutputRepoURL) == "" {
@@ -47,6 +64,11 @@ func ContentGitPush(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "missing branch"})
 		return
 	}
+	strategy, err := git.NormalizeSyncStrategy(body.ConflictStrategy)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	repoDir := filepath.Clean(filepath.Join(StateBaseDir, body.RepoPath))
 	if body.RepoPath == "" {
@@ -66,12 +88,29 @@ func ContentGitPush(c *gin.Context) {
 	gitHubToken := strings.TrimSpace(c.GetHeader("X-GitHub-Token"))
 	log.Printf("contentGitPush: tokenHeaderPresent=%t url.host.redacted=%t branch=%q", gitHubToken != "", strings.HasPrefix(body.OutputRepoURL, "https://"), body.Branch)
 
+	identity := git.CommitIdentity{
+		Mode:          body.IdentityMode,
+		Name:          body.AuthorName,
+		Email:         body.AuthorEmail,
+		CoAuthors:     body.CoAuthors,
+		RequireSigned: body.RequireSignedCommits,
+	}
+	// Signing keys never leave the pod: they are read from the mounted runner secret
+	identity.SigningKey = readRunnerSecretFile("GIT_SIGNING_KEY")
+	identity.SigningFormat = readRunnerSecretFile("GIT_SIGNING_FORMAT")
+	log.Printf("contentGitPush: identityMode=%q coAuthors=%d signingKeyPresent=%t requireSigned=%t", identity.Mode, len(identity.CoAuthors), identity.SigningKey != "", identity.RequireSigned)
+	// A configured key that didn't reach the pod must not turn into unsigned commits
+	if identity.SigningKey == "" && (GitSigningConfigured || identity.SigningFormat != "") {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "push failed", "stderr": "commit signing is configured for this project but GIT_SIGNING_KEY is not available in the content container"})
+		return
+	}
+
 	// Call refactored git push function
-	out, err := GitPushRepo(c.Request.Context(), repoDir, body.CommitMessage, body.OutputRepoURL, body.Branch, gitHubToken)
+	out, err := GitPushRepo(c.Request.Context(), repoDir, body.CommitMessage, body.OutputRepoURL, body.Branch, strategy, gitHubToken, identity)
 	if err != nil {
-		if out == "" {
-			// No changes to commit
-			c.JSON(http.StatusOK, gin.H{"ok": true, "message": "no changes"})
+		var conflictErr *git.ConflictError
+		if errors.As(err, &conflictErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": "upstream conflict", "message": err.Error(), "conflict": conflictErr.Info})
 			return
 		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": "push failed", "stderr": err.Error()})
@@ -81,6 +120,18 @@ func ContentGitPush(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"ok": true, "stdout": out})
 }
 
+// readRunnerSecretFile returns a trimmed key from the mounted runner secret, or "" when absent
+func readRunnerSecretFile(key string) string {
+	if RunnerSecretsDir == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(RunnerSecretsDir, key))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
 // ContentGitAbandon handles POST /content/github/abandon
 func ContentGitAbandon(c *gin.Context) {
 	var body struct {
@@ -16,9 +16,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -32,6 +29,8 @@ var (
 // GithubTokenManagerInterface defines the interface for GitHub token management
 type GithubTokenManagerInterface interface {
 	GenerateJWT() (string, error)
+	MintInstallationTokenForHost(ctx context.Context, installationID int64, host string) (string, time.Time, error)
+	MintScopedInstallationToken(ctx context.Context, installationID int64, host string, repositories []string, permissions map[string]string) (string, time.Time, error)
 }
 
 // GitHubAppInstallation represents a GitHub App installation for a user
@@ -40,6 +39,7 @@ type GitHubAppInstallation struct {
 	GitHubUserID   string    `json:"githubUserId"`
 	InstallationID int64     `json:"installationId"`
 	Host           string    `json:"host"`
+	Project        string    `json:"project,omitempty"`
 	UpdatedAt      time.Time `json:"updatedAt"`
 }
 
@@ -266,89 +266,6 @@ func userOwnsInstallation(userToken string, installationID int64) (bool, string,
 	return false, "", nil
 }
 
-// storeGitHubInstallation persists the GitHub App installation mapping
-func storeGitHubInstallation(ctx context.Context, projectName string, installation *GitHubAppInstallation) error {
-	if installation == nil || installation.UserID == "" {
-		return fmt.Errorf("invalid installation payload")
-	}
-	// Cluster-scoped by server namespace; ignore projectName for storage
-	const cmName = "github-app-installations"
-	for i := 0; i < 3; i++ { // retry on conflict
-		cm, err := K8sClient.CoreV1().ConfigMaps(Namespace).Get(ctx, cmName, v1.GetOptions{})
-		if err != nil {
-			if errors.IsNotFound(err) {
-				// create
-				cm = &corev1.ConfigMap{ObjectMeta: v1.ObjectMeta{Name: cmName, Namespace: Namespace}, Data: map[string]string{}}
-				if _, cerr := K8sClient.CoreV1().ConfigMaps(Namespace).Create(ctx, cm, v1.CreateOptions{}); cerr != nil && !errors.IsAlreadyExists(cerr) {
-					return fmt.Errorf("failed to create ConfigMap: %w", cerr)
-				}
-				// fetch again to get resourceVersion
-				cm, err = K8sClient.CoreV1().ConfigMaps(Namespace).Get(ctx, cmName, v1.GetOptions{})
-				if err != nil {
-					return fmt.Errorf("failed to fetch ConfigMap after create: %w", err)
-				}
-			} else {
-				return fmt.Errorf("failed to get ConfigMap: %w", err)
-			}
-		}
-		if cm.Data == nil {
-			cm.Data = map[string]string{}
-		}
-		b, err := json.Marshal(installation)
-		if err != nil {
-			return fmt.Errorf("failed to marshal installation: %w", err)
-		}
-		cm.Data[installation.UserID] = string(b)
-		if _, uerr := K8sClient.CoreV1().ConfigMaps(Namespace).Update(ctx, cm, v1.UpdateOptions{}); uerr != nil {
-			if errors.IsConflict(uerr) {
-				continue // retry
-			}
-			return fmt.Errorf("failed to update ConfigMap: %w", uerr)
-		}
-		return nil
-	}
-	return fmt.Errorf("failed to update ConfigMap after retries")
-}
-
-// GetGitHubInstallation retrieves GitHub App installation for a user
-func GetGitHubInstallation(ctx context.Context, userID string) (*GitHubAppInstallation, error) {
-	const cmName = "github-app-installations"
-	cm, err := K8sClient.CoreV1().ConfigMaps(Namespace).Get(ctx, cmName, v1.GetOptions{})
-	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil, fmt.Errorf("installation not found")
-		}
-		return nil, fmt.Errorf("failed to read ConfigMap: %w", err)
-	}
-	if cm.Data == nil {
-		return nil, fmt.Errorf("installation not found")
-	}
-	raw, ok := cm.Data[userID]
-	if !ok || raw == "" {
-		return nil, fmt.Errorf("installation not found")
-	}
-	var inst GitHubAppInstallation
-	if err := json.Unmarshal([]byte(raw), &inst); err != nil {
-		return nil, fmt.Errorf("failed to decode installation: %w", err)
-	}
-	return &inst, nil
-}
-
-// deleteGitHubInstallation removes the user mapping from ConfigMap
-func deleteGitHubInstallation(ctx context.Context, userID string) error {
-	const cmName = "github-app-installations"
-	cm, err := K8sClient.CoreV1().ConfigMaps(Namespace).Get(ctx, cmName, v1.GetOptions{})
-	if err != nil {
-		return err
-	}
-	if cm.Data == nil {
-		return nil
-	}
-	delete(cm.Data, userID)
-	_, uerr := K8sClient.CoreV1().ConfigMaps(Namespace).Update(ctx, cm, v1.UpdateOptions{})
-	return uerr
-}
-
 // ===== Global, non-project-scoped endpoints =====
 
 // LinkGitHubInstallationGlobal handles POST /auth/github/install
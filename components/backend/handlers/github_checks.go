@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+	"ambient-code-backend/tracker"
+)
+
+// Annotations tracking the GitHub Check Run attached to a session
+const (
+	githubCheckRunAnnotation   = "ambient-code.io/github-check-run-id"
+	githubCheckRepoAnnotation  = "ambient-code.io/github-check-repo"
+	githubCheckSHAAnnotation   = "ambient-code.io/github-check-sha"
+	githubCheckPhaseAnnotation = "ambient-code.io/github-check-phase"
+)
+
+// githubReportLabelKey marks sessions with a repository the GitHub reporter may comment or
+// run checks on; WatchSessionsForGitHub only watches sessions carrying it
+const githubReportLabelKey = "ambient-code.io/github-report"
+
+// markGitHubReportable labels a new session for WatchSessionsForGitHub when any of its
+// repositories is hosted on GitHub
+func markGitHubReportable(session *vteamv1alpha1.AgenticSession) {
+	for _, r := range session.Spec.Repos {
+		urls := []string{r.Input.URL}
+		if r.Output != nil {
+			urls = append(urls, r.Output.URL)
+		}
+		for _, u := range urls {
+			if _, _, ok := parseGitHubRepoURL(u); ok {
+				if session.Labels == nil {
+					session.Labels = map[string]string{}
+				}
+				session.Labels[githubReportLabelKey] = "true"
+				return
+			}
+		}
+	}
+}
+
+// maxCheckRunText is the GitHub limit for check run output summary/text
+const maxCheckRunText = 65535
+
+// checkRunTarget identifies the pull request a session works on
+type checkRunTarget struct {
+	Host           string
+	Repo           string // owner/name
+	Branch         string
+	InstallationID int64
+}
+
+// isTerminalSessionPhase reports whether a session phase is final
+func isTerminalSessionPhase(phase string) bool {
+	return phase == "Completed" || phase == "Failed" || phase == "Stopped" || phase == "Error"
+}
+
+// parseGitHubRepoURL returns host and owner/name for https and scp-style GitHub URLs
+func parseGitHubRepoURL(raw string) (string, string, bool) {
+	raw = strings.TrimSuffix(strings.TrimSpace(raw), ".git")
+	if strings.HasPrefix(raw, "git@") {
+		hostPath := strings.SplitN(strings.TrimPrefix(raw, "git@"), ":", 2)
+		if len(hostPath) != 2 {
+			return "", "", false
+		}
+		parts := strings.Split(strings.Trim(hostPath[1], "/"), "/")
+		if len(parts) != 2 {
+			return "", "", false
+		}
+		return hostPath[0], parts[0] + "/" + parts[1], true
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return u.Host, parts[0] + "/" + parts[1], true
+}
+
+// resolveCheckRunTarget finds the main repo output branch and the installation able to report on it
+func resolveCheckRunTarget(ctx context.Context, session *vteamv1alpha1.AgenticSession) (*checkRunTarget, bool) {
+	repo := session.Spec.MainRepo()
+	if repo == nil {
+		return nil, false
+	}
+
+	// The session's work lands on the output branch; fall back to the input branch
+	var repoURL, branch string
+	for _, ref := range []*vteamv1alpha1.GitRepoRef{repo.Output, &repo.Input} {
+		if ref == nil || ref.Branch == nil {
+			continue
+		}
+		if strings.TrimSpace(ref.URL) != "" && strings.TrimSpace(*ref.Branch) != "" {
+			repoURL, branch = ref.URL, *ref.Branch
+			break
+		}
+	}
+	if repoURL == "" {
+		return nil, false
+	}
+	host, fullName, ok := parseGitHubRepoURL(repoURL)
+	if !ok {
+		return nil, false
+	}
+	target := &checkRunTarget{Host: host, Repo: fullName, Branch: branch}
+
+	// Check runs always go through the project's linked installation: spec.userContext is
+	// editable by anyone who can update the session, so it must not select whose App reports
+	inst, err := GetProjectGitHubInstallation(ctx, session.Namespace)
+	if err != nil || inst == nil || inst.InstallationID == 0 {
+		return nil, false
+	}
+	target.InstallationID = inst.InstallationID
+	return target, true
+}
+
+// findOpenPullRequestHead returns the head SHA of an open PR whose head is branch in the same repo
+func findOpenPullRequestHead(ctx context.Context, target *checkRunTarget, token string) (string, error) {
+	owner := strings.SplitN(target.Repo, "/", 2)[0]
+	api := fmt.Sprintf("%s/repos/%s/pulls?state=open&head=%s", githubAPIBaseURL(target.Host), target.Repo, url.QueryEscape(owner+":"+target.Branch))
+	resp, err := doGitHubRequest(ctx, http.MethodGet, api, "token "+token, "", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(b))
+	}
+	var pulls []struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pulls); err != nil {
+		return "", err
+	}
+	if len(pulls) == 0 {
+		return "", nil
+	}
+	return pulls[0].Head.SHA, nil
+}
+
+// sendCheckRun creates (checkRunID == 0) or updates a check run and returns its ID
+func sendCheckRun(ctx context.Context, target *checkRunTarget, token string, checkRunID int64, payload map[string]interface{}) (int64, error) {
+	body, _ := json.Marshal(payload)
+	method := http.MethodPost
+	api := fmt.Sprintf("%s/repos/%s/check-runs", githubAPIBaseURL(target.Host), target.Repo)
+	expected := http.StatusCreated
+	if checkRunID != 0 {
+		method = http.MethodPatch
+		api = fmt.Sprintf("%s/%d", api, checkRunID)
+		expected = http.StatusOK
+	}
+	resp, err := doGitHubRequest(ctx, method, api, "token "+token, "", bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != expected {
+		b, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(b))
+	}
+	var out struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	return out.ID, nil
+}
+
+// checkRunOutput renders the check run title and summary for the current session state
+func checkRunOutput(session *vteamv1alpha1.AgenticSession, phase string) map[string]interface{} {
+	project, name := session.Namespace, session.Name
+	title := fmt.Sprintf("Session %s", strings.ToLower(phase))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Ambient session `%s` in project `%s`.\n\nPhase: **%s**", name, project, phase)
+	if cost := session.Status.TotalCostUSD; cost != nil {
+		fmt.Fprintf(&sb, "\n\nCost: $%.4f", *cost)
+		title = fmt.Sprintf("%s ($%.2f)", title, *cost)
+	}
+	if turns := session.Status.NumTurns; turns > 0 {
+		fmt.Fprintf(&sb, "\nTurns: %d", turns)
+	}
+	if msg := session.Status.Message; msg != "" {
+		fmt.Fprintf(&sb, "\n\n%s", msg)
+	}
+	if result := session.Status.Result; result != nil && strings.TrimSpace(*result) != "" {
+		fmt.Fprintf(&sb, "\n\n%s", strings.TrimSpace(*result))
+	}
+	summary := sb.String()
+	if len(summary) > maxCheckRunText {
+		summary = tracker.TruncateUTF8(summary, maxCheckRunText-4) + "\n..."
+	}
+	return map[string]interface{}{"title": title, "summary": summary}
+}
+
+// checkRunConclusion maps a terminal session phase to a check run conclusion
+func checkRunConclusion(session *vteamv1alpha1.AgenticSession, phase string) string {
+	switch phase {
+	case "Completed":
+		if session.Status.IsError {
+			return "failure"
+		}
+		return "success"
+	case "Stopped":
+		return "cancelled"
+	default:
+		return "failure"
+	}
+}
+
+// reportSessionCheckRun creates, updates and completes the check run for sessions on PR branches
+func reportSessionCheckRun(session *vteamv1alpha1.AgenticSession) {
+	anns := session.Annotations
+	phase := string(session.Status.Phase)
+	if phase == "" || anns[githubCheckPhaseAnnotation] == phase {
+		return
+	}
+	checkRunID, _ := strconv.ParseInt(anns[githubCheckRunAnnotation], 10, 64)
+	// Sessions that finished before we ever observed them (e.g. on backend restart) are not backfilled
+	if checkRunID == 0 && isTerminalSessionPhase(phase) && anns[githubCheckPhaseAnnotation] == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	project, name := session.Namespace, session.Name
+	markReported := map[string]string{githubCheckPhaseAnnotation: phase}
+
+	target, ok := resolveCheckRunTarget(ctx, session)
+	if !ok {
+		patchSessionAnnotations(ctx, project, name, markReported)
+		return
+	}
+	token, err := githubInstallationToken(ctx, target.InstallationID, target.Host)
+	if err != nil {
+		log.Printf("reportSessionCheckRun: no installation token for %s/%s: %v", project, name, err)
+		return
+	}
+
+	headSHA, err := findOpenPullRequestHead(ctx, target, token)
+	if err != nil {
+		log.Printf("reportSessionCheckRun: failed to look up PR for %s/%s on %s:%s: %v", project, name, target.Repo, target.Branch, err)
+		return
+	}
+
+	displayName := session.Spec.DisplayName
+	if strings.TrimSpace(displayName) == "" {
+		displayName = name
+	}
+	payload := map[string]interface{}{
+		"name":        "Ambient: " + displayName,
+		"external_id": project + "/" + name,
+		"output":      checkRunOutput(session, phase),
+	}
+	if isTerminalSessionPhase(phase) {
+		payload["status"] = "completed"
+		payload["conclusion"] = checkRunConclusion(session, phase)
+		payload["completed_at"] = time.Now().UTC().Format(time.RFC3339)
+	} else if phase == "Pending" || phase == "Creating" {
+		payload["status"] = "queued"
+	} else {
+		payload["status"] = "in_progress"
+	}
+
+	if checkRunID == 0 {
+		if headSHA == "" {
+			// Not (yet) a PR branch; look again on the next phase change
+			patchSessionAnnotations(ctx, project, name, markReported)
+			return
+		}
+		payload["head_sha"] = headSHA
+		payload["started_at"] = time.Now().UTC().Format(time.RFC3339)
+		id, err := sendCheckRun(ctx, target, token, 0, payload)
+		if err != nil {
+			log.Printf("reportSessionCheckRun: failed to create check run for %s/%s: %v", project, name, err)
+			return
+		}
+		markReported[githubCheckRunAnnotation] = strconv.FormatInt(id, 10)
+		markReported[githubCheckRepoAnnotation] = target.Repo
+		markReported[githubCheckSHAAnnotation] = headSHA
+		log.Printf("reportSessionCheckRun: created check run %d on %s@%s for %s/%s", id, target.Repo, headSHA, project, name)
+		patchSessionAnnotations(ctx, project, name, markReported)
+		return
+	}
+
+	if _, err := sendCheckRun(ctx, target, token, checkRunID, payload); err != nil {
+		log.Printf("reportSessionCheckRun: failed to update check run %d for %s/%s: %v", checkRunID, project, name, err)
+		return
+	}
+
+	// When the session pushed to the PR, surface the final result on the new head commit too
+	if isTerminalSessionPhase(phase) && headSHA != "" && headSHA != anns[githubCheckSHAAnnotation] {
+		payload["head_sha"] = headSHA
+		if _, err := sendCheckRun(ctx, target, token, 0, payload); err != nil {
+			log.Printf("reportSessionCheckRun: failed to report result on new head %s for %s/%s: %v", headSHA, project, name, err)
+		}
+	}
+	patchSessionAnnotations(ctx, project, name, markReported)
+}
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GitHub App installation mappings are stored as labelled Secrets in the backend namespace, one
+// per user and one per project. Only the backend writes there: project users can create Secrets
+// in their own namespace, so a mapping kept there could name an installation they do not own.
+// Who may link a project installation is decided by project RBAC through an access review.
+const (
+	githubInstallationLabel      = "ambient-code.io/github-installation"
+	githubInstallationScopeLabel = "ambient-code.io/github-installation-scope"
+	githubInstallationUserAnn    = "ambient-code.io/github-installation-user"
+	githubInstallationDataKey    = "installation.json"
+
+	githubInstallationScopeUser    = "user"
+	githubInstallationScopeProject = "project"
+
+	// legacyInstallationsConfigMap held all user mappings before installations moved to Secrets
+	legacyInstallationsConfigMap = "github-app-installations"
+)
+
+// userInstallationSecretName derives a DNS-safe Secret name from an arbitrary user ID
+func userInstallationSecretName(userID string) string {
+	sum := sha256.Sum256([]byte(userID))
+	return "github-installation-" + hex.EncodeToString(sum[:])[:20]
+}
+
+// projectInstallationSecretName derives the backend-namespace Secret name of a project installation
+func projectInstallationSecretName(projectName string) string {
+	sum := sha256.Sum256([]byte(projectName))
+	return "github-project-installation-" + hex.EncodeToString(sum[:])[:20]
+}
+
+// writeInstallationSecret creates or updates an installation Secret
+func writeInstallationSecret(ctx context.Context, client kubernetes.Interface, namespace, name, scope string, installation *GitHubAppInstallation) error {
+	b, err := json.Marshal(installation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal installation: %w", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				githubInstallationLabel:      "true",
+				githubInstallationScopeLabel: scope,
+			},
+			Annotations: map[string]string{
+				githubInstallationUserAnn: installation.UserID,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{githubInstallationDataKey: b},
+	}
+
+	existing, err := client.CoreV1().Secrets(namespace).Get(ctx, name, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := client.CoreV1().Secrets(namespace).Create(ctx, secret, v1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create installation secret: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get installation secret: %w", err)
+	}
+	existing.Labels = secret.Labels
+	existing.Annotations = secret.Annotations
+	existing.Data = secret.Data
+	if _, err := client.CoreV1().Secrets(namespace).Update(ctx, existing, v1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update installation secret: %w", err)
+	}
+	return nil
+}
+
+// readInstallationSecret loads an installation from its Secret
+func readInstallationSecret(ctx context.Context, client kubernetes.Interface, namespace, name string) (*GitHubAppInstallation, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, fmt.Errorf("installation not found")
+		}
+		return nil, fmt.Errorf("failed to read installation secret: %w", err)
+	}
+	if secret.Labels[githubInstallationLabel] != "true" {
+		return nil, fmt.Errorf("installation not found")
+	}
+	raw := secret.Data[githubInstallationDataKey]
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("installation not found")
+	}
+	var inst GitHubAppInstallation
+	if err := json.Unmarshal(raw, &inst); err != nil {
+		return nil, fmt.Errorf("failed to decode installation: %w", err)
+	}
+	return &inst, nil
+}
+
+// storeGitHubInstallation persists the GitHub App installation mapping.
+// An empty projectName stores the caller's user-level installation.
+func storeGitHubInstallation(ctx context.Context, projectName string, installation *GitHubAppInstallation) error {
+	if installation == nil || installation.UserID == "" {
+		return fmt.Errorf("invalid installation payload")
+	}
+	if projectName != "" {
+		installation.Project = projectName
+		return writeInstallationSecret(ctx, K8sClient, Namespace, projectInstallationSecretName(projectName), githubInstallationScopeProject, installation)
+	}
+	return writeInstallationSecret(ctx, K8sClient, Namespace, userInstallationSecretName(installation.UserID), githubInstallationScopeUser, installation)
+}
+
+// GetGitHubInstallation retrieves GitHub App installation for a user
+func GetGitHubInstallation(ctx context.Context, userID string) (*GitHubAppInstallation, error) {
+	if strings.TrimSpace(userID) == "" {
+		return nil, fmt.Errorf("installation not found")
+	}
+	inst, err := readInstallationSecret(ctx, K8sClient, Namespace, userInstallationSecretName(userID))
+	if err != nil {
+		return nil, err
+	}
+	// Guard against hash collisions
+	if inst.UserID != userID {
+		return nil, fmt.Errorf("installation not found")
+	}
+	return inst, nil
+}
+
+// GetProjectGitHubInstallation retrieves the project-level GitHub App installation
+func GetProjectGitHubInstallation(ctx context.Context, projectName string) (*GitHubAppInstallation, error) {
+	if strings.TrimSpace(projectName) == "" {
+		return nil, fmt.Errorf("installation not found")
+	}
+	inst, err := readInstallationSecret(ctx, K8sClient, Namespace, projectInstallationSecretName(projectName))
+	if err != nil {
+		return nil, err
+	}
+	// Guard against hash collisions
+	if inst.Project != projectName {
+		return nil, fmt.Errorf("installation not found")
+	}
+	return inst, nil
+}
+
+// canManageProjectGitHubInstallation asks the API server whether the caller may manage Secrets
+// in the project, the permission linking an installation stood for when it was a project Secret
+func canManageProjectGitHubInstallation(c *gin.Context, projectName, verb string) (bool, error) {
+	reqK8s, _ := GetK8sClientsForRequest(c)
+	if reqK8s == nil {
+		return false, fmt.Errorf("invalid or missing token")
+	}
+	ssar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Resource:  "secrets",
+				Verb:      verb,
+				Namespace: projectName,
+			},
+		},
+	}
+	res, err := reqK8s.AuthorizationV1().SelfSubjectAccessReviews().Create(c.Request.Context(), ssar, v1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return res.Status.Allowed, nil
+}
+
+// deleteGitHubInstallation removes the user mapping
+func deleteGitHubInstallation(ctx context.Context, userID string) error {
+	err := K8sClient.CoreV1().Secrets(Namespace).Delete(ctx, userInstallationSecretName(userID), v1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// MigrateGitHubInstallations moves user mappings from the legacy github-app-installations
+// ConfigMap into per-user Secrets and removes the ConfigMap once every entry is migrated
+func MigrateGitHubInstallations(ctx context.Context) error {
+	cm, err := K8sClient.CoreV1().ConfigMaps(Namespace).Get(ctx, legacyInstallationsConfigMap, v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read ConfigMap: %w", err)
+	}
+
+	migrated, skipped, failed := 0, 0, 0
+	for userID, raw := range cm.Data {
+		var inst GitHubAppInstallation
+		if err := json.Unmarshal([]byte(raw), &inst); err != nil || inst.InstallationID == 0 {
+			log.Printf("MigrateGitHubInstallations: dropping undecodable entry for user %s", userID)
+			skipped++
+			continue
+		}
+		inst.UserID = userID
+		// Never overwrite a mapping the user re-linked after the upgrade
+		if _, err := GetGitHubInstallation(ctx, userID); err == nil {
+			skipped++
+			continue
+		}
+		if err := storeGitHubInstallation(ctx, "", &inst); err != nil {
+			log.Printf("MigrateGitHubInstallations: failed to migrate user %s: %v", userID, err)
+			failed++
+			continue
+		}
+		migrated++
+	}
+
+	log.Printf("MigrateGitHubInstallations: migrated=%d skipped=%d failed=%d", migrated, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d installations could not be migrated; keeping ConfigMap %s", failed, legacyInstallationsConfigMap)
+	}
+	if err := K8sClient.CoreV1().ConfigMaps(Namespace).Delete(ctx, legacyInstallationsConfigMap, v1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// ===== Project-scoped endpoints =====
+
+// GetProjectGitHubInstallationStatus handles GET /api/projects/:projectName/github/installation
+func GetProjectGitHubInstallationStatus(c *gin.Context) {
+	projectName := c.Param("projectName")
+	inst, err := GetProjectGitHubInstallation(c.Request.Context(), projectName)
+	if err != nil {
+		c.JSON(http.StatusOK, gin.H{"installed": false})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"installed":      true,
+		"installationId": inst.InstallationID,
+		"host":           inst.Host,
+		"githubUserId":   inst.GitHubUserID,
+		"linkedBy":       inst.UserID,
+		"updatedAt":      inst.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// LinkProjectGitHubInstallation handles PUT /api/projects/:projectName/github/installation
+// Shares the caller's linked GitHub App installation with every session in the project.
+func LinkProjectGitHubInstallation(c *gin.Context) {
+	projectName := c.Param("projectName")
+	userID, _ := c.Get("userID")
+	if userID == nil || strings.TrimSpace(userID.(string)) == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user identity"})
+		return
+	}
+
+	var req struct {
+		InstallationID int64 `json:"installationId"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Ownership of an installation is only proven through the user OAuth link
+	userInst, err := GetGitHubInstallation(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "link the GitHub App to your account before sharing it with a project"})
+		return
+	}
+	if req.InstallationID != 0 && req.InstallationID != userInst.InstallationID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "installation is not linked to your account"})
+		return
+	}
+
+	installation := &GitHubAppInstallation{
+		UserID:         userInst.UserID,
+		GitHubUserID:   userInst.GitHubUserID,
+		InstallationID: userInst.InstallationID,
+		Host:           userInst.Host,
+		Project:        projectName,
+		UpdatedAt:      time.Now(),
+	}
+
+	// Project RBAC decides who may manage project credentials; the mapping itself is written
+	// by the backend where project users cannot touch it
+	allowed, err := canManageProjectGitHubInstallation(c, projectName, "create")
+	if err != nil {
+		log.Printf("LinkProjectGitHubInstallation: access review failed for %s: %v", projectName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions to manage project GitHub installation"})
+		return
+	}
+	if err := storeGitHubInstallation(c.Request.Context(), projectName, installation); err != nil {
+		log.Printf("Failed to link GitHub installation for project %s: %v", projectName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store installation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "GitHub App installation linked to project", "installationId": installation.InstallationID})
+}
+
+// UnlinkProjectGitHubInstallation handles DELETE /api/projects/:projectName/github/installation
+func UnlinkProjectGitHubInstallation(c *gin.Context) {
+	projectName := c.Param("projectName")
+	allowed, err := canManageProjectGitHubInstallation(c, projectName, "delete")
+	if err != nil {
+		log.Printf("UnlinkProjectGitHubInstallation: access review failed for %s: %v", projectName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions to manage project GitHub installation"})
+		return
+	}
+	err = K8sClient.CoreV1().Secrets(Namespace).Delete(c.Request.Context(), projectInstallationSecretName(projectName), v1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		log.Printf("Failed to unlink GitHub installation for project %s: %v", projectName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to unlink installation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "GitHub App installation unlinked from project"})
+}
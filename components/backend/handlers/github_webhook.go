@@ -0,0 +1,804 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+	"ambient-code-backend/tracker"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ktypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// Trigger rule types supported in ProjectSettings spec.githubTriggers.rules
+const (
+	githubTriggerLabel        = "label"
+	githubTriggerSlashCommand = "slashCommand"
+	githubTriggerIssueOpened  = "issueOpened"
+
+	defaultGitHubSlashCommand = "/ambient"
+)
+
+// Labels and annotations recorded on webhook-triggered sessions
+const (
+	githubTriggerLabelKey         = "ambient-code.io/github-trigger"
+	githubDeliveryLabelKey        = "ambient-code.io/github-delivery"
+	githubRepoAnnotation          = "ambient-code.io/github-repo"
+	githubNumberAnnotation        = "ambient-code.io/github-number"
+	githubHostAnnotation          = "ambient-code.io/github-host"
+	githubCommentAnnotation       = "ambient-code.io/github-comment-id"
+	githubReportedPhaseAnnotation = "ambient-code.io/github-reported-phase"
+)
+
+// maxGitHubWebhookBody caps the webhook payload size accepted by HandleGitHubWebhook
+const maxGitHubWebhookBody = 5 << 20
+
+// githubWebhookPayload holds the subset of issues/issue_comment/pull_request payloads we use
+type githubWebhookPayload struct {
+	Action       string `json:"action"`
+	Installation *struct {
+		ID int64 `json:"id"`
+	} `json:"installation"`
+	Repository struct {
+		FullName      string `json:"full_name"`
+		CloneURL      string `json:"clone_url"`
+		HTMLURL       string `json:"html_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+	Issue *struct {
+		Number            int    `json:"number"`
+		Title             string `json:"title"`
+		Body              string `json:"body"`
+		HTMLURL           string `json:"html_url"`
+		AuthorAssociation string `json:"author_association"`
+		PullRequest       *struct {
+			URL string `json:"url"`
+		} `json:"pull_request"`
+	} `json:"issue"`
+	PullRequest *struct {
+		Number            int    `json:"number"`
+		Title             string `json:"title"`
+		Body              string `json:"body"`
+		HTMLURL           string `json:"html_url"`
+		AuthorAssociation string `json:"author_association"`
+		Head              struct {
+			Ref  string `json:"ref"`
+			Repo *struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Comment *struct {
+		ID                int64  `json:"id"`
+		Body              string `json:"body"`
+		HTMLURL           string `json:"html_url"`
+		AuthorAssociation string `json:"author_association"`
+	} `json:"comment"`
+	Label *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+}
+
+// githubTriggerEvent is a webhook delivery normalized for trigger rule matching
+type githubTriggerEvent struct {
+	Kind           string // one of the githubTrigger* rule types
+	Label          string
+	CommentBody    string
+	Association    string
+	Repo           string
+	CloneURL       string
+	Host           string
+	DefaultBranch  string
+	Number         int
+	IsPullRequest  bool
+	HeadRef        string // PR head branch when it lives in the same repository
+	FromFork       bool   // PR head lives in another repository
+	Title          string
+	Body           string
+	URL            string
+	Actor          string
+	InstallationID int64
+	DeliveryID     string
+}
+
+// githubTriggerConfig is ProjectSettings spec.githubTriggers
+type githubTriggerConfig struct {
+	Repositories        []string
+	Rules               []githubTriggerRule
+	AllowedAssociations []string
+	AutoPush            bool
+}
+
+// githubTriggerRule is a single entry of spec.githubTriggers.rules
+type githubTriggerRule struct {
+	Type    string
+	Label   string
+	Command string
+}
+
+// verifyGitHubWebhookSignature checks the X-Hub-Signature-256 header against the payload
+func verifyGitHubWebhookSignature(secret string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// HandleGitHubWebhook handles POST /api/webhooks/github
+// Verifies the GitHub App webhook signature and starts sessions in every project whose
+// ProjectSettings githubTriggers match the delivery.
+func HandleGitHubWebhook(c *gin.Context) {
+	secret := strings.TrimSpace(os.Getenv("GITHUB_WEBHOOK_SECRET"))
+	if secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "GitHub webhooks not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxGitHubWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+		return
+	}
+	if !verifyGitHubWebhookSignature(secret, body, c.GetHeader("X-Hub-Signature-256")) {
+		log.Printf("githubWebhook: rejected delivery %s with invalid signature", c.GetHeader("X-GitHub-Delivery"))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
+		return
+	}
+
+	eventName := c.GetHeader("X-GitHub-Event")
+	deliveryID := c.GetHeader("X-GitHub-Delivery")
+	if eventName == "ping" {
+		c.JSON(http.StatusOK, gin.H{"ok": true, "message": "pong"})
+		return
+	}
+
+	var payload githubWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+
+	ev := normalizeGitHubEvent(eventName, &payload)
+	if ev == nil {
+		c.JSON(http.StatusOK, gin.H{"ok": true, "message": "event ignored"})
+		return
+	}
+	ev.DeliveryID = deliveryID
+
+	if DynamicClient == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "backend not initialized"})
+		return
+	}
+
+	projects, err := findProjectsForGitHubEvent(c.Request.Context(), ev)
+	if err != nil {
+		log.Printf("githubWebhook: failed to resolve projects for %s: %v", ev.Repo, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resolve projects"})
+		return
+	}
+
+	started := []gin.H{}
+	for project, cfg := range projects {
+		prompt, ok := matchGitHubTrigger(cfg, ev)
+		if !ok {
+			continue
+		}
+		// issue_comment deliveries don't carry the PR head; look it up once a rule matches
+		if ev.Kind == githubTriggerSlashCommand && ev.IsPullRequest && ev.HeadRef == "" && !ev.FromFork {
+			if err := resolvePullRequestHead(c.Request.Context(), ev); err != nil {
+				log.Printf("githubWebhook: failed to read pull request %s#%d: %v", ev.Repo, ev.Number, err)
+				break
+			}
+		}
+		// Commenters can't push to a fork's branch, and working on the base branch instead would
+		// ignore the PR's changes
+		if ev.Kind == githubTriggerSlashCommand && ev.FromFork {
+			log.Printf("githubWebhook: ignoring %s on %s#%d from a fork", ev.Kind, ev.Repo, ev.Number)
+			break
+		}
+		name, err := createGitHubTriggeredSession(c, project, cfg, ev, prompt)
+		if err != nil {
+			log.Printf("githubWebhook: failed to create session in %s for %s#%d: %v", project, ev.Repo, ev.Number, err)
+			continue
+		}
+		if name != "" {
+			started = append(started, gin.H{"project": project, "session": name})
+		}
+	}
+
+	log.Printf("githubWebhook: delivery=%s event=%s repo=%s number=%d sessions=%d", deliveryID, eventName, ev.Repo, ev.Number, len(started))
+	c.JSON(http.StatusAccepted, gin.H{"ok": true, "sessions": started})
+}
+
+// normalizeGitHubEvent converts a supported delivery into a trigger event, or nil when ignored
+func normalizeGitHubEvent(eventName string, p *githubWebhookPayload) *githubTriggerEvent {
+	ev := &githubTriggerEvent{
+		Repo:          p.Repository.FullName,
+		CloneURL:      p.Repository.CloneURL,
+		DefaultBranch: p.Repository.DefaultBranch,
+		Actor:         p.Sender.Login,
+		Host:          "github.com",
+	}
+	if u, err := url.Parse(p.Repository.HTMLURL); err == nil && u.Host != "" {
+		ev.Host = u.Host
+	}
+	if p.Installation != nil {
+		ev.InstallationID = p.Installation.ID
+	}
+
+	switch eventName {
+	case "issues":
+		if p.Issue == nil {
+			return nil
+		}
+		switch p.Action {
+		case "opened":
+			ev.Kind = githubTriggerIssueOpened
+		case "labeled":
+			if p.Label == nil {
+				return nil
+			}
+			ev.Kind = githubTriggerLabel
+			ev.Label = p.Label.Name
+		default:
+			return nil
+		}
+		ev.Number, ev.Title, ev.Body, ev.URL = p.Issue.Number, p.Issue.Title, p.Issue.Body, p.Issue.HTMLURL
+		ev.Association = p.Issue.AuthorAssociation
+	case "pull_request":
+		if p.PullRequest == nil || p.Action != "labeled" || p.Label == nil {
+			return nil
+		}
+		ev.Kind = githubTriggerLabel
+		ev.Label = p.Label.Name
+		ev.IsPullRequest = true
+		ev.Number, ev.Title, ev.Body, ev.URL = p.PullRequest.Number, p.PullRequest.Title, p.PullRequest.Body, p.PullRequest.HTMLURL
+		ev.Association = p.PullRequest.AuthorAssociation
+		if p.PullRequest.Head.Repo != nil && strings.EqualFold(p.PullRequest.Head.Repo.FullName, p.Repository.FullName) {
+			ev.HeadRef = p.PullRequest.Head.Ref
+		} else {
+			ev.FromFork = true
+		}
+	case "issue_comment":
+		if p.Issue == nil || p.Comment == nil || p.Action != "created" {
+			return nil
+		}
+		ev.Kind = githubTriggerSlashCommand
+		ev.CommentBody = p.Comment.Body
+		ev.Association = p.Comment.AuthorAssociation
+		ev.Number, ev.Title, ev.Body, ev.URL = p.Issue.Number, p.Issue.Title, p.Issue.Body, p.Comment.HTMLURL
+		ev.IsPullRequest = p.Issue.PullRequest != nil
+	default:
+		return nil
+	}
+
+	if ev.Repo == "" || ev.CloneURL == "" {
+		return nil
+	}
+	return ev
+}
+
+// resolvePullRequestHead reads the head branch and repository of the event's pull request,
+// setting HeadRef when the head is in the same repository and FromFork otherwise
+func resolvePullRequestHead(ctx context.Context, ev *githubTriggerEvent) error {
+	token, err := githubInstallationToken(ctx, ev.InstallationID, ev.Host)
+	if err != nil {
+		return err
+	}
+	api := fmt.Sprintf("%s/repos/%s/pulls/%d", githubAPIBaseURL(ev.Host), ev.Repo, ev.Number)
+	resp, err := doGitHubRequest(ctx, http.MethodGet, api, "token "+token, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(b))
+	}
+	var pr struct {
+		Head struct {
+			Ref  string `json:"ref"`
+			Repo *struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"head"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
+		return err
+	}
+	// A deleted fork leaves head.repo null
+	if pr.Head.Repo == nil || !strings.EqualFold(pr.Head.Repo.FullName, ev.Repo) {
+		ev.FromFork = true
+		return nil
+	}
+	ev.HeadRef = pr.Head.Ref
+	return nil
+}
+
+// findProjectsForGitHubEvent lists ProjectSettings (backend SA) whose githubTriggers include the
+// repository. Any project admin can list any repository, so a project only matches when the
+// delivery came from the installation linked to that project by someone who owns it.
+func findProjectsForGitHubEvent(ctx context.Context, ev *githubTriggerEvent) (map[string]*githubTriggerConfig, error) {
+	if ev.InstallationID == 0 {
+		return map[string]*githubTriggerConfig{}, nil
+	}
+	list, err := DynamicClient.Resource(GetProjectSettingsResource()).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]*githubTriggerConfig{}
+	for i := range list.Items {
+		item := &list.Items[i]
+		raw, found, _ := unstructured.NestedMap(item.Object, "spec", "githubTriggers")
+		if !found {
+			continue
+		}
+		cfg := parseGitHubTriggerConfig(raw)
+		listed := false
+		for _, r := range cfg.Repositories {
+			if strings.EqualFold(strings.TrimSpace(r), ev.Repo) {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			continue
+		}
+		inst, err := GetProjectGitHubInstallation(ctx, item.GetNamespace())
+		if err != nil || inst.InstallationID != ev.InstallationID || !sameGitHubHost(inst.Host, ev.Host) {
+			log.Printf("githubWebhook: project %s lists %s but is not linked to installation %d", item.GetNamespace(), ev.Repo, ev.InstallationID)
+			continue
+		}
+		result[item.GetNamespace()] = cfg
+	}
+	return result, nil
+}
+
+// sameGitHubHost compares GitHub hosts, treating an empty host as github.com
+func sameGitHubHost(a, b string) bool {
+	norm := func(h string) string {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h == "" || h == "api.github.com" {
+			return "github.com"
+		}
+		return h
+	}
+	return norm(a) == norm(b)
+}
+
+// parseGitHubTriggerConfig reads spec.githubTriggers, applying defaults
+func parseGitHubTriggerConfig(raw map[string]interface{}) *githubTriggerConfig {
+	cfg := &githubTriggerConfig{
+		AllowedAssociations: []string{"OWNER", "MEMBER", "COLLABORATOR"},
+		AutoPush:            true,
+	}
+	if repos, ok := raw["repositories"].([]interface{}); ok {
+		for _, r := range repos {
+			if s, ok := r.(string); ok && strings.TrimSpace(s) != "" {
+				cfg.Repositories = append(cfg.Repositories, strings.TrimSpace(s))
+			}
+		}
+	}
+	if rules, ok := raw["rules"].([]interface{}); ok {
+		for _, r := range rules {
+			rm, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			rule := githubTriggerRule{}
+			rule.Type, _ = rm["type"].(string)
+			rule.Label, _ = rm["label"].(string)
+			rule.Command, _ = rm["command"].(string)
+			if rule.Type == githubTriggerSlashCommand && strings.TrimSpace(rule.Command) == "" {
+				rule.Command = defaultGitHubSlashCommand
+			}
+			cfg.Rules = append(cfg.Rules, rule)
+		}
+	}
+	if assocs, ok := raw["allowedAssociations"].([]interface{}); ok && len(assocs) > 0 {
+		cfg.AllowedAssociations = nil
+		for _, a := range assocs {
+			if s, ok := a.(string); ok {
+				cfg.AllowedAssociations = append(cfg.AllowedAssociations, strings.ToUpper(strings.TrimSpace(s)))
+			}
+		}
+	}
+	if v, ok := raw["autoPush"].(bool); ok {
+		cfg.AutoPush = v
+	}
+	return cfg
+}
+
+// matchGitHubTrigger returns the session prompt when a rule matches the event
+func matchGitHubTrigger(cfg *githubTriggerConfig, ev *githubTriggerEvent) (string, bool) {
+	// Labels can only be applied by users with triage access; other triggers are gated by association
+	if ev.Kind != githubTriggerLabel {
+		allowed := false
+		for _, a := range cfg.AllowedAssociations {
+			if strings.EqualFold(a, ev.Association) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			log.Printf("githubWebhook: ignoring %s on %s#%d from %s (association %q not allowed)", ev.Kind, ev.Repo, ev.Number, ev.Actor, ev.Association)
+			return "", false
+		}
+	}
+
+	kind := "issue"
+	if ev.IsPullRequest {
+		kind = "pull request"
+	}
+	details := fmt.Sprintf("GitHub %s %s#%d: %s\n%s\n\n%s", kind, ev.Repo, ev.Number, ev.Title, ev.URL, strings.TrimSpace(ev.Body))
+
+	for _, rule := range cfg.Rules {
+		switch rule.Type {
+		case githubTriggerIssueOpened:
+			if ev.Kind == githubTriggerIssueOpened {
+				return fmt.Sprintf("Resolve the following %s.\n\n%s", kind, details), true
+			}
+		case githubTriggerLabel:
+			if ev.Kind == githubTriggerLabel && strings.EqualFold(strings.TrimSpace(rule.Label), ev.Label) {
+				return fmt.Sprintf("Resolve the following %s.\n\n%s", kind, details), true
+			}
+		case githubTriggerSlashCommand:
+			if ev.Kind != githubTriggerSlashCommand {
+				continue
+			}
+			if instruction, ok := parseSlashCommand(ev.CommentBody, rule.Command); ok {
+				if instruction == "" {
+					instruction = fmt.Sprintf("Resolve the following %s.", kind)
+				}
+				return fmt.Sprintf("%s\n\nRequested by @%s on %s.\n\n%s", instruction, ev.Actor, ev.URL, details), true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseSlashCommand finds a line starting with command and returns the text following it
+func parseSlashCommand(body, command string) (string, bool) {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == command {
+			return "", true
+		}
+		if strings.HasPrefix(line, command+" ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, command)), true
+		}
+	}
+	return "", false
+}
+
+// createGitHubTriggeredSession creates an AgenticSession for a matched trigger and acknowledges it on GitHub.
+// Returns "" without error when the delivery was already handled.
+func createGitHubTriggeredSession(c *gin.Context, project string, cfg *githubTriggerConfig, ev *githubTriggerEvent, prompt string) (string, error) {
+	ctx := c.Request.Context()
+	sessions := VteamClient.VteamV1alpha1().AgenticSessions(project)
+
+	// GitHub redelivers on timeouts; never start two sessions for the same delivery
+	if ev.DeliveryID != "" {
+		existing, err := sessions.List(ctx, v1.ListOptions{
+			LabelSelector: fmt.Sprintf("%s=%s", githubDeliveryLabelKey, ev.DeliveryID),
+		})
+		if err == nil && len(existing.Items) > 0 {
+			log.Printf("githubWebhook: delivery %s already handled by %s/%s", ev.DeliveryID, project, existing.Items[0].Name)
+			return "", nil
+		}
+	}
+
+	inputBranch := ev.DefaultBranch
+	outputBranch := fmt.Sprintf("ambient/issue-%d", ev.Number)
+	if ev.IsPullRequest && ev.HeadRef != "" {
+		inputBranch = ev.HeadRef
+		outputBranch = ev.HeadRef
+	}
+
+	policy, err := loadSessionPolicy(ctx, project)
+	if err != nil {
+		return "", err
+	}
+	llm := policy.resolveLLMSettings(nil)
+
+	name := fmt.Sprintf("agentic-session-%d", time.Now().UnixMilli())
+	labels := map[string]string{
+		githubTriggerLabelKey: "true",
+		githubReportLabelKey:  "true",
+	}
+	if ev.DeliveryID != "" {
+		labels[githubDeliveryLabelKey] = ev.DeliveryID
+	}
+	// The installation is not recorded on the session: metadata is user-editable, so tokens are
+	// always minted from the project's verified installation
+	annotations := map[string]string{
+		githubRepoAnnotation:   ev.Repo,
+		githubNumberAnnotation: strconv.Itoa(ev.Number),
+		githubHostAnnotation:   ev.Host,
+	}
+
+	mainRepoIndex := int32(0)
+	session := &vteamv1alpha1.AgenticSession{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        name,
+			Namespace:   project,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: vteamv1alpha1.AgenticSessionSpec{
+			Prompt:             prompt,
+			DisplayName:        fmt.Sprintf("%s#%d: %s", ev.Repo, ev.Number, ev.Title),
+			Project:            project,
+			LLMSettings:        sessionLLMSettings(llm),
+			Timeout:            int64(policy.Timeout),
+			AutoPushOnComplete: cfg.AutoPush,
+			Repos: []vteamv1alpha1.RepoMapping{{
+				Input:  vteamv1alpha1.GitRepoRef{URL: ev.CloneURL, Branch: &inputBranch},
+				Output: &vteamv1alpha1.GitRepoRef{URL: ev.CloneURL, Branch: &outputBranch},
+			}},
+			MainRepoIndex: &mainRepoIndex,
+			UserContext: &vteamv1alpha1.UserContext{
+				UserID:      "github:" + ev.Actor,
+				DisplayName: ev.Actor,
+				Groups:      []string{},
+			},
+		},
+		Status: vteamv1alpha1.AgenticSessionStatus{
+			Phase: vteamv1alpha1.AgenticSessionPhasePending,
+		},
+	}
+
+	if len(policy.Env) > 0 {
+		session.Spec.EnvironmentVariables = policy.resolveEnv(nil)
+	}
+	session.Spec.Secrets = secretsSpec(policy.Secrets)
+	if err := policy.checkSpec(&session.Spec); err != nil {
+		return "", err
+	}
+
+	if _, err := sessions.Create(ctx, session, v1.CreateOptions{}); err != nil {
+		return "", fmt.Errorf("create session: %w", err)
+	}
+	if err := provisionRunnerTokenForSession(c, K8sClient, sessions, name); err != nil {
+		log.Printf("Warning: failed to provision runner token for session %s/%s: %v", project, name, err)
+	}
+
+	kind := "issue"
+	if ev.IsPullRequest {
+		kind = "pull request"
+	}
+	body := fmt.Sprintf("Started Ambient session `%s` in project `%s` for this %s.\n\nPhase: **Pending**", name, project, kind)
+	if commentID, err := postGitHubIssueComment(ctx, ev.Host, ev.InstallationID, ev.Repo, ev.Number, body); err != nil {
+		log.Printf("githubWebhook: failed to post start comment on %s#%d: %v", ev.Repo, ev.Number, err)
+	} else {
+		patchSessionAnnotations(ctx, project, name, map[string]string{
+			githubCommentAnnotation:       strconv.FormatInt(commentID, 10),
+			githubReportedPhaseAnnotation: "Pending",
+		})
+	}
+
+	log.Printf("githubWebhook: created session %s/%s for %s#%d (%s)", project, name, ev.Repo, ev.Number, ev.Kind)
+	return name, nil
+}
+
+// githubInstallationToken mints an installation token through the GitHub App token manager
+func githubInstallationToken(ctx context.Context, installationID int64, host string) (string, error) {
+	if GithubTokenManager == nil {
+		return "", fmt.Errorf("GitHub App not configured")
+	}
+	if installationID == 0 {
+		return "", fmt.Errorf("delivery has no installation")
+	}
+	token, _, err := GithubTokenManager.MintInstallationTokenForHost(ctx, installationID, host)
+	return token, err
+}
+
+// webhookInstallationToken mints a token for sessions created by HandleGitHubWebhook from the
+// project's verified installation, the only installation such a session can have come from
+func webhookInstallationToken(ctx context.Context, session *vteamv1alpha1.AgenticSession) (string, bool) {
+	if session.Labels[githubTriggerLabelKey] != "true" || GithubTokenManager == nil {
+		return "", false
+	}
+	inst, err := GetProjectGitHubInstallation(ctx, session.Namespace)
+	if err != nil {
+		return "", false
+	}
+	repos, perms := sessionTokenScope(&session.Spec)
+	token, _, err := GithubTokenManager.MintScopedInstallationToken(ctx, inst.InstallationID, inst.Host, repos, perms)
+	if err != nil {
+		log.Printf("webhookInstallationToken: failed for %s/%s: %v", session.Namespace, session.Name, err)
+		return "", false
+	}
+	return token, true
+}
+
+// postGitHubIssueComment creates a comment on an issue or pull request and returns its ID
+func postGitHubIssueComment(ctx context.Context, host string, installationID int64, repo string, number int, body string) (int64, error) {
+	token, err := githubInstallationToken(ctx, installationID, host)
+	if err != nil {
+		return 0, err
+	}
+	payload, _ := json.Marshal(map[string]string{"body": body})
+	api := fmt.Sprintf("%s/repos/%s/issues/%d/comments", githubAPIBaseURL(host), repo, number)
+	resp, err := doGitHubRequest(ctx, http.MethodPost, api, "token "+token, "", bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		b, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(b))
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// updateGitHubIssueComment replaces the body of an existing issue comment
+func updateGitHubIssueComment(ctx context.Context, host string, installationID int64, repo string, commentID int64, body string) error {
+	token, err := githubInstallationToken(ctx, installationID, host)
+	if err != nil {
+		return err
+	}
+	payload, _ := json.Marshal(map[string]string{"body": body})
+	api := fmt.Sprintf("%s/repos/%s/issues/comments/%d", githubAPIBaseURL(host), repo, commentID)
+	resp, err := doGitHubRequest(ctx, http.MethodPatch, api, "token "+token, "", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(b))
+	}
+	return nil
+}
+
+// patchSessionAnnotations merges annotations into an AgenticSession using the backend SA
+func patchSessionAnnotations(ctx context.Context, project, name string, annotations map[string]string) {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if _, err := VteamClient.VteamV1alpha1().AgenticSessions(project).Patch(ctx, name, ktypes.MergePatchType, patch, v1.PatchOptions{}); err != nil {
+		log.Printf("patchSessionAnnotations: failed for %s/%s: %v", project, name, err)
+	}
+}
+
+// WatchSessionsForGitHub reports session phase changes and final results to GitHub: comments on
+// the originating issue for webhook-triggered sessions and Check Runs for sessions on PR branches
+func WatchSessionsForGitHub() {
+	for {
+		if VteamClient == nil {
+			time.Sleep(5 * time.Second)
+			continue
+		}
+		watcher, err := VteamClient.VteamV1alpha1().AgenticSessions("").Watch(context.Background(), v1.ListOptions{
+			LabelSelector: githubReportLabelKey + "=true",
+		})
+		if err != nil {
+			log.Printf("Failed to create GitHub session watcher: %v", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		for event := range watcher.ResultChan() {
+			if event.Type != watch.Added && event.Type != watch.Modified {
+				continue
+			}
+			session, ok := event.Object.(*vteamv1alpha1.AgenticSession)
+			if !ok {
+				continue
+			}
+			if GithubTokenManager == nil {
+				continue
+			}
+			reportGitHubSessionProgress(session)
+			reportSessionCheckRun(session)
+		}
+
+		watcher.Stop()
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// reportGitHubSessionProgress updates the GitHub comment when the session phase changed since last report
+func reportGitHubSessionProgress(session *vteamv1alpha1.AgenticSession) {
+	anns := session.Annotations
+	phase := string(session.Status.Phase)
+	if phase == "" || anns[githubReportedPhaseAnnotation] == phase {
+		return
+	}
+
+	repo := anns[githubRepoAnnotation]
+	number, _ := strconv.Atoi(anns[githubNumberAnnotation])
+	commentID, _ := strconv.ParseInt(anns[githubCommentAnnotation], 10, 64)
+	if session.Labels[githubTriggerLabelKey] != "true" || repo == "" || number == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	project, name := session.Namespace, session.Name
+
+	inst, err := GetProjectGitHubInstallation(ctx, project)
+	if err != nil {
+		return
+	}
+	installationID, host := inst.InstallationID, inst.Host
+
+	terminal := isTerminalSessionPhase(phase)
+	progress := fmt.Sprintf("Ambient session `%s` in project `%s`.\n\nPhase: **%s**", name, project, phase)
+	if msg := session.Status.Message; msg != "" && !terminal {
+		progress += "\n\n" + msg
+	}
+	if commentID != 0 {
+		if err := updateGitHubIssueComment(ctx, host, installationID, repo, commentID, progress); err != nil {
+			log.Printf("githubWebhook: failed to update progress comment for %s/%s: %v", project, name, err)
+		}
+	}
+
+	if terminal {
+		if _, err := postGitHubIssueComment(ctx, host, installationID, repo, number, githubSessionResultComment(session, phase)); err != nil {
+			log.Printf("githubWebhook: failed to post result comment for %s/%s: %v", project, name, err)
+		}
+	}
+
+	patchSessionAnnotations(ctx, project, name, map[string]string{githubReportedPhaseAnnotation: phase})
+}
+
+// githubSessionResultComment renders the final comment for a finished session
+func githubSessionResultComment(session *vteamv1alpha1.AgenticSession, phase string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Ambient session `%s` finished with phase **%s**.\n", session.Name, phase)
+	if cost := session.Status.TotalCostUSD; cost != nil {
+		fmt.Fprintf(&sb, "\nCost: $%.4f", *cost)
+	}
+	if turns := session.Status.NumTurns; turns > 0 {
+		fmt.Fprintf(&sb, "\nTurns: %d", turns)
+	}
+	result := ""
+	if session.Status.Result != nil {
+		result = *session.Status.Result
+	}
+	if result == "" {
+		result = session.Status.Message
+	}
+	if result = strings.TrimSpace(result); result != "" {
+		if len(result) > 60000 {
+			result = tracker.TruncateUTF8(result, 60000) + "\n..."
+		}
+		sb.WriteString("\n\n" + result)
+	}
+	return sb.String()
+}
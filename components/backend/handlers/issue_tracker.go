@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ambient-code-backend/tracker"
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResolveIssueTracker builds the issue tracker for an RFE workflow using the caller's credentials.
+// trackerType selects a specific tracker (e.g. the one an existing link was published to);
+// empty uses ProjectSettings spec.issueTracker.type.
+func ResolveIssueTracker(c *gin.Context, project string, wf *types.RFEWorkflow, trackerType string) (tracker.IssueTracker, error) {
+	reqK8s, reqDyn := GetK8sClientsForRequest(c)
+	if reqK8s == nil || reqDyn == nil {
+		return nil, fmt.Errorf("missing or invalid user token")
+	}
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	it, _, err := resolveIssueTracker(c.Request.Context(), reqK8s, reqDyn, project, wf, trackerType, userIDStr)
+	return it, err
+}
+
+// resolveIssueTracker builds an issue tracker with the given clients and also returns the
+// project's tracker configuration. Background sync passes the backend clients and no user,
+// so GitHub issues use the project's GitHub installation or runner secret.
+func resolveIssueTracker(ctx context.Context, k8s *kubernetes.Clientset, dyn dynamic.Interface, project string, wf *types.RFEWorkflow, trackerType, userID string) (tracker.IssueTracker, tracker.Config, error) {
+	spec := map[string]interface{}{}
+	if obj, err := dyn.Resource(GetProjectSettingsResource()).Namespace(project).Get(ctx, "projectsettings", v1.GetOptions{}); err == nil {
+		if s, ok := obj.Object["spec"].(map[string]interface{}); ok {
+			spec = s
+		}
+	}
+	cfg := tracker.ParseConfig(spec)
+	if trackerType == "" {
+		trackerType = cfg.Type
+	}
+
+	switch trackerType {
+	case tracker.TypeJira:
+		secretName := "ambient-runner-secrets"
+		if v, ok := spec["runnerSecretsName"].(string); ok && strings.TrimSpace(v) != "" {
+			secretName = strings.TrimSpace(v)
+		}
+		sec, err := k8s.CoreV1().Secrets(project).Get(ctx, secretName, v1.GetOptions{})
+		if err != nil {
+			return nil, cfg, fmt.Errorf("failed to read runner secret: %w", err)
+		}
+		get := func(k string) string { return strings.TrimSpace(string(sec.Data[k])) }
+		jiraURL, jiraProject, jiraToken := get("JIRA_URL"), get("JIRA_PROJECT"), get("JIRA_API_TOKEN")
+		if jiraURL == "" || jiraProject == "" || jiraToken == "" {
+			return nil, cfg, fmt.Errorf("missing Jira configuration in runner secret (JIRA_URL, JIRA_PROJECT, JIRA_API_TOKEN required)")
+		}
+		jt := tracker.NewJiraTracker(jiraURL, jiraProject, get("JIRA_EMAIL"), jiraToken, cfg.JiraIssueTypes)
+		switch cfg.JiraDeployment {
+		case "cloud":
+			jt.Cloud = true
+		case "server", "datacenter":
+			jt.Cloud = false
+		}
+		return jt, cfg, nil
+
+	case tracker.TypeGitHub:
+		repoURL := cfg.GitHubRepository
+		if repoURL == "" && wf != nil && wf.UmbrellaRepo != nil {
+			repoURL = wf.UmbrellaRepo.URL
+		}
+		host, repo := "github.com", strings.Trim(repoURL, "/")
+		if h, r, ok := parseGitHubRepoURL(repoURL); ok {
+			host, repo = h, r
+		}
+		if strings.Count(repo, "/") != 1 {
+			return nil, cfg, fmt.Errorf("no GitHub repository configured for issues (set issueTracker.github.repository or an umbrella repo)")
+		}
+		token, err := GetGitHubToken(ctx, k8s, dyn, project, userID)
+		if err != nil {
+			return nil, cfg, fmt.Errorf("failed to get GitHub token: %w", err)
+		}
+		return tracker.NewGitHubTracker(host, repo, token, cfg.GitHubLabels), cfg, nil
+	}
+	return nil, cfg, fmt.Errorf("unsupported issue tracker %q", trackerType)
+}
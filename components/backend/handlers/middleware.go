@@ -8,6 +8,9 @@ import (
 	"strings"
 	"time"
 
+	vteamclient "ambient-code-api/clientset/versioned"
+	vteamv1alpha1client "ambient-code-api/clientset/versioned/typed/vteam/v1alpha1"
+
 	"github.com/gin-gonic/gin"
 	authv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -43,45 +46,13 @@ type ContentListItem struct {
 // It supports both Authorization: Bearer and X-Forwarded-Access-Token and NEVER falls back to the backend service account.
 // Returns nil, nil if no valid user token is provided - all API operations require user authentication.
 func GetK8sClientsForRequest(c *gin.Context) (*kubernetes.Clientset, dynamic.Interface) {
-	// Prefer Authorization header (Bearer <token>)
-	rawAuth := c.GetHeader("Authorization")
-	rawFwd := c.GetHeader("X-Forwarded-Access-Token")
-	tokenSource := "none"
-	token := rawAuth
-
-	if token != "" {
-		tokenSource = "authorization"
-		parts := strings.SplitN(token, " ", 2)
-		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
-			token = strings.TrimSpace(parts[1])
-		} else {
-			token = strings.TrimSpace(token)
-		}
-	}
-	// Fallback to X-Forwarded-Access-Token
-	if token == "" {
-		if rawFwd != "" {
-			tokenSource = "x-forwarded-access-token"
-		}
-		token = rawFwd
-	}
-
-	// Debug: basic auth header state (do not log token)
-	hasAuthHeader := strings.TrimSpace(rawAuth) != ""
-	hasFwdToken := strings.TrimSpace(rawFwd) != ""
+	token, tokenSource := requestToken(c)
 
 	if token != "" && BaseKubeConfig != nil {
-		cfg := *BaseKubeConfig
-		cfg.BearerToken = token
-		// Ensure we do NOT fall back to the in-cluster SA token or other auth providers
-		cfg.BearerTokenFile = ""
-		cfg.AuthProvider = nil
-		cfg.ExecProvider = nil
-		cfg.Username = ""
-		cfg.Password = ""
+		cfg := userRestConfig(token)
 
-		kc, err1 := kubernetes.NewForConfig(&cfg)
-		dc, err2 := dynamic.NewForConfig(&cfg)
+		kc, err1 := kubernetes.NewForConfig(cfg)
+		dc, err2 := dynamic.NewForConfig(cfg)
 
 		if err1 == nil && err2 == nil {
 
@@ -94,11 +65,61 @@ func GetK8sClientsForRequest(c *gin.Context) (*kubernetes.Clientset, dynamic.Int
 		return nil, nil
 	} else {
 		// No token provided
-		log.Printf("No user token found for %s (hasAuthHeader=%t hasFwdToken=%t)", c.FullPath(), hasAuthHeader, hasFwdToken)
+		log.Printf("No user token found for %s (hasAuthHeader=%t hasFwdToken=%t)", c.FullPath(), strings.TrimSpace(c.GetHeader("Authorization")) != "", strings.TrimSpace(c.GetHeader("X-Forwarded-Access-Token")) != "")
 		return nil, nil
 	}
 }
 
+// GetSessionClientForRequest returns a typed AgenticSession client for project using the caller's
+// token, with the same rules as GetK8sClientsForRequest. Returns nil without a valid user token.
+func GetSessionClientForRequest(c *gin.Context, project string) vteamv1alpha1client.AgenticSessionInterface {
+	token, tokenSource := requestToken(c)
+	if token == "" || BaseKubeConfig == nil {
+		log.Printf("No user token found for %s", c.FullPath())
+		return nil
+	}
+	vc, err := vteamclient.NewForConfig(userRestConfig(token))
+	if err != nil {
+		log.Printf("Failed to build user-scoped session client (source=%s tokenLen=%d) for %s: %v", tokenSource, len(token), c.FullPath(), err)
+		return nil
+	}
+	return vc.VteamV1alpha1().AgenticSessions(project)
+}
+
+// requestToken returns the caller's bearer token, preferring the Authorization header over
+// X-Forwarded-Access-Token, and which of the two it came from
+func requestToken(c *gin.Context) (string, string) {
+	if token := c.GetHeader("Authorization"); token != "" {
+		parts := strings.SplitN(token, " ", 2)
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			token = strings.TrimSpace(parts[1])
+		} else {
+			token = strings.TrimSpace(token)
+		}
+		if token != "" {
+			return token, "authorization"
+		}
+	}
+	// Fallback to X-Forwarded-Access-Token
+	if token := c.GetHeader("X-Forwarded-Access-Token"); token != "" {
+		return token, "x-forwarded-access-token"
+	}
+	return "", "none"
+}
+
+// userRestConfig copies BaseKubeConfig authenticated only by token
+func userRestConfig(token string) *rest.Config {
+	cfg := *BaseKubeConfig
+	cfg.BearerToken = token
+	// Ensure we do NOT fall back to the in-cluster SA token or other auth providers
+	cfg.BearerTokenFile = ""
+	cfg.AuthProvider = nil
+	cfg.ExecProvider = nil
+	cfg.Username = ""
+	cfg.Password = ""
+	return &cfg
+}
+
 // updateAccessKeyLastUsedAnnotation attempts to update the ServiceAccount's last-used annotation
 // when the incoming token is a ServiceAccount JWT. Uses the backend service account client strictly
 // for this telemetry update and only for SAs labeled app=ambient-access-key. Best-effort; errors ignored.
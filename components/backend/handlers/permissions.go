@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	authnv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -164,6 +164,12 @@ func AddProjectPermission(c *gin.Context) {
 		return
 	}
 
+	auditAction(c, "permission.create", "permission", st+":"+req.SubjectName, map[string]string{
+		"subjectType": st,
+		"subjectName": req.SubjectName,
+		"role":        strings.ToLower(req.Role),
+	})
+
 	rbName := "ambient-permission-" + strings.ToLower(req.Role) + "-" + sanitizeName(req.SubjectName) + "-" + st
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: v1.ObjectMeta{
@@ -210,6 +216,10 @@ func RemoveProjectPermission(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "subjectName is required"})
 		return
 	}
+	auditAction(c, "permission.delete", "permission", subjectType+":"+subjectName, map[string]string{
+		"subjectType": subjectType,
+		"subjectName": subjectName,
+	})
 
 	rbs, err := reqK8s.RbacV1().RoleBindings(projectName).List(context.TODO(), v1.ListOptions{LabelSelector: "app=ambient-permission"})
 	if err != nil {
@@ -218,18 +228,24 @@ func RemoveProjectPermission(c *gin.Context) {
 		return
 	}
 
+	var removedRoles []string
 	for _, rb := range rbs.Items {
 		for _, sub := range rb.Subjects {
 			if strings.EqualFold(sub.Kind, "Group") && subjectType == "group" && sub.Name == subjectName {
-				_ = reqK8s.RbacV1().RoleBindings(projectName).Delete(context.TODO(), rb.Name, v1.DeleteOptions{})
+				if err := reqK8s.RbacV1().RoleBindings(projectName).Delete(context.TODO(), rb.Name, v1.DeleteOptions{}); err == nil {
+					removedRoles = append(removedRoles, rb.Annotations["ambient-code.io/role"])
+				}
 				break
 			}
 			if strings.EqualFold(sub.Kind, "User") && subjectType == "user" && sub.Name == subjectName {
-				_ = reqK8s.RbacV1().RoleBindings(projectName).Delete(context.TODO(), rb.Name, v1.DeleteOptions{})
+				if err := reqK8s.RbacV1().RoleBindings(projectName).Delete(context.TODO(), rb.Name, v1.DeleteOptions{}); err == nil {
+					removedRoles = append(removedRoles, rb.Annotations["ambient-code.io/role"])
+				}
 				break
 			}
 		}
 	}
+	auditDetail(c, "removedRoles", strings.Join(removedRoles, ","))
 
 	c.Status(http.StatusNoContent)
 }
@@ -272,12 +288,16 @@ func ListProjectKeys(c *gin.Context) {
 	}
 
 	type KeyInfo struct {
-		ID          string `json:"id"`
-		Name        string `json:"name"`
-		CreatedAt   string `json:"createdAt"`
-		LastUsedAt  string `json:"lastUsedAt"`
-		Description string `json:"description,omitempty"`
-		Role        string `json:"role,omitempty"`
+		ID          string   `json:"id"`
+		Name        string   `json:"name"`
+		CreatedAt   string   `json:"createdAt"`
+		LastUsedAt  string   `json:"lastUsedAt"`
+		Description string   `json:"description,omitempty"`
+		Role        string   `json:"role,omitempty"`
+		Scopes      []string `json:"scopes,omitempty"`
+		ExpiresAt   string   `json:"expiresAt,omitempty"`
+		Expired     bool     `json:"expired,omitempty"`
+		RotatedAt   string   `json:"rotatedAt,omitempty"`
 	}
 
 	items := []KeyInfo{}
@@ -289,34 +309,63 @@ func ListProjectKeys(c *gin.Context) {
 		if lu := sa.Annotations["ambient-code.io/last-used-at"]; lu != "" {
 			ki.LastUsedAt = lu
 		}
+		if scopes := sa.Annotations[annKeyScopes]; scopes != "" {
+			ki.Scopes = strings.Split(scopes, ",")
+		}
+		if exp := sa.Annotations[annKeyExpiresAt]; exp != "" {
+			ki.ExpiresAt = exp
+			if t, err := time.Parse(time.RFC3339, exp); err == nil && time.Now().After(t) {
+				ki.Expired = true
+			}
+		}
+		ki.RotatedAt = sa.Annotations[annKeyRotatedAt]
 		items = append(items, ki)
 	}
 	c.JSON(http.StatusOK, gin.H{"items": items})
 }
 
 // CreateProjectKey handles POST /api/projects/:projectName/keys
-// Creates a new access key (ServiceAccount with token and RoleBinding)
+// Creates a new access key (ServiceAccount with an expiring token and a RoleBinding). Keys either
+// get an ambient role or, with scopes, a generated Role limited to those scopes.
 func CreateProjectKey(c *gin.Context) {
 	projectName := c.Param("projectName")
 	reqK8s, _ := GetK8sClientsForRequest(c)
 
 	var req struct {
-		Name        string `json:"name" binding:"required"`
-		Description string `json:"description"`
-		Role        string `json:"role"`
+		Name        string   `json:"name" binding:"required"`
+		Description string   `json:"description"`
+		Role        string   `json:"role"`
+		TTL         string   `json:"ttl"`
+		Scopes      []string `json:"scopes"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Determine role to bind; default edit
+	ttl, err := parseAccessKeyTTL(req.TTL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scopes, err := normalizeAccessKeyScopes(req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Determine role to bind; default edit. Scoped keys are bound to a generated Role instead.
 	role := strings.ToLower(strings.TrimSpace(req.Role))
-	if role == "" {
+	if len(scopes) > 0 && role != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role and scopes are mutually exclusive"})
+		return
+	}
+	if role == "" && len(scopes) == 0 {
 		role = "edit"
 	}
 	var roleRefName string
 	switch role {
+	case "":
 	case "admin":
 		roleRefName = AmbientRoleAdmin
 	case "edit":
@@ -328,9 +377,31 @@ func CreateProjectKey(c *gin.Context) {
 		return
 	}
 
+	// A scoped key may only carry permissions its creator holds. The Role and RoleBinding are
+	// created with the caller's client, where the API server enforces that too; checking first
+	// reports what is missing before anything is created.
+	if len(scopes) > 0 {
+		missing, err := missingAccessKeyPermissions(c.Request.Context(), reqK8s, projectName, accessKeyScopeRules(scopes))
+		if err != nil {
+			log.Printf("CreateProjectKey: SSAR failed for %s: %v", projectName, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+			return
+		}
+		if len(missing) > 0 {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You cannot grant permissions you do not have: " + strings.Join(missing, ", ")})
+			return
+		}
+	}
+
 	// Create a dedicated ServiceAccount per key
 	ts := time.Now().Unix()
 	saName := fmt.Sprintf("ambient-key-%s-%d", sanitizeName(req.Name), ts)
+	auditAction(c, "access-key.create", "access-key", saName, map[string]string{
+		"name":   req.Name,
+		"role":   role,
+		"scopes": strings.Join(scopes, ","),
+		"ttl":    ttl.String(),
+	})
 	sa := &corev1.ServiceAccount{
 		ObjectMeta: v1.ObjectMeta{
 			Name:      saName,
@@ -341,16 +412,26 @@ func CreateProjectKey(c *gin.Context) {
 				"ambient-code.io/description": req.Description,
 				"ambient-code.io/created-at":  time.Now().Format(time.RFC3339),
 				"ambient-code.io/role":        role,
+				annKeyTTL:                     ttl.String(),
 			},
 		},
 	}
-	if _, err := reqK8s.CoreV1().ServiceAccounts(projectName).Create(context.TODO(), sa, v1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+	if len(scopes) > 0 {
+		sa.Annotations[annKeyScopes] = strings.Join(scopes, ",")
+	}
+	createdSA, err := reqK8s.CoreV1().ServiceAccounts(projectName).Create(context.TODO(), sa, v1.CreateOptions{})
+	if err != nil {
 		log.Printf("Failed to create ServiceAccount %s in %s: %v", saName, projectName, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create service account"})
 		return
 	}
+	// Everything below is owned by the ServiceAccount; deleting it cleans up a half-created key
+	cleanup := func() {
+		_ = reqK8s.CoreV1().ServiceAccounts(projectName).Delete(context.TODO(), saName, v1.DeleteOptions{})
+	}
+	owner := saOwnerReference(createdSA)
 
-	// Bind the SA to the selected role via RoleBinding
+	// Bind the SA to the selected role, or to a generated Role for its scopes
 	rbName := fmt.Sprintf("ambient-key-%s-%s-%d", role, sanitizeName(req.Name), ts)
 	rb := &rbacv1.RoleBinding{
 		ObjectMeta: v1.ObjectMeta{
@@ -362,21 +443,62 @@ func CreateProjectKey(c *gin.Context) {
 				"ambient-code.io/sa-name":  saName,
 				"ambient-code.io/role":     role,
 			},
+			OwnerReferences: []v1.OwnerReference{owner},
 		},
 		RoleRef:  rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: roleRefName},
 		Subjects: []rbacv1.Subject{{Kind: "ServiceAccount", Name: saName, Namespace: projectName}},
 	}
-	if _, err := reqK8s.RbacV1().RoleBindings(projectName).Create(context.TODO(), rb, v1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
-		log.Printf("Failed to create RoleBinding %s in %s: %v", rbName, projectName, err)
+	if len(scopes) > 0 {
+		scopedRole := &rbacv1.Role{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      saName,
+				Namespace: projectName,
+				Labels:    map[string]string{"app": "ambient-access-key"},
+				Annotations: map[string]string{
+					"ambient-code.io/sa-name": saName,
+					annKeyScopes:              strings.Join(scopes, ","),
+				},
+				OwnerReferences: []v1.OwnerReference{owner},
+			},
+			Rules: accessKeyScopeRules(scopes),
+		}
+		if _, err := reqK8s.RbacV1().Roles(projectName).Create(context.TODO(), scopedRole, v1.CreateOptions{}); err != nil {
+			log.Printf("Failed to create scoped Role %s in %s: %v", saName, projectName, err)
+			cleanup()
+			if errors.IsForbidden(err) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You cannot grant the permissions of these scopes"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scoped role"})
+			}
+			return
+		}
+		rb.Name = fmt.Sprintf("ambient-key-scoped-%s-%d", sanitizeName(req.Name), ts)
+		rb.Annotations[annKeyScopes] = strings.Join(scopes, ",")
+		rb.RoleRef = rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: saName}
+		_, err = reqK8s.RbacV1().RoleBindings(projectName).Create(context.TODO(), rb, v1.CreateOptions{})
+	} else {
+		_, err = reqK8s.RbacV1().RoleBindings(projectName).Create(context.TODO(), rb, v1.CreateOptions{})
+	}
+	if err != nil && !errors.IsAlreadyExists(err) {
+		log.Printf("Failed to create RoleBinding %s in %s: %v", rb.Name, projectName, err)
+		cleanup()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to bind service account"})
 		return
 	}
 
-	// Issue a one-time JWT token for this ServiceAccount (no audience; used as API key)
-	tr := &authnv1.TokenRequest{Spec: authnv1.TokenRequestSpec{}}
-	tok, err := reqK8s.CoreV1().ServiceAccounts(projectName).CreateToken(context.TODO(), saName, tr, v1.CreateOptions{})
+	// Issue an expiring JWT bound to a Secret so the key can be rotated and revoked
+	token, secret, expiresAt, err := issueAccessKeyToken(context.TODO(), reqK8s, createdSA, ttl)
 	if err != nil {
 		log.Printf("Failed to create token for SA %s/%s: %v", projectName, saName, err)
+		cleanup()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
+		return
+	}
+	createdSA.Annotations[annKeyTokenSecret] = secret.Name
+	createdSA.Annotations[annKeyExpiresAt] = expiresAt.Format(time.RFC3339)
+	if _, err := reqK8s.CoreV1().ServiceAccounts(projectName).Update(context.TODO(), createdSA, v1.UpdateOptions{}); err != nil {
+		log.Printf("Failed to record expiry of access key %s/%s: %v", projectName, saName, err)
+		cleanup()
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
 		return
 	}
@@ -384,19 +506,126 @@ func CreateProjectKey(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{
 		"id":          saName,
 		"name":        req.Name,
-		"key":         tok.Status.Token,
+		"key":         token,
 		"description": req.Description,
 		"role":        role,
+		"scopes":      scopes,
+		"expiresAt":   expiresAt.Format(time.RFC3339),
 		"lastUsedAt":  "",
 	})
 }
 
+// RotateProjectKey handles POST /api/projects/:projectName/keys/:keyId/rotate
+// Issues a new token for the key (with the key's TTL unless the body sets ttl) and revokes the
+// previous one by deleting the Secret it was bound to
+func RotateProjectKey(c *gin.Context) {
+	projectName := c.Param("projectName")
+	keyID := c.Param("keyId")
+	reqK8s, _ := GetK8sClientsForRequest(c)
+
+	var req struct {
+		TTL string `json:"ttl"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	sa, err := reqK8s.CoreV1().ServiceAccounts(projectName).Get(context.TODO(), keyID, v1.GetOptions{})
+	if err != nil || sa.Labels["app"] != "ambient-access-key" {
+		if err == nil || errors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Access key not found"})
+			return
+		}
+		log.Printf("Failed to get access key %s in %s: %v", keyID, projectName, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate access key"})
+		return
+	}
+	rawTTL := req.TTL
+	if strings.TrimSpace(rawTTL) == "" {
+		rawTTL = sa.Annotations[annKeyTTL]
+	}
+	ttl, err := parseAccessKeyTTL(rawTTL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	auditAction(c, "access-key.rotate", "access-key", keyID, map[string]string{
+		"name": sa.Annotations["ambient-code.io/key-name"],
+		"ttl":  ttl.String(),
+	})
+
+	token, secret, expiresAt, err := issueAccessKeyToken(context.TODO(), reqK8s, sa, ttl)
+	if err != nil {
+		log.Printf("Failed to rotate token for SA %s/%s: %v", projectName, keyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate access token"})
+		return
+	}
+	if sa.Annotations == nil {
+		sa.Annotations = map[string]string{}
+	}
+	previous := sa.Annotations[annKeyTokenSecret]
+	recorded := map[string]string{}
+	for _, k := range []string{annKeyTokenSecret, annKeyExpiresAt, annKeyTTL, annKeyRotatedAt} {
+		recorded[k] = sa.Annotations[k]
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	sa.Annotations[annKeyTokenSecret] = secret.Name
+	sa.Annotations[annKeyExpiresAt] = expiresAt.Format(time.RFC3339)
+	sa.Annotations[annKeyTTL] = ttl.String()
+	sa.Annotations[annKeyRotatedAt] = now
+	updated, err := reqK8s.CoreV1().ServiceAccounts(projectName).Update(context.TODO(), sa, v1.UpdateOptions{})
+	if err != nil {
+		// Keep the old token valid rather than leave the key without a recorded token
+		if rerr := revokeAccessKeyToken(context.TODO(), reqK8s, sa, secret.Name); rerr != nil {
+			log.Printf("Failed to revoke unrecorded token of access key %s/%s: %v", projectName, keyID, rerr)
+		}
+		log.Printf("Failed to record rotation of access key %s/%s: %v", projectName, keyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate access key"})
+		return
+	}
+
+	// Revoke the previous token. Keys created before rotation support have unbound tokens that
+	// can't be revoked individually; they lapse at their original expiry. If revocation fails the
+	// rotation is undone, so the key never reports a new token while the old one stays valid.
+	revoked := false
+	if previous != "" {
+		if err := revokeAccessKeyToken(context.TODO(), reqK8s, updated, previous); err != nil {
+			log.Printf("Failed to revoke previous token of access key %s/%s: %v", projectName, keyID, err)
+			for k, v := range recorded {
+				if v == "" {
+					delete(updated.Annotations, k)
+				} else {
+					updated.Annotations[k] = v
+				}
+			}
+			if _, uerr := reqK8s.CoreV1().ServiceAccounts(projectName).Update(context.TODO(), updated, v1.UpdateOptions{}); uerr != nil {
+				log.Printf("Failed to restore access key %s/%s after a failed rotation: %v", projectName, keyID, uerr)
+			} else if rerr := revokeAccessKeyToken(context.TODO(), reqK8s, updated, secret.Name); rerr != nil {
+				log.Printf("Failed to revoke new token of access key %s/%s after a failed rotation: %v", projectName, keyID, rerr)
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke the previous token; the access key was not rotated"})
+			return
+		}
+		revoked = true
+	}
+
+	auditDetail(c, "previousRevoked", strconv.FormatBool(revoked))
+	c.JSON(http.StatusOK, gin.H{
+		"id":              keyID,
+		"name":            sa.Annotations["ambient-code.io/key-name"],
+		"key":             token,
+		"expiresAt":       expiresAt.Format(time.RFC3339),
+		"rotatedAt":       now,
+		"previousRevoked": revoked,
+	})
+}
+
 // DeleteProjectKey handles DELETE /api/projects/:projectName/keys/:keyId
-// Deletes an access key (ServiceAccount and associated RoleBindings)
+// Deletes an access key (ServiceAccount and associated RoleBindings). Scoped Roles and token
+// Secrets are owned by the ServiceAccount and garbage collected with it.
 func DeleteProjectKey(c *gin.Context) {
 	projectName := c.Param("projectName")
 	keyID := c.Param("keyId")
 	reqK8s, _ := GetK8sClientsForRequest(c)
+	auditAction(c, "access-key.delete", "access-key", keyID, nil)
 
 	// Delete associated RoleBindings
 	rbs, _ := reqK8s.RbacV1().RoleBindings(projectName).List(context.TODO(), v1.ListOptions{LabelSelector: "app=ambient-access-key"})
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// projectTemplatesConfigMap holds the cluster's project templates in the backend namespace,
+	// one YAML or JSON document per data key; the key is the template name
+	projectTemplatesConfigMap = "ambient-project-templates"
+	// sessionTemplatesConfigMap holds a project's starter sessions, one JSON document per key
+	sessionTemplatesConfigMap = "ambient-session-templates"
+	// projectTemplateAnnotation records the template a project or its objects came from
+	projectTemplateAnnotation = "ambient-code.io/project-template"
+)
+
+// loadProjectTemplates reads the project templates; a missing ConfigMap means none are offered
+// and templates that do not parse are logged and skipped
+func loadProjectTemplates(ctx context.Context) (map[string]types.ProjectTemplate, error) {
+	templates := map[string]types.ProjectTemplate{}
+	cm, err := K8sClientProjects.CoreV1().ConfigMaps(Namespace).Get(ctx, projectTemplatesConfigMap, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return templates, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", projectTemplatesConfigMap, err)
+	}
+	for name, raw := range cm.Data {
+		var t types.ProjectTemplate
+		if err := yaml.Unmarshal([]byte(raw), &t); err != nil {
+			log.Printf("Skipping project template %s: %v", name, err)
+			continue
+		}
+		t.Name = name
+		if err := validateProjectTemplate(t); err != nil {
+			log.Printf("Skipping project template %s: %v", name, err)
+			continue
+		}
+		templates[name] = t
+	}
+	return templates, nil
+}
+
+// validateProjectTemplate checks what the API server cannot: secret key and session template names
+func validateProjectTemplate(t types.ProjectTemplate) error {
+	keys := map[string]bool{}
+	for _, s := range t.RunnerSecrets {
+		if msgs := validation.IsEnvVarName(s.Key); len(msgs) > 0 {
+			return fmt.Errorf("runner secret key %q: %s", s.Key, strings.Join(msgs, "; "))
+		}
+		if keys[s.Key] {
+			return fmt.Errorf("runner secret key %q is listed twice", s.Key)
+		}
+		keys[s.Key] = true
+	}
+	names := map[string]bool{}
+	for _, st := range t.SessionTemplates {
+		if msgs := validation.IsConfigMapKey(st.Name); len(msgs) > 0 {
+			return fmt.Errorf("session template %q: %s", st.Name, strings.Join(msgs, "; "))
+		}
+		if names[st.Name] {
+			return fmt.Errorf("session template %q is defined twice", st.Name)
+		}
+		names[st.Name] = true
+	}
+	return nil
+}
+
+// checkTemplateSecrets requires every required key of the template and rejects keys it does not ask for
+func checkTemplateSecrets(t types.ProjectTemplate, values map[string]string) error {
+	declared := map[string]bool{}
+	var missing []string
+	for _, s := range t.RunnerSecrets {
+		declared[s.Key] = true
+		if s.Required && strings.TrimSpace(values[s.Key]) == "" {
+			missing = append(missing, s.Key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("template %s requires runner secrets: %s", t.Name, strings.Join(missing, ", "))
+	}
+	var unknown []string
+	for k := range values {
+		if !declared[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("template %s does not ask for runner secrets: %s", t.Name, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// applyProjectTemplate creates the template's objects in a freshly created project namespace with
+// the backend service account. Quotas and limits go first so nothing runs unconstrained, and
+// ProjectSettings last since the operator reconciles access from it. The caller deletes the
+// namespace when this fails, which removes whatever was already created.
+func applyProjectTemplate(ctx context.Context, namespace string, t types.ProjectTemplate, secretValues map[string]string) error {
+	meta := func(name string) v1.ObjectMeta {
+		return v1.ObjectMeta{
+			Name:        name,
+			Namespace:   namespace,
+			Annotations: map[string]string{projectTemplateAnnotation: t.Name},
+		}
+	}
+
+	if t.ResourceQuota != nil {
+		quota := &corev1.ResourceQuota{ObjectMeta: meta("ambient-project-quota"), Spec: *t.ResourceQuota}
+		if _, err := K8sClientProjects.CoreV1().ResourceQuotas(namespace).Create(ctx, quota, v1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create ResourceQuota: %w", err)
+		}
+	}
+	if t.LimitRange != nil {
+		limits := &corev1.LimitRange{ObjectMeta: meta("ambient-project-limits"), Spec: *t.LimitRange}
+		if _, err := K8sClientProjects.CoreV1().LimitRanges(namespace).Create(ctx, limits, v1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create LimitRange: %w", err)
+		}
+	}
+
+	spec := t.ProjectSettings
+	if spec == nil {
+		spec = map[string]interface{}{}
+	}
+	if _, ok := spec["groupAccess"]; !ok {
+		spec["groupAccess"] = []interface{}{}
+	}
+
+	values := map[string]string{}
+	for k, v := range secretValues {
+		if strings.TrimSpace(v) != "" {
+			values[k] = v
+		}
+	}
+	if len(values) > 0 {
+		secretName, _ := spec["runnerSecretsName"].(string)
+		if strings.TrimSpace(secretName) == "" {
+			secretName = "ambient-runner-secrets"
+			spec["runnerSecretsName"] = secretName
+		}
+		sec := &corev1.Secret{
+			ObjectMeta: meta(secretName),
+			Type:       corev1.SecretTypeOpaque,
+			StringData: values,
+		}
+		sec.Labels = map[string]string{"app": "ambient-runner-secrets"}
+		sec.Annotations["ambient-code.io/runner-secret"] = "true"
+		if _, err := K8sClientProjects.CoreV1().Secrets(namespace).Create(ctx, sec, v1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create runner secret: %w", err)
+		}
+	}
+
+	if len(t.SessionTemplates) > 0 {
+		cm := &corev1.ConfigMap{ObjectMeta: meta(sessionTemplatesConfigMap), Data: map[string]string{}}
+		for _, st := range t.SessionTemplates {
+			b, err := json.Marshal(st)
+			if err != nil {
+				return fmt.Errorf("encode session template %s: %w", st.Name, err)
+			}
+			cm.Data[st.Name] = string(b)
+		}
+		if _, err := K8sClientProjects.CoreV1().ConfigMaps(namespace).Create(ctx, cm, v1.CreateOptions{}); err != nil {
+			return fmt.Errorf("create session templates: %w", err)
+		}
+	}
+
+	// The operator creates a default ProjectSettings for new namespaces; whichever lands first,
+	// the template's spec wins
+	gvr := GetProjectSettingsResource()
+	ps := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "vteam.ambient-code/v1alpha1",
+		"kind":       "ProjectSettings",
+		"metadata": map[string]interface{}{
+			"name":        "projectsettings",
+			"namespace":   namespace,
+			"annotations": map[string]interface{}{projectTemplateAnnotation: t.Name},
+		},
+		"spec": spec,
+	}}
+	_, err := DynamicClientProjects.Resource(gvr).Namespace(namespace).Create(ctx, ps, v1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		existing, getErr := DynamicClientProjects.Resource(gvr).Namespace(namespace).Get(ctx, "projectsettings", v1.GetOptions{})
+		if getErr != nil {
+			return fmt.Errorf("read ProjectSettings: %w", getErr)
+		}
+		existing.Object["spec"] = spec
+		anns := existing.GetAnnotations()
+		if anns == nil {
+			anns = map[string]string{}
+		}
+		anns[projectTemplateAnnotation] = t.Name
+		existing.SetAnnotations(anns)
+		_, err = DynamicClientProjects.Resource(gvr).Namespace(namespace).Update(ctx, existing, v1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("apply ProjectSettings: %w", err)
+	}
+	return nil
+}
+
+// ListProjectTemplates handles GET /api/project-templates
+func ListProjectTemplates(c *gin.Context) {
+	reqK8s, _ := GetK8sClientsForRequest(c)
+	if reqK8s == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or missing token"})
+		return
+	}
+	if K8sClientProjects == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Backend client not configured"})
+		return
+	}
+
+	templates, err := loadProjectTemplates(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to load project templates: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load project templates"})
+		return
+	}
+	items := make([]types.ProjectTemplate, 0, len(templates))
+	for _, t := range templates {
+		items = append(items, t)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+// ListSessionTemplates handles GET /api/projects/:projectName/session-templates
+func ListSessionTemplates(c *gin.Context) {
+	projectName := c.Param("projectName")
+	reqK8s, _ := GetK8sClientsForRequest(c)
+
+	items := []types.SessionTemplate{}
+	cm, err := reqK8s.CoreV1().ConfigMaps(projectName).Get(c.Request.Context(), sessionTemplatesConfigMap, v1.GetOptions{})
+	if errors.IsNotFound(err) {
+		c.JSON(http.StatusOK, gin.H{"items": items})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to read session templates in %s: %v", projectName, err)
+		if errors.IsForbidden(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions to read session templates"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read session templates"})
+		}
+		return
+	}
+	for name, raw := range cm.Data {
+		var st types.SessionTemplate
+		if err := json.Unmarshal([]byte(raw), &st); err != nil {
+			log.Printf("Skipping session template %s/%s: %v", projectName, name, err)
+			continue
+		}
+		st.Name = name
+		items = append(items, st)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
@@ -33,6 +33,7 @@ var (
 	// that require elevated permissions (e.g., creating namespaces, assigning roles)
 	K8sClientProjects *kubernetes.Clientset
 	// DynamicClientProjects is the backend SA dynamic client for OpenShift Project operations
+	// and the ProjectSettings of templated projects
 	DynamicClientProjects dynamic.Interface
 )
 
@@ -245,6 +246,33 @@ func CreateProject(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	auditProject(c, req.Name)
+	auditAction(c, "project.create", "project", req.Name, nil)
+
+	// Resolve the template up front so a bad request never leaves a namespace behind
+	var template *types.ProjectTemplate
+	if req.Template != "" {
+		auditDetail(c, "template", req.Template)
+		templates, err := loadProjectTemplates(c.Request.Context())
+		if err != nil {
+			log.Printf("CreateProject: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load project templates"})
+			return
+		}
+		t, ok := templates[req.Template]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Unknown project template %q", req.Template)})
+			return
+		}
+		if err := checkTemplateSecrets(t, req.RunnerSecrets); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		template = &t
+	} else if len(req.RunnerSecrets) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "runnerSecrets can only be set with a template"})
+		return
+	}
 
 	// Extract user identity from token
 	userSubject, err := getUserSubjectFromContext(c)
@@ -280,6 +308,9 @@ func CreateProject(c *gin.Context) {
 		}
 		ns.Annotations["openshift.io/requester"] = userSubject
 	}
+	if template != nil {
+		ns.Annotations[projectTemplateAnnotation] = template.Name
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -338,32 +369,33 @@ func CreateProject(c *gin.Context) {
 
 		// ROLLBACK: Delete the namespace since role binding failed
 		// Without the role binding, the user won't have access to their project
-		ctx3, cancel3 := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel3()
-
-		deleteErr := K8sClientProjects.CoreV1().Namespaces().Delete(ctx3, req.Name, v1.DeleteOptions{})
-		if deleteErr != nil {
-			log.Printf("CRITICAL: Failed to rollback namespace %s after role binding failure: %v", req.Name, deleteErr)
-
-			// Label the namespace as orphaned for manual cleanup
-			patch := []byte(`{"metadata":{"labels":{"ambient-code.io/orphaned":"true","ambient-code.io/orphan-reason":"role-binding-failed"}}}`)
-			ctx4, cancel4 := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel4()
-
-			_, labelErr := K8sClientProjects.CoreV1().Namespaces().Patch(
-				ctx4, req.Name, k8stypes.MergePatchType, patch, v1.PatchOptions{},
-			)
-			if labelErr != nil {
-				log.Printf("CRITICAL: Failed to label orphaned namespace %s: %v", req.Name, labelErr)
-			} else {
-				log.Printf("Labeled orphaned namespace %s for manual cleanup", req.Name)
-			}
-		}
+		rollbackProjectNamespace(req.Name, "role-binding-failed")
 
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project permissions"})
 		return
 	}
 
+	if template != nil {
+		ctx5, cancel5 := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel5()
+
+		if err := applyProjectTemplate(ctx5, req.Name, *template, req.RunnerSecrets); err != nil {
+			log.Printf("ERROR: Created namespace %s but failed to apply template %s: %v", req.Name, template.Name, err)
+
+			// ROLLBACK: a project is created with its whole template or not at all
+			rollbackProjectNamespace(req.Name, "template-failed")
+
+			status := http.StatusInternalServerError
+			if errors.IsInvalid(err) || errors.IsBadRequest(err) {
+				// Rejected by CRD validation or admission: the template itself needs fixing
+				status = http.StatusUnprocessableEntity
+			}
+			c.JSON(status, gin.H{"error": fmt.Sprintf("Failed to apply project template %q", template.Name), "details": err.Error()})
+			return
+		}
+		log.Printf("Applied project template %s to %s", template.Name, req.Name)
+	}
+
 	// On OpenShift: Update the Project resource with display metadata
 	// Use retry logic as OpenShift needs time to create the Project resource from the namespace
 	// Use backend SA dynamic client (users don't have permission to update Project resources)
@@ -447,6 +479,32 @@ func CreateProject(c *gin.Context) {
 	c.JSON(http.StatusCreated, project)
 }
 
+// rollbackProjectNamespace deletes a half-created project namespace, labelling it as orphaned for
+// manual cleanup when the delete fails
+func rollbackProjectNamespace(name, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	deleteErr := K8sClientProjects.CoreV1().Namespaces().Delete(ctx, name, v1.DeleteOptions{})
+	if deleteErr == nil {
+		return
+	}
+	log.Printf("CRITICAL: Failed to rollback namespace %s after %s: %v", name, reason, deleteErr)
+
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{"ambient-code.io/orphaned":"true","ambient-code.io/orphan-reason":%q}}}`, reason))
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel2()
+
+	_, labelErr := K8sClientProjects.CoreV1().Namespaces().Patch(
+		ctx2, name, k8stypes.MergePatchType, patch, v1.PatchOptions{},
+	)
+	if labelErr != nil {
+		log.Printf("CRITICAL: Failed to label orphaned namespace %s: %v", name, labelErr)
+	} else {
+		log.Printf("Labeled orphaned namespace %s for manual cleanup", name)
+	}
+}
+
 // GetProject handles GET /projects/:projectName
 // Returns Namespace details with OpenShift annotations if on OpenShift
 func GetProject(c *gin.Context) {
@@ -2,17 +2,14 @@ package handlers
 
 import (
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"net/url"
-	"os"
 	"strings"
 	"time"
 
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
 	"ambient-code-backend/git"
 	"ambient-code-backend/types"
 
@@ -28,9 +25,12 @@ import (
 var (
 	GetRFEWorkflowResource     func() schema.GroupVersionResource
 	UpsertProjectRFEWorkflowCR func(dynamic.Interface, *types.RFEWorkflow) error
-	PerformRepoSeeding         func(context.Context, *types.RFEWorkflow, string, string, string, string, string, string, string, string) (bool, error)
+	PerformRepoSeeding         func(context.Context, *types.RFEWorkflow, string, string, git.SeedSource) (bool, error)
 	CheckRepoSeeding           func(context.Context, string, *string, string) (bool, map[string]interface{}, error)
 	CheckBranchExists          func(context.Context, string, string, string) (bool, error)
+	PlanRepoSeeding            func(context.Context, *types.RFEWorkflow, string, string, git.SeedSource) (*git.SeedPlan, error)
+	DetectSeedDrift            func(context.Context, string, string, string, git.SeedSource) (*git.SeedDrift, error)
+	UpgradeRepoSeeding         func(context.Context, string, string, string, git.SeedSource, string) (*git.SeedUpgrade, error)
 	RfeFromUnstructured        func(*unstructured.Unstructured) *types.RFEWorkflow
 )
 
@@ -38,7 +38,7 @@ var (
 type RFEWorkflow = types.RFEWorkflow
 type CreateRFEWorkflowRequest = types.CreateRFEWorkflowRequest
 type GitRepository = types.GitRepository
-type WorkflowJiraLink = types.WorkflowJiraLink
+type WorkflowTrackerLink = types.WorkflowTrackerLink
 
 // rfeLinkSessionRequest holds the request body for linking a session to an RFE workflow
 type rfeLinkSessionRequest struct {
@@ -46,39 +46,16 @@ type rfeLinkSessionRequest struct {
 	Phase        string `json:"phase"`
 }
 
-// normalizeRepoURL normalizes a repository URL for comparison
-func normalizeRepoURL(repoURL string) string {
-	normalized := strings.ToLower(strings.TrimSpace(repoURL))
-	// Remove .git suffix
-	normalized = strings.TrimSuffix(normalized, ".git")
-	// Remove trailing slash
-	normalized = strings.TrimSuffix(normalized, "/")
-	return normalized
-}
-
 // validateUniqueRepositories checks that all repository URLs are unique
 func validateUniqueRepositories(umbrellaRepo *GitRepository, supportingRepos []GitRepository) error {
-	seen := make(map[string]bool)
-
-	// Check umbrella repo
-	if umbrellaRepo != nil && umbrellaRepo.URL != "" {
-		normalized := normalizeRepoURL(umbrellaRepo.URL)
-		seen[normalized] = true
+	urls := make([]string, 0, len(supportingRepos)+1)
+	if umbrellaRepo != nil {
+		urls = append(urls, umbrellaRepo.URL)
 	}
-
-	// Check supporting repos
 	for _, repo := range supportingRepos {
-		if repo.URL == "" {
-			continue
-		}
-		normalized := normalizeRepoURL(repo.URL)
-		if seen[normalized] {
-			return fmt.Errorf("duplicate repository URL detected: %s", repo.URL)
-		}
-		seen[normalized] = true
+		urls = append(urls, repo.URL)
 	}
-
-	return nil
+	return git.ValidateUniqueRepoURLs(urls)
 }
 
 // ListProjectRFEWorkflows lists all RFE workflows for a project
@@ -165,6 +142,10 @@ func CreateProjectRFEWorkflow(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := validateGates(req.Gates); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	workflow := &RFEWorkflow{
 		ID:              workflowID,
@@ -174,6 +155,7 @@ func CreateProjectRFEWorkflow(c *gin.Context) {
 		UmbrellaRepo:    &req.UmbrellaRepo,
 		SupportingRepos: req.SupportingRepos,
 		WorkspacePath:   req.WorkspacePath,
+		Gates:           req.Gates,
 		Project:         project,
 		CreatedAt:       now,
 		UpdatedAt:       now,
@@ -243,6 +225,25 @@ func UpdateProjectRFEWorkflow(c *gin.Context) {
 		}
 	}
 
+	if req.Gates != nil {
+		if err := validateGates(req.Gates); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if gatesChanged(wf.Gates, req.Gates) {
+			allowed, err := canEditGates(c, project)
+			if err != nil {
+				log.Printf("Failed to check gate permissions in %s: %v", project, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check permissions"})
+				return
+			}
+			if !allowed {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Only project admins can change approval gates"})
+				return
+			}
+		}
+	}
+
 	// Update the CR
 	obj := item.DeepCopy()
 	spec, ok := obj.Object["spec"].(map[string]interface{})
@@ -277,12 +278,28 @@ func UpdateProjectRFEWorkflow(c *gin.Context) {
 	if req.ParentOutcome != nil {
 		spec["parentOutcome"] = *req.ParentOutcome
 	}
+	if req.Gates != nil {
+		if len(req.Gates) == 0 {
+			delete(spec, "gates")
+		} else {
+			gates, err := gatesToUnstructured(req.Gates)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gates: " + err.Error()})
+				return
+			}
+			spec["gates"] = gates
+		}
+	}
 
 	// Update the CR
 	updated, err := reqDyn.Resource(gvr).Namespace(project).Update(c.Request.Context(), obj, v1.UpdateOptions{})
 	if err != nil {
 		log.Printf("Failed to update RFEWorkflow CR: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workflow"})
+		if errors.IsForbidden(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to update this workflow"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workflow"})
+		}
 		return
 	}
 
@@ -348,58 +365,24 @@ func SeedProjectRFEWorkflow(c *gin.Context) {
 	}
 
 	// Read request body for optional agent source and spec-kit settings
-	type SeedRequest struct {
-		AgentSourceURL    string `json:"agentSourceUrl,omitempty"`
-		AgentSourceBranch string `json:"agentSourceBranch,omitempty"`
-		AgentSourcePath   string `json:"agentSourcePath,omitempty"`
-		SpecKitRepo       string `json:"specKitRepo,omitempty"`
-		SpecKitVersion    string `json:"specKitVersion,omitempty"`
-		SpecKitTemplate   string `json:"specKitTemplate,omitempty"`
-	}
-	var req SeedRequest
+	var req seedRequest
 	_ = c.ShouldBindJSON(&req)
+	src := resolveSeedSource(req, nil)
 
-	// Defaults
-	agentURL := req.AgentSourceURL
-	if agentURL == "" {
-		agentURL = "https://github.com/ambient-code/vTeam.git"
-	}
-	agentBranch := req.AgentSourceBranch
-	if agentBranch == "" {
-		agentBranch = "main"
-	}
-	agentPath := req.AgentSourcePath
-	if agentPath == "" {
-		agentPath = "agents"
-	}
-	// Spec-kit configuration: request body > environment variables > hardcoded defaults
-	specKitRepo := req.SpecKitRepo
-	if specKitRepo == "" {
-		if envRepo := strings.TrimSpace(os.Getenv("SPEC_KIT_REPO")); envRepo != "" {
-			specKitRepo = envRepo
-		} else {
-			specKitRepo = "github/spec-kit"
-		}
-	}
-	specKitVersion := req.SpecKitVersion
-	if specKitVersion == "" {
-		if envVersion := strings.TrimSpace(os.Getenv("SPEC_KIT_VERSION")); envVersion != "" {
-			specKitVersion = envVersion
-		} else {
-			specKitVersion = "main"
-		}
-	}
-	specKitTemplate := req.SpecKitTemplate
-	if specKitTemplate == "" {
-		if envTemplate := strings.TrimSpace(os.Getenv("SPEC_KIT_TEMPLATE")); envTemplate != "" {
-			specKitTemplate = envTemplate
-		} else {
-			specKitTemplate = "spec-kit-template-claude-sh"
+	// Dry run: report the file plan without cloning or pushing
+	if req.DryRun {
+		plan, err := PlanRepoSeeding(c.Request.Context(), wf, wf.BranchName, githubToken, src)
+		if err != nil {
+			log.Printf("Failed to plan seeding of RFE workflow %s in project %s: %v", id, project, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
 		}
+		c.JSON(http.StatusOK, gin.H{"dryRun": true, "plan": plan})
+		return
 	}
 
 	// Perform seeding operations with platform-managed branch
-	branchExisted, seedErr := PerformRepoSeeding(c.Request.Context(), wf, wf.BranchName, githubToken, agentURL, agentBranch, agentPath, specKitRepo, specKitVersion, specKitTemplate)
+	branchExisted, seedErr := PerformRepoSeeding(c.Request.Context(), wf, wf.BranchName, githubToken, src)
 
 	if seedErr != nil {
 		log.Printf("Failed to seed RFE workflow %s in project %s: %v", id, project, seedErr)
@@ -407,6 +390,8 @@ func SeedProjectRFEWorkflow(c *gin.Context) {
 		return
 	}
 
+	recordWorkflowSeed(c.Request.Context(), project, id, src, "")
+
 	message := "Repository seeded successfully"
 	if branchExisted {
 		message = fmt.Sprintf("Repository seeded successfully. Note: Branch '%s' already existed and will be modified by this RFE.", wf.BranchName)
@@ -551,12 +536,29 @@ func GetProjectRFEWorkflow(c *gin.Context) {
 	if wf.ParentOutcome != nil {
 		resp["parentOutcome"] = *wf.ParentOutcome
 	}
-	if len(wf.JiraLinks) > 0 {
-		links := make([]map[string]interface{}, 0, len(wf.JiraLinks))
-		for _, l := range wf.JiraLinks {
-			links = append(links, map[string]interface{}{"path": l.Path, "jiraKey": l.JiraKey})
-		}
-		resp["jiraLinks"] = links
+	if len(wf.TrackerLinks) > 0 {
+		resp["trackerLinks"] = wf.TrackerLinks
+	}
+	if wf.TrackerSync != nil {
+		resp["trackerSync"] = wf.TrackerSync
+	}
+	if isRFEPhase(wf.Phase) {
+		resp["phase"] = wf.Phase
+	}
+	if wf.PhaseArtifacts != nil {
+		resp["phaseArtifacts"] = wf.PhaseArtifacts
+	}
+	if len(wf.PhaseHistory) > 0 {
+		resp["phaseHistory"] = wf.PhaseHistory
+	}
+	if len(wf.Gates) > 0 {
+		resp["gates"] = workflowGateStatuses(wf)
+	}
+	if len(wf.Approvals) > 0 {
+		resp["approvals"] = wf.Approvals
+	}
+	if wf.Seed != nil {
+		resp["seed"] = wf.Seed
 	}
 	if wf.UmbrellaRepo != nil {
 		u := map[string]interface{}{"url": wf.UmbrellaRepo.URL}
@@ -579,126 +581,96 @@ func GetProjectRFEWorkflow(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
-// GetProjectRFEWorkflowSummary computes derived phase/status and progress based on workspace files and linked sessions
+// GetProjectRFEWorkflowSummary reports the workflow phase, phase completion and progress computed
+// from the artifacts on the feature branch, plus the state of linked sessions
 // GET /api/projects/:projectName/rfe-workflows/:id/summary
 func GetProjectRFEWorkflowSummary(c *gin.Context) {
 	project := c.Param("projectName")
 	id := c.Param("id")
 
-	// Determine workspace and expected files
-	// workspace content removed
-	specsItems := []contentListItem{}
-
-	hasSpec := false
-	hasPlan := false
-	hasTasks := false
-
-	// helper to scan a list for target filenames
-	scanFor := func(items []contentListItem) (bool, bool, bool) {
-		s, p, t := false, false, false
-		for _, it := range items {
-			if it.IsDir {
-				continue
-			}
-			switch strings.ToLower(it.Name) {
-			case "spec.md":
-				s = true
-			case "plan.md":
-				p = true
-			case "tasks.md":
-				t = true
-			}
-		}
-		return s, p, t
-	}
-
-	// First check directly under specs/
-	if len(specsItems) > 0 {
-		s, p, t := scanFor(specsItems)
-		hasSpec, hasPlan, hasTasks = s, p, t
-		// If not found, check first subfolder under specs/
-		if !hasSpec && !hasPlan && !hasTasks {
-			for _, it := range specsItems {
-				if it.IsDir {
-					subItems := []contentListItem{}
-					s2, p2, t2 := scanFor(subItems)
-					hasSpec, hasPlan, hasTasks = s2, p2, t2
-					break
-				}
-			}
+	gvr := GetRFEWorkflowResource()
+	reqK8s, reqDyn := GetK8sClientsForRequest(c)
+	if reqDyn == nil || reqK8s == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	item, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), id, v1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workflow", "details": err.Error()})
 		}
+		return
+	}
+	wf := RfeFromUnstructured(item)
+	if wf == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse workflow"})
+		return
 	}
 
+	// Refresh phase state from the feature branch; fall back to the last recorded artifacts
+	// when the caller has no GitHub access
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	phase, artifacts, err := refreshWorkflowPhase(c.Request.Context(), reqK8s, reqDyn, project, userIDStr, wf)
+	if err != nil {
+		log.Printf("GetProjectRFEWorkflowSummary: using recorded artifacts for %s/%s: %v", project, id, err)
+	}
+	complete, missing := rfePhaseComplete(phase, artifacts)
+
 	// Sessions: find linked sessions and compute running/failed flags
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	_, reqDyn := GetK8sClientsForRequest(c)
 	anyRunning := false
 	anyFailed := false
-	if reqDyn != nil {
-		selector := fmt.Sprintf("rfe-workflow=%s,project=%s", id, project)
-		if list, err := reqDyn.Resource(gvr).Namespace(project).List(c.Request.Context(), v1.ListOptions{LabelSelector: selector}); err == nil {
+	selector := fmt.Sprintf("rfe-workflow=%s,project=%s", id, project)
+	if reqSessions := GetSessionClientForRequest(c, project); reqSessions != nil {
+		if list, err := reqSessions.List(c.Request.Context(), v1.ListOptions{LabelSelector: selector}); err == nil {
 			for _, item := range list.Items {
-				status, _ := item.Object["status"].(map[string]interface{})
-				phaseStr := strings.ToLower(fmt.Sprintf("%v", status["phase"]))
-				if phaseStr == "running" || phaseStr == "creating" || phaseStr == "pending" {
+				switch item.Status.Phase {
+				case vteamv1alpha1.AgenticSessionPhaseRunning, vteamv1alpha1.AgenticSessionPhaseCreating, vteamv1alpha1.AgenticSessionPhasePending:
 					anyRunning = true
-				}
-				if phaseStr == "failed" || phaseStr == "error" {
+				case vteamv1alpha1.AgenticSessionPhaseFailed, vteamv1alpha1.AgenticSessionPhaseError:
 					anyFailed = true
 				}
 			}
 		}
 	}
 
-	// Derive phase and status
-	var phase string
-	switch {
-	case !hasSpec && !hasPlan && !hasTasks:
-		phase = "pre"
-	case !hasSpec:
-		phase = "specify"
-	case !hasPlan:
-		phase = "plan"
-	case !hasTasks:
-		phase = "tasks"
-	default:
-		phase = "completed"
+	// Progress counts completed work phases (specify..implement)
+	done := 0
+	for _, p := range rfeWorkPhases {
+		if ok, _ := rfePhaseComplete(p, artifacts); ok {
+			done++
+		}
 	}
+	progress := float64(done) / float64(len(rfeWorkPhases)) * 100.0
 
 	status := "not started"
-	if anyRunning {
+	switch {
+	case anyRunning:
 		status = "running"
-	} else if hasSpec || hasPlan || hasTasks {
-		status = "in progress"
-	}
-	if hasSpec && hasPlan && hasTasks && !anyRunning {
+	case phase == rfePhaseDone:
 		status = "completed"
+	case done > 0 || phase != rfePhasePre:
+		status = "in progress"
 	}
 	if anyFailed && status != "running" {
 		status = "attention"
 	}
 
-	progress := float64(0)
-	done := 0
-	if hasSpec {
-		done++
-	}
-	if hasPlan {
-		done++
-	}
-	if hasTasks {
-		done++
-	}
-	progress = float64(done) / 3.0 * 100.0
-
 	c.JSON(http.StatusOK, gin.H{
-		"phase":    phase,
-		"status":   status,
-		"progress": progress,
+		"phase":          phase,
+		"derivedPhase":   deriveRFEPhase(artifacts),
+		"phaseComplete":  complete,
+		"missing":        missing,
+		"nextPhase":      nextRFEPhase(phase),
+		"status":         status,
+		"progress":       progress,
+		"phaseArtifacts": artifacts,
 		"files": gin.H{
-			"spec":  hasSpec,
-			"plan":  hasPlan,
-			"tasks": hasTasks,
+			"spec":  artifacts.Spec,
+			"plan":  artifacts.Plan,
+			"tasks": artifacts.Tasks,
 		},
 	})
 }
@@ -719,23 +691,22 @@ func DeleteProjectRFEWorkflow(c *gin.Context) {
 func ListProjectRFEWorkflowSessions(c *gin.Context) {
 	project := c.Param("projectName")
 	id := c.Param("id")
-	gvr := GetAgenticSessionV1Alpha1Resource()
 	selector := fmt.Sprintf("rfe-workflow=%s,project=%s", id, project)
-	_, reqDyn := GetK8sClientsForRequest(c)
-	if reqDyn == nil {
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user token"})
 		return
 	}
-	list, err := reqDyn.Resource(gvr).Namespace(project).List(c.Request.Context(), v1.ListOptions{LabelSelector: selector})
+	list, err := reqSessions.List(c.Request.Context(), v1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list sessions", "details": err.Error()})
 		return
 	}
 
 	// Return full session objects for UI
-	sessions := make([]map[string]interface{}, 0, len(list.Items))
-	for _, item := range list.Items {
-		sessions = append(sessions, item.Object)
+	sessions := make([]types.AgenticSession, 0, len(list.Items))
+	for i := range list.Items {
+		sessions = append(sessions, sessionResponse(&list.Items[i]))
 	}
 	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
 }
@@ -753,13 +724,12 @@ func AddProjectRFEWorkflowSession(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "existingName is required for linking in this version"})
 		return
 	}
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	_, reqDyn := GetK8sClientsForRequest(c)
-	if reqDyn == nil {
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user token"})
 		return
 	}
-	obj, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), req.ExistingName, v1.GetOptions{})
+	obj, err := reqSessions.Get(c.Request.Context(), req.ExistingName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -768,24 +738,19 @@ func AddProjectRFEWorkflowSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session", "details": err.Error()})
 		return
 	}
-	meta, _ := obj.Object["metadata"].(map[string]interface{})
-	labels, _ := meta["labels"].(map[string]interface{})
-	if labels == nil {
-		labels = map[string]interface{}{}
-		meta["labels"] = labels
+	if obj.Labels == nil {
+		obj.Labels = map[string]string{}
 	}
-	labels["project"] = project
-	labels["rfe-workflow"] = id
+	obj.Labels["project"] = project
+	obj.Labels["rfe-workflow"] = id
 	if req.Phase != "" {
-		labels["rfe-phase"] = req.Phase
+		obj.Labels["rfe-phase"] = req.Phase
 	}
 	// Update the resource
-	updated, err := reqDyn.Resource(gvr).Namespace(project).Update(c.Request.Context(), obj, v1.UpdateOptions{})
-	if err != nil {
+	if _, err := reqSessions.Update(c.Request.Context(), obj, v1.UpdateOptions{}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session labels", "details": err.Error()})
 		return
 	}
-	_ = updated
 	c.JSON(http.StatusOK, gin.H{"message": "Session linked to RFE", "session": req.ExistingName})
 }
 
@@ -795,13 +760,12 @@ func RemoveProjectRFEWorkflowSession(c *gin.Context) {
 	_ = project // currently unused but kept for parity/logging if needed
 	id := c.Param("id")
 	sessionName := c.Param("sessionName")
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	_, reqDyn := GetK8sClientsForRequest(c)
-	if reqDyn == nil {
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user token"})
 		return
 	}
-	obj, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), sessionName, v1.GetOptions{})
+	obj, err := reqSessions.Get(c.Request.Context(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -810,13 +774,9 @@ func RemoveProjectRFEWorkflowSession(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch session", "details": err.Error()})
 		return
 	}
-	meta, _ := obj.Object["metadata"].(map[string]interface{})
-	labels, _ := meta["labels"].(map[string]interface{})
-	if labels != nil {
-		delete(labels, "rfe-workflow")
-		delete(labels, "rfe-phase")
-	}
-	if _, err := reqDyn.Resource(gvr).Namespace(project).Update(c.Request.Context(), obj, v1.UpdateOptions{}); err != nil {
+	delete(obj.Labels, "rfe-workflow")
+	delete(obj.Labels, "rfe-phase")
+	if _, err := reqSessions.Update(c.Request.Context(), obj, v1.UpdateOptions{}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session labels", "details": err.Error()})
 		return
 	}
@@ -928,200 +888,9 @@ func parseOwnerRepoFromURL(repoURL string) (string, string, error) {
 	return "", "", fmt.Errorf("unable to parse repository URL")
 }
 
-// Agent represents an agent definition from .claude/agents directory
-type Agent struct {
-	Persona     string `json:"persona"`
-	Name        string `json:"name"`
-	Role        string `json:"role"`
-	Description string `json:"description"`
-}
-
-// fetchAgentsFromRepo fetches and parses agent definitions from .claude/agents directory
-func fetchAgentsFromRepo(ctx context.Context, owner, repo, ref, token string) ([]Agent, error) {
-	api := "https://api.github.com"
-	agentsPath := ".claude/agents"
-
-	// Fetch directory listing
-	treeURL := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", api, owner, repo, agentsPath, ref)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, treeURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("GitHub request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		// No .claude/agents directory - return empty array
-		return []Agent{}, nil
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(body))
-	}
-
-	var treeEntries []map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&treeEntries); err != nil {
-		return nil, fmt.Errorf("failed to parse GitHub response: %w", err)
-	}
-
-	// Filter for .md files
-	var agentFiles []string
-	for _, entry := range treeEntries {
-		name, _ := entry["name"].(string)
-		typ, _ := entry["type"].(string)
-		if typ == "file" && strings.HasSuffix(name, ".md") {
-			agentFiles = append(agentFiles, name)
-		}
-	}
-
-	// Fetch and parse each agent file
-	agents := make([]Agent, 0, len(agentFiles))
-	for _, filename := range agentFiles {
-		agent, err := fetchAndParseAgentFile(ctx, api, owner, repo, ref, filename, token)
-		if err != nil {
-			log.Printf("Warning: failed to parse agent file %s: %v", filename, err)
-			continue
-		}
-		agents = append(agents, agent)
-	}
-
-	return agents, nil
-}
-
-// fetchAndParseAgentFile fetches a single agent file and parses its metadata
-func fetchAndParseAgentFile(ctx context.Context, api, owner, repo, ref, filename, token string) (Agent, error) {
-	agentPath := fmt.Sprintf(".claude/agents/%s", filename)
-	url := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", api, owner, repo, agentPath, ref)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return Agent{}, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
-
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return Agent{}, fmt.Errorf("GitHub request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return Agent{}, fmt.Errorf("GitHub returned status %d", resp.StatusCode)
-	}
-
-	var fileData map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&fileData); err != nil {
-		return Agent{}, fmt.Errorf("failed to parse GitHub response: %w", err)
-	}
-
-	// Decode base64 content
-	content, _ := fileData["content"].(string)
-	encoding, _ := fileData["encoding"].(string)
-
-	var decodedContent string
-	if strings.ToLower(encoding) == "base64" {
-		raw := strings.ReplaceAll(content, "\n", "")
-		data, err := base64.StdEncoding.DecodeString(raw)
-		if err != nil {
-			return Agent{}, fmt.Errorf("failed to decode base64 content: %w", err)
-		}
-		decodedContent = string(data)
-	} else {
-		decodedContent = content
-	}
-
-	// Parse persona from filename
-	persona := strings.TrimSuffix(filename, ".md")
-
-	// Generate default name from filename
-	nameParts := strings.FieldsFunc(persona, func(r rune) bool {
-		return r == '-' || r == '_'
-	})
-	for i, part := range nameParts {
-		if len(part) > 0 {
-			nameParts[i] = strings.ToUpper(part[:1]) + part[1:]
-		}
-	}
-	name := strings.Join(nameParts, " ")
-
-	role := ""
-	description := ""
-
-	// Try to extract metadata from YAML frontmatter
-	// Simple regex-based parsing (consider using a YAML library for production)
-	lines := strings.Split(decodedContent, "\n")
-	inFrontmatter := false
-	for i, line := range lines {
-		if i == 0 && strings.TrimSpace(line) == "---" {
-			inFrontmatter = true
-			continue
-		}
-		if inFrontmatter && strings.TrimSpace(line) == "---" {
-			break
-		}
-		if inFrontmatter {
-			if strings.HasPrefix(line, "name:") {
-				name = strings.TrimSpace(strings.TrimPrefix(line, "name:"))
-			} else if strings.HasPrefix(line, "role:") {
-				role = strings.TrimSpace(strings.TrimPrefix(line, "role:"))
-			} else if strings.HasPrefix(line, "description:") {
-				description = strings.TrimSpace(strings.TrimPrefix(line, "description:"))
-			}
-		}
-	}
-
-	// If no description found, use first non-empty line after frontmatter
-	if description == "" {
-		afterFrontmatter := false
-		for _, line := range lines {
-			if afterFrontmatter {
-				trimmed := strings.TrimSpace(line)
-				if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
-					description = trimmed
-					if len(description) > 150 {
-						description = description[:150]
-					}
-					break
-				}
-			}
-			if strings.TrimSpace(line) == "---" {
-				if afterFrontmatter {
-					break
-				}
-				afterFrontmatter = true
-			}
-		}
-	}
-
-	if description == "" {
-		description = "No description available"
-	}
-
-	return Agent{
-		Persona:     persona,
-		Name:        name,
-		Role:        role,
-		Description: description,
-	}, nil
-}
-
-// GetWorkflowJira proxies Jira issue fetch for a linked path
-// GET /api/projects/:projectName/rfe-workflows/:id/jira?path=...
-func GetWorkflowJira(c *gin.Context) {
+// GetWorkflowTrackerIssue returns the tracker status of the issue linked to a path
+// GET /api/projects/:projectName/rfe-workflows/:id/tracker?path=... (also .../jira)
+func GetWorkflowTrackerIssue(c *gin.Context) {
 	project := c.Param("projectName")
 	id := c.Param("id")
 	reqPath := strings.TrimSpace(c.Query("path"))
@@ -1130,8 +899,7 @@ func GetWorkflowJira(c *gin.Context) {
 		return
 	}
 	_, reqDyn := GetK8sClientsForRequest(c)
-	reqK8s, _ := GetK8sClientsForRequest(c)
-	if reqDyn == nil || reqK8s == nil {
+	if reqDyn == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user token"})
 		return
 	}
@@ -1143,66 +911,27 @@ func GetWorkflowJira(c *gin.Context) {
 		return
 	}
 	wf := RfeFromUnstructured(item)
-	var key string
-	for _, jl := range wf.JiraLinks {
-		if strings.TrimSpace(jl.Path) == reqPath {
-			key = jl.JiraKey
+	var link *WorkflowTrackerLink
+	for i := range wf.TrackerLinks {
+		if strings.TrimSpace(wf.TrackerLinks[i].Path) == reqPath {
+			link = &wf.TrackerLinks[i]
 			break
 		}
 	}
-	if key == "" {
-		c.JSON(http.StatusNotFound, gin.H{"error": "No Jira linked for path"})
+	if link == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No issue linked for path"})
 		return
 	}
-	// Load Jira creds
-	// Determine secret name
-	secretName := "ambient-runner-secrets"
-	if obj, err := reqDyn.Resource(GetProjectSettingsResource()).Namespace(project).Get(c.Request.Context(), "projectsettings", v1.GetOptions{}); err == nil {
-		if spec, ok := obj.Object["spec"].(map[string]interface{}); ok {
-			if v, ok := spec["runnerSecretsName"].(string); ok && strings.TrimSpace(v) != "" {
-				secretName = strings.TrimSpace(v)
-			}
-		}
-	}
-	sec, err := reqK8s.CoreV1().Secrets(project).Get(c.Request.Context(), secretName, v1.GetOptions{})
+
+	issueTracker, err := ResolveIssueTracker(c, project, wf, link.Tracker)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read runner secret", "details": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Issue tracker not configured", "details": err.Error()})
 		return
 	}
-	get := func(k string) string {
-		if b, ok := sec.Data[k]; ok {
-			return string(b)
-		}
-		return ""
-	}
-	jiraURL := strings.TrimSpace(get("JIRA_URL"))
-	jiraToken := strings.TrimSpace(get("JIRA_API_TOKEN"))
-	if jiraURL == "" || jiraToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing Jira configuration in runner secret (JIRA_URL, JIRA_API_TOKEN required)"})
-		return
-	}
-	// Determine auth header (Cloud vs Server/Data Center)
-	authHeader := ""
-	if strings.Contains(jiraURL, "atlassian.net") {
-		// Jira Cloud - assume token is email:api_token format
-		encoded := base64.StdEncoding.EncodeToString([]byte(jiraToken))
-		authHeader = "Basic " + encoded
-	} else {
-		// Jira Server/Data Center
-		authHeader = "Bearer " + jiraToken
-	}
-
-	jiraBase := strings.TrimRight(jiraURL, "/")
-	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s", jiraBase, url.PathEscape(key))
-	httpReq, _ := http.NewRequest("GET", endpoint, nil)
-	httpReq.Header.Set("Authorization", authHeader)
-	httpClient := &http.Client{Timeout: 30 * time.Second}
-	httpResp, httpErr := httpClient.Do(httpReq)
-	if httpErr != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"error": "Jira request failed", "details": httpErr.Error()})
+	status, err := issueTracker.GetStatus(c.Request.Context(), link.Key)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Issue tracker request failed", "details": err.Error()})
 		return
 	}
-	defer httpResp.Body.Close()
-	respBody, _ := io.ReadAll(httpResp.Body)
-	c.Data(httpResp.StatusCode, "application/json", respBody)
+	c.JSON(http.StatusOK, status)
 }
@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// agentsDir is where seeding places agent personas in the spec repo
+const agentsDir = ".claude/agents/"
+
+// Agent represents an agent definition from .claude/agents directory
+type Agent struct {
+	Persona     string   `json:"persona"`
+	Name        string   `json:"name"`
+	Role        string   `json:"role"`
+	Description string   `json:"description"`
+	Tools       []string `json:"tools,omitempty"`
+	Model       string   `json:"model,omitempty"`
+	// Metadata holds any other frontmatter fields
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// errInvalidAgentPersonas marks session agent selections that don't match the RFE's seeded agents
+var errInvalidAgentPersonas = errors.New("invalid agent personas")
+
+// isInvalidAgentPersonas reports whether err rejects a session's agent selection
+func isInvalidAgentPersonas(err error) bool {
+	return errors.Is(err, errInvalidAgentPersonas)
+}
+
+// agentTree is the cached .claude/agents listing of a repo ref: blob SHAs by file name
+type agentTree struct {
+	etag  string
+	blobs map[string]string
+}
+
+// Agents are cached per ref by tree ETag, so unchanged branches cost one conditional request
+// (which GitHub doesn't count against the rate limit), and parsed per blob SHA, so only changed
+// files are downloaded
+const (
+	maxAgentTreeCache = 256
+	maxAgentBlobCache = 1024
+)
+
+var (
+	agentCacheMu   sync.Mutex
+	agentTreeCache = map[string]agentTree{}
+	agentBlobCache = map[string]Agent{}
+)
+
+// fetchAgentsFromRepo fetches and parses agent definitions from .claude/agents directory
+func fetchAgentsFromRepo(ctx context.Context, owner, repo, ref, token string) ([]Agent, error) {
+	blobs, err := fetchAgentTree(ctx, owner, repo, ref, token)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(blobs))
+	for name := range blobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	agents := make([]Agent, 0, len(names))
+	for _, filename := range names {
+		sha := blobs[filename]
+		key := sha + ":" + filename
+		agentCacheMu.Lock()
+		agent, ok := agentBlobCache[key]
+		agentCacheMu.Unlock()
+		if !ok {
+			content, err := fetchGitBlob(ctx, owner, repo, sha, token)
+			if err != nil {
+				log.Printf("Warning: failed to fetch agent file %s: %v", filename, err)
+				continue
+			}
+			agent, err = parseAgentFile(filename, content)
+			if err != nil {
+				log.Printf("Warning: failed to parse agent file %s: %v", filename, err)
+				continue
+			}
+			agentCacheMu.Lock()
+			if len(agentBlobCache) >= maxAgentBlobCache {
+				agentBlobCache = map[string]Agent{}
+			}
+			agentBlobCache[key] = agent
+			agentCacheMu.Unlock()
+		}
+		agents = append(agents, agent)
+	}
+	return agents, nil
+}
+
+// fetchAgentTree lists the markdown files directly under .claude/agents at ref with a single
+// recursive tree request, revalidating the cached listing with its ETag
+func fetchAgentTree(ctx context.Context, owner, repo, ref, token string) (map[string]string, error) {
+	cacheKey := fmt.Sprintf("%s/%s@%s", owner, repo, ref)
+	agentCacheMu.Lock()
+	cached, haveCached := agentTreeCache[cacheKey]
+	agentCacheMu.Unlock()
+
+	treeURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/trees/%s?recursive=1", owner, repo, url.PathEscape(ref))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, treeURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if haveCached && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && haveCached:
+		return cached.blobs, nil
+	case resp.StatusCode == http.StatusNotFound:
+		// Branch not seeded yet - no agents
+		return map[string]string{}, nil
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var tree struct {
+		Tree []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+			SHA  string `json:"sha"`
+		} `json:"tree"`
+		Truncated bool `json:"truncated"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	if tree.Truncated {
+		log.Printf("fetchAgentTree: tree of %s is truncated, some agents may be missing", cacheKey)
+	}
+	blobs := map[string]string{}
+	for _, e := range tree.Tree {
+		if e.Type != "blob" || !strings.HasPrefix(e.Path, agentsDir) {
+			continue
+		}
+		name := strings.TrimPrefix(e.Path, agentsDir)
+		if strings.Contains(name, "/") || !strings.HasSuffix(name, ".md") {
+			continue
+		}
+		blobs[name] = e.SHA
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		agentCacheMu.Lock()
+		if len(agentTreeCache) >= maxAgentTreeCache {
+			agentTreeCache = map[string]agentTree{}
+		}
+		agentTreeCache[cacheKey] = agentTree{etag: etag, blobs: blobs}
+		agentCacheMu.Unlock()
+	}
+	return blobs, nil
+}
+
+// fetchGitBlob downloads a blob's content by SHA
+func fetchGitBlob(ctx context.Context, owner, repo, sha, token string) ([]byte, error) {
+	blobURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/git/blobs/%s", owner, repo, sha)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, blobURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("GitHub returned status %d", resp.StatusCode)
+	}
+
+	var blob struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&blob); err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	if strings.ToLower(blob.Encoding) != "base64" {
+		return []byte(blob.Content), nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(blob.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 content: %w", err)
+	}
+	return data, nil
+}
+
+// splitFrontmatter separates a leading "---" delimited YAML block from the markdown body
+func splitFrontmatter(content []byte) ([]byte, []byte) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff"))
+	normalized := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(normalized, []byte("---\n")) {
+		return nil, normalized
+	}
+	rest := normalized[len("---\n"):]
+	if bytes.HasPrefix(rest, []byte("---\n")) {
+		return nil, rest[len("---\n"):]
+	}
+	end := bytes.Index(rest, []byte("\n---"))
+	if end < 0 {
+		return nil, normalized
+	}
+	body := rest[end+len("\n---"):]
+	if i := bytes.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	} else {
+		body = nil
+	}
+	return rest[:end+1], body
+}
+
+// parseAgentFile parses an agent markdown file. The persona is the file name; name, role,
+// description, tools and model come from the YAML frontmatter and any other fields are kept as
+// metadata. Missing names and descriptions are derived from the file name and body.
+func parseAgentFile(filename string, content []byte) (Agent, error) {
+	persona := strings.TrimSuffix(path.Base(filename), ".md")
+
+	// Generate default name from filename
+	nameParts := strings.FieldsFunc(persona, func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+	for i, part := range nameParts {
+		if len(part) > 0 {
+			nameParts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	agent := Agent{Persona: persona, Name: strings.Join(nameParts, " ")}
+
+	frontmatter, body := splitFrontmatter(content)
+	if len(frontmatter) > 0 {
+		fields := map[string]interface{}{}
+		if err := yaml.Unmarshal(frontmatter, &fields); err != nil {
+			return Agent{}, fmt.Errorf("invalid frontmatter: %w", err)
+		}
+		for key, val := range fields {
+			switch key {
+			case "name":
+				if s := frontmatterString(val); s != "" {
+					agent.Name = s
+				}
+			case "role":
+				agent.Role = frontmatterString(val)
+			case "description":
+				agent.Description = frontmatterString(val)
+			case "model":
+				agent.Model = frontmatterString(val)
+			case "tools":
+				agent.Tools = frontmatterList(val)
+			default:
+				if agent.Metadata == nil {
+					agent.Metadata = map[string]interface{}{}
+				}
+				agent.Metadata[key] = val
+			}
+		}
+	}
+
+	// If no description found, use first non-empty line after frontmatter
+	if agent.Description == "" {
+		for _, line := range strings.Split(string(body), "\n") {
+			trimmed := strings.TrimSpace(line)
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				if len(trimmed) > 150 {
+					trimmed = trimmed[:150]
+				}
+				agent.Description = trimmed
+				break
+			}
+		}
+	}
+	if agent.Description == "" {
+		agent.Description = "No description available"
+	}
+	return agent, nil
+}
+
+func frontmatterString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(t)
+	default:
+		return strings.TrimSpace(fmt.Sprint(t))
+	}
+}
+
+// frontmatterList accepts both YAML lists and the comma-separated form Claude agents use for tools
+func frontmatterList(v interface{}) []string {
+	var raw []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, it := range t {
+			raw = append(raw, frontmatterString(it))
+		}
+	default:
+		raw = strings.Split(frontmatterString(v), ",")
+	}
+	out := make([]string, 0, len(raw))
+	for _, s := range raw {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// resolveSessionAgentPersonas validates a session's requested personas against the agents seeded
+// on its RFE workflow's feature branch and returns them de-duplicated in request order. Errors
+// wrapping errInvalidAgentPersonas are the caller's fault.
+func resolveSessionAgentPersonas(c *gin.Context, project, workflowID string, personas []string) ([]string, error) {
+	var requested []string
+	seen := map[string]bool{}
+	for _, p := range personas {
+		p = strings.TrimSpace(p)
+		if p != "" && !seen[p] {
+			seen[p] = true
+			requested = append(requested, p)
+		}
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	if strings.TrimSpace(workflowID) == "" {
+		return nil, fmt.Errorf("%w: agent personas require an rfe-workflow label", errInvalidAgentPersonas)
+	}
+
+	reqK8s, reqDyn := GetK8sClientsForRequest(c)
+	if reqK8s == nil || reqDyn == nil {
+		return nil, fmt.Errorf("authentication required to resolve agent personas")
+	}
+	item, err := reqDyn.Resource(GetRFEWorkflowResource()).Namespace(project).Get(c.Request.Context(), workflowID, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: RFE workflow %s not found", errInvalidAgentPersonas, workflowID)
+		}
+		return nil, fmt.Errorf("failed to get RFE workflow %s: %w", workflowID, err)
+	}
+	wf := RfeFromUnstructured(item)
+	if wf == nil || wf.UmbrellaRepo == nil || wf.BranchName == "" {
+		return nil, fmt.Errorf("%w: RFE workflow %s has not been seeded", errInvalidAgentPersonas, workflowID)
+	}
+	owner, repo, err := parseOwnerRepoFromURL(wf.UmbrellaRepo.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid spec repo URL: %v", errInvalidAgentPersonas, err)
+	}
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	token, err := GetGitHubToken(c.Request.Context(), reqK8s, reqDyn, project, userIDStr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidAgentPersonas, err)
+	}
+	agents, err := fetchAgentsFromRepo(c.Request.Context(), owner, repo, wf.BranchName, token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agents for RFE workflow %s: %w", workflowID, err)
+	}
+
+	available := map[string]bool{}
+	for _, a := range agents {
+		available[a.Persona] = true
+	}
+	var unknown []string
+	for _, p := range requested {
+		if !available[p] {
+			unknown = append(unknown, p)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s not seeded on %s", errInvalidAgentPersonas, strings.Join(unknown, ", "), wf.BranchName)
+	}
+	return requested, nil
+}
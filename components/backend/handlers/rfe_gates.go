@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+	authv1 "k8s.io/api/authorization/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Reviewer decisions recorded in status.approvals
+const (
+	decisionApproved = "approved"
+	decisionRejected = "rejected"
+)
+
+// maxApprovals caps the status.approvals audit trail, dropping the oldest entries
+const maxApprovals = 200
+
+// validateGates checks gate phases are real, non-terminal phases configured at most once
+func validateGates(gates []types.PhaseGate) error {
+	seen := map[string]bool{}
+	for _, g := range gates {
+		if !isRFEPhase(g.Phase) || g.Phase == rfePhaseDone {
+			return fmt.Errorf("invalid gate phase %q", g.Phase)
+		}
+		if seen[g.Phase] {
+			return fmt.Errorf("duplicate gate for phase %q", g.Phase)
+		}
+		seen[g.Phase] = true
+		if g.MinApprovals < 0 {
+			return fmt.Errorf("gate %q: minApprovals must not be negative", g.Phase)
+		}
+		for _, grp := range g.RequiredGroups {
+			if strings.TrimSpace(grp) == "" {
+				return fmt.Errorf("gate %q: empty reviewer group", g.Phase)
+			}
+		}
+	}
+	return nil
+}
+
+// gatesChanged reports whether an update replaces the workflow's gates; nil and empty are the same
+func gatesChanged(old, cur []types.PhaseGate) bool {
+	if len(old) == 0 && len(cur) == 0 {
+		return false
+	}
+	a, _ := json.Marshal(old)
+	b, _ := json.Marshal(cur)
+	return string(a) != string(b)
+}
+
+// canEditGates asks the API server whether the caller may update the project's ProjectSettings,
+// the permission that separates project admins from editors. The admission webhook applies the
+// same check to changes made outside the backend.
+func canEditGates(c *gin.Context, project string) (bool, error) {
+	reqK8s, _ := GetK8sClientsForRequest(c)
+	if reqK8s == nil {
+		return false, fmt.Errorf("invalid or missing token")
+	}
+	ssar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Group:     "vteam.ambient-code",
+				Resource:  "projectsettings",
+				Verb:      "update",
+				Namespace: project,
+			},
+		},
+	}
+	res, err := reqK8s.AuthorizationV1().SelfSubjectAccessReviews().Create(c.Request.Context(), ssar, v1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return res.Status.Allowed, nil
+}
+
+// canUpdateWorkflow asks the API server whether the caller may update the RFEWorkflow. Status
+// writes that editors cannot make through rfeworkflows/status are done with the backend SA once
+// this check passes.
+func canUpdateWorkflow(c *gin.Context, project, id string) (bool, error) {
+	reqK8s, _ := GetK8sClientsForRequest(c)
+	if reqK8s == nil {
+		return false, fmt.Errorf("invalid or missing token")
+	}
+	ssar := &authv1.SelfSubjectAccessReview{
+		Spec: authv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authv1.ResourceAttributes{
+				Group:     "vteam.ambient-code",
+				Resource:  "rfeworkflows",
+				Verb:      "update",
+				Namespace: project,
+				Name:      id,
+			},
+		},
+	}
+	res, err := reqK8s.AuthorizationV1().SelfSubjectAccessReviews().Create(c.Request.Context(), ssar, v1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return res.Status.Allowed, nil
+}
+
+// gatesToUnstructured converts gates to their spec.gates representation
+func gatesToUnstructured(gates []types.PhaseGate) ([]interface{}, error) {
+	out := make([]interface{}, 0, len(gates))
+	for i := range gates {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&gates[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// gateForPhase returns the workflow's gate on leaving phase, if any
+func gateForPhase(wf *types.RFEWorkflow, phase string) *types.PhaseGate {
+	for i := range wf.Gates {
+		if wf.Gates[i].Phase == phase {
+			return &wf.Gates[i]
+		}
+	}
+	return nil
+}
+
+// evaluateGate applies each reviewer's latest decision for the gate's phase. The gate is
+// satisfied when nobody's latest decision is a rejection, enough distinct reviewers approved,
+// and every required group has an approver.
+func evaluateGate(gate types.PhaseGate, approvals []types.PhaseApproval) types.GateStatus {
+	latest := map[string]types.PhaseApproval{}
+	for _, a := range approvals {
+		if a.Phase == gate.Phase {
+			latest[a.User] = a
+		}
+	}
+	st := types.GateStatus{PhaseGate: gate}
+	covered := map[string]bool{}
+	for user, a := range latest {
+		switch a.Decision {
+		case decisionApproved:
+			st.ApprovedBy = append(st.ApprovedBy, user)
+			for _, g := range a.Groups {
+				covered[g] = true
+			}
+		case decisionRejected:
+			st.RejectedBy = append(st.RejectedBy, user)
+		}
+	}
+	sort.Strings(st.ApprovedBy)
+	sort.Strings(st.RejectedBy)
+	for _, g := range gate.RequiredGroups {
+		if !covered[g] {
+			st.MissingGroups = append(st.MissingGroups, g)
+		}
+	}
+	required := gate.MinApprovals
+	if required < 1 {
+		required = 1
+	}
+	st.Satisfied = len(st.RejectedBy) == 0 && len(st.MissingGroups) == 0 && len(st.ApprovedBy) >= required
+	return st
+}
+
+// workflowGateStatuses evaluates every configured gate of the workflow
+func workflowGateStatuses(wf *types.RFEWorkflow) []types.GateStatus {
+	out := make([]types.GateStatus, 0, len(wf.Gates))
+	for _, g := range wf.Gates {
+		out = append(out, evaluateGate(g, wf.Approvals))
+	}
+	return out
+}
+
+// reviewerGroups returns the caller's groups that are reviewer groups of gate
+func reviewerGroups(gate *types.PhaseGate, userGroups []string) []string {
+	if gate == nil {
+		return nil
+	}
+	var out []string
+	for _, ug := range userGroups {
+		ug = strings.TrimSpace(ug)
+		for _, rg := range gate.RequiredGroups {
+			if ug == rg {
+				out = append(out, ug)
+			}
+		}
+	}
+	return out
+}
+
+// GetProjectRFEWorkflowApprovals returns the workflow's gates, their evaluation and the approval audit trail
+// GET /api/projects/:projectName/rfe-workflows/:id/approvals
+func GetProjectRFEWorkflowApprovals(c *gin.Context) {
+	project := c.Param("projectName")
+	id := c.Param("id")
+
+	_, reqDyn := GetK8sClientsForRequest(c)
+	if reqDyn == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	item, err := reqDyn.Resource(GetRFEWorkflowResource()).Namespace(project).Get(c.Request.Context(), id, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workflow", "details": err.Error()})
+		}
+		return
+	}
+	wf := RfeFromUnstructured(item)
+	if wf == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse workflow"})
+		return
+	}
+	approvals := wf.Approvals
+	if approvals == nil {
+		approvals = []types.PhaseApproval{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"phase":     workflowPhase(wf),
+		"gates":     workflowGateStatuses(wf),
+		"approvals": approvals,
+	})
+}
+
+// RecordProjectRFEWorkflowApproval records the caller's approval or rejection of a phase gate.
+// Only members of the gate's reviewer groups may decide when groups are configured. Decisions
+// are appended to status.approvals with the forwarded identity, so the list is the audit trail.
+// POST /api/projects/:projectName/rfe-workflows/:id/approvals
+func RecordProjectRFEWorkflowApproval(c *gin.Context) {
+	project := c.Param("projectName")
+	id := c.Param("id")
+
+	var req types.PhaseApprovalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if req.Decision == "reject" && req.Comment == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A comment is required when rejecting"})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	userIDStr, ok := userID.(string)
+	if !ok || strings.TrimSpace(userIDStr) == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User identity required"})
+		return
+	}
+	userName, _ := c.Get("userName")
+	displayName, _ := userName.(string)
+	groupsVal, _ := c.Get("userGroups")
+	userGroups, _ := groupsVal.([]string)
+
+	_, reqDyn := GetK8sClientsForRequest(c)
+	if reqDyn == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	item, err := reqDyn.Resource(GetRFEWorkflowResource()).Namespace(project).Get(c.Request.Context(), id, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow not found"})
+		} else if k8serrors.IsForbidden(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this workflow"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workflow", "details": err.Error()})
+		}
+		return
+	}
+	wf := RfeFromUnstructured(item)
+	if wf == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse workflow"})
+		return
+	}
+
+	current := workflowPhase(wf)
+	phase := strings.TrimSpace(req.Phase)
+	if phase == "" {
+		phase = current
+	}
+	if phase != current {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Only the current phase (%s) can be reviewed", current), "phase": current})
+		return
+	}
+	gate := gateForPhase(wf, phase)
+	if gate == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Phase %s has no approval gate", phase)})
+		return
+	}
+	groups := reviewerGroups(gate, userGroups)
+	if len(gate.RequiredGroups) > 0 && len(groups) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not in a reviewer group for this phase", "requiredGroups": gate.RequiredGroups})
+		return
+	}
+
+	decision := decisionApproved
+	if req.Decision == "reject" {
+		decision = decisionRejected
+	}
+	approval := types.PhaseApproval{
+		Phase:       phase,
+		User:        userIDStr,
+		DisplayName: displayName,
+		Groups:      groups,
+		Decision:    decision,
+		Comment:     req.Comment,
+		At:          time.Now().UTC().Format(time.RFC3339),
+	}
+
+	// Reviewers may only have read access to the workflow, so the decision is written with the
+	// backend SA once the caller's access and reviewer group membership are verified
+	var approvals []types.PhaseApproval
+	err = writeWorkflowStatus(c.Request.Context(), DynamicClient, project, id, func(status map[string]interface{}) error {
+		if p, _ := status["phase"].(string); isRFEPhase(p) && p != phase {
+			return errPhaseChanged
+		}
+		entry, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&approval)
+		if err != nil {
+			return err
+		}
+		list, _ := status["approvals"].([]interface{})
+		list = append(list, entry)
+		if len(list) > maxApprovals {
+			list = list[len(list)-maxApprovals:]
+		}
+		status["approvals"] = list
+
+		approvals = approvals[:0]
+		for _, it := range list {
+			if m, ok := it.(map[string]interface{}); ok {
+				var a types.PhaseApproval
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &a); err == nil {
+					approvals = append(approvals, a)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errPhaseChanged) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Workflow phase changed, reload and retry"})
+			return
+		}
+		log.Printf("RecordProjectRFEWorkflowApproval: failed to record %s of %s/%s phase %s by %s: %v", decision, project, id, phase, userIDStr, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record approval", "details": err.Error()})
+		return
+	}
+	log.Printf("RecordProjectRFEWorkflowApproval: %s %s phase %s of %s/%s", userIDStr, decision, phase, project, id)
+
+	c.JSON(http.StatusOK, gin.H{
+		"approval": approval,
+		"gate":     evaluateGate(*gate, approvals),
+	})
+}
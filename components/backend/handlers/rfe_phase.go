@@ -0,0 +1,628 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"ambient-code-backend/tracker"
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+// RFE workflow phases. status.phase moves forward through them in order: the phase's
+// artifacts on the feature branch decide whether it is complete, and the advance endpoint
+// moves to the next one.
+const (
+	rfePhasePre       = "pre"
+	rfePhaseSpecify   = "specify"
+	rfePhasePlan      = "plan"
+	rfePhaseTasks     = "tasks"
+	rfePhaseImplement = "implement"
+	rfePhaseDone      = "done"
+)
+
+var (
+	rfePhases     = []string{rfePhasePre, rfePhaseSpecify, rfePhasePlan, rfePhaseTasks, rfePhaseImplement, rfePhaseDone}
+	rfeWorkPhases = []string{rfePhaseSpecify, rfePhasePlan, rfePhaseTasks, rfePhaseImplement}
+)
+
+// maxPhaseHistory caps status.phaseHistory
+const maxPhaseHistory = 50
+
+var errPhaseChanged = errors.New("workflow phase changed concurrently")
+
+func isRFEPhase(phase string) bool {
+	return rfePhaseIndex(phase) >= 0
+}
+
+func rfePhaseIndex(phase string) int {
+	for i, p := range rfePhases {
+		if p == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextRFEPhase returns the phase after phase, or "" when the workflow is done
+func nextRFEPhase(phase string) string {
+	i := rfePhaseIndex(phase)
+	if i < 0 || i+1 >= len(rfePhases) {
+		return ""
+	}
+	return rfePhases[i+1]
+}
+
+// workflowPhase returns the recorded phase, or the one derived from the recorded artifacts
+// for workflows whose status predates phase tracking
+func workflowPhase(wf *types.RFEWorkflow) string {
+	if isRFEPhase(wf.Phase) {
+		return wf.Phase
+	}
+	var a types.WorkflowArtifacts
+	if wf.PhaseArtifacts != nil {
+		a = *wf.PhaseArtifacts
+	}
+	return deriveRFEPhase(a)
+}
+
+// artifactPath returns the repo path of a spec-kit document
+func artifactPath(a types.WorkflowArtifacts, name string) string {
+	if a.SpecDir == "" {
+		return "specs/" + name
+	}
+	return a.SpecDir + "/" + name
+}
+
+// rfePhaseComplete reports whether phase's outputs exist, and otherwise what is missing
+func rfePhaseComplete(phase string, a types.WorkflowArtifacts) (bool, string) {
+	switch phase {
+	case rfePhasePre:
+		if !a.Seeded {
+			return false, "feature branch (seed the workflow)"
+		}
+	case rfePhaseSpecify:
+		if !a.Spec {
+			return false, artifactPath(a, "spec.md")
+		}
+	case rfePhasePlan:
+		if !a.Plan {
+			return false, artifactPath(a, "plan.md")
+		}
+	case rfePhaseTasks:
+		if !a.Tasks {
+			return false, artifactPath(a, "tasks.md")
+		}
+	case rfePhaseImplement:
+		if !a.Tasks {
+			return false, artifactPath(a, "tasks.md")
+		}
+		if a.TasksTotal == 0 || a.TasksDone < a.TasksTotal {
+			return false, fmt.Sprintf("%d of %d tasks checked off in %s", a.TasksDone, a.TasksTotal, artifactPath(a, "tasks.md"))
+		}
+	}
+	return true, ""
+}
+
+// deriveRFEPhase returns the first phase whose artifacts are missing
+func deriveRFEPhase(a types.WorkflowArtifacts) string {
+	for _, p := range rfePhases {
+		if ok, _ := rfePhaseComplete(p, a); !ok {
+			return p
+		}
+	}
+	return rfePhaseDone
+}
+
+// fetchWorkflowArtifacts inspects the workflow's feature branch in the umbrella repo for
+// spec-kit outputs under specs/ or the workflow's feature folder (specs/<feature>/, see pickSpecDir)
+func fetchWorkflowArtifacts(ctx context.Context, wf *types.RFEWorkflow, token string) (types.WorkflowArtifacts, error) {
+	a := types.WorkflowArtifacts{CheckedAt: time.Now().UTC().Format(time.RFC3339)}
+	if wf.UmbrellaRepo == nil || strings.TrimSpace(wf.UmbrellaRepo.URL) == "" {
+		return a, fmt.Errorf("no spec repo configured")
+	}
+	if wf.BranchName == "" {
+		return a, nil
+	}
+	owner, repo, err := parseOwnerRepoFromURL(wf.UmbrellaRepo.URL)
+	if err != nil {
+		return a, err
+	}
+	// Empty for URLs parseGitHubRepoURL rejects, which githubAPIBaseURL maps to github.com
+	host, _, _ := parseGitHubRepoURL(wf.UmbrellaRepo.URL)
+	seeded, err := githubBranchExists(ctx, host, owner, repo, wf.BranchName, token)
+	if err != nil {
+		return a, fmt.Errorf("failed to check feature branch: %w", err)
+	}
+	a.Seeded = seeded
+	if !seeded {
+		return a, nil
+	}
+
+	scan := func(items []contentListItem) {
+		for _, it := range items {
+			if it.IsDir {
+				continue
+			}
+			switch strings.ToLower(it.Name) {
+			case "spec.md":
+				a.Spec = true
+			case "plan.md":
+				a.Plan = true
+			case "tasks.md":
+				a.Tasks = true
+			}
+		}
+	}
+	items, err := listGitHubDir(ctx, host, owner, repo, wf.BranchName, "specs", token)
+	if err != nil {
+		return a, err
+	}
+	scan(items)
+	if !a.Spec && !a.Plan && !a.Tasks {
+		previous := ""
+		if wf.PhaseArtifacts != nil {
+			previous = wf.PhaseArtifacts.SpecDir
+		}
+		if dir := pickSpecDir(items, wf.BranchName, previous); dir != "" {
+			sub, err := listGitHubDir(ctx, host, owner, repo, wf.BranchName, dir, token)
+			if err != nil {
+				return a, err
+			}
+			a.SpecDir = dir
+			scan(sub)
+		}
+	}
+
+	if a.Tasks {
+		content, err := readGitHubFile(ctx, host, owner, repo, wf.BranchName, artifactPath(a, "tasks.md"), token)
+		if err != nil {
+			return a, fmt.Errorf("failed to read tasks.md: %w", err)
+		}
+		for _, t := range tracker.ParseTasks(string(content)) {
+			a.TasksTotal++
+			if t.Done {
+				a.TasksDone++
+			}
+		}
+	}
+	return a, nil
+}
+
+// pickSpecDir chooses the feature folder under specs/: the one recorded by an earlier refresh,
+// else the one named after the feature branch (spec-kit names both NNN-feature), else the newest,
+// which sorts last as spec-kit numbers features in order
+func pickSpecDir(items []contentListItem, branch, previous string) string {
+	var dirs []string
+	for _, it := range items {
+		if it.IsDir {
+			dirs = append(dirs, it.Path)
+		}
+	}
+	if len(dirs) == 0 {
+		return ""
+	}
+	sort.Strings(dirs)
+	for _, d := range dirs {
+		if previous != "" && d == previous {
+			return d
+		}
+	}
+	for _, d := range dirs {
+		if path.Base(d) == path.Base(branch) {
+			return d
+		}
+	}
+	return dirs[len(dirs)-1]
+}
+
+// githubBranchExists reports whether branch exists, on the GitHub host serving the repository
+func githubBranchExists(ctx context.Context, host, owner, repo, branch, token string) (bool, error) {
+	api := fmt.Sprintf("%s/repos/%s/%s/git/refs/heads/%s", githubAPIBaseURL(host), owner, repo, branch)
+	resp, err := doGitHubRequest(ctx, http.MethodGet, api, "Bearer "+token, "", nil)
+	if err != nil {
+		return false, fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return false, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(body))
+}
+
+// readGitHubFile reads a file at ref, on the GitHub host serving the repository
+func readGitHubFile(ctx context.Context, host, owner, repo, ref, filePath, token string) ([]byte, error) {
+	api := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", githubAPIBaseURL(host), owner, repo, strings.TrimPrefix(filePath, "/"), ref)
+	resp, err := doGitHubRequest(ctx, http.MethodGet, api, "Bearer "+token, "application/vnd.github.v3.raw", nil)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(body))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// listGitHubDir lists a repository directory at ref on the GitHub host serving the repository;
+// a missing directory is empty
+func listGitHubDir(ctx context.Context, host, owner, repo, ref, path, token string) ([]contentListItem, error) {
+	url := fmt.Sprintf("%s/repos/%s/%s/contents/%s?ref=%s", githubAPIBaseURL(host), owner, repo, strings.TrimPrefix(path, "/"), ref)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return []contentListItem{}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitHub API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var entries []struct {
+		Name string `json:"name"`
+		Path string `json:"path"`
+		Type string `json:"type"`
+		Size int64  `json:"size"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		// A file at path decodes as an object, not a listing
+		return []contentListItem{}, nil
+	}
+	items := make([]contentListItem, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, contentListItem{Name: e.Name, Path: e.Path, IsDir: e.Type == "dir", Size: e.Size})
+	}
+	return items, nil
+}
+
+// refreshWorkflowPhase recomputes the workflow's artifacts from its feature branch and records
+// them in status (initialising status.phase from the artifacts the first time). On failure the
+// previously recorded artifacts are returned along with the error.
+func refreshWorkflowPhase(ctx context.Context, reqK8s *kubernetes.Clientset, reqDyn dynamic.Interface, project, userID string, wf *types.RFEWorkflow) (string, types.WorkflowArtifacts, error) {
+	var artifacts types.WorkflowArtifacts
+	if wf.PhaseArtifacts != nil {
+		artifacts = *wf.PhaseArtifacts
+	}
+	phase := workflowPhase(wf)
+
+	token, err := GetGitHubToken(ctx, reqK8s, reqDyn, project, userID)
+	if err != nil {
+		return phase, artifacts, err
+	}
+	fresh, err := fetchWorkflowArtifacts(ctx, wf, token)
+	if err != nil {
+		return phase, artifacts, err
+	}
+	artifacts = fresh
+	if !isRFEPhase(wf.Phase) {
+		phase = deriveRFEPhase(artifacts)
+	}
+
+	// Computed state is recorded with the backend SA so viewers refresh it too
+	if DynamicClient != nil {
+		if err := writeWorkflowStatus(ctx, DynamicClient, project, wf.ID, func(status map[string]interface{}) error {
+			a, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&artifacts)
+			if err != nil {
+				return err
+			}
+			status["phaseArtifacts"] = a
+			if p, _ := status["phase"].(string); !isRFEPhase(p) {
+				status["phase"] = phase
+			}
+			return nil
+		}); err != nil {
+			log.Printf("refreshWorkflowPhase: failed to record phase status for %s/%s: %v", project, wf.ID, err)
+		}
+	}
+	return phase, artifacts, nil
+}
+
+// writeWorkflowStatus applies mutate to the workflow's status and writes it, retrying on conflict
+func writeWorkflowStatus(ctx context.Context, dyn dynamic.Interface, project, workflowID string, mutate func(map[string]interface{}) error) error {
+	gvr := GetRFEWorkflowResource()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := dyn.Resource(gvr).Namespace(project).Get(ctx, workflowID, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		status, _, _ := unstructured.NestedMap(obj.Object, "status")
+		if status == nil {
+			status = map[string]interface{}{}
+		}
+		if err := mutate(status); err != nil {
+			return err
+		}
+		obj.Object["status"] = status
+		_, err = dyn.Resource(gvr).Namespace(project).UpdateStatus(ctx, obj, v1.UpdateOptions{})
+		return err
+	})
+}
+
+// AdvanceProjectRFEWorkflowPhase moves the workflow to its next phase once the current phase's
+// artifacts exist on the feature branch (or unconditionally with force) and its approval gate
+// is satisfied, and launches the next phase's session
+// POST /api/projects/:projectName/rfe-workflows/:id/advance
+func AdvanceProjectRFEWorkflowPhase(c *gin.Context) {
+	project := c.Param("projectName")
+	id := c.Param("id")
+
+	var req types.AdvancePhaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	reqK8s, reqDyn := GetK8sClientsForRequest(c)
+	if reqDyn == nil || reqK8s == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	userID, _ := c.Get("userID")
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User identity required"})
+		return
+	}
+
+	item, err := reqDyn.Resource(GetRFEWorkflowResource()).Namespace(project).Get(c.Request.Context(), id, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get workflow", "details": err.Error()})
+		}
+		return
+	}
+	wf := RfeFromUnstructured(item)
+	if wf == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse workflow"})
+		return
+	}
+	if allowed, err := canUpdateWorkflow(c, project, id); err != nil {
+		log.Printf("AdvanceProjectRFEWorkflowPhase: SSAR failed for %s/%s: %v", project, id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+		return
+	} else if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to advance this workflow"})
+		return
+	}
+
+	phase, artifacts, err := refreshWorkflowPhase(c.Request.Context(), reqK8s, reqDyn, project, userIDStr, wf)
+	if err != nil && !req.Force {
+		log.Printf("AdvanceProjectRFEWorkflowPhase: failed to read artifacts for %s/%s: %v", project, id, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to read workflow artifacts from the feature branch", "details": err.Error()})
+		return
+	}
+	next := nextRFEPhase(phase)
+	if next == "" {
+		c.JSON(http.StatusConflict, gin.H{"error": "Workflow is already done", "phase": phase})
+		return
+	}
+	complete, missing := rfePhaseComplete(phase, artifacts)
+	if !complete && !req.Force {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   fmt.Sprintf("Phase %s is not complete", phase),
+			"phase":   phase,
+			"missing": missing,
+		})
+		return
+	}
+
+	// Approval gates are not bypassed by force
+	if gate := gateForPhase(wf, phase); gate != nil {
+		if st := evaluateGate(*gate, wf.Approvals); !st.Satisfied {
+			c.JSON(http.StatusConflict, gin.H{
+				"error": fmt.Sprintf("Phase %s requires reviewer approval", phase),
+				"phase": phase,
+				"gate":  st,
+			})
+			return
+		}
+	}
+
+	// Editors cannot write rfeworkflows/status, so the transition is written with the backend SA
+	// once the caller's update access to the workflow is verified
+	transition := types.PhaseTransition{
+		From:   phase,
+		To:     next,
+		At:     time.Now().UTC().Format(time.RFC3339),
+		By:     userIDStr,
+		Forced: !complete,
+	}
+	err = writeWorkflowStatus(c.Request.Context(), DynamicClient, project, id, func(status map[string]interface{}) error {
+		current, _ := status["phase"].(string)
+		if !isRFEPhase(current) {
+			current = deriveRFEPhase(artifacts)
+		}
+		if current != phase {
+			return errPhaseChanged
+		}
+		t, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&transition)
+		if err != nil {
+			return err
+		}
+		history, _ := status["phaseHistory"].([]interface{})
+		history = append(history, t)
+		if len(history) > maxPhaseHistory {
+			history = history[len(history)-maxPhaseHistory:]
+		}
+		status["phase"] = next
+		status["phaseHistory"] = history
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errPhaseChanged):
+			c.JSON(http.StatusConflict, gin.H{"error": "Workflow phase changed, reload and retry"})
+		default:
+			log.Printf("AdvanceProjectRFEWorkflowPhase: failed to update %s/%s: %v", project, id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to advance workflow", "details": err.Error()})
+		}
+		return
+	}
+	log.Printf("AdvanceProjectRFEWorkflowPhase: %s/%s %s -> %s (forced=%v) by %s", project, id, phase, next, transition.Forced, userIDStr)
+
+	resp := gin.H{
+		"message":       "Workflow advanced",
+		"previousPhase": phase,
+		"phase":         next,
+		"forced":        transition.Forced,
+	}
+	if next != rfePhaseDone && (req.StartSession == nil || *req.StartSession) {
+		sessionReq := phaseSessionRequest(c.Request.Context(), project, wf, next, artifacts, req.Agents, reqK8s, reqDyn, userIDStr)
+		created, err := createAgenticSession(c, project, sessionReq)
+		if err != nil {
+			resp["sessionError"] = "Failed to create phase session"
+		} else {
+			resp["session"] = created.GetName()
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// phaseSessionRequest builds the session for a work phase the same way the RFE phase cards do:
+// the spec-kit command for the phase, the selected agents, and the workflow's repos on the
+// feature branch
+func phaseSessionRequest(ctx context.Context, project string, wf *types.RFEWorkflow, phase string, artifacts types.WorkflowArtifacts, personas []string, reqK8s *kubernetes.Clientset, reqDyn dynamic.Interface, userID string) types.CreateAgenticSessionRequest {
+	prompt := "/speckit." + phase
+	if phase == rfePhaseSpecify {
+		prompt = "/speckit.specify Develop a new feature based on rfe.md or if that does not exist, follow these feature requirements: " + wf.Description
+	}
+	instructions, selected := phaseAgentInstructions(ctx, project, wf, personas, reqK8s, reqDyn, userID)
+	prompt += instructions
+
+	expected := "implement"
+	switch phase {
+	case rfePhaseSpecify:
+		expected = artifactPath(artifacts, "spec.md")
+	case rfePhasePlan:
+		expected = artifactPath(artifacts, "plan.md")
+	case rfePhaseTasks:
+		expected = artifactPath(artifacts, "tasks.md")
+	}
+
+	interactive := false
+	autoPush := true
+	req := types.CreateAgenticSessionRequest{
+		Prompt:             prompt,
+		DisplayName:        fmt.Sprintf("%s - %s", wf.Title, phase),
+		Interactive:        &interactive,
+		AutoPushOnComplete: &autoPush,
+		EnvironmentVariables: map[string]string{
+			"WORKFLOW_PHASE": phase,
+			"PARENT_RFE":     wf.ID,
+		},
+		Labels: map[string]string{
+			"project":      project,
+			"rfe-workflow": wf.ID,
+			"rfe-phase":    phase,
+		},
+		Annotations: map[string]string{
+			"rfe-expected": expected,
+		},
+		AgentPersonas: selected,
+	}
+	if wf.UmbrellaRepo != nil {
+		repos := []types.SessionRepoMapping{{
+			Input:  types.NamedGitRepo{URL: wf.UmbrellaRepo.URL, Branch: wf.UmbrellaRepo.Branch},
+			Output: &types.OutputNamedGitRepo{URL: wf.UmbrellaRepo.URL, Branch: wf.UmbrellaRepo.Branch},
+		}}
+		for _, r := range wf.SupportingRepos {
+			repos = append(repos, types.SessionRepoMapping{
+				Input:  types.NamedGitRepo{URL: r.URL, Branch: r.Branch},
+				Output: &types.OutputNamedGitRepo{URL: r.URL, Branch: r.Branch},
+			})
+		}
+		mainRepoIndex := 0
+		req.Repos = repos
+		req.MainRepoIndex = &mainRepoIndex
+		if b, err := json.Marshal(repos); err == nil {
+			req.EnvironmentVariables["REPOS_JSON"] = string(b)
+		}
+		req.EnvironmentVariables["MAIN_REPO_INDEX"] = "0"
+	}
+	return req
+}
+
+// phaseAgentInstructions resolves personas against the workflow's .claude/agents and returns the
+// prompt suffix inviting them into the session along with the personas that were found
+func phaseAgentInstructions(ctx context.Context, project string, wf *types.RFEWorkflow, personas []string, reqK8s *kubernetes.Clientset, reqDyn dynamic.Interface, userID string) (string, []string) {
+	if len(personas) == 0 || wf.UmbrellaRepo == nil {
+		return "", nil
+	}
+	owner, repo, err := parseOwnerRepoFromURL(wf.UmbrellaRepo.URL)
+	if err != nil {
+		return "", nil
+	}
+	token, err := GetGitHubToken(ctx, reqK8s, reqDyn, project, userID)
+	if err != nil {
+		log.Printf("phaseAgentInstructions: no GitHub token for %s: %v", project, err)
+		return "", nil
+	}
+	agents, err := fetchAgentsFromRepo(ctx, owner, repo, wf.BranchName, token)
+	if err != nil {
+		log.Printf("phaseAgentInstructions: failed to fetch agents for %s/%s: %v", project, wf.ID, err)
+		return "", nil
+	}
+	byPersona := map[string]Agent{}
+	for _, a := range agents {
+		byPersona[a.Persona] = a
+	}
+	var selected []Agent
+	var found []string
+	for _, p := range personas {
+		if a, ok := byPersona[strings.TrimSpace(p)]; ok {
+			selected = append(selected, a)
+			found = append(found, a.Persona)
+		}
+	}
+	if len(selected) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n\nIMPORTANT - Selected Agents for this workflow:\n")
+	sb.WriteString("The following agents have been selected to participate in this workflow. Invoke them by name to get their specialized perspectives:\n\n")
+	for i, a := range selected {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "- %s (%s)", a.Name, a.Role)
+	}
+	fmt.Fprintf(&sb, "\n\nYou can invoke agents by using their name in your prompts. For example: \"Let's get input from %s on this approach.\"", selected[0].Name)
+	return sb.String(), found
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"ambient-code-backend/git"
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// seedRequest holds the optional agent source and spec-kit settings for seed operations
+type seedRequest struct {
+	AgentSourceURL    string `json:"agentSourceUrl,omitempty" form:"agentSourceUrl"`
+	AgentSourceBranch string `json:"agentSourceBranch,omitempty" form:"agentSourceBranch"`
+	AgentSourcePath   string `json:"agentSourcePath,omitempty" form:"agentSourcePath"`
+	SpecKitRepo       string `json:"specKitRepo,omitempty" form:"specKitRepo"`
+	SpecKitVersion    string `json:"specKitVersion,omitempty" form:"specKitVersion"`
+	SpecKitTemplate   string `json:"specKitTemplate,omitempty" form:"specKitTemplate"`
+	// Optional SHA-256 pins; seeding fails when the fetched content doesn't match
+	SpecKitSHA256     string `json:"specKitSha256,omitempty" form:"specKitSha256"`
+	AgentSourceSHA256 string `json:"agentSourceSha256,omitempty" form:"agentSourceSha256"`
+	// DryRun returns the seeding file plan without cloning or pushing
+	DryRun bool `json:"dryRun,omitempty"`
+	// Mode selects how upgrades are applied: pr (default) or commit
+	Mode string `json:"mode,omitempty"`
+}
+
+// resolveSeedSource picks each setting from the request, then the source the workflow was last
+// seeded from, then environment variables, then defaults. Checksums are only pinned by the request
+// (or SEED_CHECKSUMS_FILE); recorded checksums are informational since branch refs move.
+func resolveSeedSource(req seedRequest, recorded *types.WorkflowSeed) git.SeedSource {
+	if recorded == nil {
+		recorded = &types.WorkflowSeed{}
+	}
+	pick := func(values ...string) string {
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	return git.SeedSource{
+		AgentSourceURL:    pick(req.AgentSourceURL, recorded.AgentSourceURL, "https://github.com/ambient-code/vTeam.git"),
+		AgentSourceBranch: pick(req.AgentSourceBranch, recorded.AgentSourceBranch, "main"),
+		AgentSourcePath:   pick(req.AgentSourcePath, recorded.AgentSourcePath, "agents"),
+		SpecKitRepo:       pick(req.SpecKitRepo, recorded.SpecKitRepo, os.Getenv("SPEC_KIT_REPO"), "github/spec-kit"),
+		SpecKitVersion:    pick(req.SpecKitVersion, recorded.SpecKitVersion, os.Getenv("SPEC_KIT_VERSION"), "main"),
+		SpecKitTemplate:   pick(req.SpecKitTemplate, recorded.SpecKitTemplate, os.Getenv("SPEC_KIT_TEMPLATE"), "spec-kit-template-claude-sh"),
+		SpecKitSHA256:     strings.ToLower(pick(req.SpecKitSHA256)),
+		AgentSourceSHA256: strings.ToLower(pick(req.AgentSourceSHA256)),
+	}
+}
+
+// recordWorkflowSeed stores the seed source in status.seed. An upgrade pull request URL marks
+// an upgrade; otherwise the branch was seeded (or upgraded in place when already seeded).
+func recordWorkflowSeed(ctx context.Context, project, workflowID string, src git.SeedSource, upgradePR string) {
+	if DynamicClient == nil {
+		return
+	}
+	err := writeWorkflowStatus(ctx, DynamicClient, project, workflowID, func(status map[string]interface{}) error {
+		seed := &types.WorkflowSeed{}
+		if existing, ok := status["seed"].(map[string]interface{}); ok {
+			_ = runtime.DefaultUnstructuredConverter.FromUnstructured(existing, seed)
+		}
+		now := time.Now().UTC().Format(time.RFC3339)
+		if seed.SeededAt == "" {
+			seed.SeededAt = now
+		} else {
+			seed.UpgradedAt = now
+		}
+		seed.UpgradePullRequest = upgradePR
+		seed.SpecKitRepo = src.SpecKitRepo
+		seed.SpecKitVersion = src.SpecKitVersion
+		seed.SpecKitTemplate = src.SpecKitTemplate
+		seed.AgentSourceURL = src.AgentSourceURL
+		seed.AgentSourceBranch = src.AgentSourceBranch
+		seed.AgentSourcePath = src.AgentSourcePath
+		seed.SpecKitSHA256 = src.SpecKitSHA256
+		seed.AgentSourceSHA256 = src.AgentSourceSHA256
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(seed)
+		if err != nil {
+			return err
+		}
+		status["seed"] = m
+		return nil
+	})
+	if err != nil {
+		log.Printf("recordWorkflowSeed: failed to record seed source for %s/%s: %v", project, workflowID, err)
+	}
+}
+
+// seededWorkflowForRequest loads the workflow and the caller's GitHub token for seed operations,
+// writing the error response itself when it returns ok=false
+func seededWorkflowForRequest(c *gin.Context) (*types.RFEWorkflow, string, bool) {
+	project := c.Param("projectName")
+	id := c.Param("id")
+
+	reqK8s, reqDyn := GetK8sClientsForRequest(c)
+	if reqDyn == nil || reqK8s == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return nil, "", false
+	}
+	item, err := reqDyn.Resource(GetRFEWorkflowResource()).Namespace(project).Get(c.Request.Context(), id, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Workflow not found"})
+		} else if k8serrors.IsForbidden(err) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to access this workflow"})
+		} else {
+			log.Printf("Failed to get workflow %s: %v", id, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve workflow"})
+		}
+		return nil, "", false
+	}
+	wf := RfeFromUnstructured(item)
+	if wf == nil || wf.UmbrellaRepo == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No spec repo configured"})
+		return nil, "", false
+	}
+	if wf.BranchName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Workflow missing branch name"})
+		return nil, "", false
+	}
+	token, ok := githubTokenForRequest(c, reqK8s, reqDyn, project)
+	if !ok {
+		return nil, "", false
+	}
+	return wf, token, true
+}
+
+// githubTokenForRequest resolves the caller's GitHub token, writing the error response on failure
+func githubTokenForRequest(c *gin.Context, reqK8s *kubernetes.Clientset, reqDyn dynamic.Interface, project string) (string, bool) {
+	userID, _ := c.Get("userID")
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User identity required"})
+		return "", false
+	}
+	token, err := GetGitHubToken(c.Request.Context(), reqK8s, reqDyn, project, userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return token, true
+}
+
+// GetProjectRFEWorkflowSeedDrift compares the feature branch's .claude/ and .specify/ files with
+// the spec-kit version and agent source it was seeded from (query parameters override them, e.g.
+// ?specKitVersion=v0.0.60 to preview an upgrade)
+// GET /api/projects/:projectName/rfe-workflows/:id/seed/drift
+func GetProjectRFEWorkflowSeedDrift(c *gin.Context) {
+	wf, token, ok := seededWorkflowForRequest(c)
+	if !ok {
+		return
+	}
+	var req seedRequest
+	_ = c.ShouldBindQuery(&req)
+	src := resolveSeedSource(req, wf.Seed)
+
+	drift, err := DetectSeedDrift(c.Request.Context(), wf.UmbrellaRepo.URL, wf.BranchName, token, src)
+	if err != nil {
+		log.Printf("GetProjectRFEWorkflowSeedDrift: %s/%s: %v", c.Param("projectName"), wf.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"drift": drift, "recorded": wf.Seed})
+}
+
+// UpgradeProjectRFEWorkflowSeed re-seeds an existing feature branch with the requested (or
+// recorded) spec-kit version and agent source. Missing and changed files are committed together,
+// by default on a new branch with a pull request into the feature branch for review.
+// POST /api/projects/:projectName/rfe-workflows/:id/seed/upgrade
+func UpgradeProjectRFEWorkflowSeed(c *gin.Context) {
+	project := c.Param("projectName")
+	wf, token, ok := seededWorkflowForRequest(c)
+	if !ok {
+		return
+	}
+	// The upgrade pushes to the feature branch and status.seed is recorded with the backend SA,
+	// so read access to the workflow is not enough
+	if allowed, err := canUpdateWorkflow(c, project, wf.ID); err != nil {
+		log.Printf("UpgradeProjectRFEWorkflowSeed: SSAR failed for %s/%s: %v", project, wf.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to perform access review"})
+		return
+	} else if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You don't have permission to upgrade this workflow's seed"})
+		return
+	}
+	var req seedRequest
+	_ = c.ShouldBindJSON(&req)
+	src := resolveSeedSource(req, wf.Seed)
+
+	result, err := UpgradeRepoSeeding(c.Request.Context(), wf.UmbrellaRepo.URL, wf.BranchName, token, src, req.Mode)
+	if err != nil {
+		log.Printf("UpgradeProjectRFEWorkflowSeed: %s/%s: %v", project, wf.ID, err)
+		status := http.StatusInternalServerError
+		if result != nil {
+			// Pushed, but the pull request could not be opened
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error(), "upgrade": result})
+		return
+	}
+	if len(result.Files) == 0 {
+		c.JSON(http.StatusOK, gin.H{"message": "Branch already matches the seed source", "upgrade": result})
+		return
+	}
+	recordWorkflowSeed(c.Request.Context(), project, wf.ID, result.Source, result.PullRequestURL)
+
+	message := "Seed upgrade committed to " + result.Branch
+	if result.PullRequestURL != "" {
+		message = "Seed upgrade opened for review: " + result.PullRequestURL
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message, "upgrade": result})
+}
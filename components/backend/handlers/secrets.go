@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -13,8 +15,34 @@ import (
 )
 
 // Runner secrets management
-// Config is stored in ProjectSettings.spec.runnerSecretsName
-// The Secret lives in the project namespace and stores key/value pairs for runners
+// Config is stored in ProjectSettings.spec.runnerSecretsName and spec.runnerSecretRefs
+// The Secret lives in the project namespace and stores key/value pairs for runners; references
+// name values in the operator's external secret provider and are resolved when a Job starts.
+// Values are write-only through this API: reads return keys and references, never values.
+
+// runnerSecretRef mirrors an entry of ProjectSettings spec.runnerSecretRefs
+type runnerSecretRef struct {
+	EnvName string `json:"envName"`
+	Path    string `json:"path"`
+	Key     string `json:"key"`
+}
+
+func parseRunnerSecretRefs(spec map[string]interface{}) []runnerSecretRef {
+	refs := []runnerSecretRef{}
+	items, _ := spec["runnerSecretRefs"].([]interface{})
+	for _, it := range items {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		r := runnerSecretRef{}
+		r.EnvName, _ = m["envName"].(string)
+		r.Path, _ = m["path"].(string)
+		r.Key, _ = m["key"].(string)
+		refs = append(refs, r)
+	}
+	return refs
+}
 
 // ListNamespaceSecrets handles GET /api/projects/:projectName/secrets -> { items: [{name, createdAt}] }
 func ListNamespaceSecrets(c *gin.Context) {
@@ -66,23 +94,53 @@ func GetRunnerSecretsConfig(c *gin.Context) {
 	}
 
 	secretName := ""
+	refs := []runnerSecretRef{}
+	bundles := []runnerSecretBundle{}
 	if obj != nil {
 		if spec, ok := obj.Object["spec"].(map[string]interface{}); ok {
 			if v, ok := spec["runnerSecretsName"].(string); ok {
 				secretName = v
 			}
+			refs = parseRunnerSecretRefs(spec)
+			bundles = parseRunnerSecretBundles(spec)
 		}
 	}
-	c.JSON(http.StatusOK, gin.H{"secretName": secretName})
+	c.JSON(http.StatusOK, gin.H{"secretName": secretName, "secretRefs": refs, "secretBundles": bundles})
+}
+
+// runnerSecretBundle mirrors an entry of ProjectSettings spec.secretBundles, a named group of
+// keys sessions can request
+type runnerSecretBundle struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Keys        []string `json:"keys"`
 }
 
-// UpdateRunnerSecretsConfig handles PUT /api/projects/:projectName/runner-secrets/config { secretName }
+func parseRunnerSecretBundles(spec map[string]interface{}) []runnerSecretBundle {
+	bundles := []runnerSecretBundle{}
+	items, _ := spec["secretBundles"].([]interface{})
+	for _, it := range items {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		b := runnerSecretBundle{Keys: stringList(m["keys"])}
+		b.Name, _ = m["name"].(string)
+		b.Description, _ = m["description"].(string)
+		bundles = append(bundles, b)
+	}
+	return bundles
+}
+
+// UpdateRunnerSecretsConfig handles PUT /api/projects/:projectName/runner-secrets/config { secretName, secretRefs? }
+// secretRefs replaces spec.runnerSecretRefs when present and is left untouched when omitted
 func UpdateRunnerSecretsConfig(c *gin.Context) {
 	projectName := c.Param("projectName")
 	_, reqDyn := GetK8sClientsForRequest(c)
 
 	var req struct {
-		SecretName string `json:"secretName" binding:"required"`
+		SecretName string             `json:"secretName" binding:"required"`
+		SecretRefs *[]runnerSecretRef `json:"secretRefs"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -92,6 +150,14 @@ func UpdateRunnerSecretsConfig(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "secretName is required"})
 		return
 	}
+	if req.SecretRefs != nil {
+		for i, r := range *req.SecretRefs {
+			if strings.TrimSpace(r.EnvName) == "" || strings.TrimSpace(r.Path) == "" || strings.TrimSpace(r.Key) == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("secretRefs[%d]: envName, path and key are required", i)})
+				return
+			}
+		}
+	}
 
 	// Operator owns ProjectSettings. If it exists, update; otherwise, return not found.
 	gvr := GetProjectSettingsResource()
@@ -112,7 +178,26 @@ func UpdateRunnerSecretsConfig(c *gin.Context) {
 		spec = map[string]interface{}{}
 		obj.Object["spec"] = spec
 	}
+	previous, _ := spec["runnerSecretsName"].(string)
 	spec["runnerSecretsName"] = req.SecretName
+	auditAction(c, "runner-secrets.config.update", "runner-secrets", req.SecretName, map[string]string{
+		"previousSecretName": previous,
+	})
+	if req.SecretRefs != nil {
+		items := make([]interface{}, 0, len(*req.SecretRefs))
+		envNames := make([]string, 0, len(*req.SecretRefs))
+		for _, r := range *req.SecretRefs {
+			items = append(items, map[string]interface{}{
+				"envName": strings.TrimSpace(r.EnvName),
+				"path":    strings.Trim(strings.TrimSpace(r.Path), "/"),
+				"key":     strings.TrimSpace(r.Key),
+			})
+			envNames = append(envNames, strings.TrimSpace(r.EnvName))
+		}
+		spec["runnerSecretRefs"] = items
+		sort.Strings(envNames)
+		auditDetail(c, "secretRefs", strings.Join(envNames, ","))
+	}
 
 	if _, err := reqDyn.Resource(gvr).Namespace(projectName).Update(c.Request.Context(), obj, v1.UpdateOptions{}); err != nil {
 		log.Printf("Failed to update ProjectSettings for %s: %v", projectName, err)
@@ -123,7 +208,14 @@ func UpdateRunnerSecretsConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"secretName": req.SecretName})
 }
 
-// ListRunnerSecrets handles GET /api/projects/:projectName/runner-secrets -> { data: { key: value } }
+// runnerSecretKey describes one stored key without its value
+type runnerSecretKey struct {
+	Key  string `json:"key"`
+	Size int    `json:"size"`
+}
+
+// ListRunnerSecrets handles GET /api/projects/:projectName/runner-secrets ->
+// { secretName, keys: [{key, size}], refs: [{envName, path, key}], updatedAt? }
 func ListRunnerSecrets(c *gin.Context) {
 	projectName := c.Param("projectName")
 	reqK8s, reqDyn := GetK8sClientsForRequest(c)
@@ -137,22 +229,25 @@ func ListRunnerSecrets(c *gin.Context) {
 		return
 	}
 	secretName := ""
+	refs := []runnerSecretRef{}
 	if obj != nil {
 		if spec, ok := obj.Object["spec"].(map[string]interface{}); ok {
 			if v, ok := spec["runnerSecretsName"].(string); ok {
 				secretName = v
 			}
+			refs = parseRunnerSecretRefs(spec)
 		}
 	}
+	resp := gin.H{"secretName": secretName, "keys": []runnerSecretKey{}, "refs": refs}
 	if secretName == "" {
-		c.JSON(http.StatusOK, gin.H{"data": map[string]string{}})
+		c.JSON(http.StatusOK, resp)
 		return
 	}
 
 	sec, err := reqK8s.CoreV1().Secrets(projectName).Get(c.Request.Context(), secretName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
-			c.JSON(http.StatusOK, gin.H{"data": map[string]string{}})
+			c.JSON(http.StatusOK, resp)
 			return
 		}
 		log.Printf("Failed to get Secret %s/%s: %v", projectName, secretName, err)
@@ -160,25 +255,50 @@ func ListRunnerSecrets(c *gin.Context) {
 		return
 	}
 
-	out := map[string]string{}
+	keys := make([]runnerSecretKey, 0, len(sec.Data))
 	for k, v := range sec.Data {
-		out[k] = string(v)
+		keys = append(keys, runnerSecretKey{Key: k, Size: len(v)})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+	resp["keys"] = keys
+	if updatedAt := lastSecretUpdate(sec); !updatedAt.IsZero() {
+		resp["updatedAt"] = updatedAt.Format(time.RFC3339)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// lastSecretUpdate is the latest managed-fields timestamp, falling back to creation time
+func lastSecretUpdate(sec *corev1.Secret) time.Time {
+	latest := sec.CreationTimestamp.Time
+	for _, mf := range sec.ManagedFields {
+		if mf.Time != nil && mf.Time.After(latest) {
+			latest = mf.Time.Time
+		}
 	}
-	c.JSON(http.StatusOK, gin.H{"data": out})
+	return latest
 }
 
-// UpdateRunnerSecrets handles PUT /api/projects/:projectName/runner-secrets { data: { key: value } }
+// UpdateRunnerSecrets handles PUT /api/projects/:projectName/runner-secrets { data: { key: value }, remove: [key] }
+// Keys in data are set, keys in remove are deleted and all other stored keys are kept, so
+// clients can update a value without ever reading the others back
 func UpdateRunnerSecrets(c *gin.Context) {
 	projectName := c.Param("projectName")
 	reqK8s, reqDyn := GetK8sClientsForRequest(c)
 
 	var req struct {
-		Data map[string]string `json:"data" binding:"required"`
+		Data   map[string]string `json:"data"`
+		Remove []string          `json:"remove"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	for _, k := range req.Remove {
+		if _, ok := req.Data[k]; ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("key %s is both set and removed", k)})
+			return
+		}
+	}
 
 	// Read config for secret name
 	gvr := GetProjectSettingsResource()
@@ -199,6 +319,10 @@ func UpdateRunnerSecrets(c *gin.Context) {
 	if secretName == "" {
 		secretName = "ambient-runner-secrets"
 	}
+	// Record which keys were written, never their values
+	auditAction(c, "runner-secrets.update", "runner-secrets", secretName, map[string]string{
+		"keys": sortedKeys(req.Data),
+	})
 
 	// Do not create/update ProjectSettings here. The operator owns it.
 
@@ -228,9 +352,22 @@ func UpdateRunnerSecrets(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read runner secrets"})
 		return
 	} else {
-		// Update existing - replace Data
+		// Update existing - merge Data
+		var removed []string
+		for _, k := range req.Remove {
+			if _, ok := sec.Data[k]; ok {
+				removed = append(removed, k)
+			}
+		}
+		sort.Strings(removed)
+		auditDetail(c, "removedKeys", strings.Join(removed, ","))
 		sec.Type = corev1.SecretTypeOpaque
-		sec.Data = map[string][]byte{}
+		if sec.Data == nil {
+			sec.Data = map[string][]byte{}
+		}
+		for _, k := range req.Remove {
+			delete(sec.Data, k)
+		}
 		for k, v := range req.Data {
 			sec.Data[k] = []byte(v)
 		}
@@ -0,0 +1,365 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"ambient-code-backend/git"
+	"ambient-code-backend/types"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Built-in session defaults, used where ProjectSettings spec.sessionDefaults is silent
+const (
+	defaultSessionModel       = "sonnet"
+	defaultSessionTemperature = 0.7
+	defaultSessionMaxTokens   = 4000
+	defaultSessionTimeout     = 300
+)
+
+// errSessionPolicy marks sessions rejected by the project's ProjectSettings guardrails
+var errSessionPolicy = errors.New("session violates project policy")
+
+// isSessionPolicyViolation reports whether err rejects a session because of project guardrails
+func isSessionPolicyViolation(err error) bool {
+	return errors.Is(err, errSessionPolicy)
+}
+
+// sessionPolicy is a project's session configuration: defaults from ProjectSettings
+// spec.sessionDefaults and guardrails from spec.sessionPolicy
+type sessionPolicy struct {
+	LLMSettings types.LLMSettings
+	Timeout     int
+	Env         map[string]string
+	Secrets     *types.SessionSecrets // nil = whole runner secret
+
+	SecretBundles map[string]bool // names of spec.secretBundles
+
+	AllowedModels        []string
+	MaxTimeout           int      // 0 = unlimited
+	AllowedRepositories  []string // globs over host/owner/repo, e.g. github.com/my-org/*
+	OutputBranchPrefixes []string
+	BannedEnvKeys        []string // exact names or globs, e.g. AWS_*
+}
+
+// parseSessionPolicy reads the session policy from a ProjectSettings spec
+func parseSessionPolicy(spec map[string]interface{}) sessionPolicy {
+	p := sessionPolicy{
+		LLMSettings: types.LLMSettings{
+			Model:       defaultSessionModel,
+			Temperature: defaultSessionTemperature,
+			MaxTokens:   defaultSessionMaxTokens,
+		},
+		Timeout: defaultSessionTimeout,
+		Env:     map[string]string{},
+	}
+
+	if defaults, found, _ := unstructured.NestedMap(spec, "sessionDefaults"); found {
+		if llm, ok := defaults["llmSettings"].(map[string]interface{}); ok {
+			if v, ok := llm["model"].(string); ok && strings.TrimSpace(v) != "" {
+				p.LLMSettings.Model = strings.TrimSpace(v)
+			}
+			if v, ok := numberValue(llm["temperature"]); ok {
+				p.LLMSettings.Temperature = v
+			}
+			if v, ok := numberValue(llm["maxTokens"]); ok && v > 0 {
+				p.LLMSettings.MaxTokens = int(v)
+			}
+		}
+		if v, ok := numberValue(defaults["timeout"]); ok && v > 0 {
+			p.Timeout = int(v)
+		}
+		if env, ok := defaults["environmentVariables"].(map[string]interface{}); ok {
+			for k, v := range env {
+				if s, ok := v.(string); ok {
+					p.Env[k] = s
+				}
+			}
+		}
+		if sel, ok := defaults["secrets"].(map[string]interface{}); ok {
+			p.Secrets = &types.SessionSecrets{Keys: stringList(sel["keys"]), Bundles: stringList(sel["bundles"])}
+		}
+	}
+
+	p.SecretBundles = map[string]bool{}
+	if bundles, ok := spec["secretBundles"].([]interface{}); ok {
+		for _, b := range bundles {
+			if m, ok := b.(map[string]interface{}); ok {
+				if name, ok := m["name"].(string); ok && strings.TrimSpace(name) != "" {
+					p.SecretBundles[strings.TrimSpace(name)] = true
+				}
+			}
+		}
+	}
+
+	if policy, found, _ := unstructured.NestedMap(spec, "sessionPolicy"); found {
+		p.AllowedModels = stringList(policy["allowedModels"])
+		if v, ok := numberValue(policy["maxTimeout"]); ok && v > 0 {
+			p.MaxTimeout = int(v)
+		}
+		p.AllowedRepositories = stringList(policy["allowedRepositories"])
+		p.OutputBranchPrefixes = stringList(policy["outputBranchPrefixes"])
+		p.BannedEnvKeys = stringList(policy["bannedEnvironmentVariables"])
+	}
+	return p
+}
+
+// loadSessionPolicy reads the project's policy with the backend service account, so guardrails
+// apply regardless of whether the caller may read ProjectSettings
+func loadSessionPolicy(ctx context.Context, project string) (sessionPolicy, error) {
+	if DynamicClient == nil {
+		return parseSessionPolicy(nil), nil
+	}
+	obj, err := DynamicClient.Resource(GetProjectSettingsResource()).Namespace(project).Get(ctx, "projectsettings", v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		return parseSessionPolicy(nil), nil
+	}
+	if err != nil {
+		return sessionPolicy{}, fmt.Errorf("read ProjectSettings: %w", err)
+	}
+	spec, _ := obj.Object["spec"].(map[string]interface{})
+	return parseSessionPolicy(spec), nil
+}
+
+// resolveLLMSettings overlays the requested settings on the project defaults
+func (p sessionPolicy) resolveLLMSettings(req *types.LLMSettings) types.LLMSettings {
+	llm := p.LLMSettings
+	if req != nil {
+		if req.Model != "" {
+			llm.Model = req.Model
+		}
+		if req.Temperature != 0 {
+			llm.Temperature = req.Temperature
+		}
+		if req.MaxTokens != 0 {
+			llm.MaxTokens = req.MaxTokens
+		}
+	}
+	return llm
+}
+
+// resolveTimeout returns the requested timeout or the project default
+func (p sessionPolicy) resolveTimeout(req *int) int {
+	if req != nil {
+		return *req
+	}
+	return p.Timeout
+}
+
+// resolveEnv merges the project's default environment variables under the requested ones
+func (p sessionPolicy) resolveEnv(req map[string]string) map[string]string {
+	env := make(map[string]string, len(p.Env)+len(req))
+	for k, v := range p.Env {
+		env[k] = v
+	}
+	for k, v := range req {
+		env[k] = v
+	}
+	return env
+}
+
+// resolveSecrets returns the requested secret selection or the project default
+func (p sessionPolicy) resolveSecrets(req *types.SessionSecrets) *types.SessionSecrets {
+	if req != nil {
+		return req
+	}
+	return p.Secrets
+}
+
+// secretsSpec returns a secret selection as stored in AgenticSession spec.secrets, or nil for none
+func secretsSpec(sel *types.SessionSecrets) *types.SessionSecrets {
+	if sel == nil {
+		return nil
+	}
+	toList := func(items []string) []string {
+		var out []string
+		for _, item := range items {
+			if item = strings.TrimSpace(item); item != "" {
+				out = append(out, item)
+			}
+		}
+		return out
+	}
+	return &types.SessionSecrets{Keys: toList(sel.Keys), Bundles: toList(sel.Bundles)}
+}
+
+// checkSecretBundles rejects secret bundles not defined in spec.secretBundles. Whether selected
+// keys exist is checked by the operator, which reads the runner secret.
+func (p sessionPolicy) checkSecretBundles(bundles []string) error {
+	var unknown []string
+	for _, b := range bundles {
+		if !p.SecretBundles[b] {
+			unknown = append(unknown, b)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: secret bundles not defined in this project: %s", errSessionPolicy, strings.Join(unknown, ", "))
+}
+
+// checkModel rejects models outside spec.sessionPolicy.allowedModels
+func (p sessionPolicy) checkModel(model string) error {
+	if len(p.AllowedModels) == 0 || model == "" {
+		return nil
+	}
+	for _, m := range p.AllowedModels {
+		if m == model {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: model %q is not allowed in this project (allowed: %s)", errSessionPolicy, model, strings.Join(p.AllowedModels, ", "))
+}
+
+// checkTimeout rejects non-positive timeouts and those above spec.sessionPolicy.maxTimeout
+func (p sessionPolicy) checkTimeout(timeout int) error {
+	if timeout <= 0 {
+		return fmt.Errorf("%w: timeout must be a positive number of seconds", errSessionPolicy)
+	}
+	if p.MaxTimeout > 0 && timeout > p.MaxTimeout {
+		return fmt.Errorf("%w: timeout %ds exceeds the project maximum of %ds", errSessionPolicy, timeout, p.MaxTimeout)
+	}
+	return nil
+}
+
+// checkEnv rejects requested environment variables matching spec.sessionPolicy.bannedEnvironmentVariables
+func (p sessionPolicy) checkEnv(env map[string]string) error {
+	var banned []string
+	for k := range env {
+		for _, pattern := range p.BannedEnvKeys {
+			if ok, _ := path.Match(pattern, k); ok || pattern == k {
+				banned = append(banned, k)
+				break
+			}
+		}
+	}
+	if len(banned) == 0 {
+		return nil
+	}
+	sort.Strings(banned)
+	return fmt.Errorf("%w: environment variables not allowed in this project: %s", errSessionPolicy, strings.Join(banned, ", "))
+}
+
+// checkRepo enforces spec.sessionPolicy.allowedRepositories and outputBranchPrefixes on one
+// repo mapping. An unset output branch is pushed to sessions/<session>.
+func (p sessionPolicy) checkRepo(i int, inputURL, outputURL, outputBranch string) error {
+	for _, u := range []string{inputURL, outputURL} {
+		if u != "" && !p.repoAllowed(u) {
+			return fmt.Errorf("%w: repos[%d]: repository %s is not allowed in this project (allowed: %s)", errSessionPolicy, i, u, strings.Join(p.AllowedRepositories, ", "))
+		}
+	}
+	if outputURL == "" || len(p.OutputBranchPrefixes) == 0 {
+		return nil
+	}
+	branch := outputBranch
+	if branch == "" {
+		branch = "sessions/"
+	}
+	for _, prefix := range p.OutputBranchPrefixes {
+		if strings.HasPrefix(branch, prefix) {
+			return nil
+		}
+	}
+	if outputBranch == "" {
+		return fmt.Errorf("%w: repos[%d]: an output branch starting with one of %s is required", errSessionPolicy, i, strings.Join(p.OutputBranchPrefixes, ", "))
+	}
+	return fmt.Errorf("%w: repos[%d]: output branch %q must start with one of %s", errSessionPolicy, i, outputBranch, strings.Join(p.OutputBranchPrefixes, ", "))
+}
+
+func (p sessionPolicy) repoAllowed(url string) bool {
+	if len(p.AllowedRepositories) == 0 {
+		return true
+	}
+	repo := git.RepoPath(url)
+	for _, pattern := range p.AllowedRepositories {
+		if ok, _ := path.Match(git.RepoPath(pattern), repo); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSpec enforces every guardrail on an AgenticSession spec as it will be stored.
+// Environment variables set by the backend itself (e.g. AGENT_PERSONAS) are passed in skipEnv.
+func (p sessionPolicy) checkSpec(spec *types.AgenticSessionSpec, skipEnv ...string) error {
+	if err := p.checkModel(spec.LLMSettings.Model); err != nil {
+		return err
+	}
+	if err := p.checkTimeout(int(spec.Timeout)); err != nil {
+		return err
+	}
+
+	env := map[string]string{}
+	for k, v := range spec.EnvironmentVariables {
+		env[k] = v
+	}
+	for k := range p.Env {
+		delete(env, k)
+	}
+	for _, k := range skipEnv {
+		delete(env, k)
+	}
+	if err := p.checkEnv(env); err != nil {
+		return err
+	}
+	if spec.Secrets != nil {
+		if err := p.checkSecretBundles(spec.Secrets.Bundles); err != nil {
+			return err
+		}
+	}
+
+	for i, r := range spec.Repos {
+		var outURL, outBranch string
+		if r.Output != nil {
+			outURL = r.Output.URL
+			if r.Output.Branch != nil {
+				outBranch = *r.Output.Branch
+			}
+		}
+		if err := p.checkRepo(i, strings.TrimSpace(r.Input.URL), strings.TrimSpace(outURL), strings.TrimSpace(outBranch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enforceSessionPolicy loads the project's policy and checks spec against it
+func enforceSessionPolicy(ctx context.Context, project string, spec *types.AgenticSessionSpec, skipEnv ...string) error {
+	p, err := loadSessionPolicy(ctx, project)
+	if err != nil {
+		log.Printf("enforceSessionPolicy: %s: %v", project, err)
+		return err
+	}
+	return p.checkSpec(spec, skipEnv...)
+}
+
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func stringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			out = append(out, strings.TrimSpace(s))
+		}
+	}
+	return out
+}
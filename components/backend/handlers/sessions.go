@@ -9,9 +9,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	vteamclient "ambient-code-api/clientset/versioned"
+	vteamv1alpha1client "ambient-code-api/clientset/versioned/typed/vteam/v1alpha1"
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+	"ambient-code-backend/git"
 	"ambient-code-backend/types"
 
 	"github.com/gin-gonic/gin"
@@ -31,10 +36,12 @@ import (
 
 // Package-level variables for session handlers (set from main package)
 var (
-	GetAgenticSessionV1Alpha1Resource func() schema.GroupVersionResource
-	DynamicClient                     dynamic.Interface
-	GetGitHubToken                    func(context.Context, *kubernetes.Clientset, dynamic.Interface, string, string) (string, error)
-	DeriveRepoFolderFromURL           func(string) string
+	DynamicClient           dynamic.Interface
+	VteamClient             vteamclient.Interface
+	GetGitHubToken          func(context.Context, *kubernetes.Clientset, dynamic.Interface, string, string) (string, error)
+	GetScopedGitHubToken    func(context.Context, *kubernetes.Clientset, dynamic.Interface, string, string, []string, map[string]string) (string, error)
+	DeriveRepoFolderFromURL func(string) string
+	SendSessionMessage      func(sessionID, msgType string, payload map[string]interface{})
 )
 
 // contentListItem represents a file/directory in the workspace
@@ -46,209 +53,48 @@ type contentListItem struct {
 	ModifiedAt string `json:"modifiedAt"`
 }
 
-// parseSpec parses AgenticSessionSpec with v1alpha1 fields
-func parseSpec(spec map[string]interface{}) types.AgenticSessionSpec {
-	result := types.AgenticSessionSpec{}
-
-	if prompt, ok := spec["prompt"].(string); ok {
-		result.Prompt = prompt
-	}
-
-	if interactive, ok := spec["interactive"].(bool); ok {
-		result.Interactive = interactive
-	}
-
-	if displayName, ok := spec["displayName"].(string); ok {
-		result.DisplayName = displayName
-	}
-
-	if project, ok := spec["project"].(string); ok {
-		result.Project = project
-	}
-
-	if timeout, ok := spec["timeout"].(float64); ok {
-		result.Timeout = int(timeout)
-	}
-
-	if llmSettings, ok := spec["llmSettings"].(map[string]interface{}); ok {
-		if model, ok := llmSettings["model"].(string); ok {
-			result.LLMSettings.Model = model
-		}
-		if temperature, ok := llmSettings["temperature"].(float64); ok {
-			result.LLMSettings.Temperature = temperature
-		}
-		if maxTokens, ok := llmSettings["maxTokens"].(float64); ok {
-			result.LLMSettings.MaxTokens = int(maxTokens)
-		}
-	}
-
-	// environmentVariables passthrough
-	if env, ok := spec["environmentVariables"].(map[string]interface{}); ok {
-		resultEnv := make(map[string]string, len(env))
-		for k, v := range env {
-			if s, ok := v.(string); ok {
-				resultEnv[k] = s
-			}
-		}
-		if len(resultEnv) > 0 {
-			result.EnvironmentVariables = resultEnv
-		}
-	}
-
-	if userContext, ok := spec["userContext"].(map[string]interface{}); ok {
-		uc := &types.UserContext{}
-		if userID, ok := userContext["userId"].(string); ok {
-			uc.UserID = userID
-		}
-		if displayName, ok := userContext["displayName"].(string); ok {
-			uc.DisplayName = displayName
-		}
-		if groups, ok := userContext["groups"].([]interface{}); ok {
-			for _, group := range groups {
-				if groupStr, ok := group.(string); ok {
-					uc.Groups = append(uc.Groups, groupStr)
-				}
-			}
-		}
-		result.UserContext = uc
-	}
-
-	if botAccount, ok := spec["botAccount"].(map[string]interface{}); ok {
-		ba := &types.BotAccountRef{}
-		if name, ok := botAccount["name"].(string); ok {
-			ba.Name = name
-		}
-		result.BotAccount = ba
-	}
-
-	if resourceOverrides, ok := spec["resourceOverrides"].(map[string]interface{}); ok {
-		ro := &types.ResourceOverrides{}
-		if cpu, ok := resourceOverrides["cpu"].(string); ok {
-			ro.CPU = cpu
-		}
-		if memory, ok := resourceOverrides["memory"].(string); ok {
-			ro.Memory = memory
-		}
-		if storageClass, ok := resourceOverrides["storageClass"].(string); ok {
-			ro.StorageClass = storageClass
-		}
-		if priorityClass, ok := resourceOverrides["priorityClass"].(string); ok {
-			ro.PriorityClass = priorityClass
-		}
-		result.ResourceOverrides = ro
+// sessionResponse converts an AgenticSession to the API shape, dropping repos without an input URL.
+// Objects read through the typed client carry no TypeMeta, so apiVersion and kind are set here.
+func sessionResponse(item *vteamv1alpha1.AgenticSession) types.AgenticSession {
+	session := types.AgenticSession{
+		APIVersion: vteamv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "AgenticSession",
+		Metadata:   item.ObjectMeta,
+		Spec:       *item.Spec.DeepCopy(),
+		Status:     item.Status.DeepCopy(),
 	}
 
-	// Multi-repo parsing (unified repos)
-	if arr, ok := spec["repos"].([]interface{}); ok {
-		repos := make([]types.SessionRepoMapping, 0, len(arr))
-		for _, it := range arr {
-			m, ok := it.(map[string]interface{})
-			if !ok {
-				continue
-			}
-			r := types.SessionRepoMapping{}
-			if in, ok := m["input"].(map[string]interface{}); ok {
-				ng := types.NamedGitRepo{}
-				if s, ok := in["url"].(string); ok {
-					ng.URL = s
-				}
-				if s, ok := in["branch"].(string); ok && strings.TrimSpace(s) != "" {
-					ng.Branch = types.StringPtr(s)
-				}
-				r.Input = ng
-			}
-			if out, ok := m["output"].(map[string]interface{}); ok {
-				og := &types.OutputNamedGitRepo{}
-				if s, ok := out["url"].(string); ok {
-					og.URL = s
-				}
-				if s, ok := out["branch"].(string); ok && strings.TrimSpace(s) != "" {
-					og.Branch = types.StringPtr(s)
-				}
-				r.Output = og
-			}
-			// Include per-repo status if present
-			if st, ok := m["status"].(string); ok {
-				r.Status = types.StringPtr(st)
-			}
-			if strings.TrimSpace(r.Input.URL) != "" {
-				repos = append(repos, r)
-			}
+	repos := make([]types.SessionRepoMapping, 0, len(session.Spec.Repos))
+	for _, r := range session.Spec.Repos {
+		if strings.TrimSpace(r.Input.URL) != "" {
+			repos = append(repos, r)
 		}
-		result.Repos = repos
-	}
-	if idx, ok := spec["mainRepoIndex"].(float64); ok {
-		idxInt := int(idx)
-		result.MainRepoIndex = &idxInt
 	}
+	session.Spec.Repos = repos
 
-	return result
+	return session
 }
 
-// parseStatus parses AgenticSessionStatus with v1alpha1 fields
-func parseStatus(status map[string]interface{}) *types.AgenticSessionStatus {
-	result := &types.AgenticSessionStatus{}
-
-	if phase, ok := status["phase"].(string); ok {
-		result.Phase = phase
-	}
-
-	if message, ok := status["message"].(string); ok {
-		result.Message = message
-	}
-
-	if startTime, ok := status["startTime"].(string); ok {
-		result.StartTime = &startTime
-	}
-
-	if completionTime, ok := status["completionTime"].(string); ok {
-		result.CompletionTime = &completionTime
-	}
-
-	if jobName, ok := status["jobName"].(string); ok {
-		result.JobName = jobName
-	}
-
-	// New: result summary fields (top-level in status)
-	if st, ok := status["subtype"].(string); ok {
-		result.Subtype = st
-	}
-
-	if ie, ok := status["is_error"].(bool); ok {
-		result.IsError = ie
-	}
-	if nt, ok := status["num_turns"].(float64); ok {
-		result.NumTurns = int(nt)
-	}
-	if sid, ok := status["session_id"].(string); ok {
-		result.SessionID = sid
-	}
-	if tcu, ok := status["total_cost_usd"].(float64); ok {
-		result.TotalCostUSD = &tcu
-	}
-	if usage, ok := status["usage"].(map[string]interface{}); ok {
-		result.Usage = usage
-	}
-	if res, ok := status["result"].(string); ok {
-		result.Result = &res
-	}
-
-	if stateDir, ok := status["stateDir"].(string); ok {
-		result.StateDir = stateDir
+// sessionLLMSettings converts API LLM settings to the AgenticSession spec field
+func sessionLLMSettings(llm types.LLMSettings) vteamv1alpha1.LLMSettings {
+	return vteamv1alpha1.LLMSettings{
+		Model:       llm.Model,
+		Temperature: llm.Temperature,
+		MaxTokens:   int64(llm.MaxTokens),
 	}
-
-	return result
 }
 
 // V2 API Handlers - Multi-tenant session management
 
 func ListSessions(c *gin.Context) {
 	project := c.GetString("project")
-	reqK8s, reqDyn := GetK8sClientsForRequest(c)
-	_ = reqK8s
-	gvr := GetAgenticSessionV1Alpha1Resource()
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
-	list, err := reqDyn.Resource(gvr).Namespace(project).List(context.TODO(), v1.ListOptions{})
+	list, err := reqSessions.List(context.TODO(), v1.ListOptions{})
 	if err != nil {
 		log.Printf("Failed to list agentic sessions in project %s: %v", project, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list agentic sessions"})
@@ -256,22 +102,8 @@ func ListSessions(c *gin.Context) {
 	}
 
 	var sessions []types.AgenticSession
-	for _, item := range list.Items {
-		session := types.AgenticSession{
-			APIVersion: item.GetAPIVersion(),
-			Kind:       item.GetKind(),
-			Metadata:   item.Object["metadata"].(map[string]interface{}),
-		}
-
-		if spec, ok := item.Object["spec"].(map[string]interface{}); ok {
-			session.Spec = parseSpec(spec)
-		}
-
-		if status, ok := item.Object["status"].(map[string]interface{}); ok {
-			session.Status = parseStatus(status)
-		}
-
-		sessions = append(sessions, session)
+	for i := range list.Items {
+		sessions = append(sessions, sessionResponse(&list.Items[i]))
 	}
 
 	c.JSON(http.StatusOK, gin.H{"items": sessions})
@@ -280,7 +112,7 @@ func ListSessions(c *gin.Context) {
 func CreateSession(c *gin.Context) {
 	project := c.GetString("project")
 	// Use backend service account clients for CR writes
-	if DynamicClient == nil {
+	if VteamClient == nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "backend not initialized"})
 		return
 	}
@@ -290,91 +122,92 @@ func CreateSession(c *gin.Context) {
 		return
 	}
 
-	// Validation for multi-repo can be added here if needed
-
-	// Set defaults for LLM settings if not provided
-	llmSettings := types.LLMSettings{
-		Model:       "sonnet",
-		Temperature: 0.7,
-		MaxTokens:   4000,
-	}
-	if req.LLMSettings != nil {
-		if req.LLMSettings.Model != "" {
-			llmSettings.Model = req.LLMSettings.Model
-		}
-		if req.LLMSettings.Temperature != 0 {
-			llmSettings.Temperature = req.LLMSettings.Temperature
-		}
-		if req.LLMSettings.MaxTokens != 0 {
-			llmSettings.MaxTokens = req.LLMSettings.MaxTokens
+	created, err := createAgenticSession(c, project, req)
+	if err != nil {
+		// errors.IsInvalid: rejected by the AgenticSession admission webhook
+		if isInvalidAgentPersonas(err) || isSessionPolicyViolation(err) || errors.IsInvalid(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create agentic session"})
+		return
 	}
 
-	timeout := 300
-	if req.Timeout != nil {
-		timeout = *req.Timeout
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Agentic session created successfully",
+		"name":    created.Name,
+		"uid":     created.UID,
+	})
+}
+
+// createAgenticSession builds the AgenticSession CR for req, creates it with the backend
+// service account and provisions the runner token. Used by CreateSession and RFE phase advances.
+func createAgenticSession(c *gin.Context, project string, req types.CreateAgenticSessionRequest) (*vteamv1alpha1.AgenticSession, error) {
+	// Project defaults and guardrails from ProjectSettings
+	policy, err := loadSessionPolicy(c.Request.Context(), project)
+	if err != nil {
+		log.Printf("CreateSession: session policy for %s: %v", project, err)
+		return nil, err
 	}
+	llmSettings := policy.resolveLLMSettings(req.LLMSettings)
+	timeout := policy.resolveTimeout(req.Timeout)
 
 	// Generate unique name
 	timestamp := time.Now().Unix()
 	name := fmt.Sprintf("agentic-session-%d", timestamp)
 
 	// Create the custom resource
-	// Metadata
-	metadata := map[string]interface{}{
-		"name":      name,
-		"namespace": project,
+	session := &vteamv1alpha1.AgenticSession{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: project,
+		},
+		Spec: vteamv1alpha1.AgenticSessionSpec{
+			Prompt:      req.Prompt,
+			DisplayName: req.DisplayName,
+			Project:     project,
+			LLMSettings: sessionLLMSettings(llmSettings),
+			Timeout:     int64(timeout),
+		},
+		Status: vteamv1alpha1.AgenticSessionStatus{
+			Phase: vteamv1alpha1.AgenticSessionPhasePending,
+		},
 	}
 	if len(req.Labels) > 0 {
-		labels := map[string]interface{}{}
+		session.Labels = map[string]string{}
 		for k, v := range req.Labels {
-			labels[k] = v
+			session.Labels[k] = v
 		}
-		metadata["labels"] = labels
 	}
 	if len(req.Annotations) > 0 {
-		annotations := map[string]interface{}{}
+		session.Annotations = map[string]string{}
 		for k, v := range req.Annotations {
-			annotations[k] = v
-		}
-		metadata["annotations"] = annotations
-	}
-
-	session := map[string]interface{}{
-		"apiVersion": "vteam.ambient-code/v1alpha1",
-		"kind":       "AgenticSession",
-		"metadata":   metadata,
-		"spec": map[string]interface{}{
-			"prompt":      req.Prompt,
-			"displayName": req.DisplayName,
-			"project":     project,
-			"llmSettings": map[string]interface{}{
-				"model":       llmSettings.Model,
-				"temperature": llmSettings.Temperature,
-				"maxTokens":   llmSettings.MaxTokens,
-			},
-			"timeout": timeout,
-		},
-		"status": map[string]interface{}{
-			"phase": "Pending",
-		},
+			session.Annotations[k] = v
+		}
 	}
 
 	// Optional environment variables passthrough (always, independent of git config presence)
-	envVars := make(map[string]string)
-	for k, v := range req.EnvironmentVariables {
-		envVars[k] = v
+	envVars := policy.resolveEnv(req.EnvironmentVariables)
+
+	// Agent personas must be seeded on the linked RFE's feature branch; the runner activates them
+	personas, err := resolveSessionAgentPersonas(c, project, req.Labels["rfe-workflow"], req.AgentPersonas)
+	if err != nil {
+		log.Printf("CreateSession: agent personas for %s: %v", project, err)
+		return nil, err
+	}
+	if len(personas) > 0 {
+		session.Spec.AgentPersonas = personas
+		envVars["AGENT_PERSONAS"] = strings.Join(personas, ",")
 	}
 
 	// Handle session continuation
 	if req.ParentSessionID != "" {
 		envVars["PARENT_SESSION_ID"] = req.ParentSessionID
 		// Add annotation to track continuation lineage
-		if metadata["annotations"] == nil {
-			metadata["annotations"] = make(map[string]interface{})
+		if session.Annotations == nil {
+			session.Annotations = map[string]string{}
 		}
-		annotations := metadata["annotations"].(map[string]interface{})
-		annotations["vteam.ambient-code/parent-session-id"] = req.ParentSessionID
+		session.Annotations["vteam.ambient-code/parent-session-id"] = req.ParentSessionID
 		log.Printf("Creating continuation session from parent %s", req.ParentSessionID)
 
 		// Clean up temp-content pod from parent session to free the PVC
@@ -393,49 +226,26 @@ func CreateSession(c *gin.Context) {
 	}
 
 	if len(envVars) > 0 {
-		spec := session["spec"].(map[string]interface{})
-		spec["environmentVariables"] = envVars
+		session.Spec.EnvironmentVariables = envVars
 	}
 
 	// Interactive flag
 	if req.Interactive != nil {
-		session["spec"].(map[string]interface{})["interactive"] = *req.Interactive
+		session.Spec.Interactive = *req.Interactive
 	}
 
 	// AutoPushOnComplete flag
 	if req.AutoPushOnComplete != nil {
-		session["spec"].(map[string]interface{})["autoPushOnComplete"] = *req.AutoPushOnComplete
+		session.Spec.AutoPushOnComplete = *req.AutoPushOnComplete
 	}
 
-	// Set multi-repo configuration on spec
-	{
-		spec := session["spec"].(map[string]interface{})
-		// Multi-repo pass-through (unified repos)
-		if len(req.Repos) > 0 {
-			arr := make([]map[string]interface{}, 0, len(req.Repos))
-			for _, r := range req.Repos {
-				m := map[string]interface{}{}
-				in := map[string]interface{}{"url": r.Input.URL}
-				if r.Input.Branch != nil {
-					in["branch"] = *r.Input.Branch
-				}
-				m["input"] = in
-				if r.Output != nil {
-					out := map[string]interface{}{"url": r.Output.URL}
-					if r.Output.Branch != nil {
-						out["branch"] = *r.Output.Branch
-					}
-					m["output"] = out
-				}
-				// Remove default repo status; status will be set explicitly when pushed/abandoned
-				// m["status"] intentionally unset at creation time
-				arr = append(arr, m)
-			}
-			spec["repos"] = arr
-		}
-		if req.MainRepoIndex != nil {
-			spec["mainRepoIndex"] = *req.MainRepoIndex
-		}
+	// Multi-repo pass-through (unified repos); repo status is set explicitly when pushed/abandoned
+	for _, r := range req.Repos {
+		session.Spec.Repos = append(session.Spec.Repos, *r.DeepCopy())
+	}
+	if req.MainRepoIndex != nil {
+		idx := int32(*req.MainRepoIndex)
+		session.Spec.MainRepoIndex = &idx
 	}
 
 	// Handle RFE workflow branch management
@@ -459,20 +269,12 @@ func CreateSession(c *gin.Context) {
 					if err == nil {
 						rfeWf := RfeFromUnstructured(rfeObj)
 						if rfeWf != nil && rfeWf.BranchName != "" {
-							// Access spec from session object
-							spec := session["spec"].(map[string]interface{})
-
 							// Override branch for all repos to use feature branch
-							if repos, ok := spec["repos"].([]map[string]interface{}); ok {
-								for i := range repos {
-									// Always override input branch with feature branch
-									if input, ok := repos[i]["input"].(map[string]interface{}); ok {
-										input["branch"] = rfeWf.BranchName
-									}
-									// Always override output branch with feature branch
-									if output, ok := repos[i]["output"].(map[string]interface{}); ok {
-										output["branch"] = rfeWf.BranchName
-									}
+							for i := range session.Spec.Repos {
+								branch := rfeWf.BranchName
+								session.Spec.Repos[i].Input.Branch = &branch
+								if session.Spec.Repos[i].Output != nil {
+									session.Spec.Repos[i].Output.Branch = &branch
 								}
 							}
 
@@ -486,6 +288,14 @@ func CreateSession(c *gin.Context) {
 		}
 	}
 
+	// Runner secret keys the session may see; the operator projects only these into the Job
+	session.Spec.Secrets = secretsSpec(policy.resolveSecrets(req.Secrets))
+
+	// Enforce guardrails on the final spec, after RFE branch overrides
+	if err := policy.checkSpec(&session.Spec, "AGENT_PERSONAS", "PARENT_SESSION_ID"); err != nil {
+		return nil, err
+	}
+
 	// Add userContext derived from authenticated caller; ignore client-supplied userId
 	{
 		uidVal, _ := c.Get("userID")
@@ -511,49 +321,43 @@ func CreateSession(c *gin.Context) {
 			if len(groups) == 0 && req.UserContext != nil {
 				groups = req.UserContext.Groups
 			}
-			session["spec"].(map[string]interface{})["userContext"] = map[string]interface{}{
-				"userId":      uid,
-				"displayName": displayName,
-				"groups":      groups,
+			uc := &vteamv1alpha1.UserContext{
+				UserID:      uid,
+				DisplayName: displayName,
+				Groups:      groups,
+			}
+			// Email comes from the forwarded identity only; used for commit attribution
+			if v, ok := c.Get("userEmail"); ok {
+				if email, ok2 := v.(string); ok2 {
+					uc.Email = strings.TrimSpace(email)
+				}
 			}
+			session.Spec.UserContext = uc
 		}
 	}
 
 	// Add botAccount if provided
 	if req.BotAccount != nil {
-		session["spec"].(map[string]interface{})["botAccount"] = map[string]interface{}{
-			"name": req.BotAccount.Name,
-		}
+		session.Spec.BotAccount = &vteamv1alpha1.BotAccountRef{Name: req.BotAccount.Name}
 	}
 
 	// Add resourceOverrides if provided
-	if req.ResourceOverrides != nil {
-		resourceOverrides := make(map[string]interface{})
-		if req.ResourceOverrides.CPU != "" {
-			resourceOverrides["cpu"] = req.ResourceOverrides.CPU
-		}
-		if req.ResourceOverrides.Memory != "" {
-			resourceOverrides["memory"] = req.ResourceOverrides.Memory
-		}
-		if req.ResourceOverrides.StorageClass != "" {
-			resourceOverrides["storageClass"] = req.ResourceOverrides.StorageClass
-		}
-		if req.ResourceOverrides.PriorityClass != "" {
-			resourceOverrides["priorityClass"] = req.ResourceOverrides.PriorityClass
-		}
-		if len(resourceOverrides) > 0 {
-			session["spec"].(map[string]interface{})["resourceOverrides"] = resourceOverrides
+	if ro := req.ResourceOverrides; ro != nil && (ro.CPU != "" || ro.Memory != "" || ro.StorageClass != "" || ro.PriorityClass != "") {
+		session.Spec.ResourceOverrides = &vteamv1alpha1.ResourceOverrides{
+			CPU:           ro.CPU,
+			Memory:        ro.Memory,
+			StorageClass:  ro.StorageClass,
+			PriorityClass: ro.PriorityClass,
 		}
 	}
 
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	obj := &unstructured.Unstructured{Object: session}
+	markGitHubReportable(session)
 
-	created, err := DynamicClient.Resource(gvr).Namespace(project).Create(context.TODO(), obj, v1.CreateOptions{})
+	sessions := VteamClient.VteamV1alpha1().AgenticSessions(project)
+	created, err := sessions.Create(context.TODO(), session, v1.CreateOptions{})
 	if err != nil {
 		log.Printf("Failed to create agentic session in project %s: %v", project, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create agentic session"})
-		return
+		return nil, err
 	}
 
 	// Best-effort prefill of agent markdown into PVC workspace for immediate UI availability
@@ -581,32 +385,28 @@ func CreateSession(c *gin.Context) {
 	}()
 
 	// Preferred method: provision a per-session ServiceAccount token for the runner (backend SA)
-	if err := provisionRunnerTokenForSession(c, K8sClient, DynamicClient, project, name); err != nil {
+	if err := provisionRunnerTokenForSession(c, K8sClient, sessions, name); err != nil {
 		// Non-fatal: log and continue. Operator may retry later if implemented.
 		log.Printf("Warning: failed to provision runner token for session %s/%s: %v", project, name, err)
 	}
 
-	c.JSON(http.StatusCreated, gin.H{
-		"message": "Agentic session created successfully",
-		"name":    name,
-		"uid":     created.GetUID(),
-	})
+	return created, nil
 }
 
 // provisionRunnerTokenForSession creates a per-session ServiceAccount, grants minimal RBAC,
 // mints a short-lived token, stores it in a Secret, and annotates the AgenticSession with the Secret name.
-func provisionRunnerTokenForSession(c *gin.Context, reqK8s *kubernetes.Clientset, reqDyn dynamic.Interface, project string, sessionName string) error {
+func provisionRunnerTokenForSession(c *gin.Context, reqK8s *kubernetes.Clientset, sessions vteamv1alpha1client.AgenticSessionInterface, sessionName string) error {
 	// Load owning AgenticSession to parent all resources
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	obj, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), sessionName, v1.GetOptions{})
+	obj, err := sessions.Get(c.Request.Context(), sessionName, v1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("get AgenticSession: %w", err)
 	}
+	project := obj.Namespace
 	ownerRef := v1.OwnerReference{
-		APIVersion: obj.GetAPIVersion(),
-		Kind:       obj.GetKind(),
-		Name:       obj.GetName(),
-		UID:        obj.GetUID(),
+		APIVersion: vteamv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "AgenticSession",
+		Name:       obj.Name,
+		UID:        obj.UID,
 		Controller: types.BoolPtr(true),
 	}
 
@@ -736,7 +536,7 @@ func provisionRunnerTokenForSession(c *gin.Context, reqK8s *kubernetes.Clientset
 		},
 	}
 	b, _ := json.Marshal(patch)
-	if _, err := reqDyn.Resource(gvr).Namespace(project).Patch(c.Request.Context(), obj.GetName(), ktypes.MergePatchType, b, v1.PatchOptions{}); err != nil {
+	if _, err := sessions.Patch(c.Request.Context(), obj.Name, ktypes.MergePatchType, b, v1.PatchOptions{}); err != nil {
 		return fmt.Errorf("annotate AgenticSession: %w", err)
 	}
 
@@ -746,11 +546,13 @@ func provisionRunnerTokenForSession(c *gin.Context, reqK8s *kubernetes.Clientset
 func GetSession(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	reqK8s, reqDyn := GetK8sClientsForRequest(c)
-	_ = reqK8s
-	gvr := GetAgenticSessionV1Alpha1Resource()
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
-	item, err := reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+	item, err := reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -761,21 +563,7 @@ func GetSession(c *gin.Context) {
 		return
 	}
 
-	session := types.AgenticSession{
-		APIVersion: item.GetAPIVersion(),
-		Kind:       item.GetKind(),
-		Metadata:   item.Object["metadata"].(map[string]interface{}),
-	}
-
-	if spec, ok := item.Object["spec"].(map[string]interface{}); ok {
-		session.Spec = parseSpec(spec)
-	}
-
-	if status, ok := item.Object["status"].(map[string]interface{}); ok {
-		session.Status = parseStatus(status)
-	}
-
-	c.JSON(http.StatusOK, session)
+	c.JSON(http.StatusOK, sessionResponse(item))
 }
 
 // POST /api/projects/:projectName/agentic-sessions/:sessionName/github/token
@@ -831,8 +619,7 @@ func MintSessionGitHubToken(c *gin.Context) {
 	}
 
 	// Load session and verify SA matches annotation
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	obj, err := DynamicClient.Resource(gvr).Namespace(project).Get(c.Request.Context(), sessionName, v1.GetOptions{})
+	obj, err := VteamClient.VteamV1alpha1().AgenticSessions(project).Get(c.Request.Context(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
@@ -841,36 +628,31 @@ func MintSessionGitHubToken(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read session"})
 		return
 	}
-	meta, _ := obj.Object["metadata"].(map[string]interface{})
-	anns, _ := meta["annotations"].(map[string]interface{})
-	expectedSA := ""
-	if anns != nil {
-		if v, ok := anns["ambient-code.io/runner-sa"].(string); ok {
-			expectedSA = strings.TrimSpace(v)
-		}
-	}
+	expectedSA := strings.TrimSpace(obj.Annotations["ambient-code.io/runner-sa"])
 	if expectedSA == "" || expectedSA != saFromToken {
 		c.JSON(http.StatusForbidden, gin.H{"error": "service account not authorized for session"})
 		return
 	}
 
 	// Read authoritative userId from spec.userContext.userId
-	spec, _ := obj.Object["spec"].(map[string]interface{})
 	userId := ""
-	if spec != nil {
-		if uc, ok := spec["userContext"].(map[string]interface{}); ok {
-			if v, ok := uc["userId"].(string); ok {
-				userId = strings.TrimSpace(v)
-			}
-		}
+	if obj.Spec.UserContext != nil {
+		userId = strings.TrimSpace(obj.Spec.UserContext.UserID)
 	}
 	if userId == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "session missing user context"})
 		return
 	}
 
-	// Get GitHub token (GitHub App or PAT fallback via project runner secret)
-	tokenStr, err := GetGitHubToken(c.Request.Context(), K8sClient, DynamicClient, project, userId)
+	// Sessions started from a GitHub webhook act through the installation that sent the event
+	if tokenStr, ok := webhookInstallationToken(c.Request.Context(), obj); ok {
+		c.JSON(http.StatusOK, gin.H{"token": tokenStr})
+		return
+	}
+
+	// Get GitHub token (GitHub App or PAT fallback via project runner secret), limited to the session's repos
+	repos, perms := sessionTokenScope(&obj.Spec)
+	tokenStr, err := GetScopedGitHubToken(c.Request.Context(), K8sClient, DynamicClient, project, userId, repos, perms)
 	if err != nil {
 		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
 		return
@@ -880,10 +662,46 @@ func MintSessionGitHubToken(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"token": tokenStr})
 }
 
+// sessionTokenScope derives least-privilege GitHub App token scope from spec.repos:
+// every input and output repository as owner/name, with metadata read always and contents and
+// pull request write only when an output is configured (the runner opens PRs when CREATE_PR is
+// set). Minting keeps only the repositories of the installation's account.
+func sessionTokenScope(spec *types.AgenticSessionSpec) ([]string, map[string]string) {
+	repos := []string{}
+	write := false
+	for _, r := range spec.Repos {
+		if r.Input.URL != "" {
+			if _, name, ok := parseGitHubRepoURL(r.Input.URL); ok {
+				repos = append(repos, name)
+			}
+		}
+		if r.Output != nil && r.Output.URL != "" {
+			if _, name, ok := parseGitHubRepoURL(r.Output.URL); ok {
+				repos = append(repos, name)
+				write = true
+			}
+		}
+	}
+	if len(repos) == 0 {
+		// No GitHub repositories: an empty repository list would grant every repo in the installation
+		return nil, map[string]string{"metadata": "read"}
+	}
+	perms := map[string]string{"metadata": "read", "contents": "read"}
+	if write {
+		perms["contents"] = "write"
+		perms["pull_requests"] = "write"
+	}
+	return repos, perms
+}
+
 func PatchSession(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	_, reqDyn := GetK8sClientsForRequest(c)
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
 	var patch map[string]interface{}
 	if err := c.ShouldBindJSON(&patch); err != nil {
@@ -891,10 +709,8 @@ func PatchSession(c *gin.Context) {
 		return
 	}
 
-	gvr := GetAgenticSessionV1Alpha1Resource()
-
 	// Get current resource
-	item, err := reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+	item, err := reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -907,33 +723,39 @@ func PatchSession(c *gin.Context) {
 	// Apply patch to metadata annotations
 	if metaPatch, ok := patch["metadata"].(map[string]interface{}); ok {
 		if annsPatch, ok := metaPatch["annotations"].(map[string]interface{}); ok {
-			metadata := item.Object["metadata"].(map[string]interface{})
-			if metadata["annotations"] == nil {
-				metadata["annotations"] = make(map[string]interface{})
+			if item.Annotations == nil {
+				item.Annotations = map[string]string{}
 			}
-			anns := metadata["annotations"].(map[string]interface{})
 			for k, v := range annsPatch {
-				anns[k] = v
+				s, ok := v.(string)
+				if !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("annotation %s must be a string", k)})
+					return
+				}
+				item.Annotations[k] = s
 			}
 		}
 	}
 
 	// Update the resource
-	updated, err := reqDyn.Resource(gvr).Namespace(project).Update(context.TODO(), item, v1.UpdateOptions{})
+	updated, err := reqSessions.Update(context.TODO(), item, v1.UpdateOptions{})
 	if err != nil {
 		log.Printf("Failed to patch agentic session %s: %v", sessionName, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to patch session"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Session patched successfully", "annotations": updated.GetAnnotations()})
+	c.JSON(http.StatusOK, gin.H{"message": "Session patched successfully", "annotations": updated.Annotations})
 }
 
 func UpdateSession(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	reqK8s, reqDyn := GetK8sClientsForRequest(c)
-	_ = reqK8s
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
 	var req types.CreateAgenticSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -941,13 +763,11 @@ func UpdateSession(c *gin.Context) {
 		return
 	}
 
-	gvr := GetAgenticSessionV1Alpha1Resource()
-
 	// Get current resource with brief retry to avoid race on creation
-	var item *unstructured.Unstructured
+	var item *vteamv1alpha1.AgenticSession
 	var err error
 	for attempt := 0; attempt < 5; attempt++ {
-		item, err = reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+		item, err = reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 		if err == nil {
 			break
 		}
@@ -965,52 +785,43 @@ func UpdateSession(c *gin.Context) {
 	}
 
 	// Update spec
-	spec := item.Object["spec"].(map[string]interface{})
-	spec["prompt"] = req.Prompt
-	spec["displayName"] = req.DisplayName
+	item.Spec.Prompt = req.Prompt
+	item.Spec.DisplayName = req.DisplayName
+
+	// Project defaults fill unset LLM fields; guardrails apply to what the request changes
+	policy, err := loadSessionPolicy(c.Request.Context(), project)
+	if err != nil {
+		log.Printf("UpdateSession: session policy for %s: %v", project, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read project session policy"})
+		return
+	}
 
 	if req.LLMSettings != nil {
-		llmSettings := make(map[string]interface{})
-		if req.LLMSettings.Model != "" {
-			llmSettings["model"] = req.LLMSettings.Model
-		}
-		if req.LLMSettings.Temperature != 0 {
-			llmSettings["temperature"] = req.LLMSettings.Temperature
-		}
-		if req.LLMSettings.MaxTokens != 0 {
-			llmSettings["maxTokens"] = req.LLMSettings.MaxTokens
+		llm := policy.resolveLLMSettings(req.LLMSettings)
+		if err := policy.checkModel(llm.Model); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
 		}
-		spec["llmSettings"] = llmSettings
+		item.Spec.LLMSettings = sessionLLMSettings(llm)
 	}
 
 	if req.Timeout != nil {
-		spec["timeout"] = *req.Timeout
+		if err := policy.checkTimeout(*req.Timeout); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		item.Spec.Timeout = int64(*req.Timeout)
 	}
 
 	// Update the resource
-	updated, err := reqDyn.Resource(gvr).Namespace(project).Update(context.TODO(), item, v1.UpdateOptions{})
+	updated, err := reqSessions.Update(context.TODO(), item, v1.UpdateOptions{})
 	if err != nil {
 		log.Printf("Failed to update agentic session %s in project %s: %v", sessionName, project, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update agentic session"})
 		return
 	}
 
-	// Parse and return updated session
-	session := types.AgenticSession{
-		APIVersion: updated.GetAPIVersion(),
-		Kind:       updated.GetKind(),
-		Metadata:   updated.Object["metadata"].(map[string]interface{}),
-	}
-
-	if spec, ok := updated.Object["spec"].(map[string]interface{}); ok {
-		session.Spec = parseSpec(spec)
-	}
-
-	if status, ok := updated.Object["status"].(map[string]interface{}); ok {
-		session.Status = parseStatus(status)
-	}
-
-	c.JSON(http.StatusOK, session)
+	c.JSON(http.StatusOK, sessionResponse(updated))
 }
 
 // PUT /api/projects/:projectName/agentic-sessions/:sessionName/displayname
@@ -1018,7 +829,11 @@ func UpdateSession(c *gin.Context) {
 func UpdateSessionDisplayName(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	_, reqDyn := GetK8sClientsForRequest(c)
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
 	var req struct {
 		DisplayName string `json:"displayName" binding:"required"`
@@ -1028,10 +843,8 @@ func UpdateSessionDisplayName(c *gin.Context) {
 		return
 	}
 
-	gvr := GetAgenticSessionV1Alpha1Resource()
-
 	// Retrieve current resource
-	item, err := reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+	item, err := reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -1043,15 +856,10 @@ func UpdateSessionDisplayName(c *gin.Context) {
 	}
 
 	// Update only displayName in spec
-	spec, ok := item.Object["spec"].(map[string]interface{})
-	if !ok {
-		spec = make(map[string]interface{})
-		item.Object["spec"] = spec
-	}
-	spec["displayName"] = req.DisplayName
+	item.Spec.DisplayName = req.DisplayName
 
 	// Persist the change
-	updated, err := reqDyn.Resource(gvr).Namespace(project).Update(context.TODO(), item, v1.UpdateOptions{})
+	updated, err := reqSessions.Update(context.TODO(), item, v1.UpdateOptions{})
 	if err != nil {
 		log.Printf("Failed to update display name for agentic session %s in project %s: %v", sessionName, project, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update display name"})
@@ -1059,29 +867,20 @@ func UpdateSessionDisplayName(c *gin.Context) {
 	}
 
 	// Respond with updated session summary
-	session := types.AgenticSession{
-		APIVersion: updated.GetAPIVersion(),
-		Kind:       updated.GetKind(),
-		Metadata:   updated.Object["metadata"].(map[string]interface{}),
-	}
-	if s, ok := updated.Object["spec"].(map[string]interface{}); ok {
-		session.Spec = parseSpec(s)
-	}
-	if st, ok := updated.Object["status"].(map[string]interface{}); ok {
-		session.Status = parseStatus(st)
-	}
-
-	c.JSON(http.StatusOK, session)
+	c.JSON(http.StatusOK, sessionResponse(updated))
 }
 
 func DeleteSession(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	reqK8s, reqDyn := GetK8sClientsForRequest(c)
-	_ = reqK8s
-	gvr := GetAgenticSessionV1Alpha1Resource()
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	auditAction(c, "session.delete", "session", sessionName, nil)
 
-	err := reqDyn.Resource(gvr).Namespace(project).Delete(context.TODO(), sessionName, v1.DeleteOptions{})
+	err := reqSessions.Delete(context.TODO(), sessionName, v1.DeleteOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -1099,6 +898,11 @@ func CloneSession(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
 	_, reqDyn := GetK8sClientsForRequest(c)
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqDyn == nil || reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
 	var req types.CloneSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -1106,10 +910,8 @@ func CloneSession(c *gin.Context) {
 		return
 	}
 
-	gvr := GetAgenticSessionV1Alpha1Resource()
-
 	// Get source session
-	sourceItem, err := reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+	sourceItem, err := reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Source session not found"})
@@ -1150,10 +952,11 @@ func CloneSession(c *gin.Context) {
 	if newName == "" {
 		newName = sessionName
 	}
+	targetSessions := GetSessionClientForRequest(c, req.TargetProject)
 	finalName := newName
 	conflicted := false
 	for i := 0; i < 50; i++ {
-		_, getErr := reqDyn.Resource(gvr).Namespace(req.TargetProject).Get(context.TODO(), finalName, v1.GetOptions{})
+		_, getErr := targetSessions.Get(context.TODO(), finalName, v1.GetOptions{})
 		if errors.IsNotFound(getErr) {
 			break
 		}
@@ -1170,55 +973,51 @@ func CloneSession(c *gin.Context) {
 	}
 
 	// Create cloned session
-	clonedSession := map[string]interface{}{
-		"apiVersion": "vteam.ambient-code/v1alpha1",
-		"kind":       "AgenticSession",
-		"metadata": map[string]interface{}{
-			"name":      finalName,
-			"namespace": req.TargetProject,
+	cloned := &vteamv1alpha1.AgenticSession{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      finalName,
+			Namespace: req.TargetProject,
 		},
-		"spec": sourceItem.Object["spec"],
-		"status": map[string]interface{}{
-			"phase": "Pending",
+		Spec: *sourceItem.Spec.DeepCopy(),
+		Status: vteamv1alpha1.AgenticSessionStatus{
+			Phase: vteamv1alpha1.AgenticSessionPhasePending,
 		},
 	}
 
 	// Update project in spec
-	clonedSpec := clonedSession["spec"].(map[string]interface{})
-	clonedSpec["project"] = req.TargetProject
+	cloned.Spec.Project = req.TargetProject
 	if conflicted {
-		if dn, ok := clonedSpec["displayName"].(string); ok && strings.TrimSpace(dn) != "" {
-			clonedSpec["displayName"] = fmt.Sprintf("%s (Duplicate)", dn)
+		if dn := cloned.Spec.DisplayName; strings.TrimSpace(dn) != "" {
+			cloned.Spec.DisplayName = fmt.Sprintf("%s (Duplicate)", dn)
 		} else {
-			clonedSpec["displayName"] = fmt.Sprintf("%s (Duplicate)", finalName)
+			cloned.Spec.DisplayName = fmt.Sprintf("%s (Duplicate)", finalName)
 		}
 	}
 
-	obj := &unstructured.Unstructured{Object: clonedSession}
+	// The clone must satisfy the target project's guardrails, not the source's
+	if err := enforceSessionPolicy(c.Request.Context(), req.TargetProject, &cloned.Spec, "AGENT_PERSONAS", "PARENT_SESSION_ID"); err != nil {
+		if isSessionPolicyViolation(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read target project session policy"})
+		return
+	}
+
+	markGitHubReportable(cloned)
 
-	created, err := reqDyn.Resource(gvr).Namespace(req.TargetProject).Create(context.TODO(), obj, v1.CreateOptions{})
+	created, err := targetSessions.Create(context.TODO(), cloned, v1.CreateOptions{})
 	if err != nil {
 		log.Printf("Failed to create cloned agentic session in project %s: %v", req.TargetProject, err)
+		if errors.IsInvalid(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create cloned agentic session"})
 		return
 	}
 
-	// Parse and return created session
-	session := types.AgenticSession{
-		APIVersion: created.GetAPIVersion(),
-		Kind:       created.GetKind(),
-		Metadata:   created.Object["metadata"].(map[string]interface{}),
-	}
-
-	if spec, ok := created.Object["spec"].(map[string]interface{}); ok {
-		session.Spec = parseSpec(spec)
-	}
-
-	if status, ok := created.Object["status"].(map[string]interface{}); ok {
-		session.Status = parseStatus(status)
-	}
-
-	c.JSON(http.StatusCreated, session)
+	c.JSON(http.StatusCreated, sessionResponse(created))
 }
 
 // ensureRunnerRolePermissions updates the runner role to ensure it has all required permissions
@@ -1276,11 +1075,15 @@ func ensureRunnerRolePermissions(c *gin.Context, reqK8s *kubernetes.Clientset, p
 func StartSession(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	reqK8s, reqDyn := GetK8sClientsForRequest(c)
-	gvr := GetAgenticSessionV1Alpha1Resource()
+	reqK8s, _ := GetK8sClientsForRequest(c)
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqK8s == nil || reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
 	// Get current resource
-	item, err := reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+	item, err := reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -1313,19 +1116,12 @@ func StartSession(c *gin.Context) {
 	// Check if this is a continuation (session is in a terminal phase)
 	// Terminal phases from CRD: Completed, Failed, Stopped, Error
 	isActualContinuation := false
-	currentPhase := ""
-	if currentStatus, ok := item.Object["status"].(map[string]interface{}); ok {
-		if phase, ok := currentStatus["phase"].(string); ok {
-			currentPhase = phase
-			terminalPhases := []string{"Completed", "Failed", "Stopped", "Error"}
-			for _, terminalPhase := range terminalPhases {
-				if phase == terminalPhase {
-					isActualContinuation = true
-					log.Printf("StartSession: Detected continuation - session is in terminal phase: %s", phase)
-					break
-				}
-			}
-		}
+	currentPhase := item.Status.Phase
+	switch currentPhase {
+	case vteamv1alpha1.AgenticSessionPhaseCompleted, vteamv1alpha1.AgenticSessionPhaseFailed,
+		vteamv1alpha1.AgenticSessionPhaseStopped, vteamv1alpha1.AgenticSessionPhaseError:
+		isActualContinuation = true
+		log.Printf("StartSession: Detected continuation - session is in terminal phase: %s", currentPhase)
 	}
 
 	if !isActualContinuation {
@@ -1335,25 +1131,21 @@ func StartSession(c *gin.Context) {
 	// Only set parent session annotation if this is an actual continuation
 	// Don't set it on first start, even though StartSession can be called for initial creation
 	if isActualContinuation {
-		annotations := item.GetAnnotations()
-		if annotations == nil {
-			annotations = make(map[string]string)
+		if item.Annotations == nil {
+			item.Annotations = make(map[string]string)
 		}
-		annotations["vteam.ambient-code/parent-session-id"] = sessionName
-		item.SetAnnotations(annotations)
+		item.Annotations["vteam.ambient-code/parent-session-id"] = sessionName
 		log.Printf("StartSession: Set parent-session-id annotation to %s for continuation (has completion time)", sessionName)
 
 		// For headless sessions being continued, force interactive mode
-		if spec, ok := item.Object["spec"].(map[string]interface{}); ok {
-			if interactive, ok := spec["interactive"].(bool); !ok || !interactive {
-				// Session was headless, convert to interactive
-				spec["interactive"] = true
-				log.Printf("StartSession: Converting headless session to interactive for continuation")
-			}
+		if !item.Spec.Interactive {
+			// Session was headless, convert to interactive
+			item.Spec.Interactive = true
+			log.Printf("StartSession: Converting headless session to interactive for continuation")
 		}
 
 		// Update the metadata and spec to persist the annotation and interactive flag
-		item, err = reqDyn.Resource(gvr).Namespace(project).Update(context.TODO(), item, v1.UpdateOptions{})
+		item, err = reqSessions.Update(context.TODO(), item, v1.UpdateOptions{})
 		if err != nil {
 			log.Printf("Failed to update agentic session metadata %s in project %s: %v", sessionName, project, err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session metadata"})
@@ -1362,7 +1154,7 @@ func StartSession(c *gin.Context) {
 
 		// Regenerate runner token for continuation (old token may have expired)
 		log.Printf("StartSession: Regenerating runner token for session continuation")
-		if err := provisionRunnerTokenForSession(c, reqK8s, reqDyn, project, sessionName); err != nil {
+		if err := provisionRunnerTokenForSession(c, reqK8s, reqSessions, sessionName); err != nil {
 			log.Printf("Warning: failed to regenerate runner token for session %s/%s: %v", project, sessionName, err)
 			// Non-fatal: continue anyway, operator may retry
 		} else {
@@ -1389,53 +1181,38 @@ func StartSession(c *gin.Context) {
 	}
 
 	// Now update status to trigger start (using the fresh object from Update)
-	if item.Object["status"] == nil {
-		item.Object["status"] = make(map[string]interface{})
-	}
-
-	status := item.Object["status"].(map[string]interface{})
 	// Set to Pending so operator will process it (operator only acts on Pending phase)
-	status["phase"] = "Pending"
-	status["message"] = "Session restart requested"
+	item.Status.Phase = vteamv1alpha1.AgenticSessionPhasePending
+	item.Status.Message = "Session restart requested"
 	// Clear completion time from previous run
-	delete(status, "completionTime")
+	item.Status.CompletionTime = nil
 	// Update start time for this run
-	status["startTime"] = time.Now().Format(time.RFC3339)
+	now := v1.Now()
+	item.Status.StartTime = &now
 
 	// Update the status subresource (must use UpdateStatus, not Update)
-	updated, err := reqDyn.Resource(gvr).Namespace(project).UpdateStatus(context.TODO(), item, v1.UpdateOptions{})
+	updated, err := reqSessions.UpdateStatus(context.TODO(), item, v1.UpdateOptions{})
 	if err != nil {
 		log.Printf("Failed to start agentic session %s in project %s: %v", sessionName, project, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start agentic session"})
 		return
 	}
 
-	// Parse and return updated session
-	session := types.AgenticSession{
-		APIVersion: updated.GetAPIVersion(),
-		Kind:       updated.GetKind(),
-		Metadata:   updated.Object["metadata"].(map[string]interface{}),
-	}
-
-	if spec, ok := updated.Object["spec"].(map[string]interface{}); ok {
-		session.Spec = parseSpec(spec)
-	}
-
-	if status, ok := updated.Object["status"].(map[string]interface{}); ok {
-		session.Status = parseStatus(status)
-	}
-
-	c.JSON(http.StatusAccepted, session)
+	c.JSON(http.StatusAccepted, sessionResponse(updated))
 }
 
 func StopSession(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	reqK8s, reqDyn := GetK8sClientsForRequest(c)
-	gvr := GetAgenticSessionV1Alpha1Resource()
+	reqK8s, _ := GetK8sClientsForRequest(c)
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqK8s == nil || reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
 	// Get current resource
-	item, err := reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+	item, err := reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -1447,14 +1224,8 @@ func StopSession(c *gin.Context) {
 	}
 
 	// Check current status
-	status, ok := item.Object["status"].(map[string]interface{})
-	if !ok {
-		status = make(map[string]interface{})
-		item.Object["status"] = status
-	}
-
-	currentPhase, _ := status["phase"].(string)
-	if currentPhase == "Completed" || currentPhase == "Failed" || currentPhase == "Stopped" {
+	currentPhase := item.Status.Phase
+	if currentPhase == vteamv1alpha1.AgenticSessionPhaseCompleted || currentPhase == vteamv1alpha1.AgenticSessionPhaseFailed || currentPhase == vteamv1alpha1.AgenticSessionPhaseStopped {
 		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Cannot stop session in %s state", currentPhase)})
 		return
 	}
@@ -1462,8 +1233,8 @@ func StopSession(c *gin.Context) {
 	log.Printf("Attempting to stop agentic session %s in project %s (current phase: %s)", sessionName, project, currentPhase)
 
 	// Get job name from status
-	jobName, jobExists := status["jobName"].(string)
-	if !jobExists || jobName == "" {
+	jobName := item.Status.JobName
+	if jobName == "" {
 		// Try to derive job name if not in status
 		jobName = fmt.Sprintf("%s-job", sessionName)
 		log.Printf("Job name not in status, trying derived name: %s", jobName)
@@ -1513,27 +1284,27 @@ func StopSession(c *gin.Context) {
 		log.Printf("Successfully deleted session-labeled pods")
 	}
 
-	// Update status to Stopped
-	status["phase"] = "Stopped"
-	status["message"] = "Session stopped by user"
-	status["completionTime"] = time.Now().Format(time.RFC3339)
-
 	// Also set interactive: true in spec so session can be restarted
-	if spec, ok := item.Object["spec"].(map[string]interface{}); ok {
-		if interactive, ok := spec["interactive"].(bool); !ok || !interactive {
-			log.Printf("Setting interactive: true for stopped session %s to allow restart", sessionName)
-			spec["interactive"] = true
-			// Update spec first (must use Update, not UpdateStatus)
-			item, err = reqDyn.Resource(gvr).Namespace(project).Update(context.TODO(), item, v1.UpdateOptions{})
-			if err != nil {
-				log.Printf("Failed to update session spec for %s: %v (continuing with status update)", sessionName, err)
-				// Continue anyway - status update is more important
-			}
+	if !item.Spec.Interactive {
+		log.Printf("Setting interactive: true for stopped session %s to allow restart", sessionName)
+		item.Spec.Interactive = true
+		// Update spec first (must use Update, not UpdateStatus)
+		if fresh, err := reqSessions.Update(context.TODO(), item, v1.UpdateOptions{}); err != nil {
+			log.Printf("Failed to update session spec for %s: %v (continuing with status update)", sessionName, err)
+			// Continue anyway - status update is more important
+		} else {
+			item = fresh
 		}
 	}
 
+	// Update status to Stopped
+	item.Status.Phase = vteamv1alpha1.AgenticSessionPhaseStopped
+	item.Status.Message = "Session stopped by user"
+	now := v1.Now()
+	item.Status.CompletionTime = &now
+
 	// Update the resource using UpdateStatus for status subresource
-	updated, err := reqDyn.Resource(gvr).Namespace(project).UpdateStatus(context.TODO(), item, v1.UpdateOptions{})
+	updated, err := reqSessions.UpdateStatus(context.TODO(), item, v1.UpdateOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Session was deleted while we were trying to update it
@@ -1546,23 +1317,8 @@ func StopSession(c *gin.Context) {
 		return
 	}
 
-	// Parse and return updated session
-	session := types.AgenticSession{
-		APIVersion: updated.GetAPIVersion(),
-		Kind:       updated.GetKind(),
-		Metadata:   updated.Object["metadata"].(map[string]interface{}),
-	}
-
-	if spec, ok := updated.Object["spec"].(map[string]interface{}); ok {
-		session.Spec = parseSpec(spec)
-	}
-
-	if status, ok := updated.Object["status"].(map[string]interface{}); ok {
-		session.Status = parseStatus(status)
-	}
-
 	log.Printf("Successfully stopped agentic session %s", sessionName)
-	c.JSON(http.StatusAccepted, session)
+	c.JSON(http.StatusAccepted, sessionResponse(updated))
 }
 
 // PUT /api/projects/:projectName/agentic-sessions/:sessionName/status
@@ -1570,7 +1326,11 @@ func StopSession(c *gin.Context) {
 func UpdateSessionStatus(c *gin.Context) {
 	project := c.GetString("project")
 	sessionName := c.Param("sessionName")
-	_, reqDyn := GetK8sClientsForRequest(c)
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
 
 	var statusUpdate map[string]interface{}
 	if err := c.ShouldBindJSON(&statusUpdate); err != nil {
@@ -1578,10 +1338,8 @@ func UpdateSessionStatus(c *gin.Context) {
 		return
 	}
 
-	gvr := GetAgenticSessionV1Alpha1Resource()
-
 	// Get current resource
-	item, err := reqDyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+	item, err := reqSessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
@@ -1592,12 +1350,6 @@ func UpdateSessionStatus(c *gin.Context) {
 		return
 	}
 
-	// Ensure status map
-	if item.Object["status"] == nil {
-		item.Object["status"] = make(map[string]interface{})
-	}
-	status := item.Object["status"].(map[string]interface{})
-
 	// Accept standard fields and result summary fields from runner
 	allowed := map[string]struct{}{
 		"phase": {}, "completionTime": {}, "cost": {}, "message": {},
@@ -1610,13 +1362,15 @@ func UpdateSessionStatus(c *gin.Context) {
 		}
 	}
 
-	// Merge remaining fields into status
-	for k, v := range statusUpdate {
-		status[k] = v
+	// Merge remaining fields into status; fields the status schema does not define are dropped
+	b, _ := json.Marshal(statusUpdate)
+	if err := json.Unmarshal(b, &item.Status); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid status: %v", err)})
+		return
 	}
 
 	// Update only the status subresource (requires agenticsessions/status perms)
-	if _, err := reqDyn.Resource(gvr).Namespace(project).UpdateStatus(context.TODO(), item, v1.UpdateOptions{}); err != nil {
+	if _, err := reqSessions.UpdateStatus(context.TODO(), item, v1.UpdateOptions{}); err != nil {
 		log.Printf("Failed to update agentic session status %s in project %s: %v", sessionName, project, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update agentic session status"})
 		return
@@ -1673,6 +1427,16 @@ func SpawnContentPod(c *gin.Context) {
 		imagePullPolicy = corev1.PullAlways
 	}
 
+	// Mount the runner secret (if configured) so pushes from the temp pod can sign commits
+	runnerSecretsName := ""
+	if _, reqDyn := GetK8sClientsForRequest(c); reqDyn != nil {
+		if psObj, err := reqDyn.Resource(GetProjectSettingsResource()).Namespace(project).Get(c.Request.Context(), "projectsettings", v1.GetOptions{}); err == nil {
+			if v, ok, _ := unstructured.NestedString(psObj.Object, "spec", "runnerSecretsName"); ok {
+				runnerSecretsName = strings.TrimSpace(v)
+			}
+		}
+	}
+
 	// Create temporary pod
 	pod := &corev1.Pod{
 		ObjectMeta: v1.ObjectMeta{
@@ -1741,6 +1505,18 @@ func SpawnContentPod(c *gin.Context) {
 		},
 	}
 
+	if runnerSecretsName != "" {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name:         "runner-secrets",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: runnerSecretsName, Optional: BoolPtr(true)}},
+		})
+		pod.Spec.Containers[0].VolumeMounts = append(pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      "runner-secrets",
+			MountPath: "/var/run/runner-secrets",
+			ReadOnly:  true,
+		})
+	}
+
 	created, err := reqK8s.CoreV1().Pods(project).Create(c.Request.Context(), pod, v1.CreateOptions{})
 	if err != nil {
 		log.Printf("Failed to create temp content pod: %v", err)
@@ -1866,22 +1642,21 @@ func GetSessionK8sResources(c *gin.Context) {
 	}
 	sessionName := c.Param("sessionName")
 
-	reqK8s, reqDyn := GetK8sClientsForRequest(c)
-	if reqK8s == nil {
+	reqK8s, _ := GetK8sClientsForRequest(c)
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqK8s == nil || reqSessions == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
 
 	// Get session to find job name
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	session, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), sessionName, v1.GetOptions{})
+	session, err := reqSessions.Get(c.Request.Context(), sessionName, v1.GetOptions{})
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
 		return
 	}
 
-	status, _ := session.Object["status"].(map[string]interface{})
-	jobName, _ := status["jobName"].(string)
+	jobName := session.Status.JobName
 	if jobName == "" {
 		jobName = fmt.Sprintf("%s-job", sessionName)
 	}
@@ -2027,77 +1802,48 @@ func GetSessionK8sResources(c *gin.Context) {
 }
 
 // setRepoStatus updates status.repos[idx] with status and diff info
-func setRepoStatus(dyn dynamic.Interface, project, sessionName string, repoIndex int, newStatus string) error {
-	gvr := GetAgenticSessionV1Alpha1Resource()
-	item, err := dyn.Resource(gvr).Namespace(project).Get(context.TODO(), sessionName, v1.GetOptions{})
+func setRepoStatus(sessions vteamv1alpha1client.AgenticSessionInterface, project, sessionName string, repoIndex int, newStatus string) error {
+	item, err := sessions.Get(context.TODO(), sessionName, v1.GetOptions{})
 	if err != nil {
 		return err
 	}
 
 	// Get repo name from spec.repos[repoIndex]
-	spec, _ := item.Object["spec"].(map[string]interface{})
-	specRepos, _ := spec["repos"].([]interface{})
-	if repoIndex < 0 || repoIndex >= len(specRepos) {
+	if repoIndex < 0 || repoIndex >= len(item.Spec.Repos) {
 		return fmt.Errorf("repo index out of range")
 	}
-	specRepo, _ := specRepos[repoIndex].(map[string]interface{})
 	repoName := ""
-	if name, ok := specRepo["name"].(string); ok {
-		repoName = name
-	} else if input, ok := specRepo["input"].(map[string]interface{}); ok {
-		if url, ok := input["url"].(string); ok {
-			repoName = DeriveRepoFolderFromURL(url)
-		}
+	if url := item.Spec.Repos[repoIndex].Input.URL; url != "" {
+		repoName = DeriveRepoFolderFromURL(url)
 	}
 	if repoName == "" {
 		repoName = fmt.Sprintf("repo-%d", repoIndex)
 	}
 
-	// Ensure status.repos exists
-	if item.Object["status"] == nil {
-		item.Object["status"] = make(map[string]interface{})
-	}
-	status := item.Object["status"].(map[string]interface{})
-	statusRepos, _ := status["repos"].([]interface{})
-	if statusRepos == nil {
-		statusRepos = []interface{}{}
-	}
-
-	// Find or create status entry for this repo
-	repoStatus := map[string]interface{}{
-		"name":         repoName,
-		"status":       newStatus,
-		"last_updated": time.Now().Format(time.RFC3339),
-	}
-
 	// Update existing or append new
+	now := v1.Now()
 	found := false
-	for i, r := range statusRepos {
-		if rm, ok := r.(map[string]interface{}); ok {
-			if n, ok := rm["name"].(string); ok && n == repoName {
-				rm["status"] = newStatus
-				rm["last_updated"] = time.Now().Format(time.RFC3339)
-				statusRepos[i] = rm
-				found = true
-				break
-			}
+	for i := range item.Status.Repos {
+		if item.Status.Repos[i].Name == repoName {
+			item.Status.Repos[i].Status = newStatus
+			item.Status.Repos[i].LastUpdated = &now
+			found = true
+			break
 		}
 	}
 	if !found {
-		statusRepos = append(statusRepos, repoStatus)
+		item.Status.Repos = append(item.Status.Repos, vteamv1alpha1.RepoStatus{
+			Name:        repoName,
+			Status:      newStatus,
+			LastUpdated: &now,
+		})
 	}
 
-	status["repos"] = statusRepos
-	item.Object["status"] = status
-
-	updated, err := dyn.Resource(gvr).Namespace(project).UpdateStatus(context.TODO(), item, v1.UpdateOptions{})
-	if err != nil {
+	if _, err := sessions.UpdateStatus(context.TODO(), item, v1.UpdateOptions{}); err != nil {
 		log.Printf("setRepoStatus: update failed project=%s session=%s repoIndex=%d status=%s err=%v", project, sessionName, repoIndex, newStatus, err)
 		return err
 	}
-	if updated != nil {
-		log.Printf("setRepoStatus: update ok project=%s session=%s repo=%s status=%s", project, sessionName, repoName, newStatus)
-	}
+	log.Printf("setRepoStatus: update ok project=%s session=%s repo=%s status=%s", project, sessionName, repoName, newStatus)
 	return nil
 }
 
@@ -2286,14 +2032,16 @@ func PushSessionRepo(c *gin.Context) {
 	session := c.Param("sessionName")
 
 	var body struct {
-		RepoIndex     int    `json:"repoIndex"`
-		CommitMessage string `json:"commitMessage"`
+		RepoIndex        int    `json:"repoIndex"`
+		CommitMessage    string `json:"commitMessage"`
+		ConflictStrategy string `json:"conflictStrategy"` // rebase (default), merge or none
+		NotifySession    bool   `json:"notifySession"`    // send conflicts to the interactive session
 	}
 	if err := c.BindJSON(&body); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON body"})
 		return
 	}
-	log.Printf("pushSessionRepo: request project=%s session=%s repoIndex=%d commitLen=%d", project, session, body.RepoIndex, len(strings.TrimSpace(body.CommitMessage)))
+	log.Printf("pushSessionRepo: request project=%s session=%s repoIndex=%d strategy=%q commitLen=%d", project, session, body.RepoIndex, body.ConflictStrategy, len(strings.TrimSpace(body.CommitMessage)))
 
 	// Try temp service first (for completed sessions), then regular service
 	serviceName := fmt.Sprintf("temp-content-%s", session)
@@ -2313,42 +2061,33 @@ func PushSessionRepo(c *gin.Context) {
 	// default branch when not defined on output
 	resolvedBranch := fmt.Sprintf("sessions/%s", session)
 	resolvedOutputURL := ""
-	if _, reqDyn := GetK8sClientsForRequest(c); reqDyn != nil {
-		gvr := GetAgenticSessionV1Alpha1Resource()
-		obj, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), session, v1.GetOptions{})
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read session"})
-			return
-		}
-		spec, _ := obj.Object["spec"].(map[string]interface{})
-		repos, _ := spec["repos"].([]interface{})
-		if body.RepoIndex < 0 || body.RepoIndex >= len(repos) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repo index"})
-			return
-		}
-		rm, _ := repos[body.RepoIndex].(map[string]interface{})
-		// Derive repoPath from input URL folder name
-		if in, ok := rm["input"].(map[string]interface{}); ok {
-			if urlv, ok2 := in["url"].(string); ok2 && strings.TrimSpace(urlv) != "" {
-				folder := DeriveRepoFolderFromURL(strings.TrimSpace(urlv))
-				if folder != "" {
-					resolvedRepoPath = fmt.Sprintf("/sessions/%s/workspace/%s", session, folder)
-				}
-			}
+	reqSessions := GetSessionClientForRequest(c, project)
+	if reqSessions == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no session client"})
+		return
+	}
+	sessionObj, err := reqSessions.Get(c.Request.Context(), session, v1.GetOptions{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read session"})
+		return
+	}
+	interactive := sessionObj.Spec.Interactive
+	if body.RepoIndex < 0 || body.RepoIndex >= len(sessionObj.Spec.Repos) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid repo index"})
+		return
+	}
+	rm := sessionObj.Spec.Repos[body.RepoIndex]
+	// Derive repoPath from input URL folder name
+	if urlv := strings.TrimSpace(rm.Input.URL); urlv != "" {
+		if folder := DeriveRepoFolderFromURL(urlv); folder != "" {
+			resolvedRepoPath = fmt.Sprintf("/sessions/%s/workspace/%s", session, folder)
 		}
-		if out, ok := rm["output"].(map[string]interface{}); ok {
-			if urlv, ok2 := out["url"].(string); ok2 && strings.TrimSpace(urlv) != "" {
-				resolvedOutputURL = strings.TrimSpace(urlv)
-			}
-			if bs, ok2 := out["branch"].(string); ok2 && strings.TrimSpace(bs) != "" {
-				resolvedBranch = strings.TrimSpace(bs)
-			} else if bv, ok2 := out["branch"].(*string); ok2 && bv != nil && strings.TrimSpace(*bv) != "" {
-				resolvedBranch = strings.TrimSpace(*bv)
-			}
+	}
+	if out := rm.Output; out != nil {
+		resolvedOutputURL = strings.TrimSpace(out.URL)
+		if out.Branch != nil && strings.TrimSpace(*out.Branch) != "" {
+			resolvedBranch = strings.TrimSpace(*out.Branch)
 		}
-	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "no dynamic client"})
-		return
 	}
 	// If input URL missing or unparsable, fall back to numeric index path (last resort)
 	if strings.TrimSpace(resolvedRepoPath) == "" {
@@ -2363,12 +2102,24 @@ func PushSessionRepo(c *gin.Context) {
 		return
 	}
 	log.Printf("pushSessionRepo: resolved repoPath=%q outputUrl=%q branch=%q", resolvedRepoPath, resolvedOutputURL, resolvedBranch)
+	auditAction(c, "session.github.push", "session", session, map[string]string{
+		"repoIndex":        strconv.Itoa(body.RepoIndex),
+		"outputUrl":        resolvedOutputURL,
+		"branch":           resolvedBranch,
+		"conflictStrategy": body.ConflictStrategy,
+	})
 
 	payload := map[string]interface{}{
-		"repoPath":      resolvedRepoPath,
-		"commitMessage": body.CommitMessage,
-		"branch":        resolvedBranch,
-		"outputRepoUrl": resolvedOutputURL,
+		"repoPath":         resolvedRepoPath,
+		"commitMessage":    body.CommitMessage,
+		"branch":           resolvedBranch,
+		"outputRepoUrl":    resolvedOutputURL,
+		"conflictStrategy": body.ConflictStrategy,
+	}
+	if _, reqDyn := GetK8sClientsForRequest(c); reqDyn != nil {
+		for k, v := range resolveCommitIdentity(c.Request.Context(), reqDyn, project, &sessionObj.Spec) {
+			payload[k] = v
+		}
 	}
 	b, _ := json.Marshal(payload)
 	req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodPost, endpoint+"/content/github/push", strings.NewReader(string(b)))
@@ -2383,20 +2134,18 @@ func PushSessionRepo(c *gin.Context) {
 	// Attach short-lived GitHub token for one-shot authenticated push
 	if reqK8s, reqDyn := GetK8sClientsForRequest(c); reqK8s != nil {
 		// Load session to get authoritative userId
-		gvr := GetAgenticSessionV1Alpha1Resource()
-		obj, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), session, v1.GetOptions{})
+		obj, err := reqSessions.Get(c.Request.Context(), session, v1.GetOptions{})
 		if err == nil {
-			spec, _ := obj.Object["spec"].(map[string]interface{})
 			userId := ""
-			if spec != nil {
-				if uc, ok := spec["userContext"].(map[string]interface{}); ok {
-					if v, ok := uc["userId"].(string); ok {
-						userId = strings.TrimSpace(v)
-					}
-				}
+			if obj.Spec.UserContext != nil {
+				userId = strings.TrimSpace(obj.Spec.UserContext.UserID)
 			}
-			if userId != "" {
-				if tokenStr, err := GetGitHubToken(c.Request.Context(), reqK8s, reqDyn, project, userId); err == nil && strings.TrimSpace(tokenStr) != "" {
+			if tokenStr, ok := webhookInstallationToken(c.Request.Context(), obj); ok {
+				req.Header.Set("X-GitHub-Token", tokenStr)
+				log.Printf("pushSessionRepo: attached installation token for webhook session project=%s session=%s", project, session)
+			} else if userId != "" {
+				repos, perms := sessionTokenScope(&obj.Spec)
+				if tokenStr, err := GetScopedGitHubToken(c.Request.Context(), reqK8s, reqDyn, project, userId, repos, perms); err == nil && strings.TrimSpace(tokenStr) != "" {
 					req.Header.Set("X-GitHub-Token", tokenStr)
 					log.Printf("pushSessionRepo: attached short-lived GitHub token for project=%s session=%s", project, session)
 				} else if err != nil {
@@ -2426,21 +2175,123 @@ func PushSessionRepo(c *gin.Context) {
 			}
 			return s
 		}())
+		if resp.StatusCode == http.StatusConflict {
+			handlePushConflict(c, project, session, body.RepoIndex, interactive && body.NotifySession, bodyBytes)
+		}
 		c.Data(resp.StatusCode, "application/json", bodyBytes)
 		return
 	}
-	if _, reqDyn := GetK8sClientsForRequest(c); reqDyn != nil {
-		log.Printf("pushSessionRepo: setting repo status to 'pushed' for repoIndex=%d", body.RepoIndex)
-		if err := setRepoStatus(reqDyn, project, session, body.RepoIndex, "pushed"); err != nil {
-			log.Printf("pushSessionRepo: setRepoStatus failed project=%s session=%s repoIndex=%d err=%v", project, session, body.RepoIndex, err)
-		}
-	} else {
-		log.Printf("pushSessionRepo: no dynamic client; cannot set repo status project=%s session=%s", project, session)
+	log.Printf("pushSessionRepo: setting repo status to 'pushed' for repoIndex=%d", body.RepoIndex)
+	if err := setRepoStatus(reqSessions, project, session, body.RepoIndex, "pushed"); err != nil {
+		log.Printf("pushSessionRepo: setRepoStatus failed project=%s session=%s repoIndex=%d err=%v", project, session, body.RepoIndex, err)
 	}
 	log.Printf("pushSessionRepo: content push succeeded status=%d body.len=%d", resp.StatusCode, len(bodyBytes))
 	c.Data(http.StatusOK, "application/json", bodyBytes)
 }
 
+// resolveCommitIdentity builds the commit identity fields sent to the content service from
+// ProjectSettings spec.commitIdentity and the session's UserContext
+func resolveCommitIdentity(ctx context.Context, dyn dynamic.Interface, project string, sessionSpec *types.AgenticSessionSpec) map[string]interface{} {
+	mode := git.IdentityModeUser
+	name, email := "", ""
+	coAuthorTrailers := true
+	requireSigned := false
+
+	gvr := GetProjectSettingsResource()
+	if obj, err := dyn.Resource(gvr).Namespace(project).Get(ctx, "projectsettings", v1.GetOptions{}); err == nil {
+		if ci, found, _ := unstructured.NestedMap(obj.Object, "spec", "commitIdentity"); found {
+			if v, ok := ci["mode"].(string); ok && strings.TrimSpace(v) != "" {
+				mode = strings.TrimSpace(v)
+			}
+			if v, ok := ci["name"].(string); ok {
+				name = strings.TrimSpace(v)
+			}
+			if v, ok := ci["email"].(string); ok {
+				email = strings.TrimSpace(v)
+			}
+			if v, ok := ci["coAuthorTrailers"].(bool); ok {
+				coAuthorTrailers = v
+			}
+			if v, ok := ci["requireSignedCommits"].(bool); ok {
+				requireSigned = v
+			}
+		}
+	} else if !errors.IsNotFound(err) {
+		log.Printf("resolveCommitIdentity: failed to read ProjectSettings in %s: %v", project, err)
+	}
+
+	requesterName, requesterEmail := "", ""
+	if uc := sessionSpec.UserContext; uc != nil {
+		requesterName, requesterEmail = uc.DisplayName, uc.Email
+		if strings.TrimSpace(requesterName) == "" {
+			requesterName = uc.UserID
+		}
+	}
+	requesterName, requesterEmail = strings.TrimSpace(requesterName), strings.TrimSpace(requesterEmail)
+
+	switch mode {
+	case git.IdentityModeUser:
+		// Session user authors the commit; the content service prefers the GitHub profile when readable
+		name, email = requesterName, requesterEmail
+	case git.IdentityModeBot:
+		if name == "" {
+			name = git.DefaultBotName
+		}
+		if email == "" {
+			email = git.DefaultBotEmail
+		}
+	}
+
+	coAuthors := []string{}
+	if coAuthorTrailers && requesterName != "" && requesterEmail != "" {
+		coAuthors = append(coAuthors, fmt.Sprintf("%s <%s>", requesterName, requesterEmail))
+	}
+
+	return map[string]interface{}{
+		"identityMode":         mode,
+		"authorName":           name,
+		"authorEmail":          email,
+		"coAuthors":            coAuthors,
+		"requireSignedCommits": requireSigned,
+	}
+}
+
+// handlePushConflict records an upstream conflict on the repo status and, when requested,
+// asks the interactive session to resolve it
+func handlePushConflict(c *gin.Context, project, session string, repoIndex int, notify bool, respBody []byte) {
+	if reqSessions := GetSessionClientForRequest(c, project); reqSessions != nil {
+		if err := setRepoStatus(reqSessions, project, session, repoIndex, "conflict"); err != nil {
+			log.Printf("pushSessionRepo: setRepoStatus failed project=%s session=%s repoIndex=%d err=%v", project, session, repoIndex, err)
+		}
+	}
+	if !notify || SendSessionMessage == nil {
+		return
+	}
+
+	var parsed struct {
+		Conflict *git.ConflictInfo `json:"conflict"`
+	}
+	if err := json.Unmarshal(respBody, &parsed); err != nil || parsed.Conflict == nil {
+		log.Printf("pushSessionRepo: could not parse conflict details for session %s: %v", session, err)
+		return
+	}
+	cf := parsed.Conflict
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Pushing to branch %s of %s failed: a %s onto upstream commit %s produced conflicts. ", cf.Branch, cf.RepoURL, cf.Strategy, cf.UpstreamSHA)
+	sb.WriteString("The operation was aborted and your local commits are unchanged. ")
+	fmt.Fprintf(&sb, "Please fetch %s, integrate it into your work, resolve the conflicts below and commit the result.\n\nConflicted files:\n", cf.Branch)
+	for _, f := range cf.Files {
+		fmt.Fprintf(&sb, "- %s (%d conflicting region(s))\n", f.Path, len(f.Hunks))
+	}
+
+	SendSessionMessage(session, "user_message", map[string]interface{}{
+		"content":  sb.String(),
+		"conflict": cf,
+	})
+	log.Printf("pushSessionRepo: sent conflict details for %d file(s) to session %s/%s", len(cf.Files), project, session)
+}
+
 // abandonSessionRepo instructs sidecar to discard local changes for a repo
 func AbandonSessionRepo(c *gin.Context) {
 	project := c.Param("projectName")
@@ -2499,12 +2350,12 @@ func AbandonSessionRepo(c *gin.Context) {
 		c.Data(resp.StatusCode, "application/json", bodyBytes)
 		return
 	}
-	if _, reqDyn := GetK8sClientsForRequest(c); reqDyn != nil {
-		if err := setRepoStatus(reqDyn, project, session, body.RepoIndex, "abandoned"); err != nil {
+	if reqSessions := GetSessionClientForRequest(c, project); reqSessions != nil {
+		if err := setRepoStatus(reqSessions, project, session, body.RepoIndex, "abandoned"); err != nil {
 			log.Printf("abandonSessionRepo: setRepoStatus failed project=%s session=%s repoIndex=%d err=%v", project, session, body.RepoIndex, err)
 		}
 	} else {
-		log.Printf("abandonSessionRepo: no dynamic client; cannot set repo status project=%s session=%s", project, session)
+		log.Printf("abandonSessionRepo: no session client; cannot set repo status project=%s session=%s", project, session)
 	}
 	c.Data(http.StatusOK, "application/json", bodyBytes)
 }
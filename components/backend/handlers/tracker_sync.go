@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+	"ambient-code-backend/tracker"
+	"ambient-code-backend/types"
+
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// defaultTrackerSyncInterval is used when RFE_TRACKER_SYNC_INTERVAL is unset
+	defaultTrackerSyncInterval = 5 * time.Minute
+	// trackerCommentHistory is the number of recent comments kept per issue in status
+	trackerCommentHistory = 5
+	// maxTrackerCommentBody truncates comment bodies mirrored into status
+	maxTrackerCommentBody = 2000
+	// maxPendingPrompts caps prompts queued while no interactive session is running
+	maxPendingPrompts = 20
+	// maxIgnoredPrompts caps the prompts from disallowed authors recorded in status
+	maxIgnoredPrompts = 20
+	// maxJiraWebhookBody caps the webhook payload size accepted by HandleJiraWebhook
+	maxJiraWebhookBody = 5 << 20
+)
+
+// trackerSyncMu serializes syncs so webhook deliveries and the periodic reconcile
+// never forward the same comment twice
+var trackerSyncMu sync.Mutex
+
+// syncTarget is one issue linked from a workflow: a published document or one of its tasks
+type syncTarget struct {
+	Path    string
+	Task    string
+	Tracker string
+	Key     string
+}
+
+func workflowSyncTargets(wf *types.RFEWorkflow) []syncTarget {
+	var targets []syncTarget
+	for _, l := range wf.TrackerLinks {
+		targets = append(targets, syncTarget{Path: l.Path, Tracker: l.Tracker, Key: l.Key})
+		taskIDs := make([]string, 0, len(l.Tasks))
+		for id := range l.Tasks {
+			taskIDs = append(taskIDs, id)
+		}
+		sort.Strings(taskIDs)
+		for _, id := range taskIDs {
+			targets = append(targets, syncTarget{Path: l.Path, Task: id, Tracker: l.Tracker, Key: l.Tasks[id]})
+		}
+	}
+	return targets
+}
+
+// workflowLinksIssue reports whether a workflow links the given issue key
+func workflowLinksIssue(wf *types.RFEWorkflow, key string) bool {
+	for _, t := range workflowSyncTargets(wf) {
+		if strings.EqualFold(t.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func truncateTrackerComment(body string) string {
+	body = strings.TrimSpace(body)
+	if len(body) <= maxTrackerCommentBody {
+		return body
+	}
+	return tracker.TruncateUTF8(body, maxTrackerCommentBody) + "…"
+}
+
+// commentPrompt returns the prompt carried by a comment, if it is addressed to the agents
+func commentPrompt(cfg tracker.CommentPromptConfig, body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	if !cfg.Enabled || body == "" {
+		return "", false
+	}
+	if cfg.Prefix == "" {
+		return body, true
+	}
+	if !strings.HasPrefix(body, cfg.Prefix) {
+		return "", false
+	}
+	prompt := strings.TrimSpace(strings.TrimPrefix(body, cfg.Prefix))
+	return prompt, prompt != ""
+}
+
+// SyncWorkflowTracker pulls status, assignee and comments of every issue linked from an
+// RFEWorkflow into its status.tracker, forwarding comment prompts to the workflow's
+// running interactive session when spec.issueTracker.commentPrompts is enabled
+func SyncWorkflowTracker(ctx context.Context, item *unstructured.Unstructured) error {
+	trackerSyncMu.Lock()
+	defer trackerSyncMu.Unlock()
+
+	project := item.GetNamespace()
+	wf := RfeFromUnstructured(item)
+	if wf == nil || len(wf.TrackerLinks) == 0 {
+		return nil
+	}
+	prev := &types.WorkflowTrackerSync{}
+	if wf.TrackerSync != nil {
+		prev = wf.TrackerSync
+	}
+	prevIssues := map[string]types.TrackedIssue{}
+	for _, is := range prev.Issues {
+		prevIssues[is.Key] = is
+	}
+
+	now := time.Now().UTC()
+	next := &types.WorkflowTrackerSync{LastSyncTime: now.Format(time.RFC3339), PendingPrompts: prev.PendingPrompts, IgnoredPrompts: prev.IgnoredPrompts}
+	trackers := map[string]tracker.IssueTracker{}
+	var cfg tracker.Config
+	var errs []string
+
+	for _, t := range workflowSyncTargets(wf) {
+		it, resolved := trackers[t.Tracker]
+		if !resolved {
+			var err error
+			it, cfg, err = resolveIssueTracker(ctx, K8sClient, DynamicClient, project, wf, t.Tracker, "")
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", t.Tracker, err))
+			}
+			trackers[t.Tracker] = it
+		}
+		old, seen := prevIssues[t.Key]
+		if it == nil {
+			if seen {
+				next.Issues = append(next.Issues, old)
+			}
+			continue
+		}
+
+		st, err := it.GetStatus(ctx, t.Key)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", t.Key, err))
+			if seen {
+				next.Issues = append(next.Issues, old)
+			}
+			continue
+		}
+		entry := types.TrackedIssue{
+			Path:          t.Path,
+			Task:          t.Task,
+			Tracker:       t.Tracker,
+			Key:           t.Key,
+			URL:           st.URL,
+			Title:         st.Title,
+			Status:        st.Status,
+			Category:      st.Category,
+			Assignee:      st.Assignee,
+			Updated:       st.Updated,
+			LastSyncedAt:  next.LastSyncTime,
+			LastCommentAt: old.LastCommentAt,
+			Comments:      old.Comments,
+		}
+
+		since := time.Time{}
+		if old.LastCommentAt != "" {
+			since, _ = time.Parse(time.RFC3339, old.LastCommentAt)
+		}
+		comments, err := it.ListComments(ctx, t.Key, since)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s comments: %v", t.Key, err))
+		}
+		for _, cm := range comments {
+			mirrored := types.TrackerComment{
+				IssueKey: t.Key,
+				ID:       cm.ID,
+				Author:   cm.Author,
+				Body:     truncateTrackerComment(cm.Body),
+				Created:  cm.Created.UTC().Format(time.RFC3339Nano),
+			}
+			entry.Comments = append([]types.TrackerComment{mirrored}, entry.Comments...)
+			entry.LastCommentAt = mirrored.Created
+			// The first sync of an issue only establishes the baseline
+			if !seen {
+				continue
+			}
+			if prompt, ok := commentPrompt(cfg.CommentPrompts, cm.Body); ok {
+				mirrored.Body = prompt
+				// Sessions hold write credentials; only trusted authors may drive them
+				if !cfg.CommentPrompts.AllowsAuthor(cm) {
+					log.Printf("SyncWorkflowTracker: ignoring prompt on %s from %s (association %q) for %s/%s", t.Key, cm.Author, cm.AuthorAssociation, project, wf.ID)
+					next.IgnoredPrompts = append(next.IgnoredPrompts, mirrored)
+					continue
+				}
+				next.PendingPrompts = append(next.PendingPrompts, mirrored)
+			}
+		}
+		if len(entry.Comments) > trackerCommentHistory {
+			entry.Comments = entry.Comments[:trackerCommentHistory]
+		}
+		next.Issues = append(next.Issues, entry)
+	}
+
+	if len(next.PendingPrompts) > 0 {
+		next.PendingPrompts = deliverCommentPrompts(ctx, project, wf.ID, next.PendingPrompts)
+		if len(next.PendingPrompts) > maxPendingPrompts {
+			dropped := len(next.PendingPrompts) - maxPendingPrompts
+			log.Printf("SyncWorkflowTracker: dropping %d undelivered prompt(s) for %s/%s", dropped, project, wf.ID)
+			next.PendingPrompts = next.PendingPrompts[dropped:]
+		}
+	}
+	if len(next.IgnoredPrompts) > maxIgnoredPrompts {
+		next.IgnoredPrompts = next.IgnoredPrompts[len(next.IgnoredPrompts)-maxIgnoredPrompts:]
+	}
+	next.Error = strings.Join(errs, "; ")
+
+	return writeWorkflowTrackerSync(ctx, project, wf.ID, next)
+}
+
+// deliverCommentPrompts sends prompts to the newest running interactive session linked to
+// the workflow and returns the prompts that could not be delivered
+func deliverCommentPrompts(ctx context.Context, project, workflowID string, prompts []types.TrackerComment) []types.TrackerComment {
+	if SendSessionMessage == nil {
+		return prompts
+	}
+	if VteamClient == nil {
+		return prompts
+	}
+	list, err := VteamClient.VteamV1alpha1().AgenticSessions(project).List(ctx, v1.ListOptions{
+		LabelSelector: fmt.Sprintf("rfe-workflow=%s,project=%s", workflowID, project),
+	})
+	if err != nil {
+		log.Printf("deliverCommentPrompts: failed to list sessions for %s/%s: %v", project, workflowID, err)
+		return prompts
+	}
+	var target *vteamv1alpha1.AgenticSession
+	for i := range list.Items {
+		s := &list.Items[i]
+		if !s.Spec.Interactive || s.Status.Phase != vteamv1alpha1.AgenticSessionPhaseRunning {
+			continue
+		}
+		if target == nil || s.CreationTimestamp.After(target.CreationTimestamp.Time) {
+			target = s
+		}
+	}
+	if target == nil {
+		log.Printf("deliverCommentPrompts: no running interactive session for %s/%s; keeping %d prompt(s) pending", project, workflowID, len(prompts))
+		return prompts
+	}
+	for _, p := range prompts {
+		SendSessionMessage(target.GetName(), "user_message", map[string]interface{}{
+			"content": fmt.Sprintf("Comment from %s on %s:\n\n%s", p.Author, p.IssueKey, p.Body),
+			"tracker": map[string]interface{}{"key": p.IssueKey, "commentId": p.ID, "author": p.Author},
+		})
+	}
+	log.Printf("deliverCommentPrompts: sent %d prompt(s) from %s/%s to session %s", len(prompts), project, workflowID, target.GetName())
+	return nil
+}
+
+// writeWorkflowTrackerSync stores status.tracker on the RFEWorkflow
+func writeWorkflowTrackerSync(ctx context.Context, project, workflowID string, ts *types.WorkflowTrackerSync) error {
+	status, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ts)
+	if err != nil {
+		return fmt.Errorf("failed to encode tracker status: %w", err)
+	}
+	gvr := GetRFEWorkflowResource()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := DynamicClient.Resource(gvr).Namespace(project).Get(ctx, workflowID, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedMap(obj.Object, status, "status", "tracker"); err != nil {
+			return err
+		}
+		_, err = DynamicClient.Resource(gvr).Namespace(project).UpdateStatus(ctx, obj, v1.UpdateOptions{})
+		return err
+	})
+}
+
+// syncAllWorkflowTrackers syncs every RFEWorkflow with tracker links, optionally only those
+// linking issueKey
+func syncAllWorkflowTrackers(ctx context.Context, issueKey string) int {
+	list, err := DynamicClient.Resource(GetRFEWorkflowResource()).List(ctx, v1.ListOptions{})
+	if err != nil {
+		log.Printf("syncAllWorkflowTrackers: failed to list RFE workflows: %v", err)
+		return 0
+	}
+	synced := 0
+	for i := range list.Items {
+		item := &list.Items[i]
+		wf := RfeFromUnstructured(item)
+		if wf == nil || len(wf.TrackerLinks) == 0 || (issueKey != "" && !workflowLinksIssue(wf, issueKey)) {
+			continue
+		}
+		if err := SyncWorkflowTracker(ctx, item); err != nil {
+			log.Printf("syncAllWorkflowTrackers: failed to sync %s/%s: %v", item.GetNamespace(), item.GetName(), err)
+			continue
+		}
+		synced++
+	}
+	return synced
+}
+
+// ReconcileWorkflowTrackers periodically pulls tracker state into RFEWorkflow status.
+// RFE_TRACKER_SYNC_INTERVAL sets the interval (Go duration, default 5m; 0 disables).
+func ReconcileWorkflowTrackers() {
+	interval := defaultTrackerSyncInterval
+	if v := strings.TrimSpace(os.Getenv("RFE_TRACKER_SYNC_INTERVAL")); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Printf("ReconcileWorkflowTrackers: invalid RFE_TRACKER_SYNC_INTERVAL %q, using %s", v, interval)
+		} else {
+			interval = d
+		}
+	}
+	if interval <= 0 {
+		log.Printf("ReconcileWorkflowTrackers: periodic tracker sync disabled")
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if DynamicClient == nil || K8sClient == nil || GetRFEWorkflowResource == nil {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		n := syncAllWorkflowTrackers(ctx, "")
+		cancel()
+		if n > 0 {
+			log.Printf("ReconcileWorkflowTrackers: synced %d workflow(s)", n)
+		}
+	}
+}
+
+// verifyJiraWebhook accepts the HMAC-SHA256 X-Hub-Signature header sent by Jira webhooks
+// configured with a secret, or a ?token= query parameter for Jira versions that cannot sign
+func verifyJiraWebhook(secret string, body []byte, signature, token string) bool {
+	if signature != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(expected), []byte(strings.TrimSpace(signature)))
+	}
+	return token != "" && hmac.Equal([]byte(secret), []byte(token))
+}
+
+// POST /api/webhooks/jira
+// HandleJiraWebhook syncs the RFE workflows linking the issue of a Jira webhook event
+// (issue updated, comment created, ...) so status and comment prompts arrive without
+// waiting for the periodic reconcile
+func HandleJiraWebhook(c *gin.Context) {
+	secret := strings.TrimSpace(os.Getenv("JIRA_WEBHOOK_SECRET"))
+	if secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Jira webhooks not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxJiraWebhookBody))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+		return
+	}
+	if !verifyJiraWebhook(secret, body, c.GetHeader("X-Hub-Signature"), c.Query("token")) {
+		log.Printf("jiraWebhook: rejected delivery with invalid signature")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid signature"})
+		return
+	}
+
+	var payload struct {
+		WebhookEvent string `json:"webhookEvent"`
+		Issue        *struct {
+			Key string `json:"key"`
+		} `json:"issue"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid JSON payload"})
+		return
+	}
+	if payload.Issue == nil || strings.TrimSpace(payload.Issue.Key) == "" {
+		c.JSON(http.StatusOK, gin.H{"ok": true, "message": "event ignored"})
+		return
+	}
+	if DynamicClient == nil || K8sClient == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "backend not initialized"})
+		return
+	}
+
+	key := strings.TrimSpace(payload.Issue.Key)
+	log.Printf("jiraWebhook: %s for %s", payload.WebhookEvent, key)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		syncAllWorkflowTrackers(ctx, key)
+	}()
+	c.JSON(http.StatusAccepted, gin.H{"ok": true, "issue": key})
+}
@@ -1,25 +1,22 @@
 package jira
 
 import (
-	"bytes"
 	"context"
-	"encoding/base64"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
 	"ambient-code-backend/git"
 	"ambient-code-backend/handlers"
+	"ambient-code-backend/tracker"
 	"ambient-code-backend/types"
 
 	"github.com/gin-gonic/gin"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -108,15 +105,38 @@ func RFEFromUnstructured(item *unstructured.Unstructured) *types.RFEWorkflow {
 		}
 	}
 
-	// Parse jiraLinks
+	// Parse trackerLinks, then legacy jiraLinks not yet migrated
+	if links, ok := spec["trackerLinks"].([]interface{}); ok {
+		for _, it := range links {
+			if m, ok := it.(map[string]interface{}); ok {
+				link := types.WorkflowTrackerLink{}
+				link.Path, _ = m["path"].(string)
+				link.Tracker, _ = m["tracker"].(string)
+				link.Key, _ = m["key"].(string)
+				link.URL, _ = m["url"].(string)
+				if tasks, ok := m["tasks"].(map[string]interface{}); ok {
+					link.Tasks = make(map[string]string, len(tasks))
+					for id, key := range tasks {
+						if k, ok := key.(string); ok && k != "" {
+							link.Tasks[id] = k
+						}
+					}
+				}
+				if strings.TrimSpace(link.Path) != "" && strings.TrimSpace(link.Key) != "" {
+					wf.TrackerLinks = append(wf.TrackerLinks, link)
+				}
+			}
+		}
+	}
 	if links, ok := spec["jiraLinks"].([]interface{}); ok {
 		for _, it := range links {
 			if m, ok := it.(map[string]interface{}); ok {
-				path := fmt.Sprintf("%v", m["path"])
-				jiraKey := fmt.Sprintf("%v", m["jiraKey"])
-				if strings.TrimSpace(path) != "" && strings.TrimSpace(jiraKey) != "" {
-					wf.JiraLinks = append(wf.JiraLinks, types.WorkflowJiraLink{Path: path, JiraKey: jiraKey})
+				path, _ := m["path"].(string)
+				jiraKey, _ := m["jiraKey"].(string)
+				if strings.TrimSpace(path) == "" || strings.TrimSpace(jiraKey) == "" || FindTrackerLink(wf, path) != nil {
+					continue
 				}
+				wf.TrackerLinks = append(wf.TrackerLinks, types.WorkflowTrackerLink{Path: path, Tracker: tracker.TypeJira, Key: jiraKey})
 			}
 		}
 	}
@@ -126,6 +146,69 @@ func RFEFromUnstructured(item *unstructured.Unstructured) *types.RFEWorkflow {
 		wf.ParentOutcome = handlers.StringPtr(strings.TrimSpace(po))
 	}
 
+	// Approval gates between phases
+	if gates, ok := spec["gates"].([]interface{}); ok {
+		for _, it := range gates {
+			m, ok := it.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var g types.PhaseGate
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &g); err == nil && g.Phase != "" {
+				wf.Gates = append(wf.Gates, g)
+			}
+		}
+	}
+
+	// Tracker state synced back from the issue tracker
+	if status, ok := obj["status"].(map[string]interface{}); ok {
+		if ts, ok := status["tracker"].(map[string]interface{}); ok {
+			sync := &types.WorkflowTrackerSync{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(ts, sync); err == nil {
+				wf.TrackerSync = sync
+			}
+		}
+		if phase, ok := status["phase"].(string); ok {
+			wf.Phase = phase
+		}
+		if a, ok := status["phaseArtifacts"].(map[string]interface{}); ok {
+			artifacts := &types.WorkflowArtifacts{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(a, artifacts); err == nil {
+				wf.PhaseArtifacts = artifacts
+			}
+		}
+		if seed, ok := status["seed"].(map[string]interface{}); ok {
+			ws := &types.WorkflowSeed{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(seed, ws); err == nil {
+				wf.Seed = ws
+			}
+		}
+		if approvals, ok := status["approvals"].([]interface{}); ok {
+			for _, it := range approvals {
+				m, ok := it.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				var a types.PhaseApproval
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &a); err == nil {
+					wf.Approvals = append(wf.Approvals, a)
+				}
+			}
+		}
+		if h, ok := status["phaseHistory"].([]interface{}); ok {
+			for _, it := range h {
+				m, ok := it.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				var t types.PhaseTransition
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &t); err == nil {
+					wf.PhaseHistory = append(wf.PhaseHistory, t)
+				}
+			}
+		}
+	}
+
 	return wf
 }
 
@@ -172,88 +255,22 @@ func StripExecutionFlow(content string) string {
 	return strings.Join(result, "\n")
 }
 
-// AttachFileToJiraIssue attaches a file to a Jira issue
-func AttachFileToJiraIssue(ctx context.Context, jiraBase, issueKey, authHeader string, filename string, content []byte) error {
-	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s/attachments", jiraBase, url.PathEscape(issueKey))
-
-	// Create multipart form body
-	body := &bytes.Buffer{}
-	writer := NewMultipartWriter(body)
-
-	part, err := writer.CreateFormFile("file", filename)
-	if err != nil {
-		return fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	if _, err := part.Write(content); err != nil {
-		return fmt.Errorf("failed to write content: %w", err)
-	}
-
-	contentType := writer.FormDataContentType()
-	if err := writer.Close(); err != nil {
-		return fmt.Errorf("failed to close writer: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	httpReq.Header.Set("Authorization", authHeader)
-	httpReq.Header.Set("Content-Type", contentType)
-	httpReq.Header.Set("X-Atlassian-Token", "no-check")
-
-	httpClient := &http.Client{Timeout: 60 * time.Second}
-	resp, err := httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira API error: %s (body: %s)", resp.Status, string(respBody))
+// FindTrackerLink returns the tracker link for a workspace path, if any
+func FindTrackerLink(wf *types.RFEWorkflow, path string) *types.WorkflowTrackerLink {
+	for i := range wf.TrackerLinks {
+		if strings.TrimSpace(wf.TrackerLinks[i].Path) == strings.TrimSpace(path) {
+			return &wf.TrackerLinks[i]
+		}
 	}
-
 	return nil
 }
 
-// NewMultipartWriter creates a new multipart writer with boundary
-type MultipartWriter struct {
-	w        io.Writer
-	boundary string
-	closed   bool
-}
-
-func NewMultipartWriter(w io.Writer) *MultipartWriter {
-	boundary := fmt.Sprintf("----WebKitFormBoundary%d", time.Now().UnixNano())
-	return &MultipartWriter{w: w, boundary: boundary}
-}
-
-func (mw *MultipartWriter) FormDataContentType() string {
-	return fmt.Sprintf("multipart/form-data; boundary=%s", mw.boundary)
-}
-
-func (mw *MultipartWriter) CreateFormFile(fieldname, filename string) (io.Writer, error) {
-	h := fmt.Sprintf("--%s\r\nContent-Disposition: form-data; name=\"%s\"; filename=\"%s\"\r\nContent-Type: application/octet-stream\r\n\r\n",
-		mw.boundary, fieldname, filename)
-	_, err := mw.w.Write([]byte(h))
-	return mw.w, err
-}
-
-func (mw *MultipartWriter) Close() error {
-	if mw.closed {
-		return nil
-	}
-	mw.closed = true
-	_, err := fmt.Fprintf(mw.w, "\r\n--%s--\r\n", mw.boundary)
-	return err
-}
-
-// POST /api/projects/:projectName/rfe-workflows/:id/jira { path, phase }
-// Creates or updates a Jira issue from a GitHub file and updates the RFEWorkflow CR with the linkage
-// Supports phase-specific logic: specify (Feature + rfe.md attachment), plan (Epic with artifact links), tasks (attach tasks.md)
-func (h *Handler) PublishWorkflowFileToJira(c *gin.Context) {
+// POST /api/projects/:projectName/rfe-workflows/:id/tracker { path, phase }
+// (also served at .../jira for existing clients)
+// Creates or updates an issue in the project's issue tracker from a GitHub file and records the link in the RFEWorkflow CR
+// Supports phase-specific logic: specify (request + rfe.md attachment), plan (feature with supporting docs),
+// tasks (epic with one story per task, dependencies linked)
+func (h *Handler) PublishWorkflowFile(c *gin.Context) {
 	project := c.Param("projectName")
 	id := c.Param("id")
 
@@ -266,57 +283,14 @@ func (h *Handler) PublishWorkflowFileToJira(c *gin.Context) {
 		return
 	}
 
-	log.Printf("DEBUG JIRA: Received phase='%s' for path='%s'", req.Phase, req.Path)
-
-	// Load runner secrets for Jira config
-	_, reqDyn := h.GetK8sClientsForRequest(c)
-	reqK8s, _ := h.GetK8sClientsForRequest(c)
-	if reqK8s == nil {
+	reqK8s, reqDyn := h.GetK8sClientsForRequest(c)
+	if reqK8s == nil || reqDyn == nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user token"})
 		return
 	}
 
-	// Determine configured secret name
-	secretName := ""
-	if reqDyn != nil {
-		gvr := h.GetProjectSettingsResource()
-		if obj, err := reqDyn.Resource(gvr).Namespace(project).Get(c.Request.Context(), "projectsettings", v1.GetOptions{}); err == nil {
-			if spec, ok := obj.Object["spec"].(map[string]interface{}); ok {
-				if v, ok := spec["runnerSecretsName"].(string); ok {
-					secretName = strings.TrimSpace(v)
-				}
-			}
-		}
-	}
-	if secretName == "" {
-		secretName = "ambient-runner-secrets"
-	}
-
-	sec, err := reqK8s.CoreV1().Secrets(project).Get(c.Request.Context(), secretName, v1.GetOptions{})
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read runner secret", "details": err.Error()})
-		return
-	}
-	get := func(k string) string {
-		if b, ok := sec.Data[k]; ok {
-			return string(b)
-		}
-		return ""
-	}
-	jiraURL := strings.TrimSpace(get("JIRA_URL"))
-	jiraProject := strings.TrimSpace(get("JIRA_PROJECT"))
-	jiraToken := strings.TrimSpace(get("JIRA_API_TOKEN"))
-	if jiraURL == "" || jiraProject == "" || jiraToken == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing Jira configuration in runner secret (JIRA_URL, JIRA_PROJECT, JIRA_API_TOKEN required)"})
-		return
-	}
-
 	// Load workflow
 	gvrWf := h.GetRFEWorkflowResource()
-	if reqDyn == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing or invalid user token"})
-		return
-	}
 	item, err := reqDyn.Resource(gvrWf).Namespace(project).Get(c.Request.Context(), id, v1.GetOptions{})
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Workflow not found"})
@@ -328,6 +302,18 @@ func (h *Handler) PublishWorkflowFileToJira(c *gin.Context) {
 		return
 	}
 
+	// Re-publishing goes to the tracker the file was first published to
+	existing := FindTrackerLink(wf, req.Path)
+	trackerType := ""
+	if existing != nil {
+		trackerType = existing.Tracker
+	}
+	issueTracker, err := handlers.ResolveIssueTracker(c, project, wf, trackerType)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Issue tracker not configured", "details": err.Error()})
+		return
+	}
+
 	// Get user ID and GitHub token
 	userID, _ := c.Get("userID")
 	userIDStr, ok := userID.(string)
@@ -371,147 +357,70 @@ func (h *Handler) PublishWorkflowFileToJira(c *gin.Context) {
 	githubURL := fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", owner, repo, branch, req.Path)
 
 	// Strip Execution Flow section from spec.md and plan.md
-	var processedContent []byte
+	var processedContent string
 	if req.Phase == "specify" || req.Phase == "plan" || req.Phase == "tasks" {
 		stripped := StripExecutionFlow(string(content))
 
 		// For tasks phase (Epic), add reference to parent Feature if it exists
 		featureReference := ""
 		if req.Phase == "tasks" {
-			for _, jl := range wf.JiraLinks {
-				if strings.Contains(jl.Path, "plan.md") {
-					featureReference = fmt.Sprintf("\n\n**Implements Feature:** %s\n\n---", jl.JiraKey)
+			for _, tl := range wf.TrackerLinks {
+				if strings.Contains(tl.Path, "plan.md") {
+					featureReference = fmt.Sprintf("\n\n**Implements Feature:** %s\n\n---", tl.Key)
 					break
 				}
 			}
 		}
 
 		// Prepend GitHub URL reference at the top
-		processedContent = []byte(fmt.Sprintf("**Source:** %s%s\n\n---\n\n%s", githubURL, featureReference, stripped))
+		processedContent = fmt.Sprintf("**Source:** %s%s\n\n---\n\n%s", githubURL, featureReference, stripped)
 	} else {
 		// For other files, just prepend the GitHub URL
-		processedContent = []byte(fmt.Sprintf("**Source:** %s\n\n---\n\n%s", githubURL, string(content)))
-	}
-
-	// Check if Jira link already exists for this path
-	existingKey := ""
-	for _, jl := range wf.JiraLinks {
-		if strings.TrimSpace(jl.Path) == strings.TrimSpace(req.Path) {
-			existingKey = jl.JiraKey
-			break
-		}
+		processedContent = fmt.Sprintf("**Source:** %s\n\n---\n\n%s", githubURL, string(content))
 	}
 
-	// Determine auth header (Cloud vs Server)
-	authHeader := ""
-	if strings.Contains(jiraURL, "atlassian.net") {
-		// Jira Cloud - assume token is email:api_token format
-		encoded := base64.StdEncoding.EncodeToString([]byte(jiraToken))
-		authHeader = "Basic " + encoded
-	} else {
-		// Jira Server/Data Center
-		authHeader = "Bearer " + jiraToken
+	issue := tracker.Issue{
+		Title: title,
+		Body:  processedContent,
+		Kind:  tracker.KindForPhase(req.Phase),
 	}
-
-	// Determine issue type based on phase
-	// specify -> Feature Request, plan -> Feature, tasks -> Epic
-	issueType := "Feature" // default
-	switch req.Phase {
-	case "specify":
-		issueType = "Feature Request"
-	case "tasks":
-		issueType = "Epic"
+	// TODO: decide correct hierarchy for parent/children tracker objects
+	// For Epic (tasks phase), the Feature reference is added to the description instead
+	if req.Phase != "tasks" && wf.ParentOutcome != nil && *wf.ParentOutcome != "" {
+		issue.ParentKey = *wf.ParentOutcome
 	}
 
-	// Create or update Jira issue
-	jiraBase := strings.TrimRight(jiraURL, "/")
-	var httpReq *http.Request
-
-	if existingKey == "" {
-		// CREATE new issue
-		jiraEndpoint := fmt.Sprintf("%s/rest/api/2/issue", jiraBase)
-
-		fields := map[string]interface{}{
-			"project":     map[string]string{"key": jiraProject},
-			"summary":     title,
-			"description": string(processedContent),
-			"issuetype":   map[string]string{"name": issueType},
-		}
-
-		// TODO: decide correct hierarchy for parent/children jira objects
-		// For Epic (tasks phase), the Feature reference is added to the description instead
-		parentKey := ""
-		if req.Phase != "tasks" {
-			// For non-Epic phases: use parent Outcome if specified
-			if wf.ParentOutcome != nil && *wf.ParentOutcome != "" {
-				parentKey = *wf.ParentOutcome
-			}
-		}
-
-		if parentKey != "" {
-			fields["parent"] = map[string]string{"key": parentKey}
-		}
-
-		reqBody := map[string]interface{}{"fields": fields}
-		payload, _ := json.Marshal(reqBody)
-		log.Printf("DEBUG JIRA: Creating issue with type '%s', payload: %s", issueType, string(payload))
-		httpReq, _ = http.NewRequestWithContext(c.Request.Context(), "POST", jiraEndpoint, bytes.NewReader(payload))
+	var ref *tracker.IssueRef
+	if existing == nil {
+		ref, err = issueTracker.CreateIssue(c.Request.Context(), issue)
 	} else {
-		// UPDATE existing issue
-		jiraEndpoint := fmt.Sprintf("%s/rest/api/2/issue/%s", jiraBase, url.PathEscape(existingKey))
-		reqBody := map[string]interface{}{
-			"fields": map[string]interface{}{
-				"summary":     title,
-				"description": string(processedContent),
-			},
-		}
-		payload, _ := json.Marshal(reqBody)
-		httpReq, _ = http.NewRequestWithContext(c.Request.Context(), "PUT", jiraEndpoint, bytes.NewReader(payload))
+		ref, err = issueTracker.UpdateIssue(c.Request.Context(), existing.Key, issue)
 	}
-
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", authHeader)
-
-	httpClient := &http.Client{Timeout: 30 * time.Second}
-	httpResp, httpErr := httpClient.Do(httpReq)
-	if httpErr != nil {
-		c.JSON(http.StatusBadGateway, gin.H{"error": "Jira request failed", "details": httpErr.Error()})
+	if err != nil {
+		log.Printf("Failed to publish %s for workflow %s/%s to %s: %v", req.Path, project, id, issueTracker.Type(), err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Issue tracker request failed", "details": err.Error()})
 		return
 	}
-	defer httpResp.Body.Close()
 
-	respBody, _ := io.ReadAll(httpResp.Body)
-	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
-		c.Data(httpResp.StatusCode, "application/json", respBody)
-		return
-	}
+	var taskKeys map[string]string
+	var taskResult *TaskPublishResult
 
-	// Extract Jira key from response
-	var outKey string
-	if existingKey == "" {
-		var created struct {
-			Key string `json:"key"`
-		}
-		_ = json.Unmarshal(respBody, &created)
-		if strings.TrimSpace(created.Key) == "" {
-			c.JSON(http.StatusBadGateway, gin.H{"error": "Jira creation returned no key"})
+	// Phase-specific attachment handling
+	attach := func(path, name string) {
+		docContent, err := git.ReadGitHubFile(c.Request.Context(), owner, repo, branch, path, githubToken)
+		if err != nil || len(docContent) == 0 {
 			return
 		}
-		outKey = created.Key
-	} else {
-		outKey = existingKey
+		if attachErr := issueTracker.AttachFile(c.Request.Context(), ref.Key, name, docContent); attachErr != nil {
+			log.Printf("Warning: failed to attach %s to %s: %v", name, ref.Key, attachErr)
+		} else {
+			log.Printf("Successfully attached %s to %s", name, ref.Key)
+		}
 	}
-
-	// Phase-specific attachment handling
 	switch req.Phase {
 	case "specify":
 		// For specify phase (Feature Request): attach rfe.md if it exists
-		rfeContent, err := git.ReadGitHubFile(c.Request.Context(), owner, repo, branch, "rfe.md", githubToken)
-		if err == nil && len(rfeContent) > 0 {
-			if attachErr := AttachFileToJiraIssue(c.Request.Context(), jiraBase, outKey, authHeader, "rfe.md", rfeContent); attachErr != nil {
-				log.Printf("Warning: failed to attach rfe.md to %s: %v", outKey, attachErr)
-			}
-		}
+		attach("rfe.md", "rfe.md")
 
 	case "plan":
 		// For plan phase (Feature): attach supporting documents if they exist
@@ -521,33 +430,52 @@ func (h *Handler) PublishWorkflowFileToJira(c *gin.Context) {
 		if len(pathParts) > 1 {
 			dirPath = strings.Join(pathParts[:len(pathParts)-1], "/")
 		}
-
-		// List of supporting documents to attach (if they exist)
-		supportingDocs := []string{"data-model.md", "quickstart.md", "research.md"}
-
-		for _, docName := range supportingDocs {
+		for _, docName := range []string{"data-model.md", "quickstart.md", "research.md"} {
 			docPath := docName
 			if dirPath != "" {
 				docPath = dirPath + "/" + docName
 			}
+			attach(docPath, docName)
+		}
 
-			// Try to read the file - skip silently if it doesn't exist
-			docContent, err := git.ReadGitHubFile(c.Request.Context(), owner, repo, branch, docPath, githubToken)
-			if err == nil && len(docContent) > 0 {
-				if attachErr := AttachFileToJiraIssue(c.Request.Context(), jiraBase, outKey, authHeader, docName, docContent); attachErr != nil {
-					log.Printf("Warning: failed to attach %s to %s: %v", docName, outKey, attachErr)
-				} else {
-					log.Printf("Successfully attached %s to %s", docName, outKey)
-				}
+	case "tasks":
+		// For tasks phase: one story per task (sub-tasks for nested items) under the Epic,
+		// reusing the task->key map from earlier publishes
+		taskKeys = map[string]string{}
+		if existing != nil {
+			for taskID, key := range existing.Tasks {
+				taskKeys[taskID] = key
 			}
 		}
+		if tasks := tracker.ParseTasks(string(content)); len(tasks) > 0 {
+			res := PublishTaskStories(c.Request.Context(), issueTracker, ref.Key, githubURL, tasks, taskKeys)
+			taskResult = &res
+			log.Printf("Published %d tasks of %s under %s: %d created, %d updated, %d links, %d failures",
+				len(tasks), req.Path, ref.Key, res.Created, res.Updated, res.Linked, len(res.Failed))
+		}
+	}
 
-	case "tasks":
-		// For tasks phase: Epic is created and linked to the Feature (plan.md)
-		// No additional attachment handling needed - Epic is standalone with parent link
+	// Update RFEWorkflow CR with the tracker link (and task map, saved even on partial
+	// failure so a retry updates the stories already created)
+	link := types.WorkflowTrackerLink{Path: req.Path, Tracker: issueTracker.Type(), Key: ref.Key, URL: ref.URL, Tasks: taskKeys}
+	if err := SetTrackerLink(c.Request.Context(), reqDyn, gvrWf, item, link); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workflow with tracker link", "details": err.Error()})
+		return
 	}
 
-	// Update RFEWorkflow CR with Jira link
+	resp := gin.H{
+		"tracker": link.Tracker,
+		"key":     link.Key,
+		"url":     link.URL,
+	}
+	if taskResult != nil {
+		resp["tasks"] = taskResult
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetTrackerLink records a link in spec.trackerLinks, folding legacy spec.jiraLinks into it
+func SetTrackerLink(ctx context.Context, dyn dynamic.Interface, gvr schema.GroupVersionResource, item *unstructured.Unstructured, link types.WorkflowTrackerLink) error {
 	obj := item.DeepCopy()
 	spec, _ := obj.Object["spec"].(map[string]interface{})
 	if spec == nil {
@@ -555,35 +483,36 @@ func (h *Handler) PublishWorkflowFileToJira(c *gin.Context) {
 		obj.Object["spec"] = spec
 	}
 
-	var links []interface{}
-	if existing, ok := spec["jiraLinks"].([]interface{}); ok {
-		links = existing
-	}
-
-	// Add or update link
+	wf := RFEFromUnstructured(obj)
 	found := false
-	for _, li := range links {
-		if m, ok := li.(map[string]interface{}); ok {
-			if fmt.Sprintf("%v", m["path"]) == req.Path {
-				m["jiraKey"] = outKey
-				found = true
-				break
-			}
+	for i := range wf.TrackerLinks {
+		if strings.TrimSpace(wf.TrackerLinks[i].Path) == strings.TrimSpace(link.Path) {
+			wf.TrackerLinks[i] = link
+			found = true
 		}
 	}
 	if !found {
-		links = append(links, map[string]interface{}{"path": req.Path, "jiraKey": outKey})
+		wf.TrackerLinks = append(wf.TrackerLinks, link)
 	}
-	spec["jiraLinks"] = links
 
-	if _, err := reqDyn.Resource(gvrWf).Namespace(project).Update(c.Request.Context(), obj, v1.UpdateOptions{}); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workflow with Jira link", "details": err.Error()})
-		return
+	links := make([]interface{}, 0, len(wf.TrackerLinks))
+	for _, l := range wf.TrackerLinks {
+		m := map[string]interface{}{"path": l.Path, "tracker": l.Tracker, "key": l.Key}
+		if l.URL != "" {
+			m["url"] = l.URL
+		}
+		if len(l.Tasks) > 0 {
+			tasks := make(map[string]interface{}, len(l.Tasks))
+			for id, key := range l.Tasks {
+				tasks[id] = key
+			}
+			m["tasks"] = tasks
+		}
+		links = append(links, m)
 	}
+	spec["trackerLinks"] = links
+	delete(spec, "jiraLinks")
 
-	// Return success
-	c.JSON(http.StatusOK, gin.H{
-		"key": outKey,
-		"url": fmt.Sprintf("%s/browse/%s", jiraBase, outKey),
-	})
+	_, err := dyn.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, v1.UpdateOptions{})
+	return err
 }
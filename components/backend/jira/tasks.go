@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"ambient-code-backend/tracker"
+)
+
+// TaskPublishResult summarizes publishing the tasks of a tasks.md under its Epic
+type TaskPublishResult struct {
+	Created int      `json:"created"`
+	Updated int      `json:"updated"`
+	Linked  int      `json:"linked"`
+	Failed  []string `json:"failed,omitempty"`
+}
+
+// PublishTaskStories creates or updates one story per task (and one sub-task per nested
+// checklist item) under epicKey. keys maps task keys to issues published previously and is
+// updated in place, so re-publishing updates existing stories instead of duplicating them.
+// Dependencies missing from the tracker are linked on every run, so links that failed or were
+// removed are restored.
+func PublishTaskStories(ctx context.Context, t tracker.IssueTracker, epicKey, sourceURL string, tasks []tracker.Task, keys map[string]string) TaskPublishResult {
+	var res TaskPublishResult
+	created := map[string]bool{}
+
+	publish := func(task tracker.Task, kind tracker.IssueKind, parentKey string) bool {
+		issue := tracker.Issue{
+			Title:     task.IssueTitle(),
+			Body:      task.Markdown(sourceURL),
+			Kind:      kind,
+			ParentKey: parentKey,
+		}
+		// Sub-tasks were once keyed by position (T004.1); adopt their issues under the content key
+		if _, ok := keys[task.Key]; !ok && task.Key != task.ID {
+			if key, ok := keys[task.ID]; ok {
+				keys[task.Key] = key
+				delete(keys, task.ID)
+			}
+		}
+		if key, ok := keys[task.Key]; ok {
+			if _, err := t.UpdateIssue(ctx, key, issue); err != nil {
+				log.Printf("PublishTaskStories: failed to update %s (%s): %v", task.ID, key, err)
+				res.Failed = append(res.Failed, fmt.Sprintf("%s: %v", task.ID, err))
+				return false
+			}
+			res.Updated++
+			return true
+		}
+		ref, err := t.CreateIssue(ctx, issue)
+		if err != nil {
+			log.Printf("PublishTaskStories: failed to create %s under %s: %v", task.ID, parentKey, err)
+			res.Failed = append(res.Failed, fmt.Sprintf("%s: %v", task.ID, err))
+			return false
+		}
+		keys[task.Key] = ref.Key
+		created[task.Key] = true
+		res.Created++
+		return true
+	}
+
+	for _, task := range tasks {
+		if !publish(task, tracker.KindStory, epicKey) {
+			continue
+		}
+		for _, sub := range task.SubTasks {
+			publish(sub, tracker.KindSubTask, keys[task.Key])
+		}
+	}
+
+	for _, task := range tasks {
+		key, ok := keys[task.Key]
+		if !ok || len(task.DependsOn) == 0 {
+			continue
+		}
+		linked := map[string]bool{}
+		if !created[task.Key] {
+			blockers, err := t.ListBlockers(ctx, key)
+			if err != nil {
+				log.Printf("PublishTaskStories: failed to list links of %s: %v", key, err)
+				res.Failed = append(res.Failed, fmt.Sprintf("%s links: %v", task.ID, err))
+				continue
+			}
+			for _, b := range blockers {
+				linked[strings.ToLower(b)] = true
+			}
+		}
+		for _, dep := range task.DependsOn {
+			blockerKey, ok := keys[dep]
+			if !ok || linked[strings.ToLower(blockerKey)] {
+				continue
+			}
+			if err := t.LinkDependency(ctx, key, blockerKey); err != nil {
+				log.Printf("PublishTaskStories: failed to link %s blocked by %s: %v", key, blockerKey, err)
+				res.Failed = append(res.Failed, fmt.Sprintf("%s depends on %s: %v", task.ID, dep, err))
+				continue
+			}
+			res.Linked++
+		}
+	}
+	return res
+}
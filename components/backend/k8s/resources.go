@@ -1,32 +1,19 @@
 package k8s
 
-import "k8s.io/apimachinery/pkg/runtime/schema"
+import (
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
 
-// GetAgenticSessionV1Alpha1Resource returns the GroupVersionResource for AgenticSession v1alpha1
-func GetAgenticSessionV1Alpha1Resource() schema.GroupVersionResource {
-	return schema.GroupVersionResource{
-		Group:    "vteam.ambient-code",
-		Version:  "v1alpha1",
-		Resource: "agenticsessions",
-	}
-}
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 // GetProjectSettingsResource returns the GroupVersionResource for ProjectSettings
 func GetProjectSettingsResource() schema.GroupVersionResource {
-	return schema.GroupVersionResource{
-		Group:    "vteam.ambient-code",
-		Version:  "v1alpha1",
-		Resource: "projectsettings",
-	}
+	return vteamv1alpha1.ProjectSettingsResource
 }
 
 // GetRFEWorkflowResource returns the GroupVersionResource for RFEWorkflow CRD
 func GetRFEWorkflowResource() schema.GroupVersionResource {
-	return schema.GroupVersionResource{
-		Group:    "vteam.ambient-code",
-		Version:  "v1alpha1",
-		Resource: "rfeworkflows",
-	}
+	return vteamv1alpha1.RFEWorkflowsResource
 }
 
 // GetOpenShiftProjectResource returns the GroupVersionResource for OpenShift Project
@@ -5,7 +5,10 @@ import (
 	"log"
 	"os"
 
+	"ambient-code-backend/admission"
+	"ambient-code-backend/audit"
 	"ambient-code-backend/crd"
+	"ambient-code-backend/egressproxy"
 	"ambient-code-backend/git"
 	"ambient-code-backend/github"
 	"ambient-code-backend/handlers"
@@ -17,6 +20,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 )
 
 func main() {
@@ -33,6 +39,8 @@ func main() {
 
 		// Only initialize what content service needs
 		handlers.StateBaseDir = server.StateBaseDir
+		handlers.RunnerSecretsDir = server.RunnerSecretsDir
+		handlers.GitSigningConfigured = server.GitSigningConfigured
 		handlers.GitPushRepo = git.PushRepo
 		handlers.GitAbandonRepo = git.AbandonRepo
 		handlers.GitDiffRepo = git.DiffRepo
@@ -45,6 +53,36 @@ func main() {
 		return
 	}
 
+	// Admission webhook mode - validates and defaults the CRDs, no K8s access needed
+	if os.Getenv("ADMISSION_WEBHOOK_MODE") == "true" {
+		log.Println("Starting in ADMISSION_WEBHOOK_MODE")
+		// Only SubjectAccessReviews and ProjectSettings reads are made, so the in-cluster config is enough
+		if cfg, err := rest.InClusterConfig(); err != nil {
+			log.Printf("Admission webhook has no in-cluster config, gate changes and sessions will be denied: %v", err)
+		} else if admission.K8sClient, err = kubernetes.NewForConfig(cfg); err != nil {
+			log.Fatalf("Failed to create Kubernetes client: %v", err)
+		} else if admission.DynamicClient, err = dynamic.NewForConfig(cfg); err != nil {
+			log.Fatalf("Failed to create dynamic client: %v", err)
+		}
+		if err := server.RunAdmissionWebhook(admission.NewHandler()); err != nil {
+			log.Fatalf("Admission webhook error: %v", err)
+		}
+		return
+	}
+
+	// Egress proxy mode - session sidecar enforcing the project's egress allow-list, no K8s access needed
+	if os.Getenv("EGRESS_PROXY_MODE") == "true" {
+		log.Println("Starting in EGRESS_PROXY_MODE")
+		allow, err := egressproxy.ParseAllowlist(os.Getenv("EGRESS_ALLOWED_HOSTS"), os.Getenv("EGRESS_ALLOWED_CIDRS"))
+		if err != nil {
+			log.Fatalf("Invalid egress allow-list: %v", err)
+		}
+		if err := server.RunEgressProxy(egressproxy.NewHandler(allow)); err != nil {
+			log.Fatalf("Egress proxy error: %v", err)
+		}
+		return
+	}
+
 	// Normal server mode - full initialization
 	log.Println("Starting in normal server mode with K8s client initialization")
 
@@ -62,7 +100,12 @@ func main() {
 	git.GetGitHubInstallation = func(ctx context.Context, userID string) (interface{}, error) {
 		return github.GetInstallation(ctx, userID)
 	}
-	git.GitHubTokenManager = github.Manager
+	git.GetProjectGitHubInstallation = func(ctx context.Context, project string) (interface{}, error) {
+		return handlers.GetProjectGitHubInstallation(ctx, project)
+	}
+	if github.Manager != nil {
+		git.GitHubTokenManager = github.Manager
+	}
 
 	// Initialize CRD package
 	crd.GetRFEWorkflowResource = k8s.GetRFEWorkflowResource
@@ -76,7 +119,14 @@ func main() {
 	// Initialize GitHub auth handlers
 	handlers.K8sClient = server.K8sClient
 	handlers.Namespace = server.Namespace
-	handlers.GithubTokenManager = github.Manager
+	if github.Manager != nil {
+		handlers.GithubTokenManager = github.Manager
+	}
+
+	// Move GitHub App installations out of the legacy shared ConfigMap
+	if err := handlers.MigrateGitHubInstallations(context.Background()); err != nil {
+		log.Printf("Warning: GitHub installation migration incomplete: %v", err)
+	}
 
 	// Initialize project handlers
 	handlers.GetOpenShiftProjectResource = k8s.GetOpenShiftProjectResource
@@ -84,10 +134,12 @@ func main() {
 	handlers.DynamicClientProjects = server.DynamicClient // Backend SA dynamic client for Project operations
 
 	// Initialize session handlers
-	handlers.GetAgenticSessionV1Alpha1Resource = k8s.GetAgenticSessionV1Alpha1Resource
 	handlers.DynamicClient = server.DynamicClient
+	handlers.VteamClient = server.VteamClient
 	handlers.GetGitHubToken = git.GetGitHubToken
+	handlers.GetScopedGitHubToken = git.GetScopedGitHubToken
 	handlers.DeriveRepoFolderFromURL = git.DeriveRepoFolderFromURL
+	handlers.SendSessionMessage = websocket.SendMessageToSession
 
 	// Initialize RFE workflow handlers
 	handlers.GetRFEWorkflowResource = k8s.GetRFEWorkflowResource
@@ -95,6 +147,9 @@ func main() {
 	handlers.PerformRepoSeeding = performRepoSeeding
 	handlers.CheckRepoSeeding = checkRepoSeeding
 	handlers.CheckBranchExists = checkBranchExists
+	handlers.PlanRepoSeeding = planRepoSeeding
+	handlers.DetectSeedDrift = git.DetectSeedDrift
+	handlers.UpgradeRepoSeeding = git.UpgradeRepoSeeding
 	handlers.RfeFromUnstructured = jira.RFEFromUnstructured
 
 	// Initialize Jira handler
@@ -112,9 +167,21 @@ func main() {
 	handlers.BaseKubeConfig = server.BaseKubeConfig
 	handlers.K8sClientMw = server.K8sClient
 
+	// Initialize audit log
+	auditLogger, err := audit.NewFromEnv(server.K8sClient, server.Namespace, server.StateBaseDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize audit log: %v", err)
+	}
+	handlers.AuditLogger = auditLogger
+	log.Printf("Audit log sinks: %v", auditLogger.Sinks())
+
 	// Initialize websocket package
 	websocket.StateBaseDir = server.StateBaseDir
 
+	// Report session progress back to GitHub (issue comments and PR Check Runs)
+	go handlers.WatchSessionsForGitHub()
+	go handlers.ReconcileWorkflowTrackers()
+
 	// Normal server mode - create closure to capture jiraHandler
 	registerRoutesWithJira := func(r *gin.Engine) {
 		registerRoutes(r, jiraHandler)
@@ -134,8 +201,13 @@ type gitRepositoryAdapter struct {
 }
 
 // Wrapper for git.PerformRepoSeeding that adapts *types.RFEWorkflow to git.Workflow interface
-func performRepoSeeding(ctx context.Context, wf *types.RFEWorkflow, branchName, githubToken, agentURL, agentBranch, agentPath, specKitRepo, specKitVersion, specKitTemplate string) (bool, error) {
-	return git.PerformRepoSeeding(ctx, &rfeWorkflowAdapter{wf: wf}, branchName, githubToken, agentURL, agentBranch, agentPath, specKitRepo, specKitVersion, specKitTemplate)
+func performRepoSeeding(ctx context.Context, wf *types.RFEWorkflow, branchName, githubToken string, src git.SeedSource) (bool, error) {
+	return git.PerformRepoSeeding(ctx, &rfeWorkflowAdapter{wf: wf}, branchName, githubToken, src)
+}
+
+// Wrapper for git.PlanRepoSeeding that adapts *types.RFEWorkflow to git.Workflow interface
+func planRepoSeeding(ctx context.Context, wf *types.RFEWorkflow, branchName, githubToken string, src git.SeedSource) (*git.SeedPlan, error) {
+	return git.PlanRepoSeeding(ctx, &rfeWorkflowAdapter{wf: wf}, branchName, githubToken, src)
 }
 
 // GetUmbrellaRepo implements git.Workflow interface
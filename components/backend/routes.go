@@ -19,13 +19,17 @@ func registerContentRoutes(r *gin.Engine) {
 
 func registerRoutes(r *gin.Engine, jiraHandler *jira.Handler) {
 	// API routes
-	api := r.Group("/api")
+	// Every mutating API request is recorded in the audit log
+	api := r.Group("/api", handlers.AuditMiddleware())
 	{
 		api.POST("/projects/:projectName/agentic-sessions/:sessionName/github/token", handlers.MintSessionGitHubToken)
 
 		projectGroup := api.Group("/projects/:projectName", handlers.ValidateProjectContext())
 		{
 			projectGroup.GET("/access", handlers.AccessCheck)
+			projectGroup.GET("/github/installation", handlers.GetProjectGitHubInstallationStatus)
+			projectGroup.PUT("/github/installation", handlers.LinkProjectGitHubInstallation)
+			projectGroup.DELETE("/github/installation", handlers.UnlinkProjectGitHubInstallation)
 			projectGroup.GET("/users/forks", handlers.ListUserForks)
 			projectGroup.POST("/users/forks", handlers.CreateUserFork)
 
@@ -58,17 +62,24 @@ func registerRoutes(r *gin.Engine, jiraHandler *jira.Handler) {
 			projectGroup.GET("/rfe-workflows/:id", handlers.GetProjectRFEWorkflow)
 			projectGroup.PUT("/rfe-workflows/:id", handlers.UpdateProjectRFEWorkflow)
 			projectGroup.GET("/rfe-workflows/:id/summary", handlers.GetProjectRFEWorkflowSummary)
+			projectGroup.POST("/rfe-workflows/:id/advance", handlers.AdvanceProjectRFEWorkflowPhase)
+			projectGroup.GET("/rfe-workflows/:id/approvals", handlers.GetProjectRFEWorkflowApprovals)
+			projectGroup.POST("/rfe-workflows/:id/approvals", handlers.RecordProjectRFEWorkflowApproval)
 			projectGroup.DELETE("/rfe-workflows/:id", handlers.DeleteProjectRFEWorkflow)
 			projectGroup.POST("/rfe-workflows/:id/seed", handlers.SeedProjectRFEWorkflow)
 			projectGroup.GET("/rfe-workflows/:id/check-seeding", handlers.CheckProjectRFEWorkflowSeeding)
+			projectGroup.GET("/rfe-workflows/:id/seed/drift", handlers.GetProjectRFEWorkflowSeedDrift)
+			projectGroup.POST("/rfe-workflows/:id/seed/upgrade", handlers.UpgradeProjectRFEWorkflowSeed)
 			projectGroup.GET("/rfe-workflows/:id/agents", handlers.GetProjectRFEWorkflowAgents)
 
 			projectGroup.GET("/sessions/:sessionId/ws", websocket.HandleSessionWebSocket)
 			projectGroup.GET("/sessions/:sessionId/messages", websocket.GetSessionMessagesWS)
 			// Removed: /messages/claude-format - Using SDK's built-in resume with persisted ~/.claude state
 			projectGroup.POST("/sessions/:sessionId/messages", websocket.PostSessionMessageWS)
-			projectGroup.POST("/rfe-workflows/:id/jira", jiraHandler.PublishWorkflowFileToJira)
-			projectGroup.GET("/rfe-workflows/:id/jira", handlers.GetWorkflowJira)
+			projectGroup.POST("/rfe-workflows/:id/tracker", jiraHandler.PublishWorkflowFile)
+			projectGroup.GET("/rfe-workflows/:id/tracker", handlers.GetWorkflowTrackerIssue)
+			projectGroup.POST("/rfe-workflows/:id/jira", jiraHandler.PublishWorkflowFile)
+			projectGroup.GET("/rfe-workflows/:id/jira", handlers.GetWorkflowTrackerIssue)
 			projectGroup.GET("/rfe-workflows/:id/sessions", handlers.ListProjectRFEWorkflowSessions)
 			projectGroup.POST("/rfe-workflows/:id/sessions/link", handlers.AddProjectRFEWorkflowSession)
 			projectGroup.DELETE("/rfe-workflows/:id/sessions/:sessionName", handlers.RemoveProjectRFEWorkflowSession)
@@ -80,12 +91,17 @@ func registerRoutes(r *gin.Engine, jiraHandler *jira.Handler) {
 			projectGroup.GET("/keys", handlers.ListProjectKeys)
 			projectGroup.POST("/keys", handlers.CreateProjectKey)
 			projectGroup.DELETE("/keys/:keyId", handlers.DeleteProjectKey)
+			projectGroup.POST("/keys/:keyId/rotate", handlers.RotateProjectKey)
 
 			projectGroup.GET("/secrets", handlers.ListNamespaceSecrets)
 			projectGroup.GET("/runner-secrets/config", handlers.GetRunnerSecretsConfig)
 			projectGroup.PUT("/runner-secrets/config", handlers.UpdateRunnerSecretsConfig)
 			projectGroup.GET("/runner-secrets", handlers.ListRunnerSecrets)
 			projectGroup.PUT("/runner-secrets", handlers.UpdateRunnerSecrets)
+
+			projectGroup.GET("/session-templates", handlers.ListSessionTemplates)
+
+			projectGroup.GET("/audit", handlers.GetProjectAuditLog)
 		}
 
 		api.POST("/auth/github/install", handlers.LinkGitHubInstallationGlobal)
@@ -93,9 +109,15 @@ func registerRoutes(r *gin.Engine, jiraHandler *jira.Handler) {
 		api.POST("/auth/github/disconnect", handlers.DisconnectGitHubGlobal)
 		api.GET("/auth/github/user/callback", handlers.HandleGitHubUserOAuthCallback)
 
+		// GitHub App webhook receiver (authenticated by signature, not by user token)
+		api.POST("/webhooks/github", handlers.HandleGitHubWebhook)
+		// Jira webhook receiver (authenticated by signature or shared token)
+		api.POST("/webhooks/jira", handlers.HandleJiraWebhook)
+
 		// Cluster info endpoint (public, no auth required)
 		api.GET("/cluster-info", handlers.GetClusterInfo)
 
+		api.GET("/project-templates", handlers.ListProjectTemplates)
 		api.GET("/projects", handlers.ListProjects)
 		api.POST("/projects", handlers.CreateProject)
 		api.GET("/projects/:projectName", handlers.GetProject)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	vteamclient "ambient-code-api/clientset/versioned"
+
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -11,12 +13,16 @@ import (
 )
 
 var (
-	K8sClient      *kubernetes.Clientset
-	DynamicClient  dynamic.Interface
-	Namespace      string
-	StateBaseDir   string
-	PvcBaseDir     string
-	BaseKubeConfig *rest.Config
+	K8sClient        *kubernetes.Clientset
+	DynamicClient    dynamic.Interface
+	VteamClient      vteamclient.Interface
+	Namespace        string
+	StateBaseDir     string
+	PvcBaseDir       string
+	RunnerSecretsDir string
+	// GitSigningConfigured is set by the operator when the project has a commit signing key
+	GitSigningConfigured bool
+	BaseKubeConfig       *rest.Config
 )
 
 // InitK8sClients initializes Kubernetes clients and configuration
@@ -49,6 +55,12 @@ func InitK8sClients() error {
 		return fmt.Errorf("failed to create dynamic client: %v", err)
 	}
 
+	// Create typed client for AgenticSessions
+	VteamClient, err = vteamclient.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create vteam client: %v", err)
+	}
+
 	// Save base config for per-request impersonation/user-token clients
 	BaseKubeConfig = config
 
@@ -74,4 +86,11 @@ func InitConfig() {
 	if PvcBaseDir == "" {
 		PvcBaseDir = "/workspace"
 	}
+
+	// Get mount path of the git signing key (content service mode)
+	RunnerSecretsDir = os.Getenv("RUNNER_SECRETS_DIR")
+	if RunnerSecretsDir == "" {
+		RunnerSecretsDir = "/var/run/runner-secrets"
+	}
+	GitSigningConfigured = os.Getenv("GIT_SIGNING_CONFIGURED") == "true"
 }
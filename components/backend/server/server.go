@@ -1,11 +1,17 @@
 package server
 
 import (
+	"crypto/tls"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
+
+	"ambient-code-backend/audit"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -25,14 +31,19 @@ func Run(registerRoutes RouterFunc) error {
 		if strings.Contains(param.Request.URL.RawQuery, "token=") {
 			path = strings.Split(path, "?")[0] + "?token=[REDACTED]"
 		}
-		return fmt.Sprintf("[GIN] %s | %3d | %s | %s\n",
+		requestID, _ := param.Keys["requestID"].(string)
+		return fmt.Sprintf("[GIN] %s | %3d | %s | %s | %s\n",
 			param.Method,
 			param.StatusCode,
 			param.ClientIP,
+			requestID,
 			path,
 		)
 	}))
 
+	// Assign each request an ID (or keep the caller's) for correlating logs and audit events
+	r.Use(requestIDMiddleware())
+
 	// Middleware to populate user context from forwarded headers
 	r.Use(forwardedIdentityMiddleware())
 
@@ -40,7 +51,8 @@ func Run(registerRoutes RouterFunc) error {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID"}
+	config.ExposeHeaders = []string{"X-Request-ID"}
 	r.Use(cors.New(config))
 
 	// Register routes
@@ -62,6 +74,23 @@ func Run(registerRoutes RouterFunc) error {
 	return nil
 }
 
+// requestIDPattern bounds caller-supplied request IDs so they are safe to log and store
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestIDMiddleware sets "requestID" in the context and the X-Request-ID response header,
+// reusing a well-formed X-Request-ID from the caller (e.g. the OAuth proxy or frontend)
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := strings.TrimSpace(c.GetHeader("X-Request-ID"))
+		if !requestIDPattern.MatchString(id) {
+			id = audit.NewID()
+		}
+		c.Set("requestID", id)
+		c.Header("X-Request-ID", id)
+		c.Next()
+	}
+}
+
 // forwardedIdentityMiddleware populates Gin context from common OAuth proxy headers
 func forwardedIdentityMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -129,3 +158,93 @@ func RunContentService(registerContentRoutes RouterFunc) error {
 	}
 	return nil
 }
+
+// RunAdmissionWebhook serves the admission webhook handler over TLS. The serving certificate is
+// re-read when it changes on disk so rotated certificates (e.g. from the OpenShift service CA)
+// are picked up without a restart.
+func RunAdmissionWebhook(handler http.Handler) error {
+	certFile := os.Getenv("ADMISSION_TLS_CERT_FILE")
+	if certFile == "" {
+		certFile = "/etc/admission-tls/tls.crt"
+	}
+	keyFile := os.Getenv("ADMISSION_TLS_KEY_FILE")
+	if keyFile == "" {
+		keyFile = "/etc/admission-tls/tls.key"
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8443"
+	}
+
+	certs := &reloadingCertificate{certFile: certFile, keyFile: keyFile}
+	if _, err := certs.get(nil); err != nil {
+		return fmt.Errorf("failed to load webhook certificate: %v", err)
+	}
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.get,
+		},
+	}
+	log.Printf("Admission webhook starting on port %s", port)
+	if err := srv.ListenAndServeTLS("", ""); err != nil {
+		return fmt.Errorf("failed to start admission webhook: %v", err)
+	}
+	return nil
+}
+
+// RunEgressProxy serves the session egress proxy on PORT (default 3128). It listens on loopback
+// only, since it runs as a sidecar next to the containers it serves.
+func RunEgressProxy(handler http.Handler) error {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "3128"
+	}
+	srv := &http.Server{
+		Addr:              "127.0.0.1:" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	log.Printf("Egress proxy starting on port %s", port)
+	if err := srv.ListenAndServe(); err != nil {
+		return fmt.Errorf("failed to start egress proxy: %v", err)
+	}
+	return nil
+}
+
+// reloadingCertificate caches a key pair until the certificate file's modification time changes
+type reloadingCertificate struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func (r *reloadingCertificate) get(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info, err := os.Stat(r.certFile)
+	if err != nil {
+		if r.cert != nil {
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	if r.cert != nil && info.ModTime().Equal(r.modTime) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		if r.cert != nil {
+			log.Printf("admission webhook: keeping previous certificate: %v", err)
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert, r.modTime = &cert, info.ModTime()
+	return r.cert, nil
+}
@@ -0,0 +1,227 @@
+package tracker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// adfNode is an Atlassian Document Format node as sent to Jira Cloud REST API v3
+type adfNode = map[string]interface{}
+
+// MarkdownToADF converts markdown to an Atlassian Document Format document
+func MarkdownToADF(markdown string) adfNode {
+	r := &adfRenderer{}
+	content := r.blocks(parseMarkdown(markdown))
+	if len(content) == 0 {
+		content = []interface{}{adfNode{"type": "paragraph", "content": []interface{}{}}}
+	}
+	return adfNode{"type": "doc", "version": 1, "content": content}
+}
+
+type adfRenderer struct {
+	nextID int
+}
+
+// localID returns a document-unique id for task lists and items
+func (r *adfRenderer) localID() string {
+	r.nextID++
+	return fmt.Sprintf("task-%d", r.nextID)
+}
+
+func (r *adfRenderer) blocks(blocks []mdBlock) []interface{} {
+	out := []interface{}{}
+	for _, b := range blocks {
+		if n := r.block(b); n != nil {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func (r *adfRenderer) block(b mdBlock) adfNode {
+	switch b.Kind {
+	case blockHeading:
+		return adfNode{"type": "heading", "attrs": adfNode{"level": b.Level}, "content": adfInline(b.Text)}
+	case blockCode:
+		node := adfNode{"type": "codeBlock", "content": []interface{}{}}
+		if b.Lang != "" {
+			node["attrs"] = adfNode{"language": b.Lang}
+		}
+		if b.Text != "" {
+			node["content"] = []interface{}{adfNode{"type": "text", "text": b.Text}}
+		}
+		return node
+	case blockRule:
+		return adfNode{"type": "rule"}
+	case blockQuote:
+		// Block quotes may only contain paragraphs, lists and code blocks
+		content := []interface{}{}
+		for _, child := range b.Children {
+			switch child.Kind {
+			case blockHeading:
+				child = mdBlock{Kind: blockParagraph, Text: "**" + child.Text + "**"}
+			case blockTable, blockQuote, blockRule:
+				child = mdBlock{Kind: blockParagraph, Text: plainText([]mdBlock{child})}
+			}
+			if n := r.block(child); n != nil {
+				content = append(content, n)
+			}
+		}
+		return adfNode{"type": "blockquote", "content": content}
+	case blockTable:
+		return r.table(b)
+	case blockList:
+		return r.list(b)
+	default:
+		return adfNode{"type": "paragraph", "content": adfInline(b.Text)}
+	}
+}
+
+func (r *adfRenderer) table(b mdBlock) adfNode {
+	cols := 0
+	for _, row := range b.Rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	rows := []interface{}{}
+	for i, row := range b.Rows {
+		cellType := "tableCell"
+		if i == 0 {
+			cellType = "tableHeader"
+		}
+		cells := []interface{}{}
+		for c := 0; c < cols; c++ {
+			text := ""
+			if c < len(row) {
+				text = row[c]
+			}
+			cells = append(cells, adfNode{
+				"type":    cellType,
+				"attrs":   adfNode{},
+				"content": []interface{}{adfNode{"type": "paragraph", "content": adfInline(text)}},
+			})
+		}
+		rows = append(rows, adfNode{"type": "tableRow", "content": cells})
+	}
+	return adfNode{
+		"type":    "table",
+		"attrs":   adfNode{"isNumberColumnEnabled": false, "layout": "default"},
+		"content": rows,
+	}
+}
+
+func isTaskList(b mdBlock) bool {
+	if b.Kind != blockList || len(b.Items) == 0 {
+		return false
+	}
+	for _, it := range b.Items {
+		if !it.Task {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *adfRenderer) list(b mdBlock) adfNode {
+	if isTaskList(b) {
+		return r.taskList(b)
+	}
+	items := []interface{}{}
+	for _, it := range b.Items {
+		text := it.Text
+		if it.Task {
+			// Mixed lists cannot use taskItem; keep the checkbox visible
+			if it.Checked {
+				text = "☑ " + text
+			} else {
+				text = "☐ " + text
+			}
+		}
+		content := []interface{}{adfNode{"type": "paragraph", "content": adfInline(text)}}
+		for _, child := range it.Children {
+			switch child.Kind {
+			case blockParagraph, blockList, blockCode:
+			default:
+				// List items only accept paragraphs, nested lists and code blocks
+				child = mdBlock{Kind: blockParagraph, Text: plainText([]mdBlock{child})}
+			}
+			if n := r.block(child); n != nil {
+				content = append(content, n)
+			}
+		}
+		items = append(items, adfNode{"type": "listItem", "content": content})
+	}
+	node := adfNode{"type": "bulletList", "content": items}
+	if b.Ordered {
+		node["type"] = "orderedList"
+		node["attrs"] = adfNode{"order": b.Start}
+	}
+	return node
+}
+
+func (r *adfRenderer) taskList(b mdBlock) adfNode {
+	content := []interface{}{}
+	for _, it := range b.Items {
+		state := "TODO"
+		if it.Checked {
+			state = "DONE"
+		}
+		inline := adfInline(it.Text)
+		var nested []interface{}
+		for _, child := range it.Children {
+			if isTaskList(child) {
+				nested = append(nested, r.taskList(child))
+				continue
+			}
+			// Task items hold inline content only; fold other children in as text
+			if text := plainText([]mdBlock{child}); text != "" {
+				for _, line := range strings.Split(text, "\n") {
+					inline = append(inline, adfNode{"type": "hardBreak"})
+					inline = append(inline, adfInline(line)...)
+				}
+			}
+		}
+		content = append(content, adfNode{
+			"type":    "taskItem",
+			"attrs":   adfNode{"localId": r.localID(), "state": state},
+			"content": inline,
+		})
+		content = append(content, nested...)
+	}
+	return adfNode{"type": "taskList", "attrs": adfNode{"localId": r.localID()}, "content": content}
+}
+
+// adfInline converts inline markdown to ADF text nodes
+func adfInline(s string) []interface{} {
+	out := []interface{}{}
+	for _, span := range parseInline(s) {
+		if span.Text == "" {
+			continue
+		}
+		node := adfNode{"type": "text", "text": span.Text}
+		marks := []interface{}{}
+		// The code mark may only be combined with link
+		if span.Code {
+			marks = append(marks, adfNode{"type": "code"})
+		} else {
+			if span.Strong {
+				marks = append(marks, adfNode{"type": "strong"})
+			}
+			if span.Em {
+				marks = append(marks, adfNode{"type": "em"})
+			}
+			if span.Strike {
+				marks = append(marks, adfNode{"type": "strike"})
+			}
+		}
+		if span.Href != "" {
+			marks = append(marks, adfNode{"type": "link", "attrs": adfNode{"href": span.Href}})
+		}
+		if len(marks) > 0 {
+			node["marks"] = marks
+		}
+		out = append(out, node)
+	}
+	return out
+}
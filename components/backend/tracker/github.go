@@ -0,0 +1,433 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// maxGitHubCommentBody is GitHub's limit for issue and comment bodies
+const maxGitHubCommentBody = 65536
+
+// maxGitHubPages bounds paginated listings, at 100 items per page
+const maxGitHubPages = 50
+
+// GitHubTracker publishes issues to a GitHub repository. Keys have the form owner/name#number.
+type GitHubTracker struct {
+	Host       string
+	Repository string // owner/name
+	Labels     []string
+	token      string
+	client     *http.Client
+}
+
+// githubKindLabels labels issues with their role in the RFE hierarchy
+var githubKindLabels = map[IssueKind]string{
+	KindRequest: "feature-request",
+	KindFeature: "feature",
+	KindEpic:    "epic",
+	KindStory:   "task",
+	KindSubTask: "sub-task",
+}
+
+// NewGitHubTracker creates a GitHub Issues tracker for repository (owner/name) on host
+func NewGitHubTracker(host, repository, token string, labels []string) *GitHubTracker {
+	if host == "" {
+		host = "github.com"
+	}
+	return &GitHubTracker{
+		Host:       host,
+		Repository: repository,
+		Labels:     labels,
+		token:      token,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Type implements IssueTracker
+func (g *GitHubTracker) Type() string { return TypeGitHub }
+
+func (g *GitHubTracker) apiBase() string {
+	if g.Host == "github.com" {
+		return "https://api.github.com"
+	}
+	return fmt.Sprintf("https://%s/api/v3", g.Host)
+}
+
+// parseKey splits owner/name#number, defaulting to the tracker repository for bare numbers
+func (g *GitHubTracker) parseKey(key string) (string, int, error) {
+	repo := g.Repository
+	num := strings.TrimPrefix(strings.TrimSpace(key), "#")
+	if i := strings.LastIndex(key, "#"); i > 0 {
+		repo, num = key[:i], key[i+1:]
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 || !strings.Contains(repo, "/") {
+		return "", 0, fmt.Errorf("invalid GitHub issue key %q", key)
+	}
+	return repo, n, nil
+}
+
+// do sends a JSON request and decodes a JSON response into out (when non-nil)
+func (g *GitHubTracker) do(ctx context.Context, method, path string, in interface{}, out interface{}) error {
+	_, err := g.send(ctx, method, g.apiBase()+path, in, out)
+	return err
+}
+
+// list GETs path and each following page named by the Link header's rel="next", passing every
+// page to decode. Pages are only followed on the tracker's own API host.
+func (g *GitHubTracker) list(ctx context.Context, path string, decode func(page []byte) error) error {
+	next := g.apiBase() + path
+	for i := 0; next != "" && i < maxGitHubPages; i++ {
+		var page json.RawMessage
+		header, err := g.send(ctx, http.MethodGet, next, nil, &page)
+		if err != nil {
+			return err
+		}
+		if err := decode(page); err != nil {
+			return fmt.Errorf("failed to decode github response: %w", err)
+		}
+		next = nextPageURL(header.Get("Link"))
+		if next != "" && !strings.HasPrefix(next, g.apiBase()+"/") {
+			return fmt.Errorf("github pagination left the API host: %s", next)
+		}
+	}
+	return nil
+}
+
+// nextPageURL returns the rel="next" target of a Link header, or ""
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segs := strings.Split(part, ";")
+		if len(segs) < 2 {
+			continue
+		}
+		for _, param := range segs[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(strings.TrimSpace(segs[0]), "<>")
+			}
+		}
+	}
+	return ""
+}
+
+// send makes a request to an absolute API URL, decoding a JSON response into out (when non-nil)
+func (g *GitHubTracker) send(ctx context.Context, method, apiURL string, in interface{}, out interface{}) (http.Header, error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "token "+g.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("github request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, apiError("github", resp.Status, respBody)
+	}
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return nil, fmt.Errorf("failed to decode github response: %w", err)
+		}
+	}
+	return resp.Header, nil
+}
+
+type githubIssue struct {
+	ID        int64  `json:"id"`
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	State     string `json:"state"`
+	HTMLURL   string `json:"html_url"`
+	UpdatedAt string `json:"updated_at"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+}
+
+func truncateGitHubBody(body string) string {
+	if len(body) <= maxGitHubCommentBody {
+		return body
+	}
+	return TruncateUTF8(body, maxGitHubCommentBody-32) + "\n\n_(truncated)_"
+}
+
+// TruncateUTF8 returns at most n bytes of s, cut on a rune boundary so a multi-byte character
+// is never split
+func TruncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
+
+// CreateIssue implements IssueTracker
+func (g *GitHubTracker) CreateIssue(ctx context.Context, issue Issue) (*IssueRef, error) {
+	labels := append([]string{}, g.Labels...)
+	if l := githubKindLabels[issue.Kind]; l != "" {
+		labels = append(labels, l)
+	}
+	var created githubIssue
+	payload := map[string]interface{}{
+		"title":  issue.Title,
+		"body":   truncateGitHubBody(issue.Body),
+		"labels": labels,
+	}
+	if err := g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues", g.Repository), payload, &created); err != nil {
+		return nil, err
+	}
+	ref := &IssueRef{Key: fmt.Sprintf("%s#%d", g.Repository, created.Number), URL: created.HTMLURL}
+	if issue.ParentKey != "" {
+		if err := g.LinkParent(ctx, ref.Key, issue.ParentKey); err != nil {
+			log.Printf("GitHubTracker: failed to link %s to parent %s: %v", ref.Key, issue.ParentKey, err)
+		}
+	}
+	return ref, nil
+}
+
+// UpdateIssue implements IssueTracker
+func (g *GitHubTracker) UpdateIssue(ctx context.Context, key string, issue Issue) (*IssueRef, error) {
+	repo, num, err := g.parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var updated githubIssue
+	payload := map[string]interface{}{"title": issue.Title, "body": truncateGitHubBody(issue.Body)}
+	if err := g.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", repo, num), payload, &updated); err != nil {
+		return nil, err
+	}
+	return &IssueRef{Key: key, URL: updated.HTMLURL}, nil
+}
+
+// AttachFile implements IssueTracker. GitHub has no attachment API for issues, so the file
+// is posted as a collapsed comment.
+func (g *GitHubTracker) AttachFile(ctx context.Context, key, filename string, content []byte) error {
+	repo, num, err := g.parseKey(key)
+	if err != nil {
+		return err
+	}
+	fence := "```"
+	for strings.Contains(string(content), fence) {
+		fence += "`"
+	}
+	body := fmt.Sprintf("<details>\n<summary>%s</summary>\n\n%s\n%s\n%s\n\n</details>", filename, fence, string(content), fence)
+	return g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, num), map[string]string{"body": truncateGitHubBody(body)}, nil)
+}
+
+// LinkParent implements IssueTracker using sub-issues, falling back to a reference comment
+// where sub-issues are unavailable (e.g. older GitHub Enterprise Server)
+func (g *GitHubTracker) LinkParent(ctx context.Context, key, parentKey string) error {
+	repo, num, err := g.parseKey(key)
+	if err != nil {
+		return err
+	}
+	parentRepo, parentNum, err := g.parseKey(parentKey)
+	if err != nil {
+		return err
+	}
+	var child githubIssue
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/issues/%d", repo, num), nil, &child); err != nil {
+		return err
+	}
+	subErr := g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/sub_issues", parentRepo, parentNum), map[string]int64{"sub_issue_id": child.ID}, nil)
+	if subErr == nil {
+		return nil
+	}
+	log.Printf("GitHubTracker: sub-issue link %s -> %s failed (%v); adding reference comment", key, parentKey, subErr)
+	ref := fmt.Sprintf("#%d", parentNum)
+	if parentRepo != repo {
+		ref = fmt.Sprintf("%s#%d", parentRepo, parentNum)
+	}
+	return g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, num), map[string]string{"body": "Part of " + ref}, nil)
+}
+
+// LinkDependency implements IssueTracker using issue dependencies, falling back to a
+// reference comment where the dependencies API is unavailable
+func (g *GitHubTracker) LinkDependency(ctx context.Context, key, blockerKey string) error {
+	repo, num, err := g.parseKey(key)
+	if err != nil {
+		return err
+	}
+	blockerRepo, blockerNum, err := g.parseKey(blockerKey)
+	if err != nil {
+		return err
+	}
+	var blocker githubIssue
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/issues/%d", blockerRepo, blockerNum), nil, &blocker); err != nil {
+		return err
+	}
+	depErr := g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/dependencies/blocked_by", repo, num), map[string]int64{"issue_id": blocker.ID}, nil)
+	if depErr == nil {
+		return nil
+	}
+	log.Printf("GitHubTracker: dependency link %s -> %s failed (%v); adding reference comment", key, blockerKey, depErr)
+	ref := fmt.Sprintf("#%d", blockerNum)
+	if blockerRepo != repo {
+		ref = fmt.Sprintf("%s#%d", blockerRepo, blockerNum)
+	}
+	return g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, num), map[string]string{"body": "Blocked by " + ref}, nil)
+}
+
+// reBlockedByComment matches the reference comments LinkDependency falls back to
+var reBlockedByComment = regexp.MustCompile(`^Blocked by ([\w.-]+/[\w.-]+)?#(\d+)$`)
+
+// ListBlockers implements IssueTracker from issue dependencies, or from the reference comments
+// LinkDependency leaves where the dependencies API is unavailable
+func (g *GitHubTracker) ListBlockers(ctx context.Context, key string) ([]string, error) {
+	repo, num, err := g.parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	type githubBlocker struct {
+		Number        int    `json:"number"`
+		RepositoryURL string `json:"repository_url"`
+	}
+	var blockers []githubBlocker
+	depErr := g.list(ctx, fmt.Sprintf("/repos/%s/issues/%d/dependencies/blocked_by?per_page=100", repo, num), func(page []byte) error {
+		var items []githubBlocker
+		if err := json.Unmarshal(page, &items); err != nil {
+			return err
+		}
+		blockers = append(blockers, items...)
+		return nil
+	})
+	var keys []string
+	if depErr == nil {
+		for _, b := range blockers {
+			blockerRepo := repo
+			if i := strings.Index(b.RepositoryURL, "/repos/"); i >= 0 {
+				blockerRepo = b.RepositoryURL[i+len("/repos/"):]
+			}
+			keys = append(keys, fmt.Sprintf("%s#%d", blockerRepo, b.Number))
+		}
+	}
+	comments, err := g.ListComments(ctx, key, time.Time{})
+	if err != nil {
+		if depErr != nil {
+			return nil, depErr
+		}
+		return keys, nil
+	}
+	for _, cm := range comments {
+		m := reBlockedByComment.FindStringSubmatch(strings.TrimSpace(cm.Body))
+		if m == nil {
+			continue
+		}
+		blockerRepo := m[1]
+		if blockerRepo == "" {
+			blockerRepo = repo
+		}
+		keys = append(keys, blockerRepo+"#"+m[2])
+	}
+	return keys, nil
+}
+
+// GetStatus implements IssueTracker
+func (g *GitHubTracker) GetStatus(ctx context.Context, key string) (*IssueStatus, error) {
+	repo, num, err := g.parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	var issue githubIssue
+	if err := g.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/issues/%d", repo, num), nil, &issue); err != nil {
+		return nil, err
+	}
+	assignee := ""
+	if len(issue.Assignees) > 0 {
+		assignee = issue.Assignees[0].Login
+	}
+	category := CategoryOpen
+	if issue.State == "closed" {
+		category = CategoryDone
+	} else if len(issue.Assignees) > 0 {
+		category = CategoryInProgress
+	}
+	return &IssueStatus{
+		Tracker:  TypeGitHub,
+		Key:      fmt.Sprintf("%s#%d", repo, issue.Number),
+		URL:      issue.HTMLURL,
+		Title:    issue.Title,
+		Status:   issue.State,
+		Category: category,
+		Assignee: assignee,
+		Updated:  issue.UpdatedAt,
+	}, nil
+}
+
+// ListComments implements IssueTracker
+func (g *GitHubTracker) ListComments(ctx context.Context, key string, since time.Time) ([]IssueComment, error) {
+	repo, num, err := g.parseKey(key)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100", repo, num)
+	if !since.IsZero() {
+		path += "&since=" + url.QueryEscape(since.UTC().Format(time.RFC3339))
+	}
+	type githubComment struct {
+		ID   int64  `json:"id"`
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+		AuthorAssociation string    `json:"author_association"`
+		CreatedAt         time.Time `json:"created_at"`
+	}
+	var comments []githubComment
+	err = g.list(ctx, path, func(page []byte) error {
+		var items []githubComment
+		if err := json.Unmarshal(page, &items); err != nil {
+			return err
+		}
+		comments = append(comments, items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out []IssueComment
+	for _, cm := range comments {
+		// since filters on update time; only report comments created afterwards
+		if !since.IsZero() && !cm.CreatedAt.After(since) {
+			continue
+		}
+		out = append(out, IssueComment{
+			ID:                strconv.FormatInt(cm.ID, 10),
+			Author:            cm.User.Login,
+			AuthorID:          cm.User.Login,
+			AuthorAssociation: cm.AuthorAssociation,
+			Body:              cm.Body,
+			Created:           cm.CreatedAt,
+		})
+	}
+	return out, nil
+}
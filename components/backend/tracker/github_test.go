@@ -0,0 +1,66 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestTruncateGitHubBodyKeepsRunesWhole(t *testing.T) {
+	// Three-byte runes put a rune boundary off the byte cut point
+	body := "a" + strings.Repeat("€", maxGitHubCommentBody)
+	got := truncateGitHubBody(body)
+	if !utf8.ValidString(got) {
+		t.Fatal("truncated body is not valid UTF-8")
+	}
+	if len(got) > maxGitHubCommentBody {
+		t.Errorf("truncated body is %d bytes; want at most %d", len(got), maxGitHubCommentBody)
+	}
+	if !strings.HasSuffix(got, "_(truncated)_") {
+		t.Errorf("truncated body lacks the truncation marker")
+	}
+	if short := "short body"; truncateGitHubBody(short) != short {
+		t.Errorf("short body was changed")
+	}
+}
+
+func TestListCommentsFollowsPagination(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/acme/app/issues/7/comments" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v3/repos/acme/app/issues/7/comments?per_page=100&page=2>; rel="next", <%s/api/v3/repos/acme/app/issues/7/comments?per_page=100&page=2>; rel="last"`, srv.URL, srv.URL))
+			fmt.Fprint(w, `[{"id":1,"body":"first","user":{"login":"a"}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":2,"body":"Blocked by #3","user":{"login":"b"}}]`)
+	}))
+	defer srv.Close()
+
+	g := NewGitHubTracker(strings.TrimPrefix(srv.URL, "https://"), "acme/app", "token", nil)
+	g.client = srv.Client()
+	comments, err := g.ListComments(context.Background(), "acme/app#7", time.Time{})
+	if err != nil {
+		t.Fatalf("ListComments: %v", err)
+	}
+	if len(comments) != 2 || comments[1].Body != "Blocked by #3" {
+		t.Fatalf("comments = %+v; want both pages", comments)
+	}
+
+	// The dependencies API 404s here, so blockers come from the second page's reference comment
+	blockers, err := g.ListBlockers(context.Background(), "acme/app#7")
+	if err != nil {
+		t.Fatalf("ListBlockers: %v", err)
+	}
+	if len(blockers) != 1 || blockers[0] != "acme/app#3" {
+		t.Fatalf("blockers = %v; want [acme/app#3]", blockers)
+	}
+}
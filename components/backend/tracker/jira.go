@@ -0,0 +1,379 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// JiraTracker publishes issues through the Jira REST API. Jira Cloud uses REST API v3 with
+// descriptions in Atlassian Document Format; Server/Data Center uses v2 with wiki markup.
+type JiraTracker struct {
+	BaseURL    string
+	ProjectKey string
+	IssueTypes map[IssueKind]string
+	// Cloud selects REST API v3 and ADF descriptions (defaults to IsJiraCloud(BaseURL))
+	Cloud      bool
+	authHeader string
+	client     *http.Client
+
+	// epicLinkField caches the Server/DC "Epic Link" custom field id ("" = not yet looked up)
+	epicLinkField *string
+}
+
+// jiraDependencyLinkType is the issue link type used for task dependencies
+const jiraDependencyLinkType = "Blocks"
+
+// NewJiraTracker creates a Jira tracker. With an email, Cloud-style basic auth is used;
+// otherwise Atlassian Cloud tokens are expected in email:api_token form and any other
+// host is treated as Server/Data Center with a personal access token.
+func NewJiraTracker(baseURL, projectKey, email, token string, issueTypes map[IssueKind]string) *JiraTracker {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	authHeader := ""
+	switch {
+	case strings.TrimSpace(email) != "":
+		authHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte(strings.TrimSpace(email)+":"+token))
+	case IsJiraCloud(baseURL):
+		authHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte(token))
+	default:
+		authHeader = "Bearer " + token
+	}
+	if issueTypes == nil {
+		issueTypes = DefaultJiraIssueTypes
+	}
+	return &JiraTracker{
+		BaseURL:    baseURL,
+		ProjectKey: projectKey,
+		IssueTypes: issueTypes,
+		Cloud:      IsJiraCloud(baseURL),
+		authHeader: authHeader,
+		client:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// IsJiraCloud reports whether a Jira base URL points at Atlassian Cloud
+func IsJiraCloud(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return strings.Contains(baseURL, "atlassian.net")
+	}
+	return strings.HasSuffix(strings.ToLower(u.Hostname()), ".atlassian.net")
+}
+
+// Type implements IssueTracker
+func (j *JiraTracker) Type() string { return TypeJira }
+
+// issuePath returns the REST path of the issue endpoint (with optional key and suffix)
+// for the API version matching the deployment
+func (j *JiraTracker) issuePath(key, suffix string) string {
+	p := "/rest/api/2/issue"
+	if j.Cloud {
+		p = "/rest/api/3/issue"
+	}
+	if key != "" {
+		p += "/" + url.PathEscape(key)
+	}
+	return p + suffix
+}
+
+// description renders a markdown body in the format the deployment's API expects
+func (j *JiraTracker) description(markdown string) interface{} {
+	if j.Cloud {
+		return MarkdownToADF(markdown)
+	}
+	return MarkdownToWiki(markdown)
+}
+
+// IssueURL returns the browser URL of an issue
+func (j *JiraTracker) IssueURL(key string) string {
+	return fmt.Sprintf("%s/browse/%s", j.BaseURL, key)
+}
+
+// do sends a JSON request and decodes a JSON response into out (when non-nil)
+func (j *JiraTracker) do(ctx context.Context, method, path string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, j.BaseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", j.authHeader)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return apiError("jira", resp.Status, respBody)
+	}
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("failed to decode jira response: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateIssue implements IssueTracker
+func (j *JiraTracker) CreateIssue(ctx context.Context, issue Issue) (*IssueRef, error) {
+	issueType := j.IssueTypes[issue.Kind]
+	if issueType == "" {
+		issueType = DefaultJiraIssueTypes[KindFeature]
+	}
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": j.ProjectKey},
+		"summary":     issue.Title,
+		"description": j.description(issue.Body),
+		"issuetype":   map[string]string{"name": issueType},
+	}
+	if issue.ParentKey != "" {
+		// Stories join their Epic through the "Epic Link" field on Server/Data Center;
+		// Cloud (and sub-tasks everywhere) use parent
+		epicField := ""
+		if issue.Kind == KindStory && !j.Cloud {
+			epicField = j.epicLinkFieldID(ctx)
+		}
+		if epicField != "" {
+			fields[epicField] = issue.ParentKey
+		} else {
+			fields["parent"] = map[string]string{"key": issue.ParentKey}
+		}
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(ctx, http.MethodPost, j.issuePath("", ""), map[string]interface{}{"fields": fields}, &created); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(created.Key) == "" {
+		return nil, fmt.Errorf("jira creation returned no key")
+	}
+	return &IssueRef{Key: created.Key, URL: j.IssueURL(created.Key)}, nil
+}
+
+// UpdateIssue implements IssueTracker
+func (j *JiraTracker) UpdateIssue(ctx context.Context, key string, issue Issue) (*IssueRef, error) {
+	fields := map[string]interface{}{
+		"summary":     issue.Title,
+		"description": j.description(issue.Body),
+	}
+	if err := j.do(ctx, http.MethodPut, j.issuePath(key, ""), map[string]interface{}{"fields": fields}, nil); err != nil {
+		return nil, err
+	}
+	return &IssueRef{Key: key, URL: j.IssueURL(key)}, nil
+}
+
+// AttachFile implements IssueTracker
+func (j *JiraTracker) AttachFile(ctx context.Context, key, filename string, content []byte) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return fmt.Errorf("failed to write content: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close writer: %w", err)
+	}
+
+	endpoint := j.BaseURL + j.issuePath(key, "/attachments")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", j.authHeader)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return apiError("jira", resp.Status, respBody)
+	}
+	return nil
+}
+
+// LinkParent implements IssueTracker
+func (j *JiraTracker) LinkParent(ctx context.Context, key, parentKey string) error {
+	fields := map[string]interface{}{"parent": map[string]string{"key": parentKey}}
+	return j.do(ctx, http.MethodPut, j.issuePath(key, ""), map[string]interface{}{"fields": fields}, nil)
+}
+
+// LinkDependency implements IssueTracker with a "Blocks" issue link
+// (the inward issue blocks the outward issue)
+func (j *JiraTracker) LinkDependency(ctx context.Context, key, blockerKey string) error {
+	path := "/rest/api/2/issueLink"
+	if j.Cloud {
+		path = "/rest/api/3/issueLink"
+	}
+	payload := map[string]interface{}{
+		"type":         map[string]string{"name": jiraDependencyLinkType},
+		"inwardIssue":  map[string]string{"key": blockerKey},
+		"outwardIssue": map[string]string{"key": key},
+	}
+	return j.do(ctx, http.MethodPost, path, payload, nil)
+}
+
+// ListBlockers implements IssueTracker from the issue's "Blocks" links
+func (j *JiraTracker) ListBlockers(ctx context.Context, key string) ([]string, error) {
+	var issue struct {
+		Fields struct {
+			IssueLinks []struct {
+				Type struct {
+					Name string `json:"name"`
+				} `json:"type"`
+				InwardIssue *struct {
+					Key string `json:"key"`
+				} `json:"inwardIssue"`
+			} `json:"issuelinks"`
+		} `json:"fields"`
+	}
+	if err := j.do(ctx, http.MethodGet, j.issuePath(key, "?fields=issuelinks"), nil, &issue); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, l := range issue.Fields.IssueLinks {
+		// Seen from the blocked issue, its blocker is the link's inward issue
+		if strings.EqualFold(l.Type.Name, jiraDependencyLinkType) && l.InwardIssue != nil {
+			keys = append(keys, l.InwardIssue.Key)
+		}
+	}
+	return keys, nil
+}
+
+// epicLinkFieldID looks up the "Epic Link" custom field on Jira Server/Data Center
+func (j *JiraTracker) epicLinkFieldID(ctx context.Context) string {
+	if j.epicLinkField != nil {
+		return *j.epicLinkField
+	}
+	var fields []struct {
+		ID     string `json:"id"`
+		Schema struct {
+			Custom string `json:"custom"`
+		} `json:"schema"`
+	}
+	id := ""
+	if err := j.do(ctx, http.MethodGet, "/rest/api/2/field", nil, &fields); err != nil {
+		log.Printf("JiraTracker: failed to list fields, linking stories by parent: %v", err)
+	}
+	for _, f := range fields {
+		if f.Schema.Custom == "com.pyxis.greenhopper.jira:gh-epic-link" {
+			id = f.ID
+			break
+		}
+	}
+	j.epicLinkField = &id
+	return id
+}
+
+// GetStatus implements IssueTracker
+func (j *JiraTracker) GetStatus(ctx context.Context, key string) (*IssueStatus, error) {
+	var issue struct {
+		Key    string `json:"key"`
+		Fields struct {
+			Summary  string `json:"summary"`
+			Updated  string `json:"updated"`
+			Assignee *struct {
+				DisplayName string `json:"displayName"`
+			} `json:"assignee"`
+			Status struct {
+				Name           string `json:"name"`
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+	if err := j.do(ctx, http.MethodGet, j.issuePath(key, "?fields=summary,status,updated,assignee"), nil, &issue); err != nil {
+		return nil, err
+	}
+	category := CategoryOpen
+	switch issue.Fields.Status.StatusCategory.Key {
+	case "indeterminate":
+		category = CategoryInProgress
+	case "done":
+		category = CategoryDone
+	}
+	st := &IssueStatus{
+		Tracker:  TypeJira,
+		Key:      issue.Key,
+		URL:      j.IssueURL(issue.Key),
+		Title:    issue.Fields.Summary,
+		Status:   issue.Fields.Status.Name,
+		Category: category,
+		Updated:  issue.Fields.Updated,
+	}
+	if issue.Fields.Assignee != nil {
+		st.Assignee = issue.Fields.Assignee.DisplayName
+	}
+	return st, nil
+}
+
+// jiraTimeLayout is the timestamp format of Jira REST responses
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// ListComments implements IssueTracker. Comments are read through REST API v2 on every
+// deployment so bodies come back as text rather than ADF.
+func (j *JiraTracker) ListComments(ctx context.Context, key string, since time.Time) ([]IssueComment, error) {
+	var page struct {
+		Comments []struct {
+			ID     string `json:"id"`
+			Body   string `json:"body"`
+			Author struct {
+				DisplayName string `json:"displayName"`
+				AccountID   string `json:"accountId"`
+				Name        string `json:"name"`
+			} `json:"author"`
+			Created string `json:"created"`
+		} `json:"comments"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/comment?orderBy=-created&maxResults=50"
+	if err := j.do(ctx, http.MethodGet, path, nil, &page); err != nil {
+		return nil, err
+	}
+	var out []IssueComment
+	for i := len(page.Comments) - 1; i >= 0; i-- {
+		cm := page.Comments[i]
+		created, err := time.Parse(jiraTimeLayout, cm.Created)
+		if err != nil {
+			continue
+		}
+		if !since.IsZero() && !created.After(since) {
+			continue
+		}
+		// Cloud identifies accounts by accountId, Server/Data Center by username
+		authorID := cm.Author.AccountID
+		if authorID == "" {
+			authorID = cm.Author.Name
+		}
+		out = append(out, IssueComment{ID: cm.ID, Author: cm.Author.DisplayName, AuthorID: authorID, Body: cm.Body, Created: created})
+	}
+	return out, nil
+}
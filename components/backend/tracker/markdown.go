@@ -0,0 +1,460 @@
+package tracker
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Minimal markdown model shared by the Jira renderers (ADF for Cloud, wiki markup for
+// Server/DC). It covers what spec-kit documents use: headings, paragraphs, fenced code,
+// nested bullet/ordered/task lists, block quotes, tables and horizontal rules.
+
+// Block kinds
+const (
+	blockHeading   = "heading"
+	blockParagraph = "paragraph"
+	blockCode      = "code"
+	blockList      = "list"
+	blockQuote     = "quote"
+	blockRule      = "rule"
+	blockTable     = "table"
+)
+
+type mdBlock struct {
+	Kind     string
+	Level    int    // heading level
+	Text     string // heading/paragraph inline source, code block content
+	Lang     string // code block language
+	Ordered  bool
+	Start    int
+	Items    []mdListItem
+	Children []mdBlock // block quote content
+	Rows     [][]string
+}
+
+type mdListItem struct {
+	Text     string
+	Task     bool
+	Checked  bool
+	Children []mdBlock
+}
+
+// mdSpan is a run of inline text sharing the same formatting
+type mdSpan struct {
+	Text   string
+	Strong bool
+	Em     bool
+	Code   bool
+	Strike bool
+	Href   string
+}
+
+var (
+	reHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	reListItem  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	reRule      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	reTableSep  = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+	reTaskMark  = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	reFenceOpen = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([^`\\s]*)")
+)
+
+// parseMarkdown parses a markdown document into blocks
+func parseMarkdown(src string) []mdBlock {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	return parseBlocks(strings.Split(src, "\n"))
+}
+
+func indentOf(line string) int {
+	n := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+func isBlank(line string) bool { return strings.TrimSpace(line) == "" }
+
+// startsBlock reports whether a line begins a non-paragraph block
+func startsBlock(lines []string, i int) bool {
+	line := lines[i]
+	t := strings.TrimSpace(line)
+	switch {
+	case reFenceOpen.MatchString(line), reHeading.MatchString(t), reRule.MatchString(line):
+		return true
+	case strings.HasPrefix(t, ">"):
+		return true
+	case reListItem.MatchString(line):
+		return true
+	case strings.HasPrefix(t, "|") && i+1 < len(lines) && reTableSep.MatchString(lines[i+1]):
+		return true
+	}
+	return false
+}
+
+func parseBlocks(lines []string) []mdBlock {
+	var blocks []mdBlock
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		t := strings.TrimSpace(line)
+
+		if t == "" {
+			i++
+			continue
+		}
+
+		// Fenced code
+		if m := reFenceOpen.FindStringSubmatch(line); m != nil {
+			fence := m[1]
+			var body []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				body = append(body, lines[i])
+				i++
+			}
+			i++ // closing fence
+			blocks = append(blocks, mdBlock{Kind: blockCode, Lang: m[2], Text: strings.Join(body, "\n")})
+			continue
+		}
+
+		if m := reHeading.FindStringSubmatch(t); m != nil {
+			blocks = append(blocks, mdBlock{Kind: blockHeading, Level: len(m[1]), Text: m[2]})
+			i++
+			continue
+		}
+
+		if reRule.MatchString(line) {
+			blocks = append(blocks, mdBlock{Kind: blockRule})
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(t, ">") {
+			var inner []string
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">") {
+				s := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				inner = append(inner, strings.TrimPrefix(s, " "))
+				i++
+			}
+			blocks = append(blocks, mdBlock{Kind: blockQuote, Children: parseBlocks(inner)})
+			continue
+		}
+
+		if strings.HasPrefix(t, "|") && i+1 < len(lines) && reTableSep.MatchString(lines[i+1]) {
+			rows := [][]string{splitTableRow(t)}
+			i += 2
+			for i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|") {
+				rows = append(rows, splitTableRow(strings.TrimSpace(lines[i])))
+				i++
+			}
+			blocks = append(blocks, mdBlock{Kind: blockTable, Rows: rows})
+			continue
+		}
+
+		if reListItem.MatchString(line) {
+			var list mdBlock
+			list, i = parseList(lines, i)
+			blocks = append(blocks, list)
+			continue
+		}
+
+		// Paragraph: consecutive lines until a blank line or another block
+		var para []string
+		for i < len(lines) && !isBlank(lines[i]) && (len(para) == 0 || !startsBlock(lines, i)) {
+			para = append(para, strings.TrimSpace(lines[i]))
+			i++
+		}
+		blocks = append(blocks, mdBlock{Kind: blockParagraph, Text: strings.Join(para, " ")})
+	}
+	return blocks
+}
+
+// parseList consumes a list starting at lines[i] and returns it with the next line index
+func parseList(lines []string, i int) (mdBlock, int) {
+	first := reListItem.FindStringSubmatch(lines[i])
+	base := indentOf(lines[i])
+	ordered := !strings.ContainsAny(first[2][:1], "-*+")
+	list := mdBlock{Kind: blockList, Ordered: ordered, Start: 1}
+	if ordered {
+		if n, err := strconv.Atoi(strings.TrimRight(first[2], ".)")); err == nil {
+			list.Start = n
+		}
+	}
+
+	for i < len(lines) {
+		m := reListItem.FindStringSubmatch(lines[i])
+		if m == nil || indentOf(lines[i]) != base {
+			break
+		}
+		isOrdered := !strings.ContainsAny(m[2][:1], "-*+")
+		if isOrdered != ordered {
+			break
+		}
+		item := mdListItem{Text: strings.TrimSpace(m[3])}
+		if tm := reTaskMark.FindStringSubmatch(item.Text); tm != nil {
+			item.Task = true
+			item.Checked = tm[1] != " "
+			item.Text = tm[2]
+		}
+		i++
+
+		// Continuation lines and nested blocks are indented deeper than the marker
+		var sub []string
+		for i < len(lines) {
+			if isBlank(lines[i]) {
+				// A blank line continues the item only if the next content is still indented
+				j := i + 1
+				for j < len(lines) && isBlank(lines[j]) {
+					j++
+				}
+				if j < len(lines) && indentOf(lines[j]) > base {
+					sub = append(sub, "")
+					i++
+					continue
+				}
+				break
+			}
+			if indentOf(lines[i]) <= base {
+				break
+			}
+			sub = append(sub, lines[i])
+			i++
+		}
+
+		// Lazy continuation text directly under the item belongs to its first line
+		for len(sub) > 0 && !isBlank(sub[0]) && !reListItem.MatchString(sub[0]) && !reFenceOpen.MatchString(sub[0]) {
+			item.Text += " " + strings.TrimSpace(sub[0])
+			sub = sub[1:]
+		}
+		if len(sub) > 0 {
+			item.Children = parseBlocks(dedent(sub))
+		}
+		list.Items = append(list.Items, item)
+
+		// Allow blank lines between items of the same list
+		j := i
+		for j < len(lines) && isBlank(lines[j]) {
+			j++
+		}
+		if j < len(lines) && j != i && reListItem.MatchString(lines[j]) && indentOf(lines[j]) == base {
+			i = j
+		}
+	}
+	return list, i
+}
+
+// dedent removes the common leading indentation from lines
+func dedent(lines []string) []string {
+	min := -1
+	for _, l := range lines {
+		if isBlank(l) {
+			continue
+		}
+		if n := indentOf(l); min < 0 || n < min {
+			min = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if isBlank(l) {
+			continue
+		}
+		l = strings.ReplaceAll(l, "\t", "    ")
+		if len(l) >= min {
+			out[i] = l[min:]
+		}
+	}
+	return out
+}
+
+// splitTableRow splits "| a | b |" into trimmed cells, honoring escaped pipes
+func splitTableRow(row string) []string {
+	row = strings.TrimSpace(row)
+	row = strings.TrimPrefix(row, "|")
+	if strings.HasSuffix(row, "|") && !strings.HasSuffix(row, `\|`) {
+		row = row[:len(row)-1]
+	}
+	var cells []string
+	var cur strings.Builder
+	for i := 0; i < len(row); i++ {
+		if row[i] == '\\' && i+1 < len(row) && row[i+1] == '|' {
+			cur.WriteByte('|')
+			i++
+			continue
+		}
+		if row[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(row[i])
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+// parseInline splits inline markdown into formatted spans
+func parseInline(s string) []mdSpan {
+	var spans []mdSpan
+	parseInlineInto(s, mdSpan{}, &spans)
+	return spans
+}
+
+func isWordChar(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func parseInlineInto(s string, style mdSpan, out *[]mdSpan) {
+	var text strings.Builder
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		span := style
+		span.Text = text.String()
+		// Merge with the previous span when formatting is identical
+		if n := len(*out); n > 0 {
+			prev := (*out)[n-1]
+			prev.Text = ""
+			cmp := span
+			cmp.Text = ""
+			if prev == cmp {
+				(*out)[n-1].Text += span.Text
+				text.Reset()
+				return
+			}
+		}
+		*out = append(*out, span)
+		text.Reset()
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!|~>", s[i+1]) >= 0:
+			text.WriteByte(s[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			if end := strings.IndexByte(s[i+1:], '`'); end >= 0 {
+				flush()
+				code := style
+				code.Code = true
+				code.Text = s[i+1 : i+1+end]
+				if code.Text != "" {
+					*out = append(*out, code)
+				}
+				i += end + 2
+				continue
+			}
+
+		case c == '[':
+			if closeText := strings.Index(s[i:], "]("); closeText > 0 {
+				if closeURL := strings.IndexByte(s[i+closeText+2:], ')'); closeURL >= 0 {
+					flush()
+					label := s[i+1 : i+closeText]
+					href := strings.TrimSpace(s[i+closeText+2 : i+closeText+2+closeURL])
+					link := style
+					link.Href = href
+					parseInlineInto(label, link, out)
+					i += closeText + 2 + closeURL + 1
+					continue
+				}
+			}
+
+		case strings.HasPrefix(s[i:], "**") || strings.HasPrefix(s[i:], "__"):
+			delim := s[i : i+2]
+			if end := strings.Index(s[i+2:], delim); end > 0 {
+				flush()
+				strong := style
+				strong.Strong = true
+				parseInlineInto(s[i+2:i+2+end], strong, out)
+				i += end + 4
+				continue
+			}
+
+		case strings.HasPrefix(s[i:], "~~"):
+			if end := strings.Index(s[i+2:], "~~"); end > 0 {
+				flush()
+				strike := style
+				strike.Strike = true
+				parseInlineInto(s[i+2:i+2+end], strike, out)
+				i += end + 4
+				continue
+			}
+
+		case c == '*' || c == '_':
+			// Intraword underscores (snake_case) are literal
+			if c == '_' && i > 0 && isWordChar(s[i-1]) {
+				break
+			}
+			if i+1 < len(s) && s[i+1] != ' ' {
+				if end := strings.IndexByte(s[i+1:], c); end > 0 {
+					after := i + 1 + end + 1
+					if c != '_' || after >= len(s) || !isWordChar(s[after]) {
+						flush()
+						em := style
+						em.Em = true
+						parseInlineInto(s[i+1:i+1+end], em, out)
+						i = after
+						continue
+					}
+				}
+			}
+		}
+		text.WriteByte(c)
+		i++
+	}
+	flush()
+}
+
+// plainText renders blocks as plain text (used where a target format cannot nest blocks)
+func plainText(blocks []mdBlock) string {
+	var parts []string
+	for _, b := range blocks {
+		switch b.Kind {
+		case blockList:
+			for _, it := range b.Items {
+				line := spansText(parseInline(it.Text))
+				if it.Task {
+					if it.Checked {
+						line = "☑ " + line
+					} else {
+						line = "☐ " + line
+					}
+				}
+				parts = append(parts, line)
+				if len(it.Children) > 0 {
+					parts = append(parts, plainText(it.Children))
+				}
+			}
+		case blockCode:
+			parts = append(parts, b.Text)
+		case blockQuote:
+			parts = append(parts, plainText(b.Children))
+		case blockTable:
+			for _, r := range b.Rows {
+				parts = append(parts, strings.Join(r, " | "))
+			}
+		case blockRule:
+		default:
+			parts = append(parts, spansText(parseInline(b.Text)))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func spansText(spans []mdSpan) string {
+	var sb strings.Builder
+	for _, s := range spans {
+		sb.WriteString(s.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package tracker
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update rewrites the golden files from the current output: go test ./tracker -update
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestMarkdownGolden(t *testing.T) {
+	renderers := []struct {
+		ext    string
+		render func(string) (string, error)
+	}{
+		{
+			ext: ".adf.json",
+			render: func(md string) (string, error) {
+				b, err := json.MarshalIndent(MarkdownToADF(md), "", "  ")
+				return string(b) + "\n", err
+			},
+		},
+		{
+			ext:    ".wiki",
+			render: func(md string) (string, error) { return MarkdownToWiki(md) + "\n", nil },
+		},
+	}
+
+	// spec-kit's three documents, as published to trackers
+	for _, doc := range []string{"spec", "plan", "tasks"} {
+		src, err := os.ReadFile(filepath.Join("testdata", doc+".md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range renderers {
+			t.Run(doc+r.ext, func(t *testing.T) {
+				got, err := r.render(string(src))
+				if err != nil {
+					t.Fatalf("render: %v", err)
+				}
+				golden := filepath.Join("testdata", doc+r.ext)
+				if *update {
+					if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("read golden file (run with -update to create it): %v", err)
+				}
+				if got != string(want) {
+					t.Errorf("%s differs from %s; run go test ./tracker -update and review the diff\ngot:\n%s", doc+".md", golden, got)
+				}
+			})
+		}
+	}
+}
+
+func TestMarkdownEmpty(t *testing.T) {
+	doc := MarkdownToADF("")
+	content, _ := doc["content"].([]interface{})
+	if len(content) != 1 {
+		t.Fatalf("empty markdown: want one empty paragraph, got %v", doc)
+	}
+	if got := MarkdownToWiki(""); got != "" {
+		t.Errorf("empty markdown: want empty wiki markup, got %q", got)
+	}
+}
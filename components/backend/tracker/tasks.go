@@ -0,0 +1,287 @@
+package tracker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Task is one entry of a spec-kit tasks.md checklist (e.g. "- [ ] T004 [P] Contract test in tests/contract/x.py")
+type Task struct {
+	ID string
+	// Key identifies the task's published issue: the ID, or for sub-tasks numbered by position
+	// the parent ID and a hash of the text, so reordering sub-tasks keeps their issues
+	Key       string
+	Title     string // description without the ID and markers
+	Phase     string // enclosing heading, e.g. "Phase 3.1: Setup"
+	Parallel  bool   // [P] marker: can run alongside other [P] tasks
+	Done      bool
+	Files     []string
+	DependsOn []string // IDs of tasks that must complete first
+	SubTasks  []Task
+}
+
+var (
+	reTaskID      = regexp.MustCompile(`^(T\d+)\b[:.]?\s*`)
+	reTaskRef     = regexp.MustCompile(`\bT(\d+)(?:\s*[-–]\s*T?(\d+))?\b`)
+	reMarker      = regexp.MustCompile(`^\[([A-Za-z0-9]+)\]\s*`)
+	reBacktick    = regexp.MustCompile("`([^`]+)`")
+	reFilePath    = regexp.MustCompile(`(?:^|[\s(])((?:[\w.-]+/)+[\w.-]+\.[A-Za-z0-9]+|[\w-]+\.(?:md|go|py|ts|tsx|js|jsx|java|rs|rb|yaml|yml|json|toml|sh|sql|proto))\b`)
+	reInlineAfter = regexp.MustCompile(`(?i)\((?:depends on|after|requires|blocked by)\s+([^)]*)\)`)
+)
+
+// Dependency phrases in the "Dependencies" section: "<blockers> before|blocks <dependents>"
+// or "<dependents> depends on|after|requires <blockers>"
+var (
+	blockerFirst   = []string{" blocks ", " before ", " blocking "}
+	dependentFirst = []string{" depends on ", " after ", " requires ", " blocked by "}
+)
+
+// ParseTasks extracts tasks, their sub-tasks and dependencies from a spec-kit tasks.md
+func ParseTasks(markdown string) []Task {
+	var tasks []Task
+	index := map[string]int{}
+	deps := map[string][]string{}
+	heading := ""
+
+	for _, b := range parseMarkdown(markdown) {
+		switch b.Kind {
+		case blockHeading:
+			heading = strings.TrimSpace(b.Text)
+		case blockList:
+			if strings.EqualFold(heading, "Dependencies") {
+				for _, it := range b.Items {
+					for dependent, blockers := range parseDependencyLine(it.Text) {
+						deps[dependent] = append(deps[dependent], blockers...)
+					}
+				}
+				continue
+			}
+			for _, it := range b.Items {
+				t, ok := parseTaskItem(it, heading, "")
+				if !ok {
+					continue
+				}
+				if _, dup := index[t.ID]; dup {
+					continue
+				}
+				index[t.ID] = len(tasks)
+				tasks = append(tasks, t)
+			}
+		}
+	}
+
+	for dependent, blockers := range deps {
+		if i, ok := index[dependent]; ok {
+			tasks[i].DependsOn = append(tasks[i].DependsOn, blockers...)
+		}
+	}
+	for i := range tasks {
+		tasks[i].DependsOn = uniqueTaskIDs(tasks[i].DependsOn, tasks[i].ID, index)
+	}
+	return tasks
+}
+
+// parseTaskItem parses a checklist item; fallbackID numbers sub-tasks without their own ID
+func parseTaskItem(it mdListItem, phase, fallbackID string) (Task, bool) {
+	if !it.Task {
+		return Task{}, false
+	}
+	text := strings.TrimSpace(it.Text)
+	t := Task{Phase: phase, Done: it.Checked, ID: fallbackID}
+	if m := reTaskID.FindStringSubmatch(text); m != nil {
+		t.ID = m[1]
+		text = text[len(m[0]):]
+	}
+	if t.ID == "" {
+		return Task{}, false
+	}
+	t.Key = t.ID
+	// Leading markers: [P] for parallel, others (e.g. [US1] story tags) are kept in the title
+	var tags []string
+	for {
+		m := reMarker.FindStringSubmatch(text)
+		if m == nil {
+			break
+		}
+		if strings.EqualFold(m[1], "P") {
+			t.Parallel = true
+		} else {
+			tags = append(tags, "["+m[1]+"]")
+		}
+		text = text[len(m[0]):]
+	}
+	if m := reInlineAfter.FindStringSubmatch(text); m != nil {
+		t.DependsOn = append(t.DependsOn, expandTaskRefs(m[1])...)
+	}
+	t.Title = strings.TrimSpace(strings.Join(append(tags, text), " "))
+	t.Files = taskFiles(text)
+
+	n := 0
+	subKeys := map[string]int{}
+	for _, child := range it.Children {
+		if child.Kind != blockList {
+			continue
+		}
+		for _, sub := range child.Items {
+			n++
+			fallback := fmt.Sprintf("%s.%d", t.ID, n)
+			st, ok := parseTaskItem(sub, phase, fallback)
+			if !ok {
+				continue
+			}
+			st.SubTasks = nil
+			if st.ID == fallback {
+				st.Key = subTaskKey(t.ID, st.Title)
+				if subKeys[st.Key]++; subKeys[st.Key] > 1 {
+					st.Key = fmt.Sprintf("%s-%d", st.Key, subKeys[st.Key])
+				}
+			}
+			t.SubTasks = append(t.SubTasks, st)
+		}
+	}
+	return t, true
+}
+
+// subTaskKey keys a sub-task without its own ID by its parent and its text
+func subTaskKey(parentID, title string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.Join(strings.Fields(title), " "))))
+	return parentID + "." + hex.EncodeToString(sum[:])[:12]
+}
+
+// taskFiles returns the file paths a task mentions, in order of appearance
+func taskFiles(text string) []string {
+	var files []string
+	seen := map[string]bool{}
+	add := func(p string) {
+		p = strings.TrimRight(strings.TrimSpace(p), ".,;:")
+		if p == "" || seen[p] || strings.Contains(p, " ") || strings.Contains(p, "://") {
+			return
+		}
+		seen[p] = true
+		files = append(files, p)
+	}
+	for _, m := range reBacktick.FindAllStringSubmatch(text, -1) {
+		if strings.Contains(m[1], "/") || strings.Contains(m[1], ".") {
+			add(m[1])
+		}
+	}
+	plain := reBacktick.ReplaceAllString(text, " ")
+	for _, m := range reFilePath.FindAllStringSubmatch(plain, -1) {
+		add(m[1])
+	}
+	return files
+}
+
+// parseDependencyLine maps dependent task IDs to their blockers for one "Dependencies" entry
+func parseDependencyLine(line string) map[string][]string {
+	lower := " " + strings.ToLower(line) + " "
+	split := func(phrases []string) (string, string, bool) {
+		for _, p := range phrases {
+			if i := strings.Index(lower, p); i >= 0 {
+				return lower[:i], lower[i+len(p):], true
+			}
+		}
+		return "", "", false
+	}
+	var blockers, dependents []string
+	if left, right, ok := split(dependentFirst); ok {
+		dependents, blockers = expandTaskRefs(left), expandTaskRefs(right)
+	} else if left, right, ok := split(blockerFirst); ok {
+		blockers, dependents = expandTaskRefs(left), expandTaskRefs(right)
+	}
+	out := map[string][]string{}
+	if len(blockers) == 0 {
+		return out
+	}
+	for _, d := range dependents {
+		out[d] = append(out[d], blockers...)
+	}
+	return out
+}
+
+// expandTaskRefs returns the task IDs referenced in text, expanding ranges like T004-T007
+func expandTaskRefs(text string) []string {
+	var ids []string
+	for _, m := range reTaskRef.FindAllStringSubmatch(strings.ToUpper(text), -1) {
+		start, _ := strconv.Atoi(m[1])
+		end := start
+		if m[2] != "" {
+			end, _ = strconv.Atoi(m[2])
+		}
+		if end < start || end-start > 500 {
+			end = start
+		}
+		for n := start; n <= end; n++ {
+			ids = append(ids, fmt.Sprintf("T%0*d", len(m[1]), n))
+		}
+	}
+	return ids
+}
+
+// uniqueTaskIDs dedupes and sorts known dependency IDs, dropping self-references
+func uniqueTaskIDs(ids []string, self string, known map[string]int) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, id := range ids {
+		if id == self || seen[id] {
+			continue
+		}
+		if _, ok := known[id]; !ok {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	sort.Slice(out, func(i, j int) bool { return known[out[i]] < known[out[j]] })
+	return out
+}
+
+// Markdown renders the task as an issue body
+func (t Task) Markdown(source string) string {
+	var sb strings.Builder
+	if source != "" {
+		fmt.Fprintf(&sb, "**Source:** %s\n\n", source)
+	}
+	fmt.Fprintf(&sb, "**Task:** %s\n\n", t.ID)
+	if t.Phase != "" {
+		fmt.Fprintf(&sb, "**Phase:** %s\n\n", t.Phase)
+	}
+	if t.Parallel {
+		sb.WriteString("**Parallel:** can run in parallel with other [P] tasks\n\n")
+	}
+	if len(t.DependsOn) > 0 {
+		fmt.Fprintf(&sb, "**Depends on:** %s\n\n", strings.Join(t.DependsOn, ", "))
+	}
+	sb.WriteString(t.Title + "\n")
+	if len(t.Files) > 0 {
+		sb.WriteString("\n**Files:**\n")
+		for _, f := range t.Files {
+			fmt.Fprintf(&sb, "- `%s`\n", f)
+		}
+	}
+	if len(t.SubTasks) > 0 {
+		sb.WriteString("\n**Sub-tasks:**\n")
+		for _, st := range t.SubTasks {
+			mark := " "
+			if st.Done {
+				mark = "x"
+			}
+			fmt.Fprintf(&sb, "- [%s] %s %s\n", mark, st.ID, st.Title)
+		}
+	}
+	return sb.String()
+}
+
+// IssueTitle is the summary used for the task's issue (Jira limits summaries to 255 characters)
+func (t Task) IssueTitle() string {
+	title := t.ID + ": " + strings.Join(strings.Fields(t.Title), " ")
+	if r := []rune(title); len(r) > 255 {
+		title = string(r[:252]) + "..."
+	}
+	return title
+}
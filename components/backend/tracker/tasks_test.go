@@ -0,0 +1,34 @@
+package tracker
+
+import "testing"
+
+func TestParseTasksSubTaskKeysFollowContent(t *testing.T) {
+	before := ParseTasks("## Phase 1\n\n- [ ] T001 Render transcripts\n  - [ ] Quote user prompts\n  - [ ] Fence tool calls\n")
+	after := ParseTasks("## Phase 1\n\n- [ ] T001 Render transcripts\n  - [ ] Strip ANSI colors\n  - [ ] Fence tool calls\n  - [ ] Quote user prompts\n")
+	if len(before) != 1 || len(after) != 1 {
+		t.Fatalf("want one task each, got %d and %d", len(before), len(after))
+	}
+
+	keys := map[string]string{}
+	for _, st := range after[0].SubTasks {
+		keys[st.Title] = st.Key
+	}
+	for _, st := range before[0].SubTasks {
+		if got := keys[st.Title]; got != st.Key {
+			t.Errorf("%q: key %q after inserting and reordering sub-tasks, was %q", st.Title, got, st.Key)
+		}
+	}
+	if before[0].Key != "T001" {
+		t.Errorf("task key = %q; want its ID", before[0].Key)
+	}
+}
+
+func TestParseTasksDuplicateSubTasksGetDistinctKeys(t *testing.T) {
+	tasks := ParseTasks("- [ ] T001 Migrate handlers\n  - [ ] Update tests\n  - [ ] Update tests\n")
+	if len(tasks) != 1 || len(tasks[0].SubTasks) != 2 {
+		t.Fatalf("want one task with two sub-tasks, got %+v", tasks)
+	}
+	if a, b := tasks[0].SubTasks[0].Key, tasks[0].SubTasks[1].Key; a == b {
+		t.Errorf("duplicate sub-tasks share key %q", a)
+	}
+}
@@ -0,0 +1,230 @@
+// Package tracker publishes RFE workflow artifacts to external issue trackers.
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Supported tracker types (ProjectSettings spec.issueTracker.type)
+const (
+	TypeJira   = "jira"
+	TypeGitHub = "github"
+)
+
+// IssueKind is the tracker-neutral role of an issue in the RFE hierarchy
+type IssueKind string
+
+const (
+	// KindRequest is the issue created for spec.md (specify phase)
+	KindRequest IssueKind = "request"
+	// KindFeature is the issue created for plan.md (plan phase)
+	KindFeature IssueKind = "feature"
+	// KindEpic is the issue created for tasks.md (tasks phase)
+	KindEpic IssueKind = "epic"
+	// KindStory is the issue created for each task in tasks.md, under the Epic
+	KindStory IssueKind = "story"
+	// KindSubTask is the issue created for each nested checklist item of a task
+	KindSubTask IssueKind = "subtask"
+)
+
+// Issue is the content of an issue to create or update
+type Issue struct {
+	Title     string
+	Body      string // markdown, converted to the tracker's native format on publish
+	Kind      IssueKind
+	ParentKey string // optional parent to link on create
+}
+
+// IssueRef identifies an issue in its tracker
+type IssueRef struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+}
+
+// Status categories reported by IssueStatus.Category
+const (
+	CategoryOpen       = "open"
+	CategoryInProgress = "in_progress"
+	CategoryDone       = "done"
+)
+
+// IssueStatus is the current state of an issue
+type IssueStatus struct {
+	Tracker  string `json:"tracker"`
+	Key      string `json:"key"`
+	URL      string `json:"url"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`   // tracker-native status name
+	Category string `json:"category"` // one of the Category* constants
+	Assignee string `json:"assignee,omitempty"`
+	Updated  string `json:"updated,omitempty"`
+}
+
+// IssueComment is a comment on an issue
+type IssueComment struct {
+	ID     string `json:"id"`
+	Author string `json:"author"`
+	// AuthorID is the stable account name: the GitHub login, or the Jira accountId (cloud) or username
+	AuthorID string `json:"authorId,omitempty"`
+	// AuthorAssociation is GitHub's author_association (OWNER, MEMBER, ...); empty for Jira
+	AuthorAssociation string    `json:"authorAssociation,omitempty"`
+	Body              string    `json:"body"`
+	Created           time.Time `json:"created"`
+}
+
+// IssueTracker is implemented by every supported issue tracker
+type IssueTracker interface {
+	// Type returns the tracker type (TypeJira, TypeGitHub)
+	Type() string
+	CreateIssue(ctx context.Context, issue Issue) (*IssueRef, error)
+	UpdateIssue(ctx context.Context, key string, issue Issue) (*IssueRef, error)
+	AttachFile(ctx context.Context, key, filename string, content []byte) error
+	LinkParent(ctx context.Context, key, parentKey string) error
+	// LinkDependency records that key is blocked by blockerKey
+	LinkDependency(ctx context.Context, key, blockerKey string) error
+	// ListBlockers returns the keys of the issues recorded as blocking key
+	ListBlockers(ctx context.Context, key string) ([]string, error)
+	GetStatus(ctx context.Context, key string) (*IssueStatus, error)
+	// ListComments returns recent comments created after since (zero for all), oldest first
+	ListComments(ctx context.Context, key string, since time.Time) ([]IssueComment, error)
+}
+
+// Config is ProjectSettings spec.issueTracker
+type Config struct {
+	Type string
+	// Jira issue type names per kind (defaults: Feature Request, Feature, Epic, Story, Sub-task)
+	JiraIssueTypes map[IssueKind]string
+	// JiraDeployment forces "cloud" (REST v3, ADF) or "server" (REST v2, wiki markup);
+	// empty detects Cloud from the *.atlassian.net host
+	JiraDeployment string
+	// GitHub repository (owner/name) for issues; empty uses the RFE umbrella repo
+	GitHubRepository string
+	// Labels added to every GitHub issue
+	GitHubLabels []string
+	// CommentPrompts forwards issue comments to the workflow's interactive session
+	CommentPrompts CommentPromptConfig
+}
+
+// CommentPromptConfig is spec.issueTracker.commentPrompts
+type CommentPromptConfig struct {
+	Enabled bool
+	// Prefix a comment must start with to be forwarded (stripped from the prompt)
+	Prefix string
+	// AllowedAuthors are account names (GitHub logins, Jira accountIds or usernames) whose
+	// comments are forwarded regardless of association
+	AllowedAuthors []string
+	// AllowedAssociations are the GitHub author associations whose comments are forwarded;
+	// Jira comments have none and need AllowedAuthors
+	AllowedAssociations []string
+}
+
+// AllowsAuthor reports whether prompts from the comment's author may be forwarded
+func (c CommentPromptConfig) AllowsAuthor(cm IssueComment) bool {
+	for _, a := range c.AllowedAuthors {
+		if cm.AuthorID != "" && strings.EqualFold(a, cm.AuthorID) {
+			return true
+		}
+	}
+	for _, a := range c.AllowedAssociations {
+		if cm.AuthorAssociation != "" && strings.EqualFold(a, cm.AuthorAssociation) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultCommentPromptAssociations are the GitHub associations allowed when none are configured
+var DefaultCommentPromptAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// DefaultCommentPromptPrefix marks tracker comments meant for the agents
+const DefaultCommentPromptPrefix = "/ambient"
+
+// DefaultJiraIssueTypes maps issue kinds to the Jira issue types used before trackers were configurable
+var DefaultJiraIssueTypes = map[IssueKind]string{
+	KindRequest: "Feature Request",
+	KindFeature: "Feature",
+	KindEpic:    "Epic",
+	KindStory:   "Story",
+	KindSubTask: "Sub-task",
+}
+
+// ParseConfig reads spec.issueTracker from a ProjectSettings spec, defaulting to Jira
+func ParseConfig(spec map[string]interface{}) Config {
+	cfg := Config{Type: TypeJira, JiraIssueTypes: map[IssueKind]string{}, CommentPrompts: CommentPromptConfig{Prefix: DefaultCommentPromptPrefix, AllowedAssociations: DefaultCommentPromptAssociations}}
+	for k, v := range DefaultJiraIssueTypes {
+		cfg.JiraIssueTypes[k] = v
+	}
+	raw, _ := spec["issueTracker"].(map[string]interface{})
+	if raw == nil {
+		return cfg
+	}
+	if t, ok := raw["type"].(string); ok && strings.TrimSpace(t) != "" {
+		cfg.Type = strings.ToLower(strings.TrimSpace(t))
+	}
+	if j, ok := raw["jira"].(map[string]interface{}); ok {
+		if v, ok := j["deployment"].(string); ok {
+			cfg.JiraDeployment = strings.ToLower(strings.TrimSpace(v))
+		}
+		if types, ok := j["issueTypes"].(map[string]interface{}); ok {
+			for _, kind := range []IssueKind{KindRequest, KindFeature, KindEpic, KindStory, KindSubTask} {
+				if v, ok := types[string(kind)].(string); ok && strings.TrimSpace(v) != "" {
+					cfg.JiraIssueTypes[kind] = strings.TrimSpace(v)
+				}
+			}
+		}
+	}
+	if cp, ok := raw["commentPrompts"].(map[string]interface{}); ok {
+		cfg.CommentPrompts.Enabled, _ = cp["enabled"].(bool)
+		if v, ok := cp["prefix"].(string); ok {
+			cfg.CommentPrompts.Prefix = strings.TrimSpace(v)
+		}
+		if authors, ok := cp["allowedAuthors"].([]interface{}); ok {
+			for _, a := range authors {
+				if s, ok := a.(string); ok && strings.TrimSpace(s) != "" {
+					cfg.CommentPrompts.AllowedAuthors = append(cfg.CommentPrompts.AllowedAuthors, strings.TrimSpace(s))
+				}
+			}
+		}
+		if assocs, ok := cp["allowedAssociations"].([]interface{}); ok {
+			cfg.CommentPrompts.AllowedAssociations = nil
+			for _, a := range assocs {
+				if s, ok := a.(string); ok && strings.TrimSpace(s) != "" {
+					cfg.CommentPrompts.AllowedAssociations = append(cfg.CommentPrompts.AllowedAssociations, strings.ToUpper(strings.TrimSpace(s)))
+				}
+			}
+		}
+	}
+	if g, ok := raw["github"].(map[string]interface{}); ok {
+		if v, ok := g["repository"].(string); ok {
+			cfg.GitHubRepository = strings.TrimSpace(v)
+		}
+		if labels, ok := g["labels"].([]interface{}); ok {
+			for _, l := range labels {
+				if s, ok := l.(string); ok && strings.TrimSpace(s) != "" {
+					cfg.GitHubLabels = append(cfg.GitHubLabels, strings.TrimSpace(s))
+				}
+			}
+		}
+	}
+	return cfg
+}
+
+// KindForPhase maps an RFE phase to the issue kind published for it
+func KindForPhase(phase string) IssueKind {
+	switch phase {
+	case "specify":
+		return KindRequest
+	case "tasks":
+		return KindEpic
+	default:
+		return KindFeature
+	}
+}
+
+// apiError formats a non-2xx tracker response
+func apiError(tracker, status string, body []byte) error {
+	return fmt.Errorf("%s API error: %s (body: %s)", tracker, status, string(body))
+}
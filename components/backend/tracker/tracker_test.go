@@ -0,0 +1,27 @@
+package tracker
+
+import "testing"
+
+func TestCommentPromptsAllowOnlyTrustedAuthors(t *testing.T) {
+	cfg := ParseConfig(map[string]interface{}{
+		"issueTracker": map[string]interface{}{
+			"commentPrompts": map[string]interface{}{"enabled": true, "allowedAuthors": []interface{}{"jira-lead"}},
+		},
+	}).CommentPrompts
+
+	cases := []struct {
+		name string
+		cm   IssueComment
+		want bool
+	}{
+		{"collaborator", IssueComment{AuthorID: "octocat", AuthorAssociation: "COLLABORATOR"}, true},
+		{"public user", IssueComment{AuthorID: "drive-by", AuthorAssociation: "NONE"}, false},
+		{"listed Jira account", IssueComment{AuthorID: "JIRA-LEAD"}, true},
+		{"unlisted Jira account", IssueComment{AuthorID: "someone", Author: "jira-lead"}, false},
+	}
+	for _, tc := range cases {
+		if got := cfg.AllowsAuthor(tc.cm); got != tc.want {
+			t.Errorf("%s: AllowsAuthor = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package tracker
+
+import (
+	"strings"
+)
+
+// MarkdownToWiki converts markdown to Jira wiki markup for Jira Server/Data Center,
+// whose REST API v2 renders descriptions as wiki markup rather than markdown.
+func MarkdownToWiki(markdown string) string {
+	var sb strings.Builder
+	wikiBlocks(&sb, parseMarkdown(markdown))
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func wikiBlocks(sb *strings.Builder, blocks []mdBlock) {
+	for i, b := range blocks {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		switch b.Kind {
+		case blockHeading:
+			sb.WriteString("h" + string(rune('0'+b.Level)) + ". " + wikiInline(b.Text) + "\n")
+		case blockCode:
+			if b.Lang != "" {
+				sb.WriteString("{code:" + b.Lang + "}\n" + b.Text + "\n{code}\n")
+			} else {
+				sb.WriteString("{noformat}\n" + b.Text + "\n{noformat}\n")
+			}
+		case blockRule:
+			sb.WriteString("----\n")
+		case blockQuote:
+			sb.WriteString("{quote}\n")
+			wikiBlocks(sb, b.Children)
+			sb.WriteString("{quote}\n")
+		case blockTable:
+			for r, row := range b.Rows {
+				sep := "|"
+				if r == 0 {
+					sep = "||"
+				}
+				sb.WriteString(sep)
+				for _, cell := range row {
+					text := wikiInline(cell)
+					if text == "" {
+						text = " "
+					}
+					sb.WriteString(text + sep)
+				}
+				sb.WriteString("\n")
+			}
+		case blockList:
+			wikiList(sb, b, "")
+		default:
+			sb.WriteString(wikiInline(b.Text) + "\n")
+		}
+	}
+}
+
+// wikiList writes list items with their nesting prefix (e.g. "*#" for a numbered list in a bullet)
+func wikiList(sb *strings.Builder, b mdBlock, prefix string) {
+	marker := "*"
+	if b.Ordered {
+		marker = "#"
+	}
+	prefix += marker
+	for _, it := range b.Items {
+		text := wikiInline(it.Text)
+		if it.Task {
+			// Wiki markup has no checklists; keep a literal checkbox
+			if it.Checked {
+				text = `\[x\] ` + text
+			} else {
+				text = `\[ \] ` + text
+			}
+		}
+		// Paragraphs under an item continue it after a forced line break; wiki lists
+		// cannot nest other blocks, so code and tables follow the item directly
+		var trailing []mdBlock
+		var nested []mdBlock
+		for _, child := range it.Children {
+			switch child.Kind {
+			case blockList:
+				nested = append(nested, child)
+			case blockParagraph, blockHeading:
+				text += ` \\ ` + wikiInline(child.Text)
+			default:
+				trailing = append(trailing, child)
+			}
+		}
+		sb.WriteString(prefix + " " + text + "\n")
+		if len(trailing) > 0 {
+			wikiBlocks(sb, trailing)
+		}
+		for _, child := range nested {
+			wikiList(sb, child, prefix)
+		}
+	}
+}
+
+// wikiInline converts inline markdown to wiki markup
+func wikiInline(s string) string {
+	var sb strings.Builder
+	for _, span := range parseInline(s) {
+		if span.Text == "" {
+			continue
+		}
+		var text string
+		if span.Code {
+			text = "{{" + strings.NewReplacer("{", `\{`, "}", `\}`).Replace(span.Text) + "}}"
+		} else {
+			text = wikiEscapeText(span.Text)
+			if span.Strike {
+				text = "-" + text + "-"
+			}
+			if span.Em {
+				text = "_" + text + "_"
+			}
+			if span.Strong {
+				text = "*" + text + "*"
+			}
+		}
+		if span.Href != "" {
+			text = "[" + text + "|" + span.Href + "]"
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// wikiEscapeText escapes markup characters only where wiki markup would pick them up:
+// brackets and braces anywhere, and formatting characters at word boundaries.
+func wikiEscapeText(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '{', '}', '[', ']', '|':
+			sb.WriteByte('\\')
+		case '*', '_', '+', '^', '~', '-', '?':
+			prevSpace := i == 0 || s[i-1] == ' '
+			nextSpace := i == len(s)-1 || s[i+1] == ' '
+			if prevSpace != nextSpace {
+				sb.WriteByte('\\')
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
@@ -11,6 +11,7 @@ type UserContext struct {
 	UserID      string   `json:"userId" binding:"required"`
 	DisplayName string   `json:"displayName" binding:"required"`
 	Groups      []string `json:"groups" binding:"required"`
+	Email       string   `json:"email,omitempty"`
 }
 
 type BotAccountRef struct {
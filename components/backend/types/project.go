@@ -1,5 +1,7 @@
 package types
 
+import corev1 "k8s.io/api/core/v1"
+
 // Project management types
 type AmbientProject struct {
 	Name              string            `json:"name"`                  // Kubernetes namespace name
@@ -16,4 +18,36 @@ type CreateProjectRequest struct {
 	Name        string `json:"name" binding:"required"`
 	DisplayName string `json:"displayName,omitempty"` // Optional: only used on OpenShift
 	Description string `json:"description,omitempty"` // Optional: only used on OpenShift
+	// Template names a cluster project template applied with the project
+	Template string `json:"template,omitempty"`
+	// RunnerSecrets are values for the keys the template prompts for
+	RunnerSecrets map[string]string `json:"runnerSecrets,omitempty"`
+}
+
+// ProjectTemplate bootstraps a new project. Templates are kept by cluster admins in the
+// ambient-project-templates ConfigMap of the backend namespace, one data key per template.
+type ProjectTemplate struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName,omitempty"`
+	Description string `json:"description,omitempty"`
+	// ProjectSettings is the spec of the project's ProjectSettings
+	ProjectSettings map[string]interface{} `json:"projectSettings,omitempty"`
+	// RunnerSecrets are the runner secret keys to ask for when creating the project
+	RunnerSecrets    []ProjectTemplateSecret   `json:"runnerSecrets,omitempty"`
+	ResourceQuota    *corev1.ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+	LimitRange       *corev1.LimitRangeSpec    `json:"limitRange,omitempty"`
+	SessionTemplates []SessionTemplate         `json:"sessionTemplates,omitempty"`
+}
+
+type ProjectTemplateSecret struct {
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// SessionTemplate is a starter session offered when creating sessions in a project
+type SessionTemplate struct {
+	Name        string                      `json:"name"`
+	Description string                      `json:"description,omitempty"`
+	Session     CreateAgenticSessionRequest `json:"session"`
 }
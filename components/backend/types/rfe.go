@@ -2,23 +2,142 @@ package types
 
 // RFE Workflow Data Structures
 type RFEWorkflow struct {
-	ID              string             `json:"id"`
-	Title           string             `json:"title"`
-	Description     string             `json:"description"`
-	BranchName      string             `json:"branchName"`
-	UmbrellaRepo    *GitRepository     `json:"umbrellaRepo,omitempty"`
-	SupportingRepos []GitRepository    `json:"supportingRepos,omitempty"`
-	Project         string             `json:"project,omitempty"`
-	WorkspacePath   string             `json:"workspacePath"`
-	CreatedAt       string             `json:"createdAt"`
-	UpdatedAt       string             `json:"updatedAt"`
-	JiraLinks       []WorkflowJiraLink `json:"jiraLinks,omitempty"`
-	ParentOutcome   *string            `json:"parentOutcome,omitempty"`
-}
-
-type WorkflowJiraLink struct {
+	ID              string                `json:"id"`
+	Title           string                `json:"title"`
+	Description     string                `json:"description"`
+	BranchName      string                `json:"branchName"`
+	UmbrellaRepo    *GitRepository        `json:"umbrellaRepo,omitempty"`
+	SupportingRepos []GitRepository       `json:"supportingRepos,omitempty"`
+	Project         string                `json:"project,omitempty"`
+	WorkspacePath   string                `json:"workspacePath"`
+	CreatedAt       string                `json:"createdAt"`
+	UpdatedAt       string                `json:"updatedAt"`
+	TrackerLinks    []WorkflowTrackerLink `json:"trackerLinks,omitempty"`
+	ParentOutcome   *string               `json:"parentOutcome,omitempty"`
+	TrackerSync     *WorkflowTrackerSync  `json:"trackerSync,omitempty"`    // status.tracker
+	Phase           string                `json:"phase,omitempty"`          // status.phase
+	PhaseArtifacts  *WorkflowArtifacts    `json:"phaseArtifacts,omitempty"` // status.phaseArtifacts
+	PhaseHistory    []PhaseTransition     `json:"phaseHistory,omitempty"`
+	Gates           []PhaseGate           `json:"gates,omitempty"`     // spec.gates
+	Approvals       []PhaseApproval       `json:"approvals,omitempty"` // status.approvals
+	Seed            *WorkflowSeed         `json:"seed,omitempty"`      // status.seed
+}
+
+// WorkflowSeed records the spec-kit release and agent source the feature branch was last
+// seeded or upgraded from; drift detection compares the branch against it
+type WorkflowSeed struct {
+	SpecKitRepo       string `json:"specKitRepo,omitempty"`
+	SpecKitVersion    string `json:"specKitVersion,omitempty"`
+	SpecKitTemplate   string `json:"specKitTemplate,omitempty"`
+	AgentSourceURL    string `json:"agentSourceUrl,omitempty"`
+	AgentSourceBranch string `json:"agentSourceBranch,omitempty"`
+	AgentSourcePath   string `json:"agentSourcePath,omitempty"`
+	// SHA-256 of the spec-kit archive and agent bundle, when pinned or reported by an upgrade
+	SpecKitSHA256     string `json:"specKitSha256,omitempty"`
+	AgentSourceSHA256 string `json:"agentSourceSha256,omitempty"`
+	SeededAt          string `json:"seededAt,omitempty"`
+	UpgradedAt        string `json:"upgradedAt,omitempty"`
+	// UpgradePullRequest is the pull request of the last upgrade made in pr mode
+	UpgradePullRequest string `json:"upgradePullRequest,omitempty"`
+}
+
+// PhaseGate requires reviewer sign-off before a workflow can leave Phase
+type PhaseGate struct {
+	Phase string `json:"phase"`
+	// RequiredGroups each need an approval from at least one member
+	RequiredGroups []string `json:"requiredGroups,omitempty"`
+	// MinApprovals is the number of distinct approvers required (at least 1)
+	MinApprovals int `json:"minApprovals,omitempty"`
+}
+
+// PhaseApproval is one reviewer decision in the status.approvals audit trail
+type PhaseApproval struct {
+	Phase       string   `json:"phase"`
+	User        string   `json:"user"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Groups      []string `json:"groups,omitempty"` // reviewer groups the user belonged to when deciding
+	Decision    string   `json:"decision"`         // approved | rejected
+	Comment     string   `json:"comment,omitempty"`
+	At          string   `json:"at"`
+}
+
+// GateStatus is the evaluation of a PhaseGate against the recorded approvals
+type GateStatus struct {
+	PhaseGate
+	Satisfied     bool     `json:"satisfied"`
+	ApprovedBy    []string `json:"approvedBy,omitempty"`
+	RejectedBy    []string `json:"rejectedBy,omitempty"`
+	MissingGroups []string `json:"missingGroups,omitempty"`
+}
+
+// WorkflowArtifacts records which phase outputs exist on the workflow's feature branch
+type WorkflowArtifacts struct {
+	Seeded     bool   `json:"seeded"`            // feature branch exists
+	SpecDir    string `json:"specDir,omitempty"` // e.g. specs/001-feature
+	Spec       bool   `json:"spec"`
+	Plan       bool   `json:"plan"`
+	Tasks      bool   `json:"tasks"`
+	TasksTotal int    `json:"tasksTotal,omitempty"`
+	TasksDone  int    `json:"tasksDone,omitempty"`
+	CheckedAt  string `json:"checkedAt,omitempty"`
+}
+
+// PhaseTransition is one entry of status.phaseHistory
+type PhaseTransition struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	At      string `json:"at"`
+	By      string `json:"by,omitempty"`
+	Forced  bool   `json:"forced,omitempty"`
+	Session string `json:"session,omitempty"` // session launched for the new phase
+}
+
+// WorkflowTrackerLink links a workspace file to the issue it was published as
+type WorkflowTrackerLink struct {
 	Path    string `json:"path"`
-	JiraKey string `json:"jiraKey"`
+	Tracker string `json:"tracker"` // jira | github
+	Key     string `json:"key"`
+	URL     string `json:"url,omitempty"`
+	// Tasks maps task keys (see tracker.Task.Key) in a published tasks.md to the story/sub-task
+	// issues created for them
+	Tasks map[string]string `json:"tasks,omitempty"`
+}
+
+// WorkflowTrackerSync is the tracker state pulled back into RFEWorkflow status.tracker
+type WorkflowTrackerSync struct {
+	LastSyncTime string         `json:"lastSyncTime,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	Issues       []TrackedIssue `json:"issues,omitempty"`
+	// PendingPrompts are comment prompts waiting for a running interactive session
+	PendingPrompts []TrackerComment `json:"pendingPrompts,omitempty"`
+	// IgnoredPrompts are recent comment prompts not forwarded because their author is not allowed
+	IgnoredPrompts []TrackerComment `json:"ignoredPrompts,omitempty"`
+}
+
+// TrackedIssue is the synced state of one published document or task issue
+type TrackedIssue struct {
+	Path          string           `json:"path"`
+	Task          string           `json:"task,omitempty"`
+	Tracker       string           `json:"tracker"`
+	Key           string           `json:"key"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title,omitempty"`
+	Status        string           `json:"status,omitempty"`
+	Category      string           `json:"category,omitempty"`
+	Assignee      string           `json:"assignee,omitempty"`
+	Updated       string           `json:"updated,omitempty"`
+	LastSyncedAt  string           `json:"lastSyncedAt,omitempty"`
+	LastCommentAt string           `json:"lastCommentAt,omitempty"`
+	Comments      []TrackerComment `json:"comments,omitempty"` // most recent first
+}
+
+// TrackerComment is an issue comment mirrored into the workflow status
+type TrackerComment struct {
+	IssueKey string `json:"issueKey,omitempty"`
+	ID       string `json:"id"`
+	Author   string `json:"author"`
+	Body     string `json:"body"`
+	Created  string `json:"created"`
 }
 
 type CreateRFEWorkflowRequest struct {
@@ -29,6 +148,7 @@ type CreateRFEWorkflowRequest struct {
 	SupportingRepos []GitRepository `json:"supportingRepos,omitempty"`
 	WorkspacePath   string          `json:"workspacePath,omitempty"`
 	ParentOutcome   *string         `json:"parentOutcome,omitempty"`
+	Gates           []PhaseGate     `json:"gates,omitempty"`
 }
 
 type UpdateRFEWorkflowRequest struct {
@@ -37,8 +157,21 @@ type UpdateRFEWorkflowRequest struct {
 	UmbrellaRepo    *GitRepository  `json:"umbrellaRepo,omitempty"`
 	SupportingRepos []GitRepository `json:"supportingRepos,omitempty"`
 	ParentOutcome   *string         `json:"parentOutcome,omitempty"`
+	Gates           []PhaseGate     `json:"gates,omitempty"` // replaces all gates; [] removes them
+}
+
+// PhaseApprovalRequest records the caller's decision on a phase gate
+type PhaseApprovalRequest struct {
+	Phase    string `json:"phase,omitempty"` // defaults to the current phase
+	Decision string `json:"decision" binding:"required,oneof=approve reject"`
+	Comment  string `json:"comment,omitempty"` // required when rejecting
 }
 
 type AdvancePhaseRequest struct {
-	Force bool `json:"force,omitempty"` // Force advance even if current phase isn't complete
+	// Force advance even if current phase isn't complete; approval gates still apply
+	Force bool `json:"force,omitempty"`
+	// StartSession launches the next phase's session (default true)
+	StartSession *bool `json:"startSession,omitempty"`
+	// Agents are personas from .claude/agents to invite into the launched session
+	Agents []string `json:"agents,omitempty"`
 }
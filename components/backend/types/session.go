@@ -1,64 +1,29 @@
 package types
 
-// AgenticSession represents the structure of our custom resource
-type AgenticSession struct {
-	APIVersion string                 `json:"apiVersion"`
-	Kind       string                 `json:"kind"`
-	Metadata   map[string]interface{} `json:"metadata"`
-	Spec       AgenticSessionSpec     `json:"spec"`
-	Status     *AgenticSessionStatus  `json:"status,omitempty"`
-}
-
-type AgenticSessionSpec struct {
-	Prompt               string             `json:"prompt" binding:"required"`
-	Interactive          bool               `json:"interactive,omitempty"`
-	DisplayName          string             `json:"displayName"`
-	LLMSettings          LLMSettings        `json:"llmSettings"`
-	Timeout              int                `json:"timeout"`
-	UserContext          *UserContext       `json:"userContext,omitempty"`
-	BotAccount           *BotAccountRef     `json:"botAccount,omitempty"`
-	ResourceOverrides    *ResourceOverrides `json:"resourceOverrides,omitempty"`
-	EnvironmentVariables map[string]string  `json:"environmentVariables,omitempty"`
-	Project              string             `json:"project,omitempty"`
-	// Multi-repo support (unified mapping)
-	Repos         []SessionRepoMapping `json:"repos,omitempty"`
-	MainRepoIndex *int                 `json:"mainRepoIndex,omitempty"`
-}
-
-// Named repository types for multi-repo session support
-type NamedGitRepo struct {
-	URL    string  `json:"url"`
-	Branch *string `json:"branch,omitempty"`
-}
-
-type OutputNamedGitRepo struct {
-	URL    string  `json:"url"`
-	Branch *string `json:"branch,omitempty"`
-}
+import (
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
 
-// Unified session repo mapping
-type SessionRepoMapping struct {
-	Input  NamedGitRepo        `json:"input"`
-	Output *OutputNamedGitRepo `json:"output,omitempty"`
-	Status *string             `json:"status,omitempty"`
-}
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
-type AgenticSessionStatus struct {
-	Phase          string  `json:"phase,omitempty"`
-	Message        string  `json:"message,omitempty"`
-	StartTime      *string `json:"startTime,omitempty"`
-	CompletionTime *string `json:"completionTime,omitempty"`
-	JobName        string  `json:"jobName,omitempty"`
-	StateDir       string  `json:"stateDir,omitempty"`
-	// Result summary fields from runner
-	Subtype      string                 `json:"subtype,omitempty"`
-	IsError      bool                   `json:"is_error,omitempty"`
-	NumTurns     int                    `json:"num_turns,omitempty"`
-	SessionID    string                 `json:"session_id,omitempty"`
-	TotalCostUSD *float64               `json:"total_cost_usd,omitempty"`
-	Usage        map[string]interface{} `json:"usage,omitempty"`
-	Result       *string                `json:"result,omitempty"`
-}
+// AgenticSession represents the structure of our custom resource
+type AgenticSession struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Metadata   metav1.ObjectMeta     `json:"metadata"`
+	Spec       AgenticSessionSpec    `json:"spec"`
+	Status     *AgenticSessionStatus `json:"status,omitempty"`
+}
+
+// The session spec and status are the typed API shared with the operator
+type (
+	AgenticSessionSpec   = vteamv1alpha1.AgenticSessionSpec
+	AgenticSessionStatus = vteamv1alpha1.AgenticSessionStatus
+	SessionRepoMapping   = vteamv1alpha1.RepoMapping
+	NamedGitRepo         = vteamv1alpha1.GitRepoRef
+	OutputNamedGitRepo   = vteamv1alpha1.GitRepoRef
+	SessionSecrets       = vteamv1alpha1.SessionSecrets
+)
 
 type CreateAgenticSessionRequest struct {
 	Prompt          string       `json:"prompt" binding:"required"`
@@ -78,6 +43,11 @@ type CreateAgenticSessionRequest struct {
 	EnvironmentVariables map[string]string    `json:"environmentVariables,omitempty"`
 	Labels               map[string]string    `json:"labels,omitempty"`
 	Annotations          map[string]string    `json:"annotations,omitempty"`
+	// AgentPersonas activates agents seeded on the rfe-workflow label's feature branch
+	AgentPersonas []string `json:"agentPersonas,omitempty"`
+	// Secrets selects the runner secret keys the session may see; omitted uses the project
+	// default from ProjectSettings, or the whole runner secret
+	Secrets *SessionSecrets `json:"secrets,omitempty"`
 }
 
 type CloneSessionRequest struct {
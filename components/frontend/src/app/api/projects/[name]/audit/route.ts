@@ -0,0 +1,21 @@
+import { BACKEND_URL } from '@/lib/config';
+import { buildForwardHeadersAsync } from '@/lib/auth';
+
+// GET /api/projects/[name]/audit - Query the project audit log (filters pass through)
+export async function GET(
+  request: Request,
+  { params }: { params: Promise<{ name: string }> }
+) {
+  try {
+    const { name } = await params;
+    const headers = await buildForwardHeadersAsync(request);
+    const { search } = new URL(request.url);
+
+    const resp = await fetch(`${BACKEND_URL}/projects/${encodeURIComponent(name)}/audit${search}`, { headers });
+    const data = await resp.json().catch(() => ({}));
+    return Response.json(data, { status: resp.status });
+  } catch (error) {
+    console.error('Error fetching audit log:', error);
+    return Response.json({ error: 'Failed to fetch audit log' }, { status: 500 });
+  }
+}
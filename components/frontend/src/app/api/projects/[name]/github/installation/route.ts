@@ -0,0 +1,35 @@
+import { BACKEND_URL } from '@/lib/config';
+import { buildForwardHeadersAsync } from '@/lib/auth';
+
+type Ctx = { params: Promise<{ name: string }> };
+
+async function proxy(request: Request, { params }: Ctx, method: 'GET' | 'PUT' | 'DELETE') {
+  try {
+    const { name } = await params;
+    const headers = await buildForwardHeadersAsync(request);
+    const body = method === 'PUT' ? await request.text() : undefined;
+
+    const resp = await fetch(`${BACKEND_URL}/projects/${encodeURIComponent(name)}/github/installation`, {
+      method,
+      headers,
+      body,
+    });
+    const data = await resp.json().catch(() => ({}));
+    return Response.json(data, { status: resp.status });
+  } catch (error) {
+    console.error(`Error proxying project GitHub installation (${method}):`, error);
+    return Response.json({ error: 'Failed to reach backend' }, { status: 500 });
+  }
+}
+
+export async function GET(request: Request, ctx: Ctx) {
+  return proxy(request, ctx, 'GET');
+}
+
+export async function PUT(request: Request, ctx: Ctx) {
+  return proxy(request, ctx, 'PUT');
+}
+
+export async function DELETE(request: Request, ctx: Ctx) {
+  return proxy(request, ctx, 'DELETE');
+}
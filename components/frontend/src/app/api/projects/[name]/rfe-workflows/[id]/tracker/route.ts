@@ -7,6 +7,12 @@ type PublishRequestBody = {
   issueTypeName?: string
 }
 
+function getPhaseForPath(path: string): 'specify' | 'plan' | 'tasks' {
+  if (path.endsWith('tasks.md')) return 'tasks'
+  if (path.endsWith('plan.md')) return 'plan'
+  return 'specify'
+}
+
 function getExpectedPathForPhase(phase: string): string {
   if (phase === 'specify') return 'specs/spec.md'
   if (phase === 'plan') return 'specs/plan.md'
@@ -23,7 +29,7 @@ export async function POST(
 
     const bodyText = await request.text()
     const body: PublishRequestBody = bodyText ? JSON.parse(bodyText) : {}
-    const phase = body.phase || 'specify'
+    const phase = body.phase || (body.path ? getPhaseForPath(body.path) : 'specify')
     const path = body.path || getExpectedPathForPhase(phase)
 
     // Resolve repo/ref for this workflow to fetch content from GitHub via backend
@@ -55,8 +61,8 @@ export async function POST(
     }
     await blobResp.json().catch(async () => ({ content: await blobResp.text() }))
 
-    // 3) Delegate to backend to create Jira and update CR (now that content can be validated server-side if needed)
-    const backendResp = await fetch(`${BACKEND_URL}/projects/${encodeURIComponent(name)}/rfe-workflows/${encodeURIComponent(id)}/jira`, {
+    // 3) Delegate to backend to create the tracker issue and update CR (now that content can be validated server-side if needed)
+    const backendResp = await fetch(`${BACKEND_URL}/projects/${encodeURIComponent(name)}/rfe-workflows/${encodeURIComponent(id)}/tracker`, {
       method: 'POST',
       headers: { 'Content-Type': 'application/json', ...headers },
       body: JSON.stringify({ path, phase })
@@ -64,12 +70,12 @@ export async function POST(
     const text = await backendResp.text()
     return new Response(text, { status: backendResp.status, headers: { 'Content-Type': 'application/json' } })
   } catch (error) {
-    console.error('Error publishing to Jira:', error)
-    return Response.json({ error: 'Failed to publish to Jira' }, { status: 500 })
+    console.error('Error publishing to issue tracker:', error)
+    return Response.json({ error: 'Failed to publish to issue tracker' }, { status: 500 })
   }
 }
 
-// GET /api/projects/[name]/rfe/[id]/jira?path=...
+// GET /api/projects/[name]/rfe-workflows/[id]/tracker?path=...
 export async function GET(
   request: Request,
   { params }: { params: Promise<{ name: string; id: string }> }
@@ -79,12 +85,12 @@ export async function GET(
     const headers = await buildForwardHeadersAsync(request)
     const url = new URL(request.url)
     const pathParam = url.searchParams.get('path') || ''
-    const backendResp = await fetch(`${BACKEND_URL}/projects/${encodeURIComponent(name)}/rfe-workflows/${encodeURIComponent(id)}/jira?path=${encodeURIComponent(pathParam)}`, { headers })
+    const backendResp = await fetch(`${BACKEND_URL}/projects/${encodeURIComponent(name)}/rfe-workflows/${encodeURIComponent(id)}/tracker?path=${encodeURIComponent(pathParam)}`, { headers })
     const text = await backendResp.text()
     return new Response(text, { status: backendResp.status, headers: { 'Content-Type': 'application/json' } })
   } catch (error) {
-    console.error('Error fetching Jira issue:', error)
-    return Response.json({ error: 'Failed to fetch Jira issue' }, { status: 500 })
+    console.error('Error fetching tracker issue:', error)
+    return Response.json({ error: 'Failed to fetch tracker issue' }, { status: 500 })
   }
 }
 
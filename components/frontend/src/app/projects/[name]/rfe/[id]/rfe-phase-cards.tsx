@@ -112,18 +112,9 @@ You can invoke agents by using their name in your prompts. For example: "Let's g
                       ? specKitDir.tasks.exists
                       : false;
 
-            const linkedKey = Array.isArray(
-              (workflow as unknown as { jiraLinks?: Array<{ path: string; jiraKey: string }> })
-                .jiraLinks
-            )
-              ? (
-                  (
-                    workflow as unknown as {
-                      jiraLinks?: Array<{ path: string; jiraKey: string }>;
-                    }
-                  ).jiraLinks || []
-                ).find((l) => l.path === expected)?.jiraKey
-              : undefined;
+            const trackerLink = (workflow.trackerLinks || []).find((l) => l.path === expected);
+            const linkedKey = trackerLink?.key;
+            const trackerLabel = trackerLink?.tracker === "github" ? "GitHub" : "Jira";
 
             const sessionForPhase = rfeSessions.find(
               (s) => s.metadata.labels?.["rfe-phase"] === phase
@@ -401,7 +392,7 @@ You can invoke agents by using their name in your prompts. For example: "Let's g
                               }
                             },
                             onError: (err) => {
-                              onError(err.message || "Failed to publish to Jira");
+                              onError(err.message || "Failed to publish to issue tracker");
                               onPublishPhase(null);
                             },
                           }
@@ -417,7 +408,7 @@ You can invoke agents by using their name in your prompts. For example: "Let's g
                       ) : (
                         <>
                           <Upload className="mr-2 h-4 w-4" />
-                          {linkedKey ? "Resync with Jira" : "Publish to Jira"}
+                          {linkedKey ? `Resync with ${trackerLabel}` : "Publish issue"}
                         </>
                       )}
                     </Button>
@@ -431,7 +422,7 @@ You can invoke agents by using their name in your prompts. For example: "Let's g
                         className="px-0 h-auto"
                         onClick={() => onOpenJira(expected)}
                       >
-                        Open in Jira
+                        Open in {trackerLabel}
                       </Button>
                     </div>
                   )}
@@ -25,6 +25,7 @@ const phaseLabel: Record<WorkflowPhase, string> = {
   tasks: 'Tasks',
   implement: 'Implement',
   review: 'Review',
+  done: 'Done',
   completed: 'Completed',
 };
 
@@ -785,7 +785,7 @@ export default function ProjectSessionDetailPage({
         </div>
 
         {/* Stats */}
-        <div className="grid grid-cols-1 sm:grid-cols-4 gap-3">
+        <div className="grid grid-cols-1 sm:grid-cols-5 gap-3">
           <Card className="py-4">
             <CardContent>
               <div className="text-xs text-muted-foreground">Duration</div>
@@ -804,6 +804,37 @@ export default function ProjectSessionDetailPage({
               <div className="text-lg font-semibold">{subagentStats.uniqueCount > 0 ? subagentStats.uniqueCount : "-"}</div>
             </CardContent>
           </Card>
+          <Card className="py-4">
+            <CardContent>
+              <div className="text-xs text-muted-foreground">Granted Secrets</div>
+              <div className="text-lg font-semibold">{session.status?.grantedSecretKeys ? session.status.grantedSecretKeys.length : "-"}</div>
+              {(session.status?.grantedSecretKeys?.length ?? 0) > 0 && (
+                <div className="text-xs font-mono text-muted-foreground truncate" title={session.status?.grantedSecretKeys?.join(", ")}>
+                  {session.status?.grantedSecretKeys?.join(", ")}
+                </div>
+              )}
+            </CardContent>
+          </Card>
+          <Card className="py-4">
+            <CardContent>
+              <div className="text-xs text-muted-foreground">Egress</div>
+              <div className="text-lg font-semibold">{session.status?.egressPolicy?.mode ?? "-"}</div>
+              {session.status?.egressPolicy?.mode === "Restricted" && (
+                <div
+                  className="text-xs font-mono text-muted-foreground truncate"
+                  title={[...(session.status.egressPolicy.allowedHosts ?? []), ...(session.status.egressPolicy.allowedCIDRs ?? [])].join(", ")}
+                >
+                  {[...(session.status.egressPolicy.presets ?? []), ...(session.status.egressPolicy.allowedHosts ?? [])].join(", ") || "no hosts"}
+                  {session.status.egressPolicy.proxy ? " (via proxy)" : ""}
+                </div>
+              )}
+              {(session.status?.egressPolicy?.warnings?.length ?? 0) > 0 && (
+                <div className="text-xs text-amber-600 truncate" title={session.status?.egressPolicy?.warnings?.join("\n")}>
+                  {session.status?.egressPolicy?.warnings?.length} warning(s)
+                </div>
+              )}
+            </CardContent>
+          </Card>
         </div>
 
         {/* Tabs */}
@@ -19,8 +19,11 @@ import { Breadcrumbs } from "@/components/breadcrumbs";
 import { RepositoryDialog } from "./repository-dialog";
 import { RepositoryList } from "./repository-list";
 import { ModelConfiguration } from "./model-configuration";
-import { useCreateSession } from "@/services/queries/use-sessions";
+import { Label } from "@/components/ui/label";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select";
+import { useCreateSession, useSessionTemplates } from "@/services/queries/use-sessions";
 import { useRfeWorkflow } from "@/services/queries/use-rfe";
+import { useSecretsConfig, useSecretsMetadata } from "@/services/queries/use-secrets";
 
 const formSchema = z
   .object({
@@ -67,10 +70,26 @@ export default function NewProjectSessionPage({ params }: { params: Promise<{ na
   const [editingRepoIndex, setEditingRepoIndex] = useState<number | null>(null);
   const [repoDialogOpen, setRepoDialogOpen] = useState(false);
   const [tempRepo, setTempRepo] = useState<{ input: { url: string; branch: string }; output?: { url: string; branch: string } }>({ input: { url: "", branch: "main" } });
+  // Runner secret selection; when off the project default applies (usually the whole runner secret)
+  const [limitSecrets, setLimitSecrets] = useState(false);
+  const [selectedSecretKeys, setSelectedSecretKeys] = useState<string[]>([]);
+  const [selectedBundles, setSelectedBundles] = useState<string[]>([]);
+  // Starter session template the form was prefilled from, if any
+  const [sessionTemplateName, setSessionTemplateName] = useState<string>("");
 
   // React Query hooks
   const createSessionMutation = useCreateSession();
   const { data: rfeWorkflow } = useRfeWorkflow(projectName, rfeWorkflowId || "");
+  const { data: secretsConfig } = useSecretsConfig(projectName);
+  const { data: secretsMetadata } = useSecretsMetadata(projectName);
+  const { data: sessionTemplates = [] } = useSessionTemplates(projectName);
+  const availableSecretKeys = Array.from(new Set([
+    ...(secretsMetadata?.keys || []).map((k) => k.key),
+    ...(secretsMetadata?.refs || []).map((r) => r.envName),
+  ])).sort();
+  const secretBundles = secretsConfig?.secretBundles || [];
+  const toggleItem = (items: string[], item: string, on: boolean) =>
+    on ? [...items.filter((i) => i !== item), item] : items.filter((i) => i !== item);
 
   useEffect(() => {
     params.then(({ name }) => setProjectName(name));
@@ -126,6 +145,36 @@ export default function NewProjectSessionPage({ params }: { params: Promise<{ na
 
   
 
+  const applySessionTemplate = (name: string) => {
+    const template = sessionTemplates.find((t) => t.name === name);
+    if (!template) return;
+    const s = template.session;
+    const current = form.getValues();
+    form.reset({
+      ...current,
+      prompt: s.prompt ?? current.prompt,
+      interactive: s.interactive ?? current.interactive,
+      timeout: s.timeout ?? current.timeout,
+      model: s.llmSettings?.model ?? current.model,
+      temperature: s.llmSettings?.temperature ?? current.temperature,
+      maxTokens: s.llmSettings?.maxTokens ?? current.maxTokens,
+      autoPushOnComplete: s.autoPushOnComplete ?? current.autoPushOnComplete,
+      repos: s.repos
+        ? s.repos.map((r) => ({
+            input: { url: r.input.url, branch: r.input.branch || "main" },
+            output: r.output ? { url: r.output.url, branch: r.output.branch || "" } : undefined,
+          }))
+        : current.repos,
+      mainRepoIndex: s.mainRepoIndex ?? current.mainRepoIndex,
+    });
+    if (s.secrets) {
+      setLimitSecrets(true);
+      setSelectedSecretKeys(s.secrets.keys || []);
+      setSelectedBundles(s.secrets.bundles || []);
+    }
+    setSessionTemplateName(name);
+  };
+
   const onSubmit = async (values: FormValues) => {
     if (!projectName) return;
 
@@ -148,6 +197,10 @@ export default function NewProjectSessionPage({ params }: { params: Promise<{ na
         request.workspacePath = prefillWorkspacePath;
       }
 
+      if (limitSecrets) {
+        request.secrets = { keys: selectedSecretKeys, bundles: selectedBundles };
+      }
+
       // Apply labels if rfeWorkflowId is present
       if (rfeWorkflowId || projectName) {
         request.labels = {
@@ -217,6 +270,26 @@ export default function NewProjectSessionPage({ params }: { params: Promise<{ na
         <CardContent>
           <Form {...form}>
             <form onSubmit={form.handleSubmit(onSubmit)} className="space-y-6">
+              {sessionTemplates.length > 0 && (
+                <div className="space-y-2">
+                  <Label>Start from template (optional)</Label>
+                  <Select value={sessionTemplateName} onValueChange={applySessionTemplate}>
+                    <SelectTrigger>
+                      <SelectValue placeholder="Select a session template" />
+                    </SelectTrigger>
+                    <SelectContent>
+                      {sessionTemplates.map((t) => (
+                        <SelectItem key={t.name} value={t.name}>
+                          {t.name}
+                          {t.description ? ` — ${t.description}` : ""}
+                        </SelectItem>
+                      ))}
+                    </SelectContent>
+                  </Select>
+                  <p className="text-sm text-muted-foreground">Prefills the form from a starter session set up with this project.</p>
+                </div>
+              )}
+
               <FormField
                 control={form.control}
                 name="interactive"
@@ -254,7 +327,7 @@ export default function NewProjectSessionPage({ params }: { params: Promise<{ na
               )}
 
 
-              <ModelConfiguration control={form.control} />
+              <ModelConfiguration key={sessionTemplateName} control={form.control} />
 
               {/* Multi-agent selection */}
               <div className="space-y-2">
@@ -328,6 +401,59 @@ export default function NewProjectSessionPage({ params }: { params: Promise<{ na
                 )}
               />
 
+              {/* Runner secrets */}
+              <div className="rounded-md border p-3 space-y-3">
+                <div className="flex flex-row items-start space-x-3">
+                  <Checkbox id="limitSecrets" checked={limitSecrets} onCheckedChange={(v) => setLimitSecrets(Boolean(v))} />
+                  <div className="space-y-1 leading-none">
+                    <label htmlFor="limitSecrets" className="text-sm font-medium">Limit runner secrets</label>
+                    <p className="text-sm text-muted-foreground">
+                      Only the selected keys are given to this session. Otherwise the project default applies.
+                    </p>
+                  </div>
+                </div>
+                {limitSecrets && (
+                  <div className="space-y-3 pl-7">
+                    {secretBundles.length > 0 && (
+                      <div className="space-y-2">
+                        <div className="text-sm font-medium">Bundles</div>
+                        {secretBundles.map((b) => (
+                          <div key={b.name} className="flex items-start gap-2">
+                            <Checkbox
+                              id={`bundle-${b.name}`}
+                              checked={selectedBundles.includes(b.name)}
+                              onCheckedChange={(v) => setSelectedBundles((prev) => toggleItem(prev, b.name, Boolean(v)))}
+                            />
+                            <label htmlFor={`bundle-${b.name}`} className="text-sm">
+                              {b.name}
+                              <span className="text-muted-foreground"> — {b.description || b.keys.join(", ")}</span>
+                            </label>
+                          </div>
+                        ))}
+                      </div>
+                    )}
+                    <div className="space-y-2">
+                      <div className="text-sm font-medium">Keys</div>
+                      {availableSecretKeys.length === 0 && (
+                        <div className="text-sm text-muted-foreground">No runner secret keys are configured in this project.</div>
+                      )}
+                      <div className="grid grid-cols-1 md:grid-cols-2 gap-2">
+                        {availableSecretKeys.map((key) => (
+                          <div key={key} className="flex items-center gap-2">
+                            <Checkbox
+                              id={`secret-${key}`}
+                              checked={selectedSecretKeys.includes(key)}
+                              onCheckedChange={(v) => setSelectedSecretKeys((prev) => toggleItem(prev, key, Boolean(v)))}
+                            />
+                            <label htmlFor={`secret-${key}`} className="text-sm font-mono">{key}</label>
+                          </div>
+                        ))}
+                      </div>
+                    </div>
+                  </div>
+                )}
+              </div>
+
               {/* Storage paths are managed automatically by the backend/operator */}
 
               {createSessionMutation.isError && (
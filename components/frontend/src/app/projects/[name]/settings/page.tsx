@@ -15,14 +15,19 @@ import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@
 import { Alert, AlertDescription } from "@/components/ui/alert";
 import { successToast, errorToast } from "@/hooks/use-toast";
 import { useProject, useUpdateProject } from "@/services/queries/use-projects";
-import { useSecretsList, useSecretsConfig, useSecretsValues, useUpdateSecretsConfig, useUpdateSecrets } from "@/services/queries/use-secrets";
+import { useSecretsList, useSecretsConfig, useSecretsMetadata, useUpdateSecretsConfig, useUpdateSecrets } from "@/services/queries/use-secrets";
 import { useMemo } from "react";
+import type { SecretRef } from "@/services/api/secrets";
 
 export default function ProjectSettingsPage({ params }: { params: Promise<{ name: string }> }) {
   const [projectName, setProjectName] = useState<string>("");
   const [formData, setFormData] = useState({ displayName: "", description: "" });
   const [secretName, setSecretName] = useState<string>("");
-  const [secrets, setSecrets] = useState<Array<{ key: string; value: string }>>([]);
+  // stored rows already exist in the Secret; their values are never sent back, so blank keeps them
+  const [secrets, setSecrets] = useState<Array<{ key: string; value: string; stored?: boolean }>>([]);
+  const [storedKeys, setStoredKeys] = useState<string[]>([]);
+  const [removedKeys, setRemovedKeys] = useState<string[]>([]);
+  const [secretRefs, setSecretRefs] = useState<SecretRef[]>([]);
   const [mode, setMode] = useState<"existing" | "new">("existing");
   const [showValues, setShowValues] = useState<Record<number, boolean>>({});
   const [anthropicApiKey, setAnthropicApiKey] = useState<string>("");
@@ -42,7 +47,7 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
   const { data: project, isLoading: projectLoading, refetch: refetchProject } = useProject(projectName);
   const { data: secretsList } = useSecretsList(projectName);
   const { data: secretsConfig } = useSecretsConfig(projectName);
-  const { data: secretsValues } = useSecretsValues(projectName);
+  const { data: secretsMetadata } = useSecretsMetadata(projectName);
   const updateProjectMutation = useUpdateProject();
   const updateSecretsConfigMutation = useUpdateSecretsConfig();
   const updateSecretsMutation = useUpdateSecrets();
@@ -69,24 +74,34 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
         setSecretName("ambient-runner-secrets");
         setMode("new");
       }
+      setSecretRefs(secretsConfig.secretRefs || []);
     }
   }, [secretsConfig]);
 
-  // Sync secrets values to state
+  // Sync stored secret keys to state; values stay server-side
   useEffect(() => {
-    if (secretsValues) {
-      const byKey: Record<string, string> = Object.fromEntries(secretsValues.map(s => [s.key, s.value]));
-      setAnthropicApiKey(byKey["ANTHROPIC_API_KEY"] || "");
-      setGitUserName(byKey["GIT_USER_NAME"] || "");
-      setGitUserEmail(byKey["GIT_USER_EMAIL"] || "");
-      setGitToken(byKey["GIT_TOKEN"] || "");
-      setJiraUrl(byKey["JIRA_URL"] || "");
-      setJiraProject(byKey["JIRA_PROJECT"] || "");
-      setJiraEmail(byKey["JIRA_EMAIL"] || "");
-      setJiraToken(byKey["JIRA_API_TOKEN"] || "");
-      setSecrets(secretsValues.filter(s => !FIXED_KEYS.includes(s.key as typeof FIXED_KEYS[number])));
+    if (secretsMetadata) {
+      const keys = secretsMetadata.keys.map((k) => k.key);
+      setStoredKeys(keys);
+      setRemovedKeys([]);
+      setAnthropicApiKey("");
+      setGitUserName("");
+      setGitUserEmail("");
+      setGitToken("");
+      setJiraUrl("");
+      setJiraProject("");
+      setJiraEmail("");
+      setJiraToken("");
+      setSecrets(
+        keys
+          .filter((key) => !FIXED_KEYS.includes(key as typeof FIXED_KEYS[number]))
+          .map((key) => ({ key, value: "", stored: true }))
+      );
     }
-  }, [secretsValues, FIXED_KEYS]);
+  }, [secretsMetadata, FIXED_KEYS]);
+
+  const placeholderFor = (key: string, fallback: string) =>
+    storedKeys.includes(key) ? "•••••••• saved, leave blank to keep" : fallback;
 
   const handleRefresh = () => {
     void refetchProject();
@@ -120,9 +135,17 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
 
     const name = secretName.trim() || "ambient-runner-secrets";
 
+    const refs = secretRefs
+      .map((r) => ({ envName: r.envName.trim(), path: r.path.trim(), key: r.key.trim() }))
+      .filter((r) => r.envName || r.path || r.key);
+    if (refs.some((r) => !r.envName || !r.path || !r.key)) {
+      errorToast("Each secret reference needs an environment variable, path and key");
+      return;
+    }
+
     // First update config
     updateSecretsConfigMutation.mutate(
-      { projectName, secretName: name },
+      { projectName, secretName: name, secretRefs: refs },
       {
         onSuccess: () => {
           // Then update secrets values
@@ -135,9 +158,10 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
           if (jiraProject) data["JIRA_PROJECT"] = jiraProject;
           if (jiraEmail) data["JIRA_EMAIL"] = jiraEmail;
           if (jiraToken) data["JIRA_API_TOKEN"] = jiraToken;
-          for (const { key, value } of secrets) {
+          for (const { key, value, stored } of secrets) {
             if (!key) continue;
             if (FIXED_KEYS.includes(key as typeof FIXED_KEYS[number])) continue;
+            if (stored && !value) continue;
             data[key] = value ?? "";
           }
 
@@ -145,6 +169,7 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
             {
               projectName,
               secrets: Object.entries(data).map(([key, value]) => ({ key, value })),
+              remove: removedKeys.filter((key) => !(key in data)),
             },
             {
               onSuccess: () => {
@@ -170,9 +195,17 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
   };
 
   const removeSecretRow = (idx: number) => {
+    const row = secrets[idx];
+    if (row?.stored) {
+      setRemovedKeys((prev) => [...prev, row.key]);
+    }
     setSecrets((prev) => prev.filter((_, i) => i !== idx));
   };
 
+  const updateSecretRef = (idx: number, field: keyof SecretRef, value: string) => {
+    setSecretRefs((prev) => prev.map((r, i) => (i === idx ? { ...r, [field]: value } : r)));
+  };
+
   return (
     <div className="container mx-auto p-6 max-w-4xl">
       <Breadcrumbs
@@ -317,7 +350,12 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
           {(mode === "new" || (mode === "existing" && !!secretName)) && (
             <div className="pt-2 space-y-2">
               <div className="flex items-center justify-between">
-                <Label>Key/Value Pairs</Label>
+                <div>
+                  <Label>Key/Value Pairs</Label>
+                  {secretsMetadata?.updatedAt && (
+                    <div className="text-xs text-muted-foreground">Last updated {new Date(secretsMetadata.updatedAt).toLocaleString()}</div>
+                  )}
+                </div>
                 <Button variant="outline" onClick={addSecretRow}>
                   <Plus className="w-4 h-4 mr-2" /> Add Row
                 </Button>
@@ -335,6 +373,7 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
                         }
                         placeholder="KEY"
                         className="w-1/3"
+                        readOnly={item.stored}
                       />
                       <div className="flex-1 flex items-center gap-2">
                         <Input
@@ -343,7 +382,7 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
                           onChange={(e) =>
                             setSecrets((prev) => prev.map((it, i) => (i === idx ? { ...it, value: e.target.value } : it)))
                           }
-                          placeholder="value"
+                          placeholder={item.stored ? "•••••••• saved, leave blank to keep" : "value"}
                           className="flex-1"
                         />
                         <Button
@@ -369,7 +408,7 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
                     <Input
                       id="anthropicApiKey"
                       type={showAnthropicKey ? "text" : "password"}
-                      placeholder="sk-ant-..."
+                      placeholder={placeholderFor("ANTHROPIC_API_KEY", "sk-ant-...")}
                       value={anthropicApiKey}
                       onChange={(e) => setAnthropicApiKey(e.target.value)}
                       className="flex-1"
@@ -391,11 +430,11 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
                 <div className="grid grid-cols-1 md:grid-cols-2 gap-3">
                   <div className="space-y-1">
                     <Label htmlFor="gitUserName">Git User Name</Label>
-                    <Input id="gitUserName" placeholder="Your Name" value={gitUserName} onChange={(e) => setGitUserName(e.target.value)} />
+                    <Input id="gitUserName" placeholder={placeholderFor("GIT_USER_NAME", "Your Name")} value={gitUserName} onChange={(e) => setGitUserName(e.target.value)} />
                   </div>
                   <div className="space-y-1">
                     <Label htmlFor="gitUserEmail">Git User Email</Label>
-                    <Input id="gitUserEmail" placeholder="you@example.com" value={gitUserEmail} onChange={(e) => setGitUserEmail(e.target.value)} />
+                    <Input id="gitUserEmail" placeholder={placeholderFor("GIT_USER_EMAIL", "you@example.com")} value={gitUserEmail} onChange={(e) => setGitUserEmail(e.target.value)} />
                   </div>
                 </div>
                 <div className="space-y-2">
@@ -404,7 +443,7 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
                     <Input
                       id="gitToken"
                       type={showGitToken ? "text" : "password"}
-                      placeholder="ghp_... or glpat-..."
+                      placeholder={placeholderFor("GIT_TOKEN", "ghp_... or glpat-...")}
                       value={gitToken}
                       onChange={(e) => setGitToken(e.target.value)}
                       className="flex-1"
@@ -425,20 +464,20 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
                 <div className="grid grid-cols-1 md:grid-cols-2 gap-3">
                   <div className="space-y-1">
                     <Label htmlFor="jiraUrl">Jira Base URL</Label>
-                    <Input id="jiraUrl" placeholder="https://your-domain.atlassian.net" value={jiraUrl} onChange={(e) => setJiraUrl(e.target.value)} />
+                    <Input id="jiraUrl" placeholder={placeholderFor("JIRA_URL", "https://your-domain.atlassian.net")} value={jiraUrl} onChange={(e) => setJiraUrl(e.target.value)} />
                   </div>
                   <div className="space-y-1">
                     <Label htmlFor="jiraProject">Jira Project Key</Label>
-                    <Input id="jiraProject" placeholder="ABC" value={jiraProject} onChange={(e) => setJiraProject(e.target.value)} />
+                    <Input id="jiraProject" placeholder={placeholderFor("JIRA_PROJECT", "ABC")} value={jiraProject} onChange={(e) => setJiraProject(e.target.value)} />
                   </div>
                   <div className="space-y-1">
                     <Label htmlFor="jiraEmail">Jira Email/Username</Label>
-                    <Input id="jiraEmail" placeholder="you@example.com" value={jiraEmail} onChange={(e) => setJiraEmail(e.target.value)} />
+                    <Input id="jiraEmail" placeholder={placeholderFor("JIRA_EMAIL", "you@example.com")} value={jiraEmail} onChange={(e) => setJiraEmail(e.target.value)} />
                   </div>
                   <div className="space-y-1">
                     <Label htmlFor="jiraToken">Jira API Token</Label>
                     <div className="flex items-center gap-2">
-                      <Input id="jiraToken" type={showJiraToken ? "text" : "password"} placeholder="token" value={jiraToken} onChange={(e) => setJiraToken(e.target.value)} />
+                      <Input id="jiraToken" type={showJiraToken ? "text" : "password"} placeholder={placeholderFor("JIRA_API_TOKEN", "token")} value={jiraToken} onChange={(e) => setJiraToken(e.target.value)} />
                       <Button type="button" variant="ghost" onClick={() => setShowJiraToken((v) => !v)} aria-label={showJiraToken ? "Hide token" : "Show token"}>
                         {showJiraToken ? <EyeOff className="w-4 h-4" /> : <Eye className="w-4 h-4" />}
                       </Button>
@@ -450,6 +489,33 @@ export default function ProjectSettingsPage({ params }: { params: Promise<{ name
             </div>
           )}
 
+          <div className="pt-4 space-y-2 border-t">
+            <div className="flex items-center justify-between pt-3">
+              <div>
+                <Label className="text-base font-semibold">External Secret References</Label>
+                <div className="text-xs text-muted-foreground">
+                  Read from the cluster&apos;s secret store under this project&apos;s scope when each session starts. Values never pass through this page.
+                </div>
+              </div>
+              <Button variant="outline" onClick={() => setSecretRefs((prev) => [...prev, { envName: "", path: "", key: "" }])}>
+                <Plus className="w-4 h-4 mr-2" /> Add Reference
+              </Button>
+            </div>
+            {secretRefs.length === 0 && (
+              <div className="text-sm text-muted-foreground">No references configured.</div>
+            )}
+            {secretRefs.map((ref, idx) => (
+              <div key={idx} className="flex gap-2 items-center">
+                <Input value={ref.envName} onChange={(e) => updateSecretRef(idx, "envName", e.target.value)} placeholder="ENV_NAME" className="w-1/3" />
+                <Input value={ref.path} onChange={(e) => updateSecretRef(idx, "path", e.target.value)} placeholder="path/in/store" className="flex-1" />
+                <Input value={ref.key} onChange={(e) => updateSecretRef(idx, "key", e.target.value)} placeholder="key" className="w-1/4" />
+                <Button variant="ghost" onClick={() => setSecretRefs((prev) => prev.filter((_, i) => i !== idx))} aria-label="Remove reference">
+                  <Trash2 className="w-4 h-4" />
+                </Button>
+              </div>
+            ))}
+          </div>
+
           <div className="pt-2">
             <Button
               onClick={handleSaveSecrets}
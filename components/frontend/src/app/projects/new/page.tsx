@@ -18,7 +18,8 @@ import { CreateProjectRequest } from "@/types/project";
 import { ArrowLeft, Save, Loader2, Info } from "lucide-react";
 import { successToast, errorToast } from "@/hooks/use-toast";
 import { Breadcrumbs } from "@/components/breadcrumbs";
-import { useCreateProject } from "@/services/queries";
+import { useCreateProject, useProjectTemplates } from "@/services/queries";
+import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from "@/components/ui/select";
 import { useClusterInfo } from "@/hooks/use-cluster-info";
 import { Alert, AlertDescription } from "@/components/ui/alert";
 
@@ -26,6 +27,7 @@ export default function NewProjectPage() {
   const router = useRouter();
   const createProjectMutation = useCreateProject();
   const { isOpenShift, isLoading: clusterLoading } = useClusterInfo();
+  const { data: templates = [] } = useProjectTemplates();
   const [error, setError] = useState<string | null>(null);
   const [formData, setFormData] = useState<CreateProjectRequest>({
     name: "",
@@ -34,6 +36,11 @@ export default function NewProjectPage() {
   });
 
   const [nameError, setNameError] = useState<string | null>(null);
+  // Project template and the runner secret values it asks for
+  const [templateName, setTemplateName] = useState<string>("");
+  const [runnerSecrets, setRunnerSecrets] = useState<Record<string, string>>({});
+  const template = templates.find((t) => t.name === templateName);
+  const missingSecrets = (template?.runnerSecrets || []).filter((s) => s.required && !runnerSecrets[s.key]?.trim());
 
   const validateProjectName = (name: string) => {
     // Validate name pattern: ^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
@@ -78,6 +85,13 @@ export default function NewProjectPage() {
       ...(isOpenShift && formData.displayName?.trim() && { displayName: formData.displayName.trim() }),
       ...(isOpenShift && formData.description?.trim() && { description: formData.description.trim() }),
     };
+    if (template) {
+      payload.template = template.name;
+      const values = Object.fromEntries(Object.entries(runnerSecrets).filter(([, v]) => v.trim()));
+      if (Object.keys(values).length > 0) {
+        payload.runnerSecrets = values;
+      }
+    }
 
     createProjectMutation.mutate(payload, {
       onSuccess: (project) => {
@@ -185,7 +199,69 @@ export default function NewProjectPage() {
               )}
             </div>
 
-            {/* Resource quota inputs removed */}
+            {templates.length > 0 && (
+              <div className="space-y-4">
+                <h3 className="text-lg font-semibold">Template</h3>
+
+                <div className="space-y-2">
+                  <Label htmlFor="template">Project Template</Label>
+                  <Select
+                    value={templateName || "none"}
+                    onValueChange={(v) => {
+                      setTemplateName(v === "none" ? "" : v);
+                      setRunnerSecrets({});
+                    }}
+                  >
+                    <SelectTrigger id="template">
+                      <SelectValue placeholder="No template" />
+                    </SelectTrigger>
+                    <SelectContent>
+                      <SelectItem value="none">No template</SelectItem>
+                      {templates.map((t) => (
+                        <SelectItem key={t.name} value={t.name}>
+                          {t.displayName || t.name}
+                        </SelectItem>
+                      ))}
+                    </SelectContent>
+                  </Select>
+                  <p className="text-sm text-gray-600">
+                    {template?.description ||
+                      "Templates set up project settings, quotas, runner secrets and starter sessions together."}
+                  </p>
+                  {template && (
+                    <p className="text-xs text-muted-foreground">
+                      Includes:{" "}
+                      {[
+                        template.projectSettings && "project settings",
+                        template.resourceQuota && "resource quota",
+                        template.limitRange && "limit range",
+                        (template.sessionTemplates?.length ?? 0) > 0 &&
+                          `${template.sessionTemplates?.length} starter session(s)`,
+                      ]
+                        .filter(Boolean)
+                        .join(", ") || "nothing beyond the project"}
+                    </p>
+                  )}
+                </div>
+
+                {(template?.runnerSecrets || []).map((s) => (
+                  <div key={s.key} className="space-y-2">
+                    <Label htmlFor={`secret-${s.key}`} className="font-mono">
+                      {s.key}
+                      {s.required ? " *" : ""}
+                    </Label>
+                    <Input
+                      id={`secret-${s.key}`}
+                      type="password"
+                      autoComplete="off"
+                      value={runnerSecrets[s.key] || ""}
+                      onChange={(e) => setRunnerSecrets((prev) => ({ ...prev, [s.key]: e.target.value }))}
+                    />
+                    {s.description && <p className="text-sm text-gray-600">{s.description}</p>}
+                  </div>
+                ))}
+              </div>
+            )}
 
             {error && (
               <div className="p-4 bg-red-50 border border-red-200 rounded-md">
@@ -194,7 +270,7 @@ export default function NewProjectPage() {
             )}
 
             <div className="flex gap-4 pt-4">
-              <Button type="submit" disabled={createProjectMutation.isPending || !!nameError}>
+              <Button type="submit" disabled={createProjectMutation.isPending || !!nameError || missingSecrets.length > 0}>
                 {createProjectMutation.isPending ? (
                   <>
                     <Loader2 className="w-4 h-4 mr-2 animate-spin" />
@@ -149,6 +149,7 @@ export const WORKFLOW_PHASE_LABELS = {
   tasks: "✅ Tasks",
   implement: "🚧 Implement",
   review: "👁️ Review",
+  done: "🎉 Done",
   completed: "🎉 Completed"
 };
 
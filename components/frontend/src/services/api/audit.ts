@@ -0,0 +1,64 @@
+/**
+ * API service for the project audit log
+ */
+
+import { apiClient } from './client';
+
+export type AuditOutcome = 'success' | 'failure' | 'denied';
+
+export type AuditEvent = {
+  id: string;
+  time: string;
+  project?: string;
+  requestId?: string;
+  actor: {
+    type: 'user' | 'access-key' | 'service-account' | 'anonymous';
+    id: string;
+    name?: string;
+  };
+  /** e.g. "session.delete", "permission.create", "runner-secrets.update" */
+  action: string;
+  target: {
+    kind: string;
+    name?: string;
+  };
+  method?: string;
+  path?: string;
+  status?: number;
+  outcome: AuditOutcome;
+  details?: Record<string, string>;
+};
+
+export type AuditFilter = {
+  actor?: string;
+  /** Exact action or a prefix ("permission" matches "permission.create") */
+  action?: string;
+  targetKind?: string;
+  target?: string;
+  outcome?: AuditOutcome;
+  requestId?: string;
+  /** RFC3339 time or a duration back from now, e.g. "24h" */
+  since?: string;
+  until?: string;
+  limit?: number;
+};
+
+export type ListAuditEventsResponse = {
+  items: AuditEvent[];
+};
+
+/**
+ * Query audit events for a project, newest first
+ */
+export async function listAuditEvents(
+  projectName: string,
+  filter: AuditFilter = {}
+): Promise<AuditEvent[]> {
+  const params = Object.fromEntries(
+    Object.entries(filter).filter(([, v]) => v !== undefined && v !== '')
+  ) as Record<string, string | number>;
+  const response = await apiClient.get<ListAuditEventsResponse>(`/projects/${projectName}/audit`, {
+    params,
+  });
+  return response.items || [];
+}
@@ -10,6 +10,7 @@ export * as sessionsApi from './sessions';
 export * as rfeApi from './rfe';
 export * as githubApi from './github';
 export * as keysApi from './keys';
+export * as auditApi from './audit';
 export * as repoApi from './repo';
 export * as workspaceApi from './workspace';
 export * as authApi from './auth';
@@ -5,6 +5,14 @@
 import { apiClient } from './client';
 
 // Types
+export type KeyScope =
+  | 'sessions:read'
+  | 'sessions:write'
+  | 'rfe:read'
+  | 'rfe:write'
+  | 'secrets:read'
+  | 'secrets:write';
+
 export type ProjectKey = {
   id: string;
   name: string;
@@ -12,12 +20,20 @@ export type ProjectKey = {
   createdAt?: string;
   lastUsedAt?: string;
   role?: 'view' | 'edit' | 'admin';
+  scopes?: KeyScope[];
+  expiresAt?: string;
+  expired?: boolean;
+  rotatedAt?: string;
 };
 
 export type CreateKeyRequest = {
   name: string;
   description?: string;
+  /** Mutually exclusive with scopes */
   role?: 'view' | 'edit' | 'admin';
+  scopes?: KeyScope[];
+  /** Go duration, e.g. "720h"; defaults to 90 days */
+  ttl?: string;
 };
 
 export type CreateKeyResponse = {
@@ -26,6 +42,21 @@ export type CreateKeyResponse = {
   key: string;
   description?: string;
   role?: 'view' | 'edit' | 'admin';
+  scopes?: KeyScope[];
+  expiresAt?: string;
+};
+
+export type RotateKeyRequest = {
+  ttl?: string;
+};
+
+export type RotateKeyResponse = {
+  id: string;
+  name: string;
+  key: string;
+  expiresAt: string;
+  rotatedAt: string;
+  previousRevoked: boolean;
 };
 
 export type ListKeysResponse = {
@@ -50,6 +81,20 @@ export async function createKey(
   return apiClient.post<CreateKeyResponse, CreateKeyRequest>(`/projects/${projectName}/keys`, data);
 }
 
+/**
+ * Issue a new token for an access key and revoke the previous one
+ */
+export async function rotateKey(
+  projectName: string,
+  keyId: string,
+  data: RotateKeyRequest = {}
+): Promise<RotateKeyResponse> {
+  return apiClient.post<RotateKeyResponse, RotateKeyRequest>(
+    `/projects/${projectName}/keys/${keyId}/rotate`,
+    data
+  );
+}
+
 /**
  * Delete an access key
  */
@@ -11,6 +11,7 @@ import type {
   ListProjectsResponse,
   DeleteProjectResponse,
   PermissionAssignment,
+  ProjectTemplate,
 } from '@/types/api';
 
 /**
@@ -38,6 +39,14 @@ export async function createProject(data: CreateProjectRequest): Promise<Project
   );
 }
 
+/**
+ * List the cluster's project templates
+ */
+export async function listProjectTemplates(): Promise<ProjectTemplate[]> {
+  const response = await apiClient.get<{ items: ProjectTemplate[] }>('/project-templates');
+  return response.items || [];
+}
+
 /**
  * Update an existing project
  */
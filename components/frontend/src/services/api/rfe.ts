@@ -12,12 +12,24 @@ import type {
   UpdateRFEWorkflowRequest,
   StartPhaseRequest,
   StartPhaseResponse,
+  AdvancePhaseRequest,
+  AdvancePhaseResponse,
+  PhaseApprovalRequest,
+  PhaseApprovalResponse,
+  GetApprovalsResponse,
+  SeedSource,
+  SeedPlan,
+  SeedDrift,
+  SeedUpgrade,
+  SeedUpgradeRequest,
+  WorkflowSeed,
   GetArtifactsResponse,
   GetArtifactContentResponse,
   RFEWorkflowStatusResponse,
   ArtifactFile,
   AgenticSession,
   AgentPersona,
+  TrackerIssueStatus,
 } from '@/types/api';
 
 /**
@@ -103,6 +115,46 @@ export async function startWorkflowPhase(
   return response.sessionsCreated;
 }
 
+/**
+ * Advance a workflow to its next phase, launching that phase's session
+ */
+export async function advanceWorkflowPhase(
+  projectName: string,
+  workflowId: string,
+  data: AdvancePhaseRequest = {}
+): Promise<AdvancePhaseResponse> {
+  return apiClient.post<AdvancePhaseResponse, AdvancePhaseRequest>(
+    `/projects/${projectName}/rfe-workflows/${workflowId}/advance`,
+    data
+  );
+}
+
+/**
+ * Get approval gates and the approval audit trail of an RFE workflow
+ */
+export async function getWorkflowApprovals(
+  projectName: string,
+  workflowId: string
+): Promise<GetApprovalsResponse> {
+  return apiClient.get<GetApprovalsResponse>(
+    `/projects/${projectName}/rfe-workflows/${workflowId}/approvals`
+  );
+}
+
+/**
+ * Approve or reject the current phase of an RFE workflow
+ */
+export async function recordWorkflowApproval(
+  projectName: string,
+  workflowId: string,
+  data: PhaseApprovalRequest
+): Promise<PhaseApprovalResponse> {
+  return apiClient.post<PhaseApprovalResponse, PhaseApprovalRequest>(
+    `/projects/${projectName}/rfe-workflows/${workflowId}/approvals`,
+    data
+  );
+}
+
 /**
  * Get RFE workflow status
  */
@@ -193,16 +245,61 @@ export async function seedRfeWorkflow(
 }
 
 /**
- * Get Jira issue for a workflow path
+ * Preview seeding an RFE workflow without changing the repository
+ */
+export async function planRfeWorkflowSeeding(
+  projectName: string,
+  workflowId: string,
+  source: SeedSource = {}
+): Promise<SeedPlan> {
+  const response = await apiClient.post<{ dryRun: boolean; plan: SeedPlan }, SeedSource & { dryRun: boolean }>(
+    `/projects/${projectName}/rfe-workflows/${workflowId}/seed`,
+    { ...source, dryRun: true }
+  );
+  return response.plan;
+}
+
+/**
+ * Compare a seeded workflow branch with its spec-kit version and agent source
+ */
+export async function getRfeWorkflowSeedDrift(
+  projectName: string,
+  workflowId: string,
+  source: SeedSource = {}
+): Promise<{ drift: SeedDrift; recorded?: WorkflowSeed }> {
+  const params = new URLSearchParams(
+    Object.entries(source).filter(([, v]) => !!v) as [string, string][]
+  ).toString();
+  return apiClient.get<{ drift: SeedDrift; recorded?: WorkflowSeed }>(
+    `/projects/${projectName}/rfe-workflows/${workflowId}/seed/drift${params ? `?${params}` : ''}`
+  );
+}
+
+/**
+ * Apply spec-kit and agent updates to a seeded workflow branch as a pull request or commit
+ */
+export async function upgradeRfeWorkflowSeed(
+  projectName: string,
+  workflowId: string,
+  data: SeedUpgradeRequest = {}
+): Promise<{ message: string; upgrade: SeedUpgrade }> {
+  return apiClient.post<{ message: string; upgrade: SeedUpgrade }, SeedUpgradeRequest>(
+    `/projects/${projectName}/rfe-workflows/${workflowId}/seed/upgrade`,
+    data
+  );
+}
+
+/**
+ * Get the tracker issue linked to a workflow path
  */
 export async function getWorkflowJiraIssue(
   projectName: string,
   workflowId: string,
   path: string
-): Promise<{ self: string; key: string } | null> {
+): Promise<TrackerIssueStatus | null> {
   try {
-    return await apiClient.get<{ self: string; key: string }>(
-      `/projects/${projectName}/rfe-workflows/${workflowId}/jira`,
+    return await apiClient.get<TrackerIssueStatus>(
+      `/projects/${projectName}/rfe-workflows/${workflowId}/tracker`,
       {
         params: { path },
       }
@@ -213,15 +310,15 @@ export async function getWorkflowJiraIssue(
 }
 
 /**
- * Publish a workflow path to Jira (create or update issue)
+ * Publish a workflow path to the project's issue tracker (create or update issue)
  */
 export async function publishWorkflowPathToJira(
   projectName: string,
   workflowId: string,
   path: string
-): Promise<{ self: string; key: string }> {
-  return apiClient.post<{ self: string; key: string }, { path: string }>(
-    `/projects/${projectName}/rfe-workflows/${workflowId}/jira`,
+): Promise<{ tracker: string; key: string; url: string }> {
+  return apiClient.post<{ tracker: string; key: string; url: string }, { path: string }>(
+    `/projects/${projectName}/rfe-workflows/${workflowId}/tracker`,
     { path }
   );
 }
@@ -14,8 +14,36 @@ export type SecretList = {
   items: { name: string }[];
 };
 
+export type SecretRef = {
+  envName: string;
+  path: string;
+  key: string;
+};
+
+/**
+ * Named group of runner secret keys that sessions can request (ProjectSettings spec.secretBundles)
+ */
+export type SecretBundle = {
+  name: string;
+  description?: string;
+  keys: string[];
+};
+
 export type SecretsConfig = {
   secretName: string;
+  secretRefs?: SecretRef[];
+  /** Read-only; bundles are defined in ProjectSettings */
+  secretBundles?: SecretBundle[];
+};
+
+/**
+ * Stored runner secret keys and external references; values are never returned
+ */
+export type SecretsMetadata = {
+  secretName: string;
+  keys: { key: string; size: number }[];
+  refs: SecretRef[];
+  updatedAt?: string;
 };
 
 /**
@@ -37,41 +65,42 @@ export async function getSecretsConfig(projectName: string): Promise<SecretsConf
 }
 
 /**
- * Get runner secrets values
+ * Get runner secrets keys and references
  */
-export async function getSecretsValues(projectName: string): Promise<Secret[]> {
-  // apiClient.get already unwraps the 'data' field from the response
-  const data = await apiClient.get<Record<string, string>>(
+export async function getSecretsMetadata(projectName: string): Promise<SecretsMetadata> {
+  return apiClient.get<SecretsMetadata>(
     `/projects/${projectName}/runner-secrets`
   );
-  return Object.entries<string>(data || {}).map(([key, value]) => ({ key, value }));
 }
 
 /**
- * Update runner secrets configuration
+ * Update runner secrets configuration; secretRefs replaces the stored references when given
  */
 export async function updateSecretsConfig(
   projectName: string,
-  secretName: string
+  secretName: string,
+  secretRefs?: SecretRef[]
 ): Promise<void> {
-  await apiClient.put<void, { secretName: string }>(
+  await apiClient.put<void, Omit<SecretsConfig, 'secretBundles'>>(
     `/projects/${projectName}/runner-secrets/config`,
-    { secretName }
+    { secretName, secretRefs }
   );
 }
 
 /**
- * Update runner secrets values
+ * Update runner secrets values: keys in secrets are set, keys in remove are deleted and
+ * every other stored key is kept
  */
 export async function updateSecrets(
   projectName: string,
-  secrets: Secret[]
+  secrets: Secret[],
+  remove: string[] = []
 ): Promise<void> {
   const data: Record<string, string> = Object.fromEntries(
     secrets.map(s => [s.key, s.value])
   );
-  await apiClient.put<void, { data: Record<string, string> }>(
+  await apiClient.put<void, { data: Record<string, string>; remove: string[] }>(
     `/projects/${projectName}/runner-secrets`,
-    { data }
+    { data, remove }
   );
 }
@@ -16,6 +16,7 @@ import type {
   CloneAgenticSessionResponse,
   Message,
   GetSessionMessagesResponse,
+  SessionTemplate,
 } from '@/types/api';
 
 /**
@@ -32,6 +33,16 @@ export async function listSessions(projectName: string): Promise<AgenticSession[
   return response.items || [];
 }
 
+/**
+ * List the project's starter session templates
+ */
+export async function listSessionTemplates(projectName: string): Promise<SessionTemplate[]> {
+  const response = await apiClient.get<{ items: SessionTemplate[] }>(
+    `/projects/${projectName}/session-templates`
+  );
+  return response.items || [];
+}
+
 /**
  * Get a single session
  */
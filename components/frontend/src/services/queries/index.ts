@@ -9,6 +9,7 @@ export * from './use-sessions';
 export * from './use-rfe';
 export * from './use-github';
 export * from './use-keys';
+export * from './use-audit';
 export * from './use-secrets';
 export * from './use-repo';
 export * from './use-workspace';
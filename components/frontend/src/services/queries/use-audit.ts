@@ -0,0 +1,26 @@
+/**
+ * React Query hooks for the project audit log
+ */
+
+import { useQuery } from '@tanstack/react-query';
+import * as auditApi from '../api/audit';
+
+// Query key factory
+export const auditKeys = {
+  all: ['audit'] as const,
+  lists: () => [...auditKeys.all, 'list'] as const,
+  list: (projectName: string, filter: auditApi.AuditFilter) =>
+    [...auditKeys.lists(), projectName, filter] as const,
+};
+
+/**
+ * Hook to query a project's audit log
+ */
+export function useAuditEvents(projectName: string, filter: auditApi.AuditFilter = {}) {
+  return useQuery({
+    queryKey: auditKeys.list(projectName, filter),
+    queryFn: () => auditApi.listAuditEvents(projectName, filter),
+    staleTime: 30 * 1000, // 30 seconds
+    enabled: !!projectName,
+  });
+}
@@ -40,6 +40,29 @@ export function useCreateKey() {
   });
 }
 
+/**
+ * Hook to rotate an access key
+ */
+export function useRotateKey() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({
+      projectName,
+      keyId,
+      data,
+    }: {
+      projectName: string;
+      keyId: string;
+      data?: keysApi.RotateKeyRequest;
+    }) => keysApi.rotateKey(projectName, keyId, data),
+    onSuccess: (_data, variables) => {
+      // Invalidate keys list to refetch
+      queryClient.invalidateQueries({ queryKey: keysKeys.list(variables.projectName) });
+    },
+  });
+}
+
 /**
  * Hook to delete an access key
  */
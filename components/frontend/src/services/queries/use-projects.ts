@@ -21,6 +21,7 @@ export const projectKeys = {
   details: () => [...projectKeys.all, 'detail'] as const,
   detail: (name: string) => [...projectKeys.details(), name] as const,
   permissions: (name: string) => [...projectKeys.detail(name), 'permissions'] as const,
+  templates: () => [...projectKeys.all, 'templates'] as const,
 };
 
 /**
@@ -44,6 +45,16 @@ export function useProject(name: string) {
   });
 }
 
+/**
+ * Hook to fetch the cluster's project templates
+ */
+export function useProjectTemplates() {
+  return useQuery({
+    queryKey: projectKeys.templates(),
+    queryFn: projectsApi.listProjectTemplates,
+  });
+}
+
 /**
  * Hook to create a project
  */
@@ -9,6 +9,9 @@ import type {
   CreateRFEWorkflowRequest,
   UpdateRFEWorkflowRequest,
   StartPhaseRequest,
+  AdvancePhaseRequest,
+  PhaseApprovalRequest,
+  SeedUpgradeRequest,
 } from '@/types/api';
 
 /**
@@ -31,8 +34,12 @@ export const rfeKeys = {
     [...rfeKeys.detail(projectName, workflowId), 'sessions'] as const,
   agents: (projectName: string, workflowId: string) =>
     [...rfeKeys.detail(projectName, workflowId), 'agents'] as const,
+  approvals: (projectName: string, workflowId: string) =>
+    [...rfeKeys.detail(projectName, workflowId), 'approvals'] as const,
   seeding: (projectName: string, workflowId: string) =>
     [...rfeKeys.detail(projectName, workflowId), 'seeding'] as const,
+  seedDrift: (projectName: string, workflowId: string) =>
+    [...rfeKeys.detail(projectName, workflowId), 'seed-drift'] as const,
   jira: (projectName: string, workflowId: string, path: string) =>
     [...rfeKeys.detail(projectName, workflowId), 'jira', path] as const,
 };
@@ -109,6 +116,41 @@ export function useRfeWorkflowSessions(projectName: string, workflowId: string)
   });
 }
 
+/**
+ * Hook to fetch approval gates and the approval audit trail of an RFE workflow
+ */
+export function useRfeWorkflowApprovals(projectName: string, workflowId: string) {
+  return useQuery({
+    queryKey: rfeKeys.approvals(projectName, workflowId),
+    queryFn: () => rfeApi.getWorkflowApprovals(projectName, workflowId),
+    enabled: !!projectName && !!workflowId,
+  });
+}
+
+/**
+ * Hook to approve or reject the current phase of an RFE workflow
+ */
+export function useRecordWorkflowApproval() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({
+      projectName,
+      workflowId,
+      data,
+    }: {
+      projectName: string;
+      workflowId: string;
+      data: PhaseApprovalRequest;
+    }) => rfeApi.recordWorkflowApproval(projectName, workflowId, data),
+    onSuccess: (_result, { projectName, workflowId }) => {
+      queryClient.invalidateQueries({
+        queryKey: rfeKeys.detail(projectName, workflowId),
+      });
+    },
+  });
+}
+
 /**
  * Hook to fetch agents for an RFE workflow
  */
@@ -230,6 +272,33 @@ export function useStartWorkflowPhase() {
   });
 }
 
+/**
+ * Hook to advance a workflow to its next phase
+ */
+export function useAdvanceWorkflowPhase() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({
+      projectName,
+      workflowId,
+      data,
+    }: {
+      projectName: string;
+      workflowId: string;
+      data?: AdvancePhaseRequest;
+    }) => rfeApi.advanceWorkflowPhase(projectName, workflowId, data),
+    onSuccess: (_result, { projectName, workflowId }) => {
+      queryClient.invalidateQueries({
+        queryKey: rfeKeys.detail(projectName, workflowId),
+      });
+      queryClient.invalidateQueries({
+        queryKey: rfeKeys.sessions(projectName, workflowId),
+      });
+    },
+  });
+}
+
 /**
  * Hook to check if an RFE workflow has been seeded
  */
@@ -310,7 +379,7 @@ export function usePublishToJira() {
       path: string;
     }) => rfeApi.publishWorkflowPathToJira(projectName, workflowId, path),
     onSuccess: (_data, { projectName, workflowId }) => {
-      // Invalidate workflow to refetch updated jiraLinks
+      // Invalidate workflow to refetch updated trackerLinks
       queryClient.invalidateQueries({
         queryKey: rfeKeys.detail(projectName, workflowId),
       });
@@ -340,16 +409,8 @@ export function useOpenJiraIssue(projectName: string, workflowId: string) {
           staleTime: 10 * 60 * 1000,
         });
 
-        if (!data) return;
-
-        const { self: selfUrl, key } = data;
-        if (selfUrl && key) {
-          try {
-            const origin = new URL(selfUrl).origin;
-            window.open(`${origin}/browse/${encodeURIComponent(key)}`, '_blank');
-          } catch {
-            // Invalid URL, skip
-          }
+        if (data?.url) {
+          window.open(data.url, '_blank');
         }
       } catch {
         // Silent fail - user can retry if needed
@@ -357,3 +418,38 @@ export function useOpenJiraIssue(projectName: string, workflowId: string) {
     },
   };
 }
+
+/**
+ * Hook to compare a seeded workflow branch with its spec-kit version and agent source
+ */
+export function useRfeWorkflowSeedDrift(projectName: string, workflowId: string, enabled = true) {
+  return useQuery({
+    queryKey: rfeKeys.seedDrift(projectName, workflowId),
+    queryFn: () => rfeApi.getRfeWorkflowSeedDrift(projectName, workflowId),
+    enabled: enabled && !!projectName && !!workflowId,
+  });
+}
+
+/**
+ * Hook to upgrade the spec-kit and agents of a seeded workflow branch
+ */
+export function useUpgradeRfeWorkflowSeed() {
+  const queryClient = useQueryClient();
+
+  return useMutation({
+    mutationFn: ({
+      projectName,
+      workflowId,
+      data,
+    }: {
+      projectName: string;
+      workflowId: string;
+      data?: SeedUpgradeRequest;
+    }) => rfeApi.upgradeRfeWorkflowSeed(projectName, workflowId, data),
+    onSuccess: (_result, { projectName, workflowId }) => {
+      queryClient.invalidateQueries({
+        queryKey: rfeKeys.detail(projectName, workflowId),
+      });
+    },
+  });
+}
@@ -17,10 +17,10 @@ export function useSecretsConfig(projectName: string) {
   });
 }
 
-export function useSecretsValues(projectName: string) {
+export function useSecretsMetadata(projectName: string) {
   return useQuery({
     queryKey: ['secrets', 'values', projectName],
-    queryFn: () => secretsApi.getSecretsValues(projectName),
+    queryFn: () => secretsApi.getSecretsMetadata(projectName),
     enabled: !!projectName,
   });
 }
@@ -32,10 +32,12 @@ export function useUpdateSecretsConfig() {
     mutationFn: ({
       projectName,
       secretName,
+      secretRefs,
     }: {
       projectName: string;
       secretName: string;
-    }) => secretsApi.updateSecretsConfig(projectName, secretName),
+      secretRefs?: secretsApi.SecretRef[];
+    }) => secretsApi.updateSecretsConfig(projectName, secretName, secretRefs),
     onSuccess: (_, { projectName }) => {
       queryClient.invalidateQueries({ queryKey: ['secrets', 'config', projectName] });
       // Also invalidate values since they come from the configured secret
@@ -51,10 +53,12 @@ export function useUpdateSecrets() {
     mutationFn: ({
       projectName,
       secrets,
+      remove,
     }: {
       projectName: string;
       secrets: secretsApi.Secret[];
-    }) => secretsApi.updateSecrets(projectName, secrets),
+      remove?: string[];
+    }) => secretsApi.updateSecrets(projectName, secrets, remove),
     onSuccess: (_, { projectName }) => {
       queryClient.invalidateQueries({ queryKey: ['secrets', 'values', projectName] });
     },
@@ -23,6 +23,7 @@ export const sessionKeys = {
     [...sessionKeys.details(), projectName, sessionName] as const,
   messages: (projectName: string, sessionName: string) =>
     [...sessionKeys.detail(projectName, sessionName), 'messages'] as const,
+  templates: (projectName: string) => [...sessionKeys.all, 'templates', projectName] as const,
 };
 
 /**
@@ -36,6 +37,17 @@ export function useSessions(projectName: string) {
   });
 }
 
+/**
+ * Hook to fetch a project's starter session templates
+ */
+export function useSessionTemplates(projectName: string) {
+  return useQuery({
+    queryKey: sessionKeys.templates(projectName),
+    queryFn: () => sessionsApi.listSessionTemplates(projectName),
+    enabled: !!projectName,
+  });
+}
+
 /**
  * Hook to fetch a single session
  */
@@ -24,7 +24,7 @@ export type SessionRepoOutput = {
 export type SessionRepo = {
     input: SessionRepoInput;
     output?: SessionRepoOutput;
-    status?: "pushed" | "abandoned";
+    status?: "pushed" | "abandoned" | "conflict";
 };
 
 export type AgenticSessionSpec = {
@@ -37,6 +37,14 @@ export type AgenticSessionSpec = {
 	// Multi-repo support
 	repos?: SessionRepo[];
 	mainRepoIndex?: number;
+	agentPersonas?: string[];
+	secrets?: SessionSecrets;
+};
+
+// Runner secret keys a session may see, directly or through ProjectSettings secret bundles
+export type SessionSecrets = {
+	keys?: string[];
+	bundles?: string[];
 };
 
 // -----------------------------
@@ -130,6 +138,18 @@ export type AgenticSessionStatus = {
 	jobName?: string;
   	// Storage & counts (align with CRD)
   	stateDir?: string;
+	// Runner secret keys projected into the Job, recorded for audit
+	grantedSecretKeys?: string[];
+	// Effective egress restrictions applied to the Job
+	egressPolicy?: {
+		mode: "Unrestricted" | "Restricted";
+		presets?: string[];
+		allowedHosts?: string[];
+		allowedCIDRs?: string[];
+		proxy?: boolean;
+		networkPolicy?: string;
+		warnings?: string[];
+	};
 	// Runner result summary fields
 	subtype?: string;
 	is_error?: boolean;
@@ -169,16 +189,23 @@ export type CreateAgenticSessionRequest = {
 	autoPushOnComplete?: boolean;
 	labels?: Record<string, string>;
 	annotations?: Record<string, string>;
+	// Agent personas seeded on the rfe-workflow label's feature branch
+	agentPersonas?: string[];
+	// Runner secret keys the session may see; omitted uses the project default
+	secrets?: SessionSecrets;
 };
 
 // New types for RFE workflows
-export type WorkflowPhase = "pre" | "ideate" | "specify" | "plan" | "tasks" | "implement" | "review" | "completed";
+export type WorkflowPhase = "pre" | "ideate" | "specify" | "plan" | "tasks" | "implement" | "review" | "done" | "completed";
 
 export type AgentPersona = {
 	persona: string;
 	name: string;
 	role: string;
 	description: string;
+	tools?: string[];
+	model?: string;
+	metadata?: Record<string, unknown>;
 };
 
 export type ArtifactFile = {
@@ -219,7 +246,7 @@ export type RFEWorkflow = {
 	createdAt: string;
 	updatedAt: string;
   phaseResults?: { [phase: string]: PhaseResult };
-  jiraLinks?: Array<{ path: string; jiraKey: string }>;
+  trackerLinks?: Array<{ path: string; tracker: "jira" | "github"; key: string; url?: string }>;
 };
 
 export type CreateRFEWorkflowRequest = {
@@ -24,6 +24,28 @@ export type CreateProjectRequest = {
   displayName?: string; // Optional: only used on OpenShift
   description?: string; // Optional: only used on OpenShift
   labels?: Record<string, string>;
+  /** Cluster project template to apply with the project */
+  template?: string;
+  /** Values for the runner secret keys the template asks for */
+  runnerSecrets?: Record<string, string>;
+};
+
+export type ProjectTemplateSecret = {
+  key: string;
+  description?: string;
+  required?: boolean;
+};
+
+/** Cluster-level bootstrap bundle for new projects */
+export type ProjectTemplate = {
+  name: string;
+  displayName?: string;
+  description?: string;
+  projectSettings?: Record<string, unknown>;
+  runnerSecrets?: ProjectTemplateSecret[];
+  resourceQuota?: { hard?: Record<string, string> };
+  limitRange?: Record<string, unknown>;
+  sessionTemplates?: { name: string; description?: string }[];
 };
 
 export type CreateProjectResponse = {
@@ -10,6 +10,7 @@ export type WorkflowPhase =
   | 'tasks'
   | 'implement'
   | 'review'
+  | 'done'
   | 'completed';
 
 export type RFEWorkflowStatus = 'active' | 'completed' | 'failed' | 'paused';
@@ -26,6 +27,10 @@ export type AgentPersona = {
   name: string;
   role: string;
   description: string;
+  tools?: string[];
+  model?: string;
+  /** Other frontmatter fields */
+  metadata?: Record<string, unknown>;
 };
 
 export type ArtifactFile = {
@@ -60,9 +65,157 @@ export type PhaseResult = {
   metadata?: Record<string, unknown>;
 };
 
-export type JiraLink = {
+export type IssueTrackerType = 'jira' | 'github';
+
+export type TrackerLink = {
   path: string;
-  jiraKey: string;
+  tracker: IssueTrackerType;
+  key: string;
+  url?: string;
+  /** Task ID -> story/sub-task key for a published tasks.md */
+  tasks?: Record<string, string>;
+};
+
+export type TrackerIssueStatus = {
+  tracker: IssueTrackerType;
+  key: string;
+  url: string;
+  title: string;
+  status: string;
+  category: 'open' | 'in_progress' | 'done';
+  assignee?: string;
+  updated?: string;
+};
+
+export type TrackerComment = {
+  issueKey?: string;
+  id: string;
+  author: string;
+  body: string;
+  created: string;
+};
+
+export type TrackedIssue = {
+  path: string;
+  task?: string;
+  tracker: IssueTrackerType;
+  key: string;
+  url?: string;
+  title?: string;
+  status?: string;
+  category?: 'open' | 'in_progress' | 'done';
+  assignee?: string;
+  updated?: string;
+  lastSyncedAt?: string;
+  lastCommentAt?: string;
+  comments?: TrackerComment[];
+};
+
+export type WorkflowTrackerSync = {
+  lastSyncTime?: string;
+  error?: string;
+  issues?: TrackedIssue[];
+  pendingPrompts?: TrackerComment[];
+  ignoredPrompts?: TrackerComment[];
+};
+
+export type WorkflowArtifacts = {
+  seeded: boolean;
+  specDir?: string;
+  spec: boolean;
+  plan: boolean;
+  tasks: boolean;
+  tasksTotal?: number;
+  tasksDone?: number;
+  checkedAt?: string;
+};
+
+export type PhaseTransition = {
+  from?: WorkflowPhase;
+  to: WorkflowPhase;
+  at: string;
+  by?: string;
+  forced?: boolean;
+};
+
+export type PhaseGate = {
+  phase: WorkflowPhase;
+  requiredGroups?: string[];
+  minApprovals?: number;
+};
+
+export type GateStatus = PhaseGate & {
+  satisfied: boolean;
+  approvedBy?: string[];
+  rejectedBy?: string[];
+  missingGroups?: string[];
+};
+
+export type PhaseApproval = {
+  phase: WorkflowPhase;
+  user: string;
+  displayName?: string;
+  groups?: string[];
+  decision: 'approved' | 'rejected';
+  comment?: string;
+  at: string;
+};
+
+export type SeedSource = {
+  specKitRepo?: string;
+  specKitVersion?: string;
+  specKitTemplate?: string;
+  agentSourceUrl?: string;
+  agentSourceBranch?: string;
+  agentSourcePath?: string;
+  /** SHA-256 pins on requests; checksums of the content used on results */
+  specKitSha256?: string;
+  agentSourceSha256?: string;
+};
+
+export type WorkflowSeed = SeedSource & {
+  seededAt?: string;
+  upgradedAt?: string;
+  upgradePullRequest?: string;
+};
+
+export type SeedFileChange = {
+  path: string;
+  origin?: 'spec-kit' | 'agents';
+  action: 'create' | 'update' | 'unchanged' | 'keep' | 'extra';
+};
+
+export type SeedPlan = {
+  branch: string;
+  baseBranch: string;
+  branchExists: boolean;
+  source: SeedSource;
+  files: SeedFileChange[];
+  counts: Record<string, number>;
+  supportingRepos?: { url: string; branchExists: boolean; error?: string }[];
+};
+
+export type SeedDrift = {
+  branch: string;
+  source: SeedSource;
+  inSync: boolean;
+  files: SeedFileChange[];
+  counts: Record<string, number>;
+};
+
+export type SeedUpgrade = {
+  mode: 'pr' | 'commit';
+  branch: string;
+  upgradeBranch?: string;
+  commit?: string;
+  pullRequestUrl?: string;
+  pullRequestNumber?: number;
+  files: SeedFileChange[];
+  source: SeedSource;
+};
+
+export type SeedUpgradeRequest = SeedSource & {
+  mode?: 'pr' | 'commit';
 };
 
 export type RFEWorkflow = {
@@ -81,7 +234,14 @@ export type RFEWorkflow = {
   createdAt: string;
   updatedAt: string;
   phaseResults?: Record<string, PhaseResult>;
-  jiraLinks?: JiraLink[];
+  trackerLinks?: TrackerLink[];
+  trackerSync?: WorkflowTrackerSync;
+  phase?: WorkflowPhase;
+  phaseArtifacts?: WorkflowArtifacts;
+  phaseHistory?: PhaseTransition[];
+  gates?: GateStatus[];
+  approvals?: PhaseApproval[];
+  seed?: WorkflowSeed;
 };
 
 export type CreateRFEWorkflowRequest = {
@@ -92,6 +252,7 @@ export type CreateRFEWorkflowRequest = {
   supportingRepos?: GitRepository[];
   workspacePath?: string;
   parentOutcome?: string;
+  gates?: PhaseGate[];
 };
 
 export type CreateRFEWorkflowResponse = {
@@ -114,6 +275,7 @@ export type UpdateRFEWorkflowRequest = {
   umbrellaRepo?: GitRepository;
   supportingRepos?: GitRepository[];
   parentOutcome?: string;
+  gates?: PhaseGate[];
 };
 
 export type UpdateRFEWorkflowResponse = {
@@ -125,6 +287,38 @@ export type StartPhaseRequest = {
   agents?: string[];
 };
 
+export type AdvancePhaseRequest = {
+  force?: boolean;
+  startSession?: boolean;
+  agents?: string[];
+};
+
+export type AdvancePhaseResponse = {
+  message: string;
+  previousPhase: WorkflowPhase;
+  phase: WorkflowPhase;
+  forced: boolean;
+  session?: string;
+  sessionError?: string;
+};
+
+export type PhaseApprovalRequest = {
+  phase?: WorkflowPhase;
+  decision: 'approve' | 'reject';
+  comment?: string;
+};
+
+export type PhaseApprovalResponse = {
+  approval: PhaseApproval;
+  gate: GateStatus;
+};
+
+export type GetApprovalsResponse = {
+  phase: WorkflowPhase;
+  gates: GateStatus[];
+  approvals: PhaseApproval[];
+};
+
 export type StartPhaseResponse = {
   message: string;
   sessionsCreated: string[];
@@ -7,6 +7,7 @@ export type UserContext = {
   userId: string;
   displayName: string;
   groups: string[];
+  email?: string;
 };
 
 export type BotAccountRef = {
@@ -45,7 +46,7 @@ export type SessionRepoOutput = {
   branch?: string;
 };
 
-export type SessionRepoStatus = 'pushed' | 'abandoned';
+export type SessionRepoStatus = 'pushed' | 'abandoned' | 'conflict';
 
 export type SessionRepo = {
   input: SessionRepoInput;
@@ -62,6 +63,25 @@ export type AgenticSessionSpec = {
   interactive?: boolean;
   repos?: SessionRepo[];
   mainRepoIndex?: number;
+  agentPersonas?: string[];
+  secrets?: SessionSecrets;
+};
+
+/** Runner secret keys a session may see, directly or through ProjectSettings secret bundles */
+export type SessionSecrets = {
+  keys?: string[];
+  bundles?: string[];
+};
+
+/** Egress restrictions applied to the session Job, as resolved from ProjectSettings */
+export type SessionEgressPolicy = {
+  mode: 'Unrestricted' | 'Restricted';
+  presets?: string[];
+  allowedHosts?: string[];
+  allowedCIDRs?: string[];
+  proxy?: boolean;
+  networkPolicy?: string;
+  warnings?: string[];
 };
 
 export type AgenticSessionStatus = {
@@ -71,6 +91,8 @@ export type AgenticSessionStatus = {
   completionTime?: string;
   jobName?: string;
   stateDir?: string;
+  grantedSecretKeys?: string[];
+  egressPolicy?: SessionEgressPolicy;
   subtype?: string;
   is_error?: boolean;
   num_turns?: number;
@@ -111,6 +133,17 @@ export type CreateAgenticSessionRequest = {
   resourceOverrides?: ResourceOverrides;
   labels?: Record<string, string>;
   annotations?: Record<string, string>;
+  /** Agent personas seeded on the rfe-workflow label's feature branch */
+  agentPersonas?: string[];
+  /** Runner secret keys the session may see; omitted uses the project default */
+  secrets?: SessionSecrets;
+};
+
+/** Starter session installed in a project by its project template */
+export type SessionTemplate = {
+  name: string;
+  description?: string;
+  session: Partial<CreateAgenticSessionRequest>;
 };
 
 export type CreateAgenticSessionResponse = {
@@ -124,6 +124,8 @@ export interface CreateProjectRequest {
   name: string;
   displayName?: string; // Optional: only used on OpenShift
   description?: string; // Optional: only used on OpenShift
+  template?: string; // Cluster project template to apply
+  runnerSecrets?: Record<string, string>; // Values for the keys the template asks for
 }
 
 export type ProjectPhase = "Pending" | "Active" | "Error" | "Terminating";
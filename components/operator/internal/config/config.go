@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 
+	vteamclient "ambient-code-api/clientset/versioned"
+	"ambient-code-operator/internal/secrets"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
@@ -15,6 +18,10 @@ import (
 var (
 	K8sClient     *kubernetes.Clientset
 	DynamicClient dynamic.Interface
+	// VteamClient is the typed clientset for AgenticSessions
+	VteamClient vteamclient.Interface
+	// SecretProvider resolves ProjectSettings runner secret references; nil when not configured
+	SecretProvider secrets.Provider
 )
 
 // Config holds the operator configuration
@@ -56,6 +63,12 @@ func InitK8sClients() error {
 		return fmt.Errorf("failed to create dynamic client: %v", err)
 	}
 
+	// Create typed client for AgenticSessions
+	VteamClient, err = vteamclient.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create vteam client: %v", err)
+	}
+
 	return nil
 }
 
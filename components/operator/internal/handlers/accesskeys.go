@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ambient-code-operator/internal/config"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// accessKeySweepInterval is how often expired project access keys are deleted
+const accessKeySweepInterval = 5 * time.Minute
+
+// CleanupExpiredAccessKeys periodically deletes project access keys (ServiceAccounts labeled
+// app=ambient-access-key) whose ambient-code.io/expires-at has passed. Their RoleBindings, scoped
+// Roles and token Secrets are owned by the ServiceAccount and garbage collected with it. Keys
+// without an expiry (created before expiring keys) are left alone.
+func CleanupExpiredAccessKeys() {
+	log.Println("Starting expired access key cleanup goroutine")
+	for {
+		time.Sleep(accessKeySweepInterval)
+
+		sas, err := config.K8sClient.CoreV1().ServiceAccounts("").List(context.TODO(), v1.ListOptions{
+			LabelSelector: "app=ambient-access-key",
+		})
+		if err != nil {
+			log.Printf("Failed to list access keys: %v", err)
+			continue
+		}
+
+		now := time.Now()
+		for _, sa := range sas.Items {
+			expiresAtStr := sa.Annotations["ambient-code.io/expires-at"]
+			if expiresAtStr == "" {
+				continue
+			}
+			expiresAt, err := time.Parse(time.RFC3339, expiresAtStr)
+			if err != nil {
+				log.Printf("Failed to parse expires-at for access key %s/%s: %v", sa.Namespace, sa.Name, err)
+				continue
+			}
+			if now.Before(expiresAt) {
+				continue
+			}
+
+			log.Printf("Deleting expired access key %s/%s (expired %s)", sa.Namespace, sa.Name, expiresAtStr)
+			policy := v1.DeletePropagationBackground
+			err = config.K8sClient.CoreV1().ServiceAccounts(sa.Namespace).Delete(context.TODO(), sa.Name, v1.DeleteOptions{
+				PropagationPolicy: &policy,
+				// Don't delete a key that was rotated after we listed it
+				Preconditions: &v1.Preconditions{ResourceVersion: &sa.ResourceVersion},
+			})
+			if err != nil && !errors.IsNotFound(err) && !errors.IsConflict(err) {
+				log.Printf("Failed to delete expired access key %s/%s: %v", sa.Namespace, sa.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"ambient-code-operator/internal/config"
+)
+
+// managedBindingSelector selects the RoleBindings owned by ProjectSettings access reconciliation
+const managedBindingSelector = "ambient-code.io/managed=true"
+
+// maxDriftCorrections bounds the history of corrections kept in status
+const maxDriftCorrections = 20
+
+// accessBinding is one RoleBinding declared by ProjectSettings
+type accessBinding struct {
+	name             string
+	subjectKind      string // Group, User or ServiceAccount
+	subjectName      string
+	subjectNamespace string // ServiceAccount only
+	role             string
+}
+
+// desiredAccessBindings collects the bindings declared in spec.groupAccess, spec.userAccess and
+// spec.serviceAccountAccess. Group bindings keep their historical <group>-<role> names so
+// existing bindings are adopted rather than recreated.
+func desiredAccessBindings(namespace string, spec map[string]interface{}) ([]accessBinding, []string) {
+	var out []accessBinding
+	var problems []string
+	add := func(b accessBinding) {
+		if b.subjectName == "" || b.role == "" {
+			return
+		}
+		b.role = strings.ToLower(b.role)
+		if b.role != "admin" && b.role != "edit" && b.role != "view" {
+			problems = append(problems, fmt.Sprintf("%s %s: unknown role %q", b.subjectKind, b.subjectName, b.role))
+			return
+		}
+		out = append(out, b)
+	}
+
+	if entries, found, _ := unstructured.NestedSlice(spec, "groupAccess"); found {
+		for _, e := range entries {
+			m, _ := e.(map[string]interface{})
+			group, _, _ := unstructured.NestedString(m, "groupName")
+			role, _, _ := unstructured.NestedString(m, "role")
+			add(accessBinding{name: bindingName(group, role), subjectKind: "Group", subjectName: group, role: role})
+		}
+	}
+	if entries, found, _ := unstructured.NestedSlice(spec, "userAccess"); found {
+		for _, e := range entries {
+			m, _ := e.(map[string]interface{})
+			user, _, _ := unstructured.NestedString(m, "userName")
+			role, _, _ := unstructured.NestedString(m, "role")
+			add(accessBinding{name: bindingName("user-"+user, role), subjectKind: "User", subjectName: user, role: role})
+		}
+	}
+	if entries, found, _ := unstructured.NestedSlice(spec, "serviceAccountAccess"); found {
+		for _, e := range entries {
+			m, _ := e.(map[string]interface{})
+			sa, _, _ := unstructured.NestedString(m, "name")
+			saNamespace, _, _ := unstructured.NestedString(m, "namespace")
+			if saNamespace == "" {
+				saNamespace = namespace
+			}
+			role, _, _ := unstructured.NestedString(m, "role")
+			add(accessBinding{name: bindingName("sa-"+saNamespace+"-"+sa, role), subjectKind: "ServiceAccount", subjectName: sa, subjectNamespace: saNamespace, role: role})
+		}
+	}
+
+	// The same name declared for different subjects can't both be honoured
+	seen := map[string]accessBinding{}
+	deduped := out[:0]
+	for _, b := range out {
+		if prev, ok := seen[b.name]; ok {
+			if prev != b {
+				problems = append(problems, fmt.Sprintf("%s %s and %s %s both map to RoleBinding %s; ignoring the latter", prev.subjectKind, prev.subjectName, b.subjectKind, b.subjectName, b.name))
+			}
+			continue
+		}
+		seen[b.name] = b
+		deduped = append(deduped, b)
+	}
+	sort.Slice(deduped, func(i, j int) bool { return deduped[i].name < deduped[j].name })
+	return deduped, problems
+}
+
+// bindingName builds a RoleBinding name, replacing characters not allowed in object names
+func bindingName(subject, role string) string {
+	r := strings.NewReplacer("/", "-", "%", "-")
+	return r.Replace(subject) + "-" + strings.ToLower(role)
+}
+
+func (b accessBinding) roleBinding(namespace string) *rbacv1.RoleBinding {
+	subject := rbacv1.Subject{Kind: b.subjectKind, Name: b.subjectName}
+	if b.subjectKind == "ServiceAccount" {
+		subject.Namespace = b.subjectNamespace
+	} else {
+		subject.APIGroup = "rbac.authorization.k8s.io"
+	}
+	return &rbacv1.RoleBinding{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      b.name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"ambient-code.io/managed": "true",
+			},
+			Annotations: map[string]string{
+				"ambient-code.io/subject-kind": b.subjectKind,
+				"ambient-code.io/subject-name": b.subjectName,
+				"ambient-code.io/role":         b.role,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     mapRoleToKubernetesRole(b.role),
+		},
+		Subjects: []rbacv1.Subject{subject},
+	}
+}
+
+func (b accessBinding) status() map[string]interface{} {
+	s := map[string]interface{}{
+		"name":        b.name,
+		"subjectKind": b.subjectKind,
+		"subjectName": b.subjectName,
+		"role":        b.role,
+	}
+	if b.subjectNamespace != "" {
+		s["subjectNamespace"] = b.subjectNamespace
+	}
+	return s
+}
+
+func subjectsEqual(a, b []rbacv1.Subject) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// accessReconcileResult is what reconcileProjectAccess reports into ProjectSettings status
+type accessReconcileResult struct {
+	effective   []accessBinding
+	corrections []map[string]interface{}
+	problems    []string
+}
+
+// reconcileProjectAccess makes the managed RoleBindings in namespace match the declared ones:
+// missing bindings are created, bindings whose subjects were edited are restored, bindings whose
+// role changed are recreated (roleRef is immutable) and bindings no longer declared are deleted.
+// New bindings are created before stale ones are deleted so a role change never drops access.
+func reconcileProjectAccess(namespace string, spec map[string]interface{}) accessReconcileResult {
+	ctx := context.TODO()
+	rbClient := config.K8sClient.RbacV1().RoleBindings(namespace)
+	desired, problems := desiredAccessBindings(namespace, spec)
+	res := accessReconcileResult{problems: problems}
+	now := time.Now().UTC().Format(time.RFC3339)
+	correct := func(binding, action, reason string) {
+		log.Printf("ProjectSettings %s: %s RoleBinding %s (%s)", namespace, action, binding, reason)
+		res.corrections = append(res.corrections, map[string]interface{}{
+			"time":    now,
+			"binding": binding,
+			"action":  action,
+			"reason":  reason,
+		})
+	}
+
+	list, err := rbClient.List(ctx, v1.ListOptions{LabelSelector: managedBindingSelector})
+	if err != nil {
+		res.problems = append(res.problems, fmt.Sprintf("list managed RoleBindings: %v", err))
+		return res
+	}
+	existing := map[string]*rbacv1.RoleBinding{}
+	for i := range list.Items {
+		existing[list.Items[i].Name] = &list.Items[i]
+	}
+
+	for _, b := range desired {
+		want := b.roleBinding(namespace)
+		cur, ok := existing[b.name]
+		delete(existing, b.name)
+
+		if !ok {
+			if _, err := rbClient.Get(ctx, b.name, v1.GetOptions{}); err == nil {
+				res.problems = append(res.problems, fmt.Sprintf("RoleBinding %s exists but is not managed by ProjectSettings; leaving it untouched", b.name))
+				continue
+			} else if !errors.IsNotFound(err) {
+				res.problems = append(res.problems, fmt.Sprintf("get RoleBinding %s: %v", b.name, err))
+				continue
+			}
+			if _, err := rbClient.Create(ctx, want, v1.CreateOptions{}); err != nil {
+				res.problems = append(res.problems, fmt.Sprintf("create RoleBinding %s: %v", b.name, err))
+				continue
+			}
+			correct(b.name, "created", fmt.Sprintf("%s %s declared with role %s", b.subjectKind, b.subjectName, b.role))
+			res.effective = append(res.effective, b)
+			continue
+		}
+
+		if cur.RoleRef != want.RoleRef {
+			if err := rbClient.Delete(ctx, b.name, v1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				res.problems = append(res.problems, fmt.Sprintf("delete RoleBinding %s for recreate: %v", b.name, err))
+				continue
+			}
+			if _, err := rbClient.Create(ctx, want, v1.CreateOptions{}); err != nil {
+				res.problems = append(res.problems, fmt.Sprintf("recreate RoleBinding %s: %v", b.name, err))
+				continue
+			}
+			correct(b.name, "recreated", fmt.Sprintf("roleRef was %s %s, expected %s %s", cur.RoleRef.Kind, cur.RoleRef.Name, want.RoleRef.Kind, want.RoleRef.Name))
+		} else if !subjectsEqual(cur.Subjects, want.Subjects) {
+			cur.Subjects = want.Subjects
+			if cur.Annotations == nil {
+				cur.Annotations = map[string]string{}
+			}
+			for k, v := range want.Annotations {
+				cur.Annotations[k] = v
+			}
+			if _, err := rbClient.Update(ctx, cur, v1.UpdateOptions{}); err != nil {
+				res.problems = append(res.problems, fmt.Sprintf("update RoleBinding %s: %v", b.name, err))
+				continue
+			}
+			correct(b.name, "updated", "subjects were modified outside ProjectSettings")
+		}
+		res.effective = append(res.effective, b)
+	}
+
+	stale := make([]string, 0, len(existing))
+	for name := range existing {
+		stale = append(stale, name)
+	}
+	sort.Strings(stale)
+	for _, name := range stale {
+		if err := rbClient.Delete(ctx, name, v1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			res.problems = append(res.problems, fmt.Sprintf("delete RoleBinding %s: %v", name, err))
+			continue
+		}
+		correct(name, "deleted", "no longer declared in ProjectSettings")
+	}
+	return res
+}
+
+// statusFields renders the result into ProjectSettings status, appending corrections to the
+// history already recorded (newest last, capped at maxDriftCorrections)
+func (r accessReconcileResult) statusFields(previous map[string]interface{}) map[string]interface{} {
+	groups := 0
+	effective := make([]interface{}, 0, len(r.effective))
+	for _, b := range r.effective {
+		if b.subjectKind == "Group" {
+			groups++
+		}
+		effective = append(effective, b.status())
+	}
+
+	history, _, _ := unstructured.NestedSlice(previous, "driftCorrections")
+	for _, c := range r.corrections {
+		history = append(history, c)
+	}
+	if len(history) > maxDriftCorrections {
+		history = history[len(history)-maxDriftCorrections:]
+	}
+
+	problems := make([]interface{}, 0, len(r.problems))
+	for _, p := range r.problems {
+		problems = append(problems, p)
+	}
+
+	return map[string]interface{}{
+		"groupBindingsCreated": int64(groups),
+		"effectiveBindings":    effective,
+		"driftCorrections":     history,
+		"bindingErrors":        problems,
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
-	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -17,6 +17,9 @@ import (
 	"ambient-code-operator/internal/types"
 )
 
+// projectSettingsResyncInterval is how often all ProjectSettings are reconciled to correct drift
+const projectSettingsResyncInterval = 10 * time.Minute
+
 // WatchProjectSettings watches for ProjectSettings resources and reconciles them
 func WatchProjectSettings() {
 	gvr := types.GetProjectSettingsResource()
@@ -118,83 +121,50 @@ func handleProjectSettingsEvent(obj *unstructured.Unstructured) error {
 	return reconcileProjectSettings(currentObj)
 }
 
+// reconcileMu serializes reconciles from the watch and the periodic resync
+var reconcileMu sync.Mutex
+
 func reconcileProjectSettings(obj *unstructured.Unstructured) error {
+	reconcileMu.Lock()
+	defer reconcileMu.Unlock()
+
 	namespace := obj.GetNamespace()
 	name := obj.GetName()
 
 	spec, _, _ := unstructured.NestedMap(obj.Object, "spec")
+	previous, _, _ := unstructured.NestedMap(obj.Object, "status")
 
-	// Reconcile group access (RoleBindings)
-	groupBindingsCreated := 0
-	if groupAccess, found, _ := unstructured.NestedSlice(spec, "groupAccess"); found {
-		for _, accessInterface := range groupAccess {
-			access := accessInterface.(map[string]interface{})
-			groupName, _, _ := unstructured.NestedString(access, "groupName")
-			role, _, _ := unstructured.NestedString(access, "role")
-			if groupName != "" && role != "" {
-				if err := ensureRoleBinding(namespace, groupName, role); err != nil {
-					log.Printf("Error creating RoleBinding for group %s in namespace %s: %v", groupName, namespace, err)
-					continue
-				}
-				groupBindingsCreated++
-			}
-		}
+	// Reconcile group, user and service account access (managed RoleBindings)
+	result := reconcileProjectAccess(namespace, spec)
+	for _, p := range result.problems {
+		log.Printf("ProjectSettings %s/%s: %s", namespace, name, p)
 	}
 
 	// Update status with reconciliation results (only fields defined in CRD)
-	statusUpdate := map[string]interface{}{
-		"groupBindingsCreated": groupBindingsCreated,
-	}
+	statusUpdate := result.statusFields(previous)
+	statusUpdate["observedGeneration"] = obj.GetGeneration()
 
 	return updateProjectSettingsStatus(namespace, name, statusUpdate)
 }
 
-func ensureRoleBinding(namespace, groupName, role string) error {
-	// Map role to ClusterRole used for ambient project access
-	roleName := mapRoleToKubernetesRole(role)
-	rbName := fmt.Sprintf("%s-%s", groupName, role)
-
-	// Check if RoleBinding already exists
-	_, err := config.K8sClient.RbacV1().RoleBindings(namespace).Get(context.TODO(), rbName, v1.GetOptions{})
-	if err == nil {
-		log.Printf("RoleBinding %s already exists in namespace %s", rbName, namespace)
-		return nil
-	}
-
-	if !errors.IsNotFound(err) {
-		return fmt.Errorf("error checking existing RoleBinding: %v", err)
-	}
-
-	// Create RoleBinding
-	rb := &rbacv1.RoleBinding{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      rbName,
-			Namespace: namespace,
-			Labels: map[string]string{
-				"ambient-code.io/managed": "true",
-			},
-		},
-		RoleRef: rbacv1.RoleRef{
-			APIGroup: "rbac.authorization.k8s.io",
-			Kind:     "ClusterRole",
-			Name:     roleName,
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:     "Group",
-				Name:     groupName,
-				APIGroup: "rbac.authorization.k8s.io",
-			},
-		},
-	}
+// ResyncProjectSettings periodically reconciles every ProjectSettings so managed RoleBindings
+// edited or deleted by hand are restored even when ProjectSettings itself doesn't change
+func ResyncProjectSettings() {
+	gvr := types.GetProjectSettingsResource()
+	for {
+		time.Sleep(projectSettingsResyncInterval)
 
-	_, err = config.K8sClient.RbacV1().RoleBindings(namespace).Create(context.TODO(), rb, v1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create RoleBinding: %v", err)
+		list, err := config.DynamicClient.Resource(gvr).List(context.TODO(), v1.ListOptions{})
+		if err != nil {
+			log.Printf("Failed to list ProjectSettings for resync: %v", err)
+			continue
+		}
+		for i := range list.Items {
+			if err := reconcileProjectSettings(&list.Items[i]); err != nil {
+				log.Printf("Error resyncing ProjectSettings %s/%s: %v", list.Items[i].GetNamespace(), list.Items[i].GetName(), err)
+			}
+		}
 	}
-
-	log.Printf("Created RoleBinding %s for group %s in namespace %s", rbName, groupName, namespace)
-	return nil
 }
 
 func mapRoleToKubernetesRole(role string) string {
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+	"ambient-code-operator/internal/config"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const egressProxyPort = 3128
+
+// backendServiceName is the Service the runner reaches the backend API and websocket through
+const backendServiceName = "backend-service"
+
+// clusterDNSPeers are the cluster DNS pods on OpenShift (openshift-dns, serving on 5353) and on
+// Kubernetes (CoreDNS or kube-dns in kube-system)
+var clusterDNSPeers = []networkingv1.NetworkPolicyPeer{
+	{
+		NamespaceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "openshift-dns"}},
+		PodSelector:       &v1.LabelSelector{MatchLabels: map[string]string{"dns.operator.openshift.io/daemonset-dns": "default"}},
+	},
+	{
+		NamespaceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"}},
+		PodSelector:       &v1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "kube-dns"}},
+	},
+}
+
+// egressPresets are the hosts behind the ProjectSettings spec.egressPolicy.presets names
+var egressPresets = map[string][]string{
+	"github":    {"github.com", "api.github.com", "codeload.github.com", "uploads.github.com", "objects.githubusercontent.com", "raw.githubusercontent.com"},
+	"gitlab":    {"gitlab.com", "registry.gitlab.com"},
+	"anthropic": {"api.anthropic.com", "statsig.anthropic.com"},
+	"pypi":      {"pypi.org", "files.pythonhosted.org"},
+	"npm":       {"registry.npmjs.org"},
+}
+
+// egressPolicy is ProjectSettings spec.egressPolicy
+type egressPolicy struct {
+	restricted bool
+	presets    []string
+	hosts      []string
+	cidrs      []string
+	proxy      bool
+}
+
+func parseEgressPolicy(psSpec map[string]interface{}) egressPolicy {
+	m, _ := psSpec["egressPolicy"].(map[string]interface{})
+	p := egressPolicy{}
+	if mode, _ := m["mode"].(string); mode == "Restricted" {
+		p.restricted = true
+	}
+	p.presets = stringSlice(m["presets"])
+	p.hosts = stringSlice(m["allowedHosts"])
+	p.cidrs = stringSlice(m["allowedCIDRs"])
+	p.proxy, _ = m["proxy"].(bool)
+	return p
+}
+
+// effectiveEgress is an egressPolicy with presets expanded and hostnames resolved
+type effectiveEgress struct {
+	policy egressPolicy
+	// hosts are the allowed hostnames, presets included, matched by the proxy
+	hosts []string
+	// cidrs are the declared ranges plus the resolved addresses of the hosts
+	cidrs    []string
+	warnings []string
+}
+
+// resolveEgress expands presets and resolves exact hostnames to addresses for the NetworkPolicy.
+// Addresses are resolved once, at Job creation; hosts whose addresses change afterwards are only
+// reliably reachable through the proxy.
+func resolveEgress(ctx context.Context, p egressPolicy) (effectiveEgress, error) {
+	e := effectiveEgress{policy: p}
+	if !p.restricted {
+		return e, nil
+	}
+
+	hostSet := map[string]bool{}
+	for _, preset := range p.presets {
+		hosts, ok := egressPresets[preset]
+		if !ok {
+			return e, fmt.Errorf("unknown egress preset %q", preset)
+		}
+		for _, h := range hosts {
+			hostSet[h] = true
+		}
+	}
+	for _, h := range p.hosts {
+		h = strings.ToLower(h)
+		// Wildcards would need the NetworkPolicy to open ports 80 and 443 to every address, which
+		// the proxy sidecar shares with the runner; admission rejects them, so only older
+		// ProjectSettings still carry them
+		if strings.HasPrefix(h, "*.") {
+			e.warnings = append(e.warnings, fmt.Sprintf("%s is a wildcard; wildcard hosts are not supported and it is not reachable", h))
+			continue
+		}
+		hostSet[h] = true
+	}
+	for h := range hostSet {
+		e.hosts = append(e.hosts, h)
+	}
+	sort.Strings(e.hosts)
+
+	cidrSet := map[string]bool{}
+	for _, c := range p.cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return e, fmt.Errorf("invalid egress CIDR %q: %w", c, err)
+		}
+		cidrSet[n.String()] = true
+	}
+
+	var unresolved []string
+	for _, h := range e.hosts {
+		lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		addrs, err := net.DefaultResolver.LookupIPAddr(lookupCtx, h)
+		cancel()
+		if err != nil || len(addrs) == 0 {
+			unresolved = append(unresolved, h)
+			continue
+		}
+		for _, a := range addrs {
+			if a.IP.To4() != nil {
+				cidrSet[a.IP.String()+"/32"] = true
+			} else {
+				cidrSet[a.IP.String()+"/128"] = true
+			}
+		}
+	}
+	if len(unresolved) > 0 {
+		e.warnings = append(e.warnings, fmt.Sprintf("could not resolve %s; they are not reachable", strings.Join(unresolved, ", ")))
+	}
+	for c := range cidrSet {
+		e.cidrs = append(e.cidrs, c)
+	}
+	sort.Strings(e.cidrs)
+	return e, nil
+}
+
+// status renders the effective policy as AgenticSession status.egressPolicy
+func (e effectiveEgress) status(networkPolicyName string) *vteamv1alpha1.EgressPolicyStatus {
+	if !e.policy.restricted {
+		return &vteamv1alpha1.EgressPolicyStatus{Mode: "Unrestricted"}
+	}
+	return &vteamv1alpha1.EgressPolicyStatus{
+		Mode:          "Restricted",
+		Presets:       e.policy.presets,
+		AllowedHosts:  e.hosts,
+		AllowedCIDRs:  e.cidrs,
+		Proxy:         e.policy.proxy,
+		NetworkPolicy: networkPolicyName,
+		Warnings:      e.warnings,
+	}
+}
+
+func egressNetworkPolicyName(sessionName string) string {
+	return fmt.Sprintf("ambient-egress-%s", sessionName)
+}
+
+// createEgressNetworkPolicy restricts egress of the Job's pods to DNS, the backend and the
+// effective allow-list. It is created before the Job so no pod ever runs unrestricted;
+// ownEgressNetworkPolicy later attaches it to the Job.
+func createEgressNetworkPolicy(namespace, sessionName, jobName, backendNamespace string, e effectiveEgress) error {
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	port := func(proto *corev1.Protocol, p int) networkingv1.NetworkPolicyPort {
+		v := intstr.FromInt(p)
+		return networkingv1.NetworkPolicyPort{Protocol: proto, Port: &v}
+	}
+
+	// Backend API and websocket used by the runner: only the backend Service's pods and target
+	// ports, as NetworkPolicies apply after the Service address is translated
+	svc, err := config.K8sClient.CoreV1().Services(backendNamespace).Get(context.TODO(), backendServiceName, v1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("read backend Service %s/%s: %w", backendNamespace, backendServiceName, err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return fmt.Errorf("backend Service %s/%s has no pod selector", backendNamespace, backendServiceName)
+	}
+	var backendPorts []networkingv1.NetworkPolicyPort
+	for _, p := range svc.Spec.Ports {
+		target := p.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt(int(p.Port))
+		}
+		proto := p.Protocol
+		if proto == "" {
+			proto = corev1.ProtocolTCP
+		}
+		backendPorts = append(backendPorts, networkingv1.NetworkPolicyPort{Protocol: &proto, Port: &target})
+	}
+
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		// Cluster DNS (5353 is the OpenShift DNS pod port)
+		{
+			To:    clusterDNSPeers,
+			Ports: []networkingv1.NetworkPolicyPort{port(&udp, 53), port(&tcp, 53), port(&udp, 5353), port(&tcp, 5353)},
+		},
+		{
+			To: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &v1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": backendNamespace}},
+				PodSelector:       &v1.LabelSelector{MatchLabels: svc.Spec.Selector},
+			}},
+			Ports: backendPorts,
+		},
+	}
+	if len(e.cidrs) > 0 {
+		peers := make([]networkingv1.NetworkPolicyPeer, 0, len(e.cidrs))
+		for _, c := range e.cidrs {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: c}})
+		}
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+
+	np := &networkingv1.NetworkPolicy{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      egressNetworkPolicyName(sessionName),
+			Namespace: namespace,
+			Labels:    map[string]string{"app": "ambient-code-runner", "agentic-session": sessionName},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: v1.LabelSelector{MatchLabels: map[string]string{"job-name": jobName}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      rules,
+		},
+	}
+	_, err = config.K8sClient.NetworkingV1().NetworkPolicies(namespace).Create(context.TODO(), np, v1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// Left over from an earlier attempt; replace its rules with the current policy
+		existing, gerr := config.K8sClient.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), np.Name, v1.GetOptions{})
+		if gerr != nil {
+			return fmt.Errorf("get egress NetworkPolicy: %w", gerr)
+		}
+		existing.Spec = np.Spec
+		existing.OwnerReferences = nil
+		_, err = config.K8sClient.NetworkingV1().NetworkPolicies(namespace).Update(context.TODO(), existing, v1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("create egress NetworkPolicy: %w", err)
+	}
+	return nil
+}
+
+func ownEgressNetworkPolicy(namespace, name string, job *batchv1.Job) error {
+	np, err := config.K8sClient.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	np.OwnerReferences = []v1.OwnerReference{{
+		APIVersion: "batch/v1",
+		Kind:       "Job",
+		Name:       job.Name,
+		UID:        job.UID,
+		Controller: boolPtr(true),
+	}}
+	_, err = config.K8sClient.NetworkingV1().NetworkPolicies(namespace).Update(context.TODO(), np, v1.UpdateOptions{})
+	return err
+}
+
+func deleteEgressNetworkPolicy(namespace, name string) {
+	if err := config.K8sClient.NetworkingV1().NetworkPolicies(namespace).Delete(context.TODO(), name, v1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		log.Printf("Failed to delete egress NetworkPolicy %s/%s: %v", namespace, name, err)
+	}
+}
+
+// egressProxyContainer runs the backend image in EGRESS_PROXY_MODE, listening on loopback
+func egressProxyContainer(image string, pullPolicy corev1.PullPolicy, e effectiveEgress) corev1.Container {
+	return corev1.Container{
+		Name:            "egress-proxy",
+		Image:           image,
+		ImagePullPolicy: pullPolicy,
+		Env: []corev1.EnvVar{
+			{Name: "EGRESS_PROXY_MODE", Value: "true"},
+			{Name: "PORT", Value: fmt.Sprintf("%d", egressProxyPort)},
+			{Name: "EGRESS_ALLOWED_HOSTS", Value: strings.Join(e.hosts, ",")},
+			{Name: "EGRESS_ALLOWED_CIDRS", Value: strings.Join(e.policy.cidrs, ",")},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: boolPtr(false),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		},
+	}
+}
+
+// egressProxyEnv points HTTP clients in the other containers at the proxy sidecar
+func egressProxyEnv(backendNamespace string) []corev1.EnvVar {
+	proxyURL := fmt.Sprintf("http://127.0.0.1:%d", egressProxyPort)
+	noProxy := strings.Join([]string{"localhost", "127.0.0.1", ".svc", ".cluster.local", "backend-service", fmt.Sprintf("backend-service.%s", backendNamespace)}, ",")
+	var env []corev1.EnvVar
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+		env = append(env, corev1.EnvVar{Name: name, Value: proxyURL})
+	}
+	return append(env, corev1.EnvVar{Name: "NO_PROXY", Value: noProxy}, corev1.EnvVar{Name: "no_proxy", Value: noProxy})
+}
+
+func stringSlice(v interface{}) []string {
+	items, _ := v.([]interface{})
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+			out = append(out, strings.TrimSpace(s))
+		}
+	}
+	return out
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
+	"ambient-code-operator/internal/config"
+	"ambient-code-operator/internal/secrets"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// runnerSecretGrant is what a session's Job may see of the project's runner secret and
+// external secret references
+type runnerSecretGrant struct {
+	// all injects the whole runner secret (sessions that do not select keys)
+	all bool
+	// secretKeys are runner secret keys projected one by one when all is false
+	secretKeys []string
+	// refs are the external references to resolve for the Job
+	refs []secrets.Ref
+	// granted lists every key the session sees, recorded in status.grantedSecretKeys
+	granted []string
+}
+
+// grantRunnerSecrets works out the keys a session may see. Without spec.secrets every key of the
+// runner secret and every reference is granted; otherwise only the named keys and the keys of
+// the named ProjectSettings secretBundles are, and each must exist in one of the two sources.
+// References win over runner secret keys of the same name.
+func grantRunnerSecrets(namespace, runnerSecretsName string, psSpec map[string]interface{}, sel *vteamv1alpha1.SessionSecrets, refs []secrets.Ref) (runnerSecretGrant, error) {
+	secretKeys := map[string]bool{}
+	if runnerSecretsName != "" {
+		sec, err := config.K8sClient.CoreV1().Secrets(namespace).Get(context.TODO(), runnerSecretsName, v1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return runnerSecretGrant{}, fmt.Errorf("read runner secret %s: %w", runnerSecretsName, err)
+		}
+		if err == nil {
+			for k := range sec.Data {
+				secretKeys[k] = true
+			}
+		}
+	}
+	refsByEnv := map[string]secrets.Ref{}
+	for _, r := range refs {
+		refsByEnv[r.EnvName] = r
+	}
+
+	if sel == nil {
+		g := runnerSecretGrant{all: true, refs: refs}
+		for k := range secretKeys {
+			if _, ok := refsByEnv[k]; !ok {
+				g.granted = append(g.granted, k)
+			}
+		}
+		for k := range refsByEnv {
+			g.granted = append(g.granted, k)
+		}
+		sort.Strings(g.granted)
+		return g, nil
+	}
+
+	wanted := map[string]bool{}
+	for _, k := range sel.Keys {
+		if k = strings.TrimSpace(k); k != "" {
+			wanted[k] = true
+		}
+	}
+	bundles := secretBundles(psSpec)
+	for _, b := range sel.Bundles {
+		keys, ok := bundles[strings.TrimSpace(b)]
+		if !ok {
+			return runnerSecretGrant{}, fmt.Errorf("secret bundle %q is not defined in ProjectSettings", b)
+		}
+		for _, k := range keys {
+			wanted[k] = true
+		}
+	}
+
+	g := runnerSecretGrant{}
+	var missing []string
+	for k := range wanted {
+		if r, ok := refsByEnv[k]; ok {
+			g.refs = append(g.refs, r)
+		} else if secretKeys[k] {
+			g.secretKeys = append(g.secretKeys, k)
+		} else {
+			missing = append(missing, k)
+			continue
+		}
+		g.granted = append(g.granted, k)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return runnerSecretGrant{}, fmt.Errorf("secret keys not found in the runner secret or references: %s", strings.Join(missing, ", "))
+	}
+	sort.Strings(g.secretKeys)
+	sort.Strings(g.granted)
+	sort.Slice(g.refs, func(i, j int) bool { return g.refs[i].EnvName < g.refs[j].EnvName })
+	return g, nil
+}
+
+// secretBundles reads spec.secretBundles from a ProjectSettings spec
+func secretBundles(psSpec map[string]interface{}) map[string][]string {
+	out := map[string][]string{}
+	items, _ := psSpec["secretBundles"].([]interface{})
+	for _, it := range items {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		keys, _ := m["keys"].([]interface{})
+		for _, k := range keys {
+			if s, ok := k.(string); ok && strings.TrimSpace(s) != "" {
+				out[name] = append(out[name], strings.TrimSpace(s))
+			}
+		}
+		if _, ok := out[name]; !ok {
+			out[name] = nil
+		}
+	}
+	return out
+}
+
+// gitSigningKeys sign the commits the content container pushes. They are given to it whatever the
+// session selected; the runner only sees them when granted like any other key.
+var gitSigningKeys = []string{"GIT_SIGNING_KEY", "GIT_SIGNING_FORMAT"}
+
+// gitSigningSecretName holds the signing key and format mounted into the content container
+func gitSigningSecretName(sessionName string) string {
+	return fmt.Sprintf("ambient-git-signing-%s", sessionName)
+}
+
+// gitSigningMaterial reads the project's signing key and format from the runner secret or from
+// external references, which win as they do for the runner's environment. Nothing is returned
+// when the project has no signing key.
+func gitSigningMaterial(ctx context.Context, namespace, runnerSecretsName string, refs []secrets.Ref) (map[string]string, error) {
+	values := map[string]string{}
+	if runnerSecretsName != "" {
+		sec, err := config.K8sClient.CoreV1().Secrets(namespace).Get(ctx, runnerSecretsName, v1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("read runner secret %s: %w", runnerSecretsName, err)
+		}
+		if err == nil {
+			for _, k := range gitSigningKeys {
+				if v := strings.TrimSpace(string(sec.Data[k])); v != "" {
+					values[k] = v
+				}
+			}
+		}
+	}
+	var signingRefs []secrets.Ref
+	for _, r := range refs {
+		for _, k := range gitSigningKeys {
+			if r.EnvName == k {
+				signingRefs = append(signingRefs, r)
+			}
+		}
+	}
+	resolved, err := secrets.Resolve(ctx, config.SecretProvider, secrets.ProjectScope(namespace), signingRefs)
+	if err != nil {
+		return nil, fmt.Errorf("resolve signing key references: %w", err)
+	}
+	for k, v := range resolved {
+		values[k] = v
+	}
+	if strings.TrimSpace(values["GIT_SIGNING_KEY"]) == "" {
+		return nil, nil
+	}
+	return values, nil
+}
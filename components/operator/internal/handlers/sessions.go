@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
 	"ambient-code-operator/internal/config"
+	"ambient-code-operator/internal/secrets"
 	"ambient-code-operator/internal/services"
 	"ambient-code-operator/internal/types"
 
@@ -16,18 +18,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	intstr "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/watch"
 )
 
 // WatchAgenticSessions watches for AgenticSession custom resources and creates jobs
 func WatchAgenticSessions() {
-	gvr := types.GetAgenticSessionResource()
-
 	for {
 		// Watch AgenticSessions across all namespaces
-		watcher, err := config.DynamicClient.Resource(gvr).Watch(context.TODO(), v1.ListOptions{})
+		watcher, err := config.VteamClient.VteamV1alpha1().AgenticSessions("").Watch(context.TODO(), v1.ListOptions{})
 		if err != nil {
 			log.Printf("Failed to create AgenticSession watcher: %v", err)
 			time.Sleep(5 * time.Second)
@@ -39,10 +38,13 @@ func WatchAgenticSessions() {
 		for event := range watcher.ResultChan() {
 			switch event.Type {
 			case watch.Added, watch.Modified:
-				obj := event.Object.(*unstructured.Unstructured)
+				obj, ok := event.Object.(*vteamv1alpha1.AgenticSession)
+				if !ok {
+					continue
+				}
 
 				// Only process resources in managed namespaces
-				ns := obj.GetNamespace()
+				ns := obj.Namespace
 				if ns == "" {
 					continue
 				}
@@ -63,17 +65,19 @@ func WatchAgenticSessions() {
 					log.Printf("Error handling AgenticSession event: %v", err)
 				}
 			case watch.Deleted:
-				obj := event.Object.(*unstructured.Unstructured)
-				sessionName := obj.GetName()
-				sessionNamespace := obj.GetNamespace()
+				obj, ok := event.Object.(*vteamv1alpha1.AgenticSession)
+				if !ok {
+					continue
+				}
+				sessionName := obj.Name
+				sessionNamespace := obj.Namespace
 				log.Printf("AgenticSession %s/%s deleted", sessionNamespace, sessionName)
 
 				// Cancel any ongoing job monitoring for this session
 				// (We could implement this with a context cancellation if needed)
 				// OwnerReferences handle cleanup of per-session resources
 			case watch.Error:
-				obj := event.Object.(*unstructured.Unstructured)
-				log.Printf("Watch error for AgenticSession: %v", obj)
+				log.Printf("Watch error for AgenticSession: %v", errors.FromObject(event.Object))
 			}
 		}
 
@@ -83,13 +87,12 @@ func WatchAgenticSessions() {
 	}
 }
 
-func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
-	name := obj.GetName()
-	sessionNamespace := obj.GetNamespace()
+func handleAgenticSessionEvent(obj *vteamv1alpha1.AgenticSession) error {
+	name := obj.Name
+	sessionNamespace := obj.Namespace
 
 	// Verify the resource still exists before processing (in its own namespace)
-	gvr := types.GetAgenticSessionResource()
-	currentObj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), name, v1.GetOptions{})
+	session, err := getAgenticSession(sessionNamespace, name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Printf("AgenticSession %s no longer exists, skipping processing", name)
@@ -100,16 +103,12 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 
 	// Get the current status from the fresh object (status may be empty right after creation
 	// because the API server drops .status on create when the status subresource is enabled)
-	stMap, found, _ := unstructured.NestedMap(currentObj.Object, "status")
-	phase := ""
-	if found {
-		if p, ok := stMap["phase"].(string); ok {
-			phase = p
-		}
-	}
+	phase := string(session.Status.Phase)
 	// If status.phase is missing, treat as Pending and initialize it
 	if phase == "" {
-		_ = updateAgenticSessionStatus(sessionNamespace, name, map[string]interface{}{"phase": "Pending"})
+		_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+			s.Phase = vteamv1alpha1.AgenticSessionPhasePending
+		})
 		phase = "Pending"
 	}
 
@@ -180,17 +179,14 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 	// Check for session continuation (parent session ID)
 	parentSessionID := ""
 	// Check annotations first
-	annotations := currentObj.GetAnnotations()
+	annotations := session.Annotations
 	if val, ok := annotations["vteam.ambient-code/parent-session-id"]; ok {
 		parentSessionID = strings.TrimSpace(val)
 	}
 	// Check environmentVariables as fallback
 	if parentSessionID == "" {
-		spec, _, _ := unstructured.NestedMap(currentObj.Object, "spec")
-		if envVars, found, _ := unstructured.NestedStringMap(spec, "environmentVariables"); found {
-			if val, ok := envVars["PARENT_SESSION_ID"]; ok {
-				parentSessionID = strings.TrimSpace(val)
-			}
+		if val, ok := session.Spec.EnvironmentVariables["PARENT_SESSION_ID"]; ok {
+			parentSessionID = strings.TrimSpace(val)
 		}
 	}
 
@@ -210,10 +206,10 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 		pvcName = fmt.Sprintf("ambient-workspace-%s", name)
 		ownerRefs = []v1.OwnerReference{
 			{
-				APIVersion: "vteam.ambient-code/v1",
+				APIVersion: vteamv1alpha1.SchemeGroupVersion.String(),
 				Kind:       "AgenticSession",
-				Name:       currentObj.GetName(),
-				UID:        currentObj.GetUID(),
+				Name:       session.Name,
+				UID:        session.UID,
 				Controller: boolPtr(true),
 				// BlockOwnerDeletion intentionally omitted to avoid permission issues
 			},
@@ -234,10 +230,10 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 			pvcName = fmt.Sprintf("ambient-workspace-%s", name)
 			ownerRefs = []v1.OwnerReference{
 				{
-					APIVersion: "vteam.ambient-code/v1",
+					APIVersion: vteamv1alpha1.SchemeGroupVersion.String(),
 					Kind:       "AgenticSession",
-					Name:       currentObj.GetName(),
-					UID:        currentObj.GetUID(),
+					Name:       session.Name,
+					UID:        session.UID,
 					Controller: boolPtr(true),
 				},
 			}
@@ -260,50 +256,119 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 		return nil
 	}
 
-	// Extract spec information from the fresh object
-	spec, _, _ := unstructured.NestedMap(currentObj.Object, "spec")
-	prompt, _, _ := unstructured.NestedString(spec, "prompt")
-	timeout, _, _ := unstructured.NestedInt64(spec, "timeout")
-	interactive, _, _ := unstructured.NestedBool(spec, "interactive")
-
-	llmSettings, _, _ := unstructured.NestedMap(spec, "llmSettings")
-	model, _, _ := unstructured.NestedString(llmSettings, "model")
-	temperature, _, _ := unstructured.NestedFloat64(llmSettings, "temperature")
-	maxTokens, _, _ := unstructured.NestedInt64(llmSettings, "maxTokens")
+	spec := session.Spec
 
 	// Read runner secrets configuration from ProjectSettings in the session's namespace
 	runnerSecretsName := ""
+	var psSpec map[string]interface{}
 	{
 		psGvr := types.GetProjectSettingsResource()
 		if psObj, err := config.DynamicClient.Resource(psGvr).Namespace(sessionNamespace).Get(context.TODO(), "projectsettings", v1.GetOptions{}); err == nil {
-			if psSpec, ok := psObj.Object["spec"].(map[string]interface{}); ok {
-				if v, ok := psSpec["runnerSecretsName"].(string); ok {
-					runnerSecretsName = strings.TrimSpace(v)
-				}
+			psSpec, _ = psObj.Object["spec"].(map[string]interface{})
+			if v, ok := psSpec["runnerSecretsName"].(string); ok {
+				runnerSecretsName = strings.TrimSpace(v)
 			}
 		}
 	}
 
-	// Extract input/output git configuration (support flat and nested forms)
-	inputRepo, _, _ := unstructured.NestedString(spec, "inputRepo")
-	inputBranch, _, _ := unstructured.NestedString(spec, "inputBranch")
-	outputRepo, _, _ := unstructured.NestedString(spec, "outputRepo")
-	outputBranch, _, _ := unstructured.NestedString(spec, "outputBranch")
-	if v, found, _ := unstructured.NestedString(spec, "input", "repo"); found && strings.TrimSpace(v) != "" {
-		inputRepo = v
+	// Only the runner secret keys the session selected (all of them by default) reach the Job
+	grant, err := grantRunnerSecrets(sessionNamespace, runnerSecretsName, psSpec, spec.Secrets, secrets.ParseRefs(psSpec))
+	if err != nil {
+		log.Printf("Failed to select runner secrets for %s/%s: %v", sessionNamespace, name, err)
+		_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+			s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+			s.Message = fmt.Sprintf("Failed to select runner secrets: %v", err)
+		})
+		return fmt.Errorf("failed to select runner secrets: %w", err)
+	}
+
+	// Egress policy from ProjectSettings; a restricted project gets a NetworkPolicy before the Job runs
+	egress, err := resolveEgress(context.TODO(), parseEgressPolicy(psSpec))
+	if err != nil {
+		log.Printf("Invalid egress policy for %s/%s: %v", sessionNamespace, name, err)
+		_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+			s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+			s.Message = fmt.Sprintf("Invalid egress policy: %v", err)
+		})
+		return fmt.Errorf("invalid egress policy: %w", err)
 	}
-	if v, found, _ := unstructured.NestedString(spec, "input", "branch"); found && strings.TrimSpace(v) != "" {
-		inputBranch = v
+	networkPolicyName := ""
+	if egress.policy.restricted {
+		networkPolicyName = egressNetworkPolicyName(name)
+		if err := createEgressNetworkPolicy(sessionNamespace, name, jobName, appConfig.BackendNamespace, egress); err != nil {
+			log.Printf("Failed to create egress NetworkPolicy for %s/%s: %v", sessionNamespace, name, err)
+			_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+				s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+				s.Message = fmt.Sprintf("Failed to apply egress policy: %v", err)
+			})
+			return err
+		}
+	}
+
+	// Resolve external secret references now so a missing secret fails the session before any Job exists
+	resolvedSecretName := ""
+	if len(grant.refs) > 0 {
+		values, err := secrets.Resolve(context.TODO(), config.SecretProvider, secrets.ProjectScope(sessionNamespace), grant.refs)
+		if err != nil {
+			log.Printf("Failed to resolve runner secret references for %s/%s: %v", sessionNamespace, name, err)
+			_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+				s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+				s.Message = fmt.Sprintf("Failed to resolve runner secret references: %v", err)
+			})
+			return fmt.Errorf("failed to resolve runner secret references: %w", err)
+		}
+		resolvedSecretName = resolvedRunnerSecretName(name)
+		if err := createResolvedRunnerSecret(sessionNamespace, resolvedSecretName, name, values); err != nil {
+			log.Printf("Failed to create resolved runner secret for %s/%s: %v", sessionNamespace, name, err)
+			_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+				s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+				s.Message = fmt.Sprintf("Failed to create runner secret: %v", err)
+			})
+			return err
+		}
 	}
-	if v, found, _ := unstructured.NestedString(spec, "output", "repo"); found && strings.TrimSpace(v) != "" {
-		outputRepo = v
+
+	// The content container signs pushed commits with the project's key, whether or not the session
+	// selected it and wherever it is stored
+	signing, err := gitSigningMaterial(context.TODO(), sessionNamespace, runnerSecretsName, secrets.ParseRefs(psSpec))
+	if err != nil {
+		log.Printf("Failed to read git signing key for %s/%s: %v", sessionNamespace, name, err)
+		_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+			s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+			s.Message = fmt.Sprintf("Failed to read git signing key: %v", err)
+		})
+		return fmt.Errorf("failed to read git signing key: %w", err)
 	}
-	if v, found, _ := unstructured.NestedString(spec, "output", "branch"); found && strings.TrimSpace(v) != "" {
-		outputBranch = v
+	signingSecretName := ""
+	if len(signing) > 0 {
+		signingSecretName = gitSigningSecretName(name)
+		if err := createResolvedRunnerSecret(sessionNamespace, signingSecretName, name, signing); err != nil {
+			log.Printf("Failed to create git signing secret for %s/%s: %v", sessionNamespace, name, err)
+			if resolvedSecretName != "" {
+				deleteResolvedRunnerSecret(sessionNamespace, resolvedSecretName)
+			}
+			_ = updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+				s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+				s.Message = fmt.Sprintf("Failed to create git signing secret: %v", err)
+			})
+			return err
+		}
 	}
 
-	// Read autoPushOnComplete flag
-	autoPushOnComplete, _, _ := unstructured.NestedBool(spec, "autoPushOnComplete")
+	// The main repo is also exposed through the single-repo variables for older runners
+	var inputRepo, inputBranch, outputRepo, outputBranch string
+	if main := spec.MainRepo(); main != nil {
+		inputRepo = main.Input.URL
+		if main.Input.Branch != nil {
+			inputBranch = *main.Input.Branch
+		}
+		if main.Output != nil {
+			outputRepo = main.Output.URL
+			if main.Output.Branch != nil {
+				outputBranch = *main.Output.Branch
+			}
+		}
+	}
 
 	// Create the Job
 	job := &batchv1.Job{
@@ -316,10 +381,10 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 			},
 			OwnerReferences: []v1.OwnerReference{
 				{
-					APIVersion: "vteam.ambient-code/v1",
+					APIVersion: vteamv1alpha1.SchemeGroupVersion.String(),
 					Kind:       "AgenticSession",
-					Name:       currentObj.GetName(),
-					UID:        currentObj.GetUID(),
+					Name:       session.Name,
+					UID:        session.UID,
 					Controller: boolPtr(true),
 					// Remove BlockOwnerDeletion to avoid permission issues
 					// BlockOwnerDeletion: boolPtr(true),
@@ -416,7 +481,7 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 							Env: func() []corev1.EnvVar {
 								base := []corev1.EnvVar{
 									{Name: "DEBUG", Value: "true"},
-									{Name: "INTERACTIVE", Value: fmt.Sprintf("%t", interactive)},
+									{Name: "INTERACTIVE", Value: fmt.Sprintf("%t", spec.Interactive)},
 									{Name: "AGENTIC_SESSION_NAME", Value: name},
 									{Name: "AGENTIC_SESSION_NAMESPACE", Value: sessionNamespace},
 									// Provide session id and workspace path for the runner wrapper
@@ -427,12 +492,12 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 									{Name: "INPUT_BRANCH", Value: inputBranch},
 									{Name: "OUTPUT_REPO_URL", Value: outputRepo},
 									{Name: "OUTPUT_BRANCH", Value: outputBranch},
-									{Name: "PROMPT", Value: prompt},
-									{Name: "LLM_MODEL", Value: model},
-									{Name: "LLM_TEMPERATURE", Value: fmt.Sprintf("%.2f", temperature)},
-									{Name: "LLM_MAX_TOKENS", Value: fmt.Sprintf("%d", maxTokens)},
-									{Name: "TIMEOUT", Value: fmt.Sprintf("%d", timeout)},
-									{Name: "AUTO_PUSH_ON_COMPLETE", Value: fmt.Sprintf("%t", autoPushOnComplete)},
+									{Name: "PROMPT", Value: spec.Prompt},
+									{Name: "LLM_MODEL", Value: spec.LLMSettings.Model},
+									{Name: "LLM_TEMPERATURE", Value: fmt.Sprintf("%.2f", spec.LLMSettings.Temperature)},
+									{Name: "LLM_MAX_TOKENS", Value: fmt.Sprintf("%d", spec.LLMSettings.MaxTokens)},
+									{Name: "TIMEOUT", Value: fmt.Sprintf("%d", spec.Timeout)},
+									{Name: "AUTO_PUSH_ON_COMPLETE", Value: fmt.Sprintf("%t", spec.AutoPushOnComplete)},
 									{Name: "BACKEND_API_URL", Value: fmt.Sprintf("http://backend-service.%s.svc.cluster.local:8080/api", appConfig.BackendNamespace)},
 									// WebSocket URL used by runner-shell to connect back to backend
 									{Name: "WEBSOCKET_URL", Value: fmt.Sprintf("ws://backend-service.%s.svc.cluster.local:8080/api/projects/%s/sessions/%s/ws", appConfig.BackendNamespace, sessionNamespace, name)},
@@ -447,12 +512,8 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 								// Secret contains: 'k8s-token' (for CR updates)
 								// Prefer annotated secret name; fallback to deterministic name
 								secretName := ""
-								if meta, ok := currentObj.Object["metadata"].(map[string]interface{}); ok {
-									if anns, ok := meta["annotations"].(map[string]interface{}); ok {
-										if v, ok := anns["ambient-code.io/runner-token-secret"].(string); ok && strings.TrimSpace(v) != "" {
-											secretName = strings.TrimSpace(v)
-										}
-									}
+								if v := strings.TrimSpace(session.Annotations["ambient-code.io/runner-token-secret"]); v != "" {
+									secretName = v
 								}
 								if secretName == "" {
 									secretName = fmt.Sprintf("ambient-runner-token-%s", name)
@@ -464,64 +525,57 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 										Key:                  "k8s-token",
 									}},
 								})
+								// Inject REPOS_JSON and MAIN_REPO_INDEX from spec.repos and spec.mainRepoIndex
+								// This ensures runner gets repos even if env vars weren't passed from frontend
+								if len(spec.Repos) > 0 {
+									b, _ := json.Marshal(spec.Repos)
+									base = append(base, corev1.EnvVar{Name: "REPOS_JSON", Value: string(b)})
+								}
+								if spec.MainRepoIndex != nil {
+									base = append(base, corev1.EnvVar{Name: "MAIN_REPO_INDEX", Value: fmt.Sprintf("%d", *spec.MainRepoIndex)})
+								}
 								// Add CR-provided envs last (override base when same key)
-								if spec, ok := currentObj.Object["spec"].(map[string]interface{}); ok {
-									// Inject REPOS_JSON and MAIN_REPO_NAME from spec.repos and spec.mainRepoName if present
-									if repos, ok := spec["repos"].([]interface{}); ok && len(repos) > 0 {
-										// Use a minimal JSON serialization via fmt (we'll rely on client to pass REPOS_JSON too)
-										// This ensures runner gets repos even if env vars weren't passed from frontend
-										b, _ := json.Marshal(repos)
-										base = append(base, corev1.EnvVar{Name: "REPOS_JSON", Value: string(b)})
-									}
-									if mrn, ok := spec["mainRepoName"].(string); ok && strings.TrimSpace(mrn) != "" {
-										base = append(base, corev1.EnvVar{Name: "MAIN_REPO_NAME", Value: mrn})
-									}
-									// Inject MAIN_REPO_INDEX if provided
-									if mriRaw, ok := spec["mainRepoIndex"]; ok {
-										switch v := mriRaw.(type) {
-										case int64:
-											base = append(base, corev1.EnvVar{Name: "MAIN_REPO_INDEX", Value: fmt.Sprintf("%d", v)})
-										case int32:
-											base = append(base, corev1.EnvVar{Name: "MAIN_REPO_INDEX", Value: fmt.Sprintf("%d", v)})
-										case int:
-											base = append(base, corev1.EnvVar{Name: "MAIN_REPO_INDEX", Value: fmt.Sprintf("%d", v)})
-										case float64:
-											base = append(base, corev1.EnvVar{Name: "MAIN_REPO_INDEX", Value: fmt.Sprintf("%d", int64(v))})
-										case string:
-											if strings.TrimSpace(v) != "" {
-												base = append(base, corev1.EnvVar{Name: "MAIN_REPO_INDEX", Value: v})
-											}
+								for k, v := range spec.EnvironmentVariables {
+									replaced := false
+									for i := range base {
+										if base[i].Name == k {
+											base[i].Value = v
+											replaced = true
+											break
 										}
 									}
-									if envMap, ok := spec["environmentVariables"].(map[string]interface{}); ok {
-										for k, v := range envMap {
-											if vs, ok := v.(string); ok {
-												// replace if exists
-												replaced := false
-												for i := range base {
-													if base[i].Name == k {
-														base[i].Value = vs
-														replaced = true
-														break
-													}
-												}
-												if !replaced {
-													base = append(base, corev1.EnvVar{Name: k, Value: vs})
-												}
-											}
-										}
+									if !replaced {
+										base = append(base, corev1.EnvVar{Name: k, Value: v})
+									}
+								}
+								// Selected runner secret keys, which like EnvFrom never override the values above
+								for _, k := range grant.secretKeys {
+									if _, set := spec.EnvironmentVariables[k]; set {
+										continue
 									}
+									base = append(base, corev1.EnvVar{
+										Name: k,
+										ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{Name: runnerSecretsName},
+											Key:                  k,
+										}},
+									})
 								}
 
 								return base
 							}(),
 
-							// If configured, import all keys from the runner Secret as environment variables
+							// Sessions that select no keys import all keys from the runner Secret as environment variables
+							// Resolved references come last so they win over keys of the same name
 							EnvFrom: func() []corev1.EnvFromSource {
-								if runnerSecretsName != "" {
-									return []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: runnerSecretsName}}}}
+								sources := []corev1.EnvFromSource{}
+								if runnerSecretsName != "" && grant.all {
+									sources = append(sources, corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: runnerSecretsName}}})
+								}
+								if resolvedSecretName != "" {
+									sources = append(sources, corev1.EnvFromSource{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: resolvedSecretName}}})
 								}
-								return []corev1.EnvFromSource{}
+								return sources
 							}(),
 
 							Resources: corev1.ResourceRequirements{},
@@ -532,27 +586,38 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 		},
 	}
 
-	// If a runner secret is configured, mount it as a volume in addition to EnvFrom
-	if strings.TrimSpace(runnerSecretsName) != "" {
+	// The content container reads only the signing key and format from its runner-secrets mount.
+	// GIT_SIGNING_CONFIGURED makes pushes fail rather than go out unsigned if the key is missing.
+	if signingSecretName != "" && len(job.Spec.Template.Spec.Containers) > 0 {
 		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, corev1.Volume{
 			Name:         "runner-secrets",
-			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: runnerSecretsName}},
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: signingSecretName}},
 		})
-		if len(job.Spec.Template.Spec.Containers) > 0 {
-			job.Spec.Template.Spec.Containers[0].VolumeMounts = append(job.Spec.Template.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
-				Name:      "runner-secrets",
-				MountPath: "/var/run/runner-secrets",
-				ReadOnly:  true,
-			})
-		}
+		content := &job.Spec.Template.Spec.Containers[0]
+		content.VolumeMounts = append(content.VolumeMounts, corev1.VolumeMount{
+			Name:      "runner-secrets",
+			MountPath: "/var/run/runner-secrets",
+			ReadOnly:  true,
+		})
+		content.Env = append(content.Env, corev1.EnvVar{Name: "GIT_SIGNING_CONFIGURED", Value: "true"})
 	}
 
 	// Do not mount runner Secret volume; runner fetches tokens on demand
 
+	// Hostname filtering: route HTTP(S) of the other containers through the egress proxy sidecar
+	if egress.policy.restricted && egress.policy.proxy {
+		proxyEnv := egressProxyEnv(appConfig.BackendNamespace)
+		for i := range job.Spec.Template.Spec.Containers {
+			job.Spec.Template.Spec.Containers[i].Env = append(job.Spec.Template.Spec.Containers[i].Env, proxyEnv...)
+		}
+		job.Spec.Template.Spec.Containers = append(job.Spec.Template.Spec.Containers,
+			egressProxyContainer(appConfig.ContentServiceImage, appConfig.ImagePullPolicy, egress))
+	}
+
 	// Update status to Creating before attempting job creation
-	if err := updateAgenticSessionStatus(sessionNamespace, name, map[string]interface{}{
-		"phase":   "Creating",
-		"message": "Creating Kubernetes job",
+	if err := updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+		s.Phase = vteamv1alpha1.AgenticSessionPhaseCreating
+		s.Message = "Creating Kubernetes job"
 	}); err != nil {
 		log.Printf("Failed to update AgenticSession status to Creating: %v", err)
 		// Continue anyway - resource might have been deleted
@@ -567,22 +632,48 @@ func handleAgenticSessionEvent(obj *unstructured.Unstructured) error {
 			return nil
 		}
 		log.Printf("Failed to create job %s: %v", jobName, err)
+		if resolvedSecretName != "" {
+			deleteResolvedRunnerSecret(sessionNamespace, resolvedSecretName)
+		}
+		if signingSecretName != "" {
+			deleteResolvedRunnerSecret(sessionNamespace, signingSecretName)
+		}
+		if networkPolicyName != "" {
+			deleteEgressNetworkPolicy(sessionNamespace, networkPolicyName)
+		}
 		// Update status to Error if job creation fails and resource still exists
-		updateAgenticSessionStatus(sessionNamespace, name, map[string]interface{}{
-			"phase":   "Error",
-			"message": fmt.Sprintf("Failed to create job: %v", err),
+		updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+			s.Phase = vteamv1alpha1.AgenticSessionPhaseError
+			s.Message = fmt.Sprintf("Failed to create job: %v", err)
 		})
 		return fmt.Errorf("failed to create job: %v", err)
 	}
 
 	log.Printf("Created job %s for AgenticSession %s", jobName, name)
 
+	// Hand the resolved secret to the Job so it is garbage collected with it
+	for _, secretName := range []string{resolvedSecretName, signingSecretName} {
+		if secretName == "" {
+			continue
+		}
+		if err := ownResolvedRunnerSecret(sessionNamespace, secretName, createdJob); err != nil {
+			log.Printf("Failed to set owner on runner secret %s/%s: %v", sessionNamespace, secretName, err)
+		}
+	}
+	if networkPolicyName != "" {
+		if err := ownEgressNetworkPolicy(sessionNamespace, networkPolicyName, createdJob); err != nil {
+			log.Printf("Failed to set owner on egress NetworkPolicy %s/%s: %v", sessionNamespace, networkPolicyName, err)
+		}
+	}
+
 	// Update AgenticSession status to Running
-	if err := updateAgenticSessionStatus(sessionNamespace, name, map[string]interface{}{
-		"phase":     "Creating",
-		"message":   "Job is being set up",
-		"startTime": time.Now().Format(time.RFC3339),
-		"jobName":   jobName,
+	if err := updateAgenticSessionStatus(sessionNamespace, name, func(s *vteamv1alpha1.AgenticSessionStatus) {
+		s.Phase = vteamv1alpha1.AgenticSessionPhaseCreating
+		s.Message = "Job is being set up"
+		s.StartTime = metaNow()
+		s.JobName = jobName
+		s.GrantedSecretKeys = grant.granted
+		s.EgressPolicy = egress.status(networkPolicyName)
 	}); err != nil {
 		log.Printf("Failed to update AgenticSession status to Creating: %v", err)
 		// Don't return error here - the job was created successfully
@@ -632,8 +723,7 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 		time.Sleep(5 * time.Second)
 
 		// Ensure the AgenticSession still exists
-		gvr := types.GetAgenticSessionResource()
-		if _, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{}); err != nil {
+		if _, err := getAgenticSession(sessionNamespace, sessionName); err != nil {
 			if errors.IsNotFound(err) {
 				log.Printf("AgenticSession %s no longer exists, stopping job monitoring for %s", sessionName, jobName)
 				return
@@ -680,23 +770,17 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 		// BUT: respect terminal statuses already set by wrapper (Failed, Completed)
 		if job.Status.Succeeded > 0 {
 			// Check current status before overriding
-			gvr := types.GetAgenticSessionResource()
-			currentObj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{})
-			currentPhase := ""
-			if err == nil && currentObj != nil {
-				if status, found, _ := unstructured.NestedMap(currentObj.Object, "status"); found {
-					if v, ok := status["phase"].(string); ok {
-						currentPhase = v
-					}
-				}
+			var currentPhase vteamv1alpha1.AgenticSessionPhase
+			if current, err := getAgenticSession(sessionNamespace, sessionName); err == nil {
+				currentPhase = current.Status.Phase
 			}
 			// Only set to Completed if not already in a terminal state (Failed, Completed, Stopped)
 			if currentPhase != "Failed" && currentPhase != "Completed" && currentPhase != "Stopped" {
 				log.Printf("Job %s marked succeeded by Kubernetes, setting to Completed", jobName)
-				_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-					"phase":          "Completed",
-					"message":        "Job completed successfully",
-					"completionTime": time.Now().Format(time.RFC3339),
+				_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+					s.Phase = vteamv1alpha1.AgenticSessionPhaseCompleted
+					s.Message = "Job completed successfully"
+					s.CompletionTime = metaNow()
 				})
 				// Ensure session is interactive so it can be restarted
 				_ = ensureSessionIsInteractive(sessionNamespace, sessionName)
@@ -721,19 +805,13 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 			}
 
 			// Only update to Failed if not already in a terminal state
-			gvr := types.GetAgenticSessionResource()
-			if currentObj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{}); err == nil {
-				currentPhase := ""
-				if status, found, _ := unstructured.NestedMap(currentObj.Object, "status"); found {
-					if v, ok := status["phase"].(string); ok {
-						currentPhase = v
-					}
-				}
+			if current, err := getAgenticSession(sessionNamespace, sessionName); err == nil {
+				currentPhase := current.Status.Phase
 				if currentPhase != "Failed" && currentPhase != "Completed" && currentPhase != "Stopped" {
-					_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-						"phase":          "Failed",
-						"message":        failureMsg,
-						"completionTime": time.Now().Format(time.RFC3339),
+					_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+						s.Phase = vteamv1alpha1.AgenticSessionPhaseFailed
+						s.Message = failureMsg
+						s.CompletionTime = metaNow()
 					})
 					// Ensure session is interactive so it can be restarted
 					_ = ensureSessionIsInteractive(sessionNamespace, sessionName)
@@ -753,21 +831,15 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 		// Check for job with no active pods (pod evicted/preempted/deleted)
 		if len(pods.Items) == 0 && job.Status.Active == 0 && job.Status.Succeeded == 0 && job.Status.Failed == 0 {
 			// Check current phase to see if this is unexpected
-			gvr := types.GetAgenticSessionResource()
-			if currentObj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{}); err == nil {
-				currentPhase := ""
-				if status, found, _ := unstructured.NestedMap(currentObj.Object, "status"); found {
-					if v, ok := status["phase"].(string); ok {
-						currentPhase = v
-					}
-				}
+			if current, err := getAgenticSession(sessionNamespace, sessionName); err == nil {
+				currentPhase := current.Status.Phase
 				// If session is Running but pod is gone, mark as Failed
 				if currentPhase == "Running" || currentPhase == "Creating" {
 					log.Printf("Job %s has no pods but session is %s, marking as Failed", jobName, currentPhase)
-					_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-						"phase":          "Failed",
-						"message":        "Job pod was deleted or evicted unexpectedly",
-						"completionTime": time.Now().Format(time.RFC3339),
+					_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+						s.Phase = vteamv1alpha1.AgenticSessionPhaseFailed
+						s.Message = "Job pod was deleted or evicted unexpectedly"
+						s.CompletionTime = metaNow()
 					})
 					_ = deleteJobAndPerJobService(sessionNamespace, jobName, sessionName)
 					return
@@ -783,22 +855,16 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 
 		// Check for pod-level failures (ImagePullBackOff, CrashLoopBackOff, etc.)
 		if pod.Status.Phase == corev1.PodFailed {
-			gvr := types.GetAgenticSessionResource()
-			if currentObj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{}); err == nil {
-				currentPhase := ""
-				if status, found, _ := unstructured.NestedMap(currentObj.Object, "status"); found {
-					if v, ok := status["phase"].(string); ok {
-						currentPhase = v
-					}
-				}
+			if current, err := getAgenticSession(sessionNamespace, sessionName); err == nil {
+				currentPhase := current.Status.Phase
 				// Only update if not already in terminal state
 				if currentPhase != "Failed" && currentPhase != "Completed" && currentPhase != "Stopped" {
 					failureMsg := fmt.Sprintf("Pod failed: %s - %s", pod.Status.Reason, pod.Status.Message)
 					log.Printf("Job %s pod in Failed phase, updating session to Failed: %s", jobName, failureMsg)
-					_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-						"phase":          "Failed",
-						"message":        failureMsg,
-						"completionTime": time.Now().Format(time.RFC3339),
+					_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+						s.Phase = vteamv1alpha1.AgenticSessionPhaseFailed
+						s.Message = failureMsg
+						s.CompletionTime = metaNow()
 					})
 					_ = deleteJobAndPerJobService(sessionNamespace, jobName, sessionName)
 					return
@@ -814,22 +880,16 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 				errorStates := []string{"ImagePullBackOff", "ErrImagePull", "CrashLoopBackOff", "CreateContainerConfigError", "InvalidImageName"}
 				for _, errState := range errorStates {
 					if waiting.Reason == errState {
-						gvr := types.GetAgenticSessionResource()
-						if currentObj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{}); err == nil {
-							currentPhase := ""
-							if status, found, _ := unstructured.NestedMap(currentObj.Object, "status"); found {
-								if v, ok := status["phase"].(string); ok {
-									currentPhase = v
-								}
-							}
+						if current, err := getAgenticSession(sessionNamespace, sessionName); err == nil {
+							currentPhase := current.Status.Phase
 							// Only update if not already in terminal state and we've been in this state for a while
 							if currentPhase == "Running" || currentPhase == "Creating" {
 								failureMsg := fmt.Sprintf("Container %s failed: %s - %s", cs.Name, waiting.Reason, waiting.Message)
 								log.Printf("Job %s container in error state, updating session to Failed: %s", jobName, failureMsg)
-								_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-									"phase":          "Failed",
-									"message":        failureMsg,
-									"completionTime": time.Now().Format(time.RFC3339),
+								_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+									s.Phase = vteamv1alpha1.AgenticSessionPhaseFailed
+									s.Message = failureMsg
+									s.CompletionTime = metaNow()
 								})
 								_ = deleteJobAndPerJobService(sessionNamespace, jobName, sessionName)
 								return
@@ -845,25 +905,20 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 			if cs.State.Running != nil {
 				// Avoid downgrading terminal phases; only set Running when not already terminal
 				func() {
-					gvr := types.GetAgenticSessionResource()
-					obj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{})
-					if err != nil || obj == nil {
+					obj, err := getAgenticSession(sessionNamespace, sessionName)
+					if err != nil {
 						// Best-effort: still try to set Running
-						_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-							"phase":   "Running",
-							"message": "Agent is running",
+						_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+							s.Phase = vteamv1alpha1.AgenticSessionPhaseRunning
+							s.Message = "Agent is running"
 						})
 						return
 					}
-					status, _, _ := unstructured.NestedMap(obj.Object, "status")
-					current := ""
-					if v, ok := status["phase"].(string); ok {
-						current = v
-					}
+					current := obj.Status.Phase
 					if current != "Completed" && current != "Stopped" && current != "Failed" && current != "Running" {
-						_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-							"phase":   "Running",
-							"message": "Agent is running",
+						_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+							s.Phase = vteamv1alpha1.AgenticSessionPhaseRunning
+							s.Message = "Agent is running"
 						})
 					}
 				}()
@@ -881,14 +936,9 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 			term := runnerStatus.State.Terminated
 
 			// Get current CR status to check if wrapper already set it
-			gvr := types.GetAgenticSessionResource()
-			obj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), sessionName, v1.GetOptions{})
-			currentPhase := ""
-			if err == nil && obj != nil {
-				status, _, _ := unstructured.NestedMap(obj.Object, "status")
-				if v, ok := status["phase"].(string); ok {
-					currentPhase = v
-				}
+			var currentPhase vteamv1alpha1.AgenticSessionPhase
+			if obj, err := getAgenticSession(sessionNamespace, sessionName); err == nil {
+				currentPhase = obj.Status.Phase
 			}
 
 			// If wrapper already set status to Completed, clean up immediately
@@ -909,10 +959,10 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 
 			// Runner exit code 0 = success (fallback if wrapper didn't set status)
 			if term.ExitCode == 0 {
-				_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-					"phase":          "Completed",
-					"message":        "Runner completed successfully",
-					"completionTime": time.Now().Format(time.RFC3339),
+				_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+					s.Phase = vteamv1alpha1.AgenticSessionPhaseCompleted
+					s.Message = "Runner completed successfully"
+					s.CompletionTime = metaNow()
 				})
 				// Ensure session is interactive so it can be restarted
 				_ = ensureSessionIsInteractive(sessionNamespace, sessionName)
@@ -926,9 +976,9 @@ func monitorJob(jobName, sessionName, sessionNamespace string) {
 			if msg == "" {
 				msg = fmt.Sprintf("Runner container exited with code %d", term.ExitCode)
 			}
-			_ = updateAgenticSessionStatus(sessionNamespace, sessionName, map[string]interface{}{
-				"phase":   "Failed",
-				"message": msg,
+			_ = updateAgenticSessionStatus(sessionNamespace, sessionName, func(s *vteamv1alpha1.AgenticSessionStatus) {
+				s.Phase = vteamv1alpha1.AgenticSessionPhaseFailed
+				s.Message = msg
 			})
 			// Ensure session is interactive so it can be restarted
 			_ = ensureSessionIsInteractive(sessionNamespace, sessionName)
@@ -960,6 +1010,10 @@ func deleteJobAndPerJobService(namespace, jobName, sessionName string) error {
 		log.Printf("Failed to delete per-job service %s/%s: %v", namespace, svcName, err)
 	}
 
+	// Resolved runner secrets and the egress NetworkPolicy only live as long as their Job
+	deleteResolvedRunnerSecret(namespace, resolvedRunnerSecretName(sessionName))
+	deleteEgressNetworkPolicy(namespace, egressNetworkPolicyName(sessionName))
+
 	// Delete the Job with background propagation
 	policy := v1.DeletePropagationBackground
 	if err := config.K8sClient.BatchV1().Jobs(namespace).Delete(context.TODO(), jobName, v1.DeleteOptions{PropagationPolicy: &policy}); err != nil && !errors.IsNotFound(err) {
@@ -985,11 +1039,16 @@ func deleteJobAndPerJobService(namespace, jobName, sessionName string) error {
 	return nil
 }
 
-func updateAgenticSessionStatus(sessionNamespace, name string, statusUpdate map[string]interface{}) error {
-	gvr := types.GetAgenticSessionResource()
+// getAgenticSession reads a session with the typed client
+func getAgenticSession(namespace, name string) (*vteamv1alpha1.AgenticSession, error) {
+	return config.VteamClient.VteamV1alpha1().AgenticSessions(namespace).Get(context.TODO(), name, v1.GetOptions{})
+}
 
+// updateAgenticSessionStatus applies update to the session's current status and writes it
+// through the status subresource
+func updateAgenticSessionStatus(sessionNamespace, name string, update func(*vteamv1alpha1.AgenticSessionStatus)) error {
 	// Get current resource
-	obj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), name, v1.GetOptions{})
+	session, err := getAgenticSession(sessionNamespace, name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Printf("AgenticSession %s no longer exists, skipping status update", name)
@@ -998,18 +1057,9 @@ func updateAgenticSessionStatus(sessionNamespace, name string, statusUpdate map[
 		return fmt.Errorf("failed to get AgenticSession %s: %v", name, err)
 	}
 
-	// Update status
-	if obj.Object["status"] == nil {
-		obj.Object["status"] = make(map[string]interface{})
-	}
-
-	status := obj.Object["status"].(map[string]interface{})
-	for key, value := range statusUpdate {
-		status[key] = value
-	}
+	update(&session.Status)
 
-	// Update the resource with retry logic
-	_, err = config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).UpdateStatus(context.TODO(), obj, v1.UpdateOptions{})
+	_, err = config.VteamClient.VteamV1alpha1().AgenticSessions(sessionNamespace).UpdateStatus(context.TODO(), session, v1.UpdateOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Printf("AgenticSession %s was deleted during status update, skipping", name)
@@ -1021,13 +1071,17 @@ func updateAgenticSessionStatus(sessionNamespace, name string, statusUpdate map[
 	return nil
 }
 
+// metaNow returns the current time for status timestamps
+func metaNow() *v1.Time {
+	now := v1.Now()
+	return &now
+}
+
 // ensureSessionIsInteractive updates a session's spec to set interactive: true
 // This allows completed sessions to be restarted without requiring manual spec file removal
 func ensureSessionIsInteractive(sessionNamespace, name string) error {
-	gvr := types.GetAgenticSessionResource()
-
 	// Get current resource
-	obj, err := config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Get(context.TODO(), name, v1.GetOptions{})
+	session, err := getAgenticSession(sessionNamespace, name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Printf("AgenticSession %s no longer exists, skipping interactive update", name)
@@ -1036,32 +1090,16 @@ func ensureSessionIsInteractive(sessionNamespace, name string) error {
 		return fmt.Errorf("failed to get AgenticSession %s: %v", name, err)
 	}
 
-	// Check if spec exists and if interactive is already true
-	spec, found, err := unstructured.NestedMap(obj.Object, "spec")
-	if err != nil {
-		return fmt.Errorf("failed to get spec from AgenticSession %s: %v", name, err)
-	}
-	if !found {
-		log.Printf("AgenticSession %s has no spec, cannot update interactive", name)
-		return nil
-	}
-
-	// Check current interactive value
-	interactive, _, _ := unstructured.NestedBool(spec, "interactive")
-	if interactive {
+	if session.Spec.Interactive {
 		log.Printf("AgenticSession %s is already interactive, no update needed", name)
 		return nil
 	}
 
-	// Update spec to set interactive: true
-	if err := unstructured.SetNestedField(obj.Object, true, "spec", "interactive"); err != nil {
-		return fmt.Errorf("failed to set interactive field for AgenticSession %s: %v", name, err)
-	}
-
 	log.Printf("Setting interactive: true for AgenticSession %s to allow restart", name)
+	session.Spec.Interactive = true
 
 	// Update the resource (not UpdateStatus, since we're modifying spec)
-	_, err = config.DynamicClient.Resource(gvr).Namespace(sessionNamespace).Update(context.TODO(), obj, v1.UpdateOptions{})
+	_, err = config.VteamClient.VteamV1alpha1().AgenticSessions(sessionNamespace).Update(context.TODO(), session, v1.UpdateOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
 			log.Printf("AgenticSession %s was deleted during spec update, skipping", name)
@@ -1128,3 +1166,67 @@ var (
 	int32Ptr = func(i int32) *int32 { return &i }
 	int64Ptr = func(i int64) *int64 { return &i }
 )
+
+// resolvedRunnerSecretName is the Secret holding a session's resolved runner secret references
+func resolvedRunnerSecretName(sessionName string) string {
+	return fmt.Sprintf("ambient-runner-refs-%s", sessionName)
+}
+
+// createResolvedRunnerSecret writes resolved reference values before the Job exists so the runner
+// never starts without them; ownResolvedRunnerSecret later attaches it to the Job
+func createResolvedRunnerSecret(namespace, secretName, sessionName string, values map[string]string) error {
+	sec := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      secretName,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": "ambient-code-runner", "agentic-session": sessionName},
+			Annotations: map[string]string{
+				"ambient-code.io/resolved-runner-secret": "true",
+			},
+		},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: values,
+	}
+	// The signing secret may hold only values copied from the runner secret
+	if config.SecretProvider != nil {
+		sec.Annotations["ambient-code.io/secret-provider"] = config.SecretProvider.Name()
+	}
+	_, err := config.K8sClient.CoreV1().Secrets(namespace).Create(context.TODO(), sec, v1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		// Left over from an earlier attempt; refresh it with the current values
+		existing, gerr := config.K8sClient.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, v1.GetOptions{})
+		if gerr != nil {
+			return fmt.Errorf("get resolved runner secret: %w", gerr)
+		}
+		existing.Data = nil
+		existing.StringData = values
+		existing.OwnerReferences = nil
+		_, err = config.K8sClient.CoreV1().Secrets(namespace).Update(context.TODO(), existing, v1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("create resolved runner secret: %w", err)
+	}
+	return nil
+}
+
+func ownResolvedRunnerSecret(namespace, secretName string, job *batchv1.Job) error {
+	sec, err := config.K8sClient.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	sec.OwnerReferences = []v1.OwnerReference{{
+		APIVersion: "batch/v1",
+		Kind:       "Job",
+		Name:       job.Name,
+		UID:        job.UID,
+		Controller: boolPtr(true),
+	}}
+	_, err = config.K8sClient.CoreV1().Secrets(namespace).Update(context.TODO(), sec, v1.UpdateOptions{})
+	return err
+}
+
+func deleteResolvedRunnerSecret(namespace, secretName string) {
+	if err := config.K8sClient.CoreV1().Secrets(namespace).Delete(context.TODO(), secretName, v1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		log.Printf("Failed to delete resolved runner secret %s/%s: %v", namespace, secretName, err)
+	}
+}
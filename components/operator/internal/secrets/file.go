@@ -0,0 +1,46 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider is a development stand-in for an external store: the secret at path "a/b" is the
+// JSON object of string values in <dir>/a/b.json
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider returns a provider reading JSON files under dir
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+// Name implements Provider
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+// Get implements Provider
+func (p *FileProvider) Get(ctx context.Context, path string) (map[string]string, error) {
+	clean := filepath.Clean("/" + path)
+	if clean == "/" || strings.Contains(path, "..") {
+		return nil, fmt.Errorf("invalid secret path %q", path)
+	}
+	b, err := os.ReadFile(filepath.Join(p.dir, clean+".json"))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]string
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, fmt.Errorf("parse %s.json: %w", clean, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,113 @@
+// Package secrets resolves the runner secret references declared in ProjectSettings
+// (spec.runnerSecretRefs) against an external secret store.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// Provider reads secrets from an external store
+type Provider interface {
+	// Name identifies the provider in logs and session messages
+	Name() string
+	// Get returns every key stored at path
+	Get(ctx context.Context, path string) (map[string]string, error)
+}
+
+// Ref maps one runner environment variable to a key stored at a path relative to the
+// project's scope in the provider
+type Ref struct {
+	EnvName string
+	Path    string
+	Key     string
+}
+
+// ErrNotFound is returned by providers when nothing is stored at a path
+var ErrNotFound = errors.New("secret not found")
+
+// NewFromEnv builds the provider selected by SECRET_PROVIDER ("vault" or "file"). It returns
+// nil when no provider is configured; sessions in projects that declare references then fail.
+func NewFromEnv() (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv("SECRET_PROVIDER"))) {
+	case "":
+		return nil, nil
+	case "vault":
+		return NewVaultProviderFromEnv()
+	case "file":
+		dir := strings.TrimSpace(os.Getenv("SECRET_PROVIDER_FILE_DIR"))
+		if dir == "" {
+			dir = "/etc/ambient-secrets"
+		}
+		return NewFileProvider(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown SECRET_PROVIDER %q (expected vault or file)", os.Getenv("SECRET_PROVIDER"))
+	}
+}
+
+// ParseRefs reads spec.runnerSecretRefs from a ProjectSettings spec
+func ParseRefs(spec map[string]interface{}) []Ref {
+	items, _ := spec["runnerSecretRefs"].([]interface{})
+	refs := make([]Ref, 0, len(items))
+	for _, it := range items {
+		m, ok := it.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		r := Ref{}
+		r.EnvName, _ = m["envName"].(string)
+		r.Path, _ = m["path"].(string)
+		r.Key, _ = m["key"].(string)
+		r.EnvName, r.Path, r.Key = strings.TrimSpace(r.EnvName), strings.Trim(strings.TrimSpace(r.Path), "/"), strings.TrimSpace(r.Key)
+		if r.EnvName == "" || r.Path == "" || r.Key == "" || strings.Contains(r.Path, "..") {
+			continue
+		}
+		refs = append(refs, r)
+	}
+	return refs
+}
+
+// ProjectScope is the provider path prefix for a project's references, so a project cannot read
+// another project's secrets: <SECRET_PROVIDER_PATH_PREFIX>/<project>, prefix default ambient-code
+func ProjectScope(project string) string {
+	prefix := strings.Trim(strings.TrimSpace(os.Getenv("SECRET_PROVIDER_PATH_PREFIX")), "/")
+	if prefix == "" {
+		prefix = "ambient-code"
+	}
+	return path.Join(prefix, project)
+}
+
+// Resolve reads every reference under scope, fetching each path once, and returns the values
+// keyed by environment variable name. Any missing path or key fails the whole resolution.
+func Resolve(ctx context.Context, p Provider, scope string, refs []Ref) (map[string]string, error) {
+	if len(refs) == 0 {
+		return map[string]string{}, nil
+	}
+	if p == nil {
+		return nil, fmt.Errorf("project declares %d runner secret references but the operator has no SECRET_PROVIDER configured", len(refs))
+	}
+	byPath := map[string]map[string]string{}
+	out := make(map[string]string, len(refs))
+	for _, r := range refs {
+		full := path.Join(scope, r.Path)
+		data, ok := byPath[full]
+		if !ok {
+			var err error
+			data, err = p.Get(ctx, full)
+			if err != nil {
+				return nil, fmt.Errorf("%s: read %s for %s: %w", p.Name(), full, r.EnvName, err)
+			}
+			byPath[full] = data
+		}
+		v, ok := data[r.Key]
+		if !ok {
+			return nil, fmt.Errorf("%s: key %q not found at %s for %s", p.Name(), r.Key, full, r.EnvName)
+		}
+		out[r.EnvName] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,194 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// VaultProvider reads from a HashiCorp Vault KV version 2 secrets engine. It authenticates with a
+// static token (VAULT_TOKEN or VAULT_TOKEN_FILE) or, when VAULT_K8S_ROLE is set, with the
+// operator's ServiceAccount token through Vault's Kubernetes auth method.
+type VaultProvider struct {
+	addr      string
+	mount     string
+	namespace string
+
+	staticToken string
+	k8sRole     string
+	k8sMount    string
+
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+// NewVaultProviderFromEnv configures a VaultProvider from VAULT_ADDR, VAULT_KV_MOUNT (default
+// "secret"), VAULT_NAMESPACE and the authentication variables above
+func NewVaultProviderFromEnv() (*VaultProvider, error) {
+	addr := strings.TrimRight(strings.TrimSpace(os.Getenv("VAULT_ADDR")), "/")
+	if addr == "" {
+		return nil, fmt.Errorf("VAULT_ADDR is required for the vault secret provider")
+	}
+	p := &VaultProvider{
+		addr:       addr,
+		mount:      strings.Trim(strings.TrimSpace(os.Getenv("VAULT_KV_MOUNT")), "/"),
+		namespace:  strings.TrimSpace(os.Getenv("VAULT_NAMESPACE")),
+		k8sRole:    strings.TrimSpace(os.Getenv("VAULT_K8S_ROLE")),
+		k8sMount:   strings.Trim(strings.TrimSpace(os.Getenv("VAULT_K8S_AUTH_MOUNT")), "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+	if p.mount == "" {
+		p.mount = "secret"
+	}
+	if p.k8sMount == "" {
+		p.k8sMount = "kubernetes"
+	}
+	p.staticToken = strings.TrimSpace(os.Getenv("VAULT_TOKEN"))
+	if p.staticToken == "" {
+		if f := strings.TrimSpace(os.Getenv("VAULT_TOKEN_FILE")); f != "" {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("read VAULT_TOKEN_FILE: %w", err)
+			}
+			p.staticToken = strings.TrimSpace(string(b))
+		}
+	}
+	if p.staticToken == "" && p.k8sRole == "" {
+		return nil, fmt.Errorf("the vault secret provider needs VAULT_TOKEN, VAULT_TOKEN_FILE or VAULT_K8S_ROLE")
+	}
+	return p, nil
+}
+
+// Name implements Provider
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// Get implements Provider by reading the latest version of the KV v2 secret at path
+func (p *VaultProvider) Get(ctx context.Context, path string) (map[string]string, error) {
+	token, err := p.clientToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	status, err := p.do(ctx, http.MethodGet, "/v1/"+p.mount+"/data/"+escapePath(path), token, nil, &body)
+	if status == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(body.Data.Data))
+	for k, v := range body.Data.Data {
+		switch val := v.(type) {
+		case string:
+			out[k] = val
+		default:
+			b, _ := json.Marshal(val)
+			out[k] = string(b)
+		}
+	}
+	return out, nil
+}
+
+// clientToken returns the static token, or a cached Kubernetes-auth token renewed a minute
+// before its lease ends
+func (p *VaultProvider) clientToken(ctx context.Context) (string, error) {
+	if p.staticToken != "" {
+		return p.staticToken, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.token != "" && time.Now().Before(p.tokenExpiry) {
+		return p.token, nil
+	}
+
+	jwt, err := os.ReadFile(serviceAccountTokenFile)
+	if err != nil {
+		return "", fmt.Errorf("read ServiceAccount token for vault login: %w", err)
+	}
+	var body struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	login := map[string]string{"role": p.k8sRole, "jwt": strings.TrimSpace(string(jwt))}
+	if _, err := p.do(ctx, http.MethodPost, "/v1/auth/"+p.k8sMount+"/login", "", login, &body); err != nil {
+		return "", fmt.Errorf("vault kubernetes login: %w", err)
+	}
+	if body.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault kubernetes login returned no client token")
+	}
+	ttl := time.Duration(body.Auth.LeaseDuration) * time.Second
+	if ttl > 2*time.Minute {
+		ttl -= time.Minute
+	}
+	p.token, p.tokenExpiry = body.Auth.ClientToken, time.Now().Add(ttl)
+	return p.token, nil
+}
+
+func (p *VaultProvider) do(ctx context.Context, method, path, token string, in, out interface{}) (int, error) {
+	var reader io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.addr+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Vault error bodies carry messages only, never secret data
+		return resp.StatusCode, fmt.Errorf("vault %s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(b)))
+	}
+	if out != nil {
+		if err := json.Unmarshal(b, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("decode vault response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+func escapePath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
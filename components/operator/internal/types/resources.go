@@ -1,21 +1,12 @@
 package types
 
-import "k8s.io/apimachinery/pkg/runtime/schema"
+import (
+	vteamv1alpha1 "ambient-code-api/vteam/v1alpha1"
 
-// GetAgenticSessionResource returns the GroupVersionResource for AgenticSession
-func GetAgenticSessionResource() schema.GroupVersionResource {
-	return schema.GroupVersionResource{
-		Group:    "vteam.ambient-code",
-		Version:  "v1alpha1",
-		Resource: "agenticsessions",
-	}
-}
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 // GetProjectSettingsResource returns the GroupVersionResource for ProjectSettings
 func GetProjectSettingsResource() schema.GroupVersionResource {
-	return schema.GroupVersionResource{
-		Group:    "vteam.ambient-code",
-		Version:  "v1alpha1",
-		Resource: "projectsettings",
-	}
+	return vteamv1alpha1.ProjectSettingsResource
 }
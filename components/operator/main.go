@@ -5,6 +5,7 @@ import (
 
 	"ambient-code-operator/internal/config"
 	"ambient-code-operator/internal/handlers"
+	"ambient-code-operator/internal/secrets"
 )
 
 func main() {
@@ -19,6 +20,16 @@ func main() {
 	log.Printf("Agentic Session Operator starting in namespace: %s", appConfig.Namespace)
 	log.Printf("Using ambient-code runner image: %s", appConfig.AmbientCodeRunnerImage)
 
+	// Initialize the external secret provider for runner secret references
+	provider, err := secrets.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to initialize secret provider: %v", err)
+	}
+	if provider != nil {
+		config.SecretProvider = provider
+		log.Printf("Resolving runner secret references with the %s provider", provider.Name())
+	}
+
 	// Start watching AgenticSession resources
 	go handlers.WatchAgenticSessions()
 
@@ -28,9 +39,15 @@ func main() {
 	// Start watching ProjectSettings resources
 	go handlers.WatchProjectSettings()
 
+	// Periodically restore managed RoleBindings changed outside ProjectSettings
+	go handlers.ResyncProjectSettings()
+
 	// Start cleanup of expired temporary content pods
 	go handlers.CleanupExpiredTempContentPods()
 
+	// Start cleanup of expired project access keys
+	go handlers.CleanupExpiredAccessKeys()
+
 	// Keep the operator running
 	select {}
 }
@@ -204,15 +204,26 @@ class ClaudeCodeAdapter:
                     allowed_tools.append(f"mcp__{server_name}")
                 logging.info(f"MCP tool permissions granted for servers: {list(mcp_servers.keys())}")
 
+            # Agent personas selected for this session (validated by the backend against .claude/agents)
+            system_prompt = {"type":"preset",
+                             "preset":"claude_code"}
+            personas = [p.strip() for p in (os.getenv('AGENT_PERSONAS') or '').split(',') if p.strip()]
+            if personas:
+                system_prompt["append"] = (
+                    "The following agent personas are active for this session: "
+                    + ", ".join(personas)
+                    + ". Only delegate to these agents."
+                )
+                logging.info(f"Active agent personas: {personas}")
+
             # Configure SDK options with session resumption if continuing
             options = ClaudeAgentOptions(
-                cwd=cwd_path, 
+                cwd=cwd_path,
                 permission_mode="acceptEdits",
                 allowed_tools= allowed_tools,
                 mcp_servers=mcp_servers,
                 setting_sources=["project"],
-                system_prompt={"type":"preset",
-                               "preset":"claude_code"}
+                system_prompt=system_prompt
                 )
             
             # Use SDK's built-in session resumption if continuing
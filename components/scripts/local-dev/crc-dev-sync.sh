@@ -8,6 +8,7 @@ set -euo pipefail
 SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 REPO_ROOT="$(cd "${SCRIPT_DIR}/../../.." && pwd)"
 BACKEND_DIR="${REPO_ROOT}/components/backend"
+API_DIR="${REPO_ROOT}/components/api"
 FRONTEND_DIR="${REPO_ROOT}/components/frontend"
 
 PROJECT_NAME="${PROJECT_NAME:-vteam-dev}"
@@ -48,6 +49,11 @@ sync_backend() {
   
   log "Syncing to backend pod: $pod_name"
   
+  # The backend's go.mod replaces the shared API module with ../api
+  oc rsync "$API_DIR/" "$pod_name:/api/" \
+    --exclude=.git \
+    -n "$PROJECT_NAME"
+
   # Initial full sync
   oc rsync "$BACKEND_DIR/" "$pod_name:/app/" \
     --exclude=tmp \
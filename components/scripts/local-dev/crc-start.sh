@@ -29,6 +29,7 @@ DEV_MODE="${DEV_MODE:-false}"
 BACKEND_DIR="${REPO_ROOT}/components/backend"
 FRONTEND_DIR="${REPO_ROOT}/components/frontend"
 OPERATOR_DIR="${REPO_ROOT}/components/operator"
+COMPONENTS_DIR="${REPO_ROOT}/components"
 CRDS_DIR="${REPO_ROOT}/components/manifests/crds"
 
 ###############
@@ -294,14 +295,15 @@ build_and_deploy() {
   oc apply -f "${MANIFESTS_DIR}/operator-build-config.yaml" -n "$PROJECT_NAME"
   
   # Start builds
+  # Backend and operator build from components/ to include the shared API module
   log "Building backend image..."
-  oc start-build vteam-backend --from-dir="$BACKEND_DIR" --wait -n "$PROJECT_NAME"
+  oc start-build vteam-backend --from-dir="$COMPONENTS_DIR" --wait -n "$PROJECT_NAME"
   
   log "Building frontend image..."  
   oc start-build vteam-frontend --from-dir="$FRONTEND_DIR" --wait -n "$PROJECT_NAME"
   
   log "Building operator image..."
-  oc start-build vteam-operator --from-dir="$OPERATOR_DIR" --wait -n "$PROJECT_NAME"
+  oc start-build vteam-operator --from-dir="$COMPONENTS_DIR" --wait -n "$PROJECT_NAME"
   
   # Deploy services
   log "Creating backend PVC..."